@@ -0,0 +1,326 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// actionsEnabled returns true iff the GitHub Actions mode is enabled.
+func actionsEnabled() bool {
+	return viper.GetBool(githubActionsCfgKey)
+}
+
+// escapeWorkflowCommandData escapes the given message of a workflow command.
+func escapeWorkflowCommandData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// writeWorkflowCommand writes the GitHub Actions workflow command with the
+// given name and message to the given writer.
+func writeWorkflowCommand(w io.Writer, command string, message string) error {
+	_, err := fmt.Fprintf(w, "::%s::%s\n", command, escapeWorkflowCommandData(message))
+	return err
+}
+
+// workflowCommandCore is a zapcore.Core emitting log entries of level warning
+// and above as GitHub Actions workflow commands, so that they are shown as
+// annotations of the workflow run.
+type workflowCommandCore struct {
+	out    io.Writer
+	fields []zapcore.Field
+}
+
+// Enabled returns true iff the given level is at least warning.
+func (c *workflowCommandCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.WarnLevel
+}
+
+// With adds the given fields to the entries emitted by the returned core.
+func (c *workflowCommandCore) With(fields []zapcore.Field) zapcore.Core {
+	return &workflowCommandCore{out: c.out, fields: append(append([]zapcore.Field(nil), c.fields...), fields...)}
+}
+
+// Check adds the core to the given checked entry if the entry's level is
+// enabled.
+func (c *workflowCommandCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write emits the given entry as a warning or error workflow command. The
+// fields are appended to the message.
+func (c *workflowCommandCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range append(append([]zapcore.Field(nil), c.fields...), fields...) {
+		field.AddTo(enc)
+	}
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	message := entry.Message
+	for _, key := range keys {
+		message += fmt.Sprintf(" %s=%v", key, enc.Fields[key])
+	}
+	command := "warning"
+	if entry.Level >= zapcore.ErrorLevel {
+		command = "error"
+	}
+	return writeWorkflowCommand(c.out, command, message)
+}
+
+// Sync does nothing as entries aren't buffered.
+func (c *workflowCommandCore) Sync() error {
+	return nil
+}
+
+// runResults records the results of a run, e.g., for reporting them to GitHub
+// Actions.
+type runResults struct {
+	mutex sync.Mutex
+
+	// The files generated by the run
+	files []string
+
+	// The summary of the contributions analyzed by the run, nil if no
+	// contributions have been collected
+	summary *internal.Summary
+
+	// The contributions summarized and the last day analyzed
+	contributions []internal.Contribution
+	lastDay       time.Time
+
+	// The number of contributions made on the last day analyzed
+	lastDayContributions int
+
+	// The URLs generated files have been published or uploaded to
+	links []string
+
+	// The time spent collecting contributions
+	collectionDuration time.Duration
+
+	// The last known remaining requests by GitHub API rate limit resource
+	// (e.g., 'core' or 'search')
+	rateLimitRemaining map[string]int
+}
+
+// results records the results of the current run.
+var results runResults
+
+// reset forgets the results recorded so far, e.g., before the next scheduled
+// run of a command in daemon mode.
+func (r *runResults) reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.files = nil
+	r.summary = nil
+	r.contributions = nil
+	r.lastDay = time.Time{}
+	r.lastDayContributions = 0
+	r.links = nil
+	r.collectionDuration = 0
+	r.rateLimitRemaining = nil
+}
+
+// recordFile records that the file with the given name has been generated.
+func (r *runResults) recordFile(filename string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.files = append(r.files, filename)
+}
+
+// generatedFiles returns the files generated so far.
+func (r *runResults) generatedFiles() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.files...)
+}
+
+// recordContributions records the summary of the given contributions made
+// within the 52 weeks ending with the given day. Only the contributions
+// recorded first are summarized, as further ones are collected for
+// comparisons.
+func (r *runResults) recordContributions(contributions []internal.Contribution, lastDay time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.summary == nil {
+		r.summary = internal.NewSummary(contributions, lastDay)
+		r.contributions, r.lastDay = contributions, lastDay
+		records := internal.DailyRecords(contributions, lastDay)
+		r.lastDayContributions = records[len(records)-1].Count
+	}
+}
+
+// recordLink records that a generated file has been published or uploaded to
+// the given URL.
+func (r *runResults) recordLink(u string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.links = append(r.links, u)
+}
+
+// recordCollectionDuration records that collecting contributions took the
+// given time.
+func (r *runResults) recordCollectionDuration(d time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.collectionDuration += d
+}
+
+// recordRateLimit records the remaining requests of the GitHub API rate limit
+// of the given resource.
+func (r *runResults) recordRateLimit(resource string, remaining int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.rateLimitRemaining == nil {
+		r.rateLimitRemaining = make(map[string]int)
+	}
+	r.rateLimitRemaining[resource] = remaining
+}
+
+// reportToActions reports the results of the given command to GitHub
+// Actions. The generated files and totals are written to the step outputs and
+// a job summary listing the generated files is added, if the respective files
+// are provided by the runner.
+func (r *runResults) reportToActions(cmd *cobra.Command) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.summary != nil {
+		if err := writeWorkflowCommand(os.Stderr, "notice", fmt.Sprintf("%d contributions by %d contributors to %d repositories",
+			r.summary.TotalContributions, r.summary.UniqueContributors, r.summary.ActiveRepositories)); err != nil {
+			return err
+		}
+	}
+	if filename := os.Getenv("GITHUB_OUTPUT"); filename != "" {
+		if err := appendToFile(filename, r.outputs); err != nil {
+			return fmt.Errorf("writing step outputs failed: %w", err)
+		}
+	}
+	if filename := os.Getenv("GITHUB_STEP_SUMMARY"); filename != "" {
+		if err := appendToFile(filename, func(w io.Writer) error {
+			return r.jobSummary(w, cmd.Name())
+		}); err != nil {
+			return fmt.Errorf("writing job summary failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// appendToFile appends the content written by the given function to the file
+// with the given name.
+func appendToFile(filename string, write func(w io.Writer) error) error {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// outputs writes the step outputs in the format of the GITHUB_OUTPUT file.
+// These are the generated 'files' (one per line), the first generated 'file',
+// and the totals of the analyzed contributions.
+func (r *runResults) outputs(w io.Writer) error {
+	delimiter := make([]byte, 16)
+	if _, err := rand.Read(delimiter); err != nil {
+		return err
+	}
+	outputs := fmt.Sprintf("files<<ghadelimiter_%[1]s\n%[2]s\nghadelimiter_%[1]s\n", hex.EncodeToString(delimiter), strings.Join(r.files, "\n"))
+	if len(r.files) != 0 {
+		outputs += fmt.Sprintf("file=%s\n", r.files[0])
+	}
+	if s := r.summary; s != nil {
+		outputs += fmt.Sprintf("total-contributions=%d\ncommits=%d\nissues=%d\npull-requests=%d\ncontributors=%d\nactive-repositories=%d\n",
+			s.TotalContributions, s.ContributionsByType[internal.CommitContribution], s.ContributionsByType[internal.IssueContribution],
+			s.ContributionsByType[internal.PullRequestContribution], s.UniqueContributors, s.ActiveRepositories)
+	}
+	_, err := io.WriteString(w, outputs)
+	return err
+}
+
+// writeSummaryTable writes the totals of the given summary as a markdown
+// table.
+func writeSummaryTable(b *strings.Builder, s *internal.Summary) {
+	fmt.Fprintf(b, "| Period | Contributions | Commits | Issues | Pull Requests | Contributors | Active Repositories |\n")
+	fmt.Fprintf(b, "| ------ | ------------- | ------- | ------ | ------------- | ------------ | ------------------- |\n")
+	fmt.Fprintf(b, "| %s – %s | %d | %d | %d | %d | %d | %d |\n\n", s.From, s.Until, s.TotalContributions,
+		s.ContributionsByType[internal.CommitContribution], s.ContributionsByType[internal.IssueContribution],
+		s.ContributionsByType[internal.PullRequestContribution], s.UniqueContributors, s.ActiveRepositories)
+}
+
+// fileLink returns the URL the file with the given name has been published or
+// uploaded to. Returns an empty string if it hasn't been.
+func (r *runResults) fileLink(filename string) string {
+	suffix := "/" + publishedPath(filename)
+	for _, u := range r.links {
+		if strings.HasSuffix(u, suffix) {
+			return u
+		}
+	}
+	return ""
+}
+
+// jobSummary writes the markdown job summary of the command with the given
+// name. The generated files are listed in a table. As GitHub strips data URLs
+// from job summaries, only SVG documents that have been published or uploaded
+// are shown as images.
+func (r *runResults) jobSummary(w io.Writer, command string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## herdstat %s\n\n", command)
+	if r.summary != nil {
+		writeSummaryTable(&b, r.summary)
+	}
+	if len(r.files) == 0 {
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	fmt.Fprintf(&b, "| File | Size | Link |\n")
+	fmt.Fprintf(&b, "| ---- | ---- | ---- |\n")
+	var images []string
+	for _, filename := range r.files {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		link := "-"
+		if u := r.fileLink(filename); u != "" {
+			link = fmt.Sprintf("[%s](%s)", path.Base(u), u)
+			if filepath.Ext(filename) == ".svg" {
+				images = append(images, fmt.Sprintf("![%s](%s)\n\n", filename, u))
+			}
+		}
+		fmt.Fprintf(&b, "| `%s` | %d KB | %s |\n", filename, (info.Size()+1023)/1024, link)
+	}
+	b.WriteString("\n")
+	for _, image := range images {
+		b.WriteString(image)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
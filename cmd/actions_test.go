@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("GitHub Actions mode", func() {
+	It("emits warnings as workflow commands", func() {
+		var out bytes.Buffer
+		l := zap.New(&workflowCommandCore{out: &out}).Sugar()
+		l.Infow("Not emitted")
+		l.With("repository", "herdstat/herdstat").Warnw("Cloning failed\n100%", "attempt", 2)
+		Expect(out.String()).To(Equal("::warning::Cloning failed%0A100%25 attempt=2 repository=herdstat/herdstat\n"))
+	})
+
+	It("writes the generated files and totals as step outputs", func() {
+		lastDay := time.Date(2013, time.April, 22, 23, 59, 0, 0, time.UTC)
+		var run runResults
+		run.recordFile("contribution-graph.svg")
+		run.recordContributions([]internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "a", Date: lastDay},
+			{Type: internal.IssueContribution, Repository: "herdstat/herdstat", Login: "b", Date: lastDay},
+		}, lastDay)
+		run.recordContributions(nil, lastDay)
+		var out bytes.Buffer
+		Expect(run.outputs(&out)).To(Succeed())
+		Expect(out.String()).To(MatchRegexp("^files<<ghadelimiter_[0-9a-f]+\ncontribution-graph.svg\nghadelimiter_[0-9a-f]+\n"))
+		Expect(out.String()).To(ContainSubstring("\nfile=contribution-graph.svg\ntotal-contributions=2\ncommits=1\nissues=1\npull-requests=0\ncontributors=2\n"))
+	})
+
+	It("lists the generated files in the job summary", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "graph.svg")
+		Expect(os.WriteFile(filename, []byte("<svg/>"), 0o644)).To(Succeed())
+		var run runResults
+		run.recordFile(filename)
+		var out bytes.Buffer
+		Expect(run.jobSummary(&out, "contribution-graph")).To(Succeed())
+		Expect(out.String()).To(HavePrefix("## herdstat contribution-graph\n"))
+		Expect(out.String()).To(ContainSubstring(fmt.Sprintf("| `%s` | 1 KB | - |\n", filename)))
+		Expect(out.String()).NotTo(ContainSubstring("data:"))
+	})
+
+	It("shows published SVG documents as images in the job summary", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "graph.svg")
+		Expect(os.WriteFile(filename, []byte("<svg/>"), 0o644)).To(Succeed())
+		var run runResults
+		run.recordFile(filename)
+		run.recordLink("https://github.com/herdstat/stats/raw/0123abc/graph.svg")
+		var out bytes.Buffer
+		Expect(run.jobSummary(&out, "contribution-graph")).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("| [graph.svg](https://github.com/herdstat/stats/raw/0123abc/graph.svg) |\n"))
+		Expect(out.String()).To(ContainSubstring(fmt.Sprintf("![%s](https://github.com/herdstat/stats/raw/0123abc/graph.svg)", filename)))
+	})
+})
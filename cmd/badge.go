@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the badge command
+const (
+	// The name of the output SVG file
+	badgeFilenameCfgKey = "badge.filename"
+	// The number of weeks visualized by the sparkline
+	badgeWeeksCfgKey = "badge.weeks"
+	// The label rendered on the left side of the badge
+	badgeLabelCfgKey = "badge.label"
+	// The background color of the sparkline part of the badge
+	badgeColorCfgKey = "badge.color"
+)
+
+// badgeCmd represents the badge command
+var badgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "Generates a compact sparkline badge visualizing the recent contribution trend",
+	Args:  cobra.NoArgs,
+	RunE:  runBadge,
+}
+
+func runBadge(cmd *cobra.Command, args []string) error {
+
+	colorStr := viper.GetString(badgeColorCfgKey)
+	badgeColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	weeks := viper.GetInt(badgeWeeksCfgKey)
+	if weeks < 2 || weeks > 52 {
+		return fmt.Errorf("invalid number of weeks; allowed range is [2..52]")
+	}
+
+	data, _, err := collectContributionRecords(cmd)
+	if err != nil {
+		return err
+	}
+
+	sparkline := internal.NewSparkline(data, weeks, viper.GetString(badgeLabelCfgKey), badgeColor)
+	filename, err := writeSVG(cmd, sparkline, viper.GetString(badgeFilenameCfgKey), true, false)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Badge written to '%s'\n", filename)
+
+	return nil
+}
+
+// Initialize the 'badge' command.
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+
+	// Flag to control the number of weeks visualized
+	const weeksFlag = "weeks"
+	badgeCmd.Flags().Int(
+		weeksFlag,
+		12,
+		"The number of weeks visualized by the sparkline")
+	if err := viper.BindPFlag(badgeWeeksCfgKey, badgeCmd.Flags().Lookup(weeksFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", weeksFlag, "Error", err)
+	}
+
+	// Flag to control the badge label
+	const labelFlag = "label"
+	badgeCmd.Flags().String(
+		labelFlag,
+		"activity",
+		"The label rendered on the left side of the badge")
+	if err := viper.BindPFlag(badgeLabelCfgKey, badgeCmd.Flags().Lookup(labelFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", labelFlag, "Error", err)
+	}
+
+	// Flag to control the badge color
+	const colorFlag = "color"
+	badgeCmd.Flags().String(
+		colorFlag,
+		"44CC11",
+		"The background color of the sparkline part of the badge")
+	if err := viper.BindPFlag(badgeColorCfgKey, badgeCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	badgeCmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"contribution-badge.svg",
+		"The name of the generated SVG file")
+	if err := viper.BindPFlag(badgeFilenameCfgKey, badgeCmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"image/color"
+	"sort"
+)
+
+// Configuration keys for the burndown command
+const (
+	// The name of the output file
+	burndownFilenameCfgKey = "burndown.filename"
+	// The kind of chart
+	burndownTypeCfgKey = "burndown.type"
+	// The length of the periods issues are aggregated over
+	burndownGranularityCfgKey = "burndown.granularity"
+	// The color of the line
+	burndownColorCfgKey = "burndown.color"
+	// Toggle for generating a chart per repository
+	burndownPerRepositoryCfgKey = "burndown.per-repository"
+)
+
+// Kinds of charts generated by the burndown command.
+const (
+	burndownChart = "burndown"
+	burnupChart   = "burnup"
+)
+
+// burndownCmd represents the burndown command
+var burndownCmd = &cobra.Command{
+	Use:   "burndown",
+	Short: "Generates a burndown or burnup chart of the issues",
+	Long: `Reconstructs the number of open issues over the analyzed period from the
+points in time issues have been opened and closed. Renders either a burndown
+chart of the open issues or a burnup chart of the cumulative number of opened
+and closed issues across all repositories and optionally per repository.`,
+	Args: cobra.NoArgs,
+	RunE: runBurndown,
+}
+
+func runBurndown(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(burndownColorCfgKey)
+	lineColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	granularity, err := internal.ParseGranularity(viper.GetString(burndownGranularityCfgKey))
+	if err != nil {
+		return err
+	}
+
+	chartType := viper.GetString(burndownTypeCfgKey)
+	if chartType != burndownChart && chartType != burnupChart {
+		return fmt.Errorf("unknown chart type '%s'; supported are %s and %s", chartType, burndownChart, burnupChart)
+	}
+
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var issues []internal.Issue
+	perRepository := make(map[string][]internal.Issue)
+	for _, repository := range repositories {
+		i, err := collectIssueLifecycles(repository, lastDay)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, i...)
+		perRepository[repository.GetFullName()] = i
+	}
+
+	write := func(title string, issues []internal.Issue, filename string) error {
+		flow := internal.NewIssueFlow(issues, lastDay, granularity)
+		filename, err = writeSVG(cmd, newIssueFlowChart(chartType, title, flow, lineColor), filename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Issue %s chart written to '%s'\n", chartType, filename)
+		return nil
+	}
+
+	filename := viper.GetString(burndownFilenameCfgKey)
+	if err := write(fmt.Sprintf("Issue %s", chartType), issues, filename); err != nil {
+		return err
+	}
+	if viper.GetBool(burndownPerRepositoryCfgKey) {
+		names := internal.Keys(perRepository)
+		sort.Strings(names)
+		for _, name := range names {
+			err := write(fmt.Sprintf("Issue %s of %s", chartType, name), perRepository[name], suffixedFilename(filename, name))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newIssueFlowChart creates the chart of the given type visualizing the given
+// issue flow.
+func newIssueFlowChart(chartType string, title string, flow *internal.IssueFlow, color color.RGBA) *internal.LineChart {
+	if chartType == burnupChart {
+		return internal.NewBurnupChart(title, flow, color)
+	}
+	return internal.NewBurndownChart(title, flow, color, true)
+}
+
+// Initialize the 'burndown' command.
+func init() {
+	rootCmd.AddCommand(burndownCmd)
+
+	// Flag to control the kind of chart
+	const typeFlag = "type"
+	burndownCmd.Flags().String(
+		typeFlag,
+		burndownChart,
+		fmt.Sprintf("The kind of chart (%s or %s)", burndownChart, burnupChart))
+	if err := viper.BindPFlag(burndownTypeCfgKey, burndownCmd.Flags().Lookup(typeFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", typeFlag, "Error", err)
+	}
+
+	// Flag to control the granularity
+	const granularityFlag = "granularity"
+	burndownCmd.Flags().String(
+		granularityFlag,
+		internal.WeeklyGranularityName,
+		fmt.Sprintf("The length of the periods issues are aggregated over (%s, %s, or %s)",
+			internal.DailyGranularityName, internal.WeeklyGranularityName, internal.MonthlyGranularityName))
+	if err := viper.BindPFlag(burndownGranularityCfgKey, burndownCmd.Flags().Lookup(granularityFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", granularityFlag, "Error", err)
+	}
+
+	// Flag to control the line color
+	const colorFlag = "color"
+	burndownCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the line")
+	if err := viper.BindPFlag(burndownColorCfgKey, burndownCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	// Flag to toggle charts per repository
+	const perRepositoryFlag = "per-repository"
+	burndownCmd.Flags().Bool(
+		perRepositoryFlag,
+		false,
+		"Flag to toggle generating an additional chart per repository")
+	if err := viper.BindPFlag(burndownPerRepositoryCfgKey, burndownCmd.Flags().Lookup(perRepositoryFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", perRepositoryFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	burndownCmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"burndown.svg",
+		"The name of the generated SVG file")
+	if err := viper.BindPFlag(burndownFilenameCfgKey, burndownCmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
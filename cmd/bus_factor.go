@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the bus-factor command
+const (
+	// The 50% bus factor below which repositories are flagged
+	busFactorThresholdCfgKey = "bus-factor.threshold"
+	// The format of the report
+	busFactorFormatCfgKey = "bus-factor.format"
+	// The name of the output file
+	busFactorFilenameCfgKey = "bus-factor.filename"
+)
+
+// busFactorCmd represents the bus-factor command
+var busFactorCmd = &cobra.Command{
+	Use:   "bus-factor",
+	Short: "Computes how many contributors account for the majority of contributions",
+	Long: `Computes the number of contributors accounting for 50% and 80% of the
+contributions per repository and across all repositories. Repositories with a
+50% bus factor below the configured threshold are flagged.`,
+	Args: cobra.NoArgs,
+	RunE: runBusFactor,
+}
+
+func runBusFactor(cmd *cobra.Command, args []string) error {
+	threshold := viper.GetInt(busFactorThresholdCfgKey)
+	if threshold < 1 {
+		return fmt.Errorf("bus factor threshold must be positive but is %d", threshold)
+	}
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewBusFactorReport(contributions, lastDay, threshold)
+	return writeReport(cmd, report, viper.GetString(busFactorFormatCfgKey), viper.GetString(busFactorFilenameCfgKey))
+}
+
+// Initialize the 'bus-factor' command.
+func init() {
+	rootCmd.AddCommand(busFactorCmd)
+
+	// Flag to control the bus factor threshold
+	const thresholdFlag = "threshold"
+	busFactorCmd.Flags().IntP(thresholdFlag, "t", 2,
+		"The 50% bus factor below which repositories are flagged")
+	if err := viper.BindPFlag(busFactorThresholdCfgKey, busFactorCmd.Flags().Lookup(thresholdFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", thresholdFlag, "Error", err)
+	}
+
+	addReportFlags(busFactorCmd, busFactorFormatCfgKey, busFactorFilenameCfgKey)
+}
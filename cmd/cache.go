@@ -0,0 +1,666 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/viper"
+	bolt "go.etcd.io/bbolt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buckets and keys of the cache. The cache contains a top-level bucket per
+// repository holding the issues and pull requests as well as the reviews of
+// the pull requests keyed by number. Responses to GET requests are stored in
+// a separate top-level bucket keyed by URL.
+var (
+	cacheIssuesBucket    = []byte("issues")
+	cacheReviewsBucket   = []byte("reviews")
+	cacheResponsesBucket = []byte("responses")
+	cacheSyncedKey       = []byte("synced")
+	cacheSinceKey        = []byte("since")
+)
+
+// cacheSyncOverlap is subtracted from the time of the last synchronization
+// when fetching the issues updated since then to compensate for clock skew
+// between the local machine and GitHub.
+const cacheSyncOverlap = time.Hour
+
+// cacheTimeout is the time to wait for other processes to release the cache.
+const cacheTimeout = 10 * time.Second
+
+// apiCache is an on-disk cache of the issues, pull requests, and reviews
+// fetched from the GitHub API. It allows repeated runs to only fetch the
+// issues and pull requests updated since the previous run. In addition, it
+// stores the responses to GET requests along with their validators, so that
+// unchanged resources can be requested conditionally.
+type apiCache struct {
+	db *bolt.DB
+}
+
+// cachedResponse is a successful response to a GET request along with the
+// validators used for conditionally requesting the resource again.
+type cachedResponse struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+var (
+	// The cache shared by all collectors of a run. Opened on first use.
+	sharedCache *apiCache
+
+	// Guards the initialization of sharedCache.
+	sharedCacheMutex sync.Mutex
+)
+
+// getCache returns the configured cache. Returns nil if caching is disabled.
+// The cache is opened on first use and kept open for the remaining run, as the
+// cache file is locked exclusively while open.
+func getCache() (*apiCache, error) {
+	filename := viper.GetString(cacheCfgKey)
+	if filename == "" {
+		return nil, nil
+	}
+	sharedCacheMutex.Lock()
+	defer sharedCacheMutex.Unlock()
+	if sharedCache == nil {
+		cache, err := openAPICache(filename)
+		if err != nil {
+			return nil, err
+		}
+		sharedCache = cache
+	}
+	return sharedCache, nil
+}
+
+// cachedReviews are the reviews of a pull request along with the time the pull
+// request was last updated when fetching them.
+type cachedReviews struct {
+	Updated time.Time                   `json:"updated"`
+	Reviews []*github.PullRequestReview `json:"reviews"`
+}
+
+// openAPICache opens the cache stored in the file with the given name. The
+// file is created if it doesn't exist.
+func openAPICache(filename string) (*apiCache, error) {
+	db, err := bolt.Open(filename, 0644, &bolt.Options{Timeout: cacheTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("opening cache '%s' failed: %w", filename, err)
+	}
+	return &apiCache{db: db}, nil
+}
+
+// Close closes the cache.
+func (c *apiCache) Close() error {
+	return c.db.Close()
+}
+
+// cacheKey encodes the given issue or pull request number as a key preserving
+// the numeric order.
+func cacheKey(number int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(number))
+	return key
+}
+
+// syncState returns the time of the last synchronization of the issues of the
+// given repository and the update time since which all issues are cached.
+// Returns false if the issues of the repository have never been cached.
+func (c *apiCache) syncState(repository string) (time.Time, time.Time, bool, error) {
+	var synced, since time.Time
+	ok := false
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(repository))
+		if b == nil || b.Get(cacheSyncedKey) == nil {
+			return nil
+		}
+		ok = true
+		if err := synced.UnmarshalText(b.Get(cacheSyncedKey)); err != nil {
+			return err
+		}
+		return since.UnmarshalText(b.Get(cacheSinceKey))
+	})
+	return synced, since, ok, err
+}
+
+// storeIssues stores the given issues of the given repository and records the
+// time of the synchronization as well as the update time since which all
+// issues are cached.
+func (c *apiCache) storeIssues(repository string, issues []*github.Issue, synced time.Time, since time.Time) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(repository))
+		if err != nil {
+			return err
+		}
+		issuesBucket, err := b.CreateBucketIfNotExists(cacheIssuesBucket)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			value, err := json.Marshal(issue)
+			if err != nil {
+				return err
+			}
+			if err := issuesBucket.Put(cacheKey(issue.GetNumber()), value); err != nil {
+				return err
+			}
+		}
+		for key, t := range map[string]time.Time{string(cacheSyncedKey): synced, string(cacheSinceKey): since} {
+			value, err := t.MarshalText()
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// issues returns the cached issues of the given repository updated after since
+// ordered by descending number.
+func (c *apiCache) issues(repository string, since time.Time) ([]*github.Issue, error) {
+	var issues []*github.Issue
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(repository))
+		if b == nil || b.Bucket(cacheIssuesBucket) == nil {
+			return nil
+		}
+		return b.Bucket(cacheIssuesBucket).ForEach(func(_, value []byte) error {
+			var issue github.Issue
+			if err := json.Unmarshal(value, &issue); err != nil {
+				return err
+			}
+			if !issue.GetUpdatedAt().Before(since) {
+				issues = append(issues, &issue)
+			}
+			return nil
+		})
+	})
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].GetNumber() > issues[j].GetNumber()
+	})
+	return issues, err
+}
+
+// updated returns the time the cached issue or pull request with the given
+// number was last updated. Returns false if it isn't cached.
+func (c *apiCache) updated(repository string, number int) (time.Time, bool, error) {
+	var updated time.Time
+	ok := false
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(repository))
+		if b == nil || b.Bucket(cacheIssuesBucket) == nil {
+			return nil
+		}
+		value := b.Bucket(cacheIssuesBucket).Get(cacheKey(number))
+		if value == nil {
+			return nil
+		}
+		var issue github.Issue
+		if err := json.Unmarshal(value, &issue); err != nil {
+			return err
+		}
+		updated, ok = issue.GetUpdatedAt().Time, true
+		return nil
+	})
+	return updated, ok, err
+}
+
+// reviews returns the cached reviews of the pull request with the given
+// number. Returns false if the reviews aren't cached or the pull request has
+// been updated since they were fetched.
+func (c *apiCache) reviews(repository string, number int) ([]*github.PullRequestReview, bool, error) {
+	updated, ok, err := c.updated(repository, number)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	var cached cachedReviews
+	found := false
+	err = c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(repository))
+		if b == nil || b.Bucket(cacheReviewsBucket) == nil {
+			return nil
+		}
+		value := b.Bucket(cacheReviewsBucket).Get(cacheKey(number))
+		if value == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(value, &cached)
+	})
+	if err != nil || !found || !cached.Updated.Equal(updated) {
+		return nil, false, err
+	}
+	return cached.Reviews, true, nil
+}
+
+// storeReviews stores the given reviews of the pull request with the given
+// number. Reviews of pull requests that aren't cached are not stored as their
+// validity can't be determined.
+func (c *apiCache) storeReviews(repository string, number int, reviews []*github.PullRequestReview) error {
+	updated, ok, err := c.updated(repository, number)
+	if err != nil || !ok {
+		return err
+	}
+	value, err := json.Marshal(cachedReviews{Updated: updated, Reviews: reviews})
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(repository))
+		if err != nil {
+			return err
+		}
+		reviewsBucket, err := b.CreateBucketIfNotExists(cacheReviewsBucket)
+		if err != nil {
+			return err
+		}
+		return reviewsBucket.Put(cacheKey(number), value)
+	})
+}
+
+// response returns the cached response to the GET request with the given key.
+// Returns false if no response is cached.
+func (c *apiCache) response(key string) (*cachedResponse, bool, error) {
+	var cached cachedResponse
+	found := false
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheResponsesBucket)
+		if b == nil {
+			return nil
+		}
+		value := b.Get([]byte(key))
+		if value == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(value, &cached)
+	})
+	if err != nil || !found {
+		return nil, false, err
+	}
+	return &cached, true, nil
+}
+
+// storeResponse stores the given response to the GET request with the given
+// key.
+func (c *apiCache) storeResponse(key string, response *cachedResponse) error {
+	value, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(cacheResponsesBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), value)
+	})
+}
+
+// cachedRepository summarizes the issues and reviews of a repository held by
+// the cache.
+type cachedRepository struct {
+	name    string
+	issues  int
+	reviews int
+	synced  time.Time
+	since   time.Time
+}
+
+// cachedRepositories summarizes the repositories held by the cache ordered by
+// name.
+func (c *apiCache) cachedRepositories() ([]cachedRepository, error) {
+	var repositories []cachedRepository
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if bytes.Equal(name, cacheResponsesBucket) {
+				return nil
+			}
+			r := cachedRepository{name: string(name)}
+			if issues := b.Bucket(cacheIssuesBucket); issues != nil {
+				r.issues = issues.Stats().KeyN
+			}
+			if reviews := b.Bucket(cacheReviewsBucket); reviews != nil {
+				r.reviews = reviews.Stats().KeyN
+			}
+			if synced := b.Get(cacheSyncedKey); synced != nil {
+				if err := r.synced.UnmarshalText(synced); err != nil {
+					return err
+				}
+				if err := r.since.UnmarshalText(b.Get(cacheSinceKey)); err != nil {
+					return err
+				}
+			}
+			repositories = append(repositories, r)
+			return nil
+		})
+	})
+	return repositories, err
+}
+
+// cachedResponses returns the number of cached responses and the times the
+// oldest and the newest of them were fetched.
+func (c *apiCache) cachedResponses() (int, time.Time, time.Time, error) {
+	var count int
+	var oldest, newest time.Time
+	err := c.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheResponsesBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, value []byte) error {
+			fetched, err := responseFetched(value)
+			if err != nil {
+				return err
+			}
+			count++
+			if oldest.IsZero() || fetched.Before(oldest) {
+				oldest = fetched
+			}
+			if fetched.After(newest) {
+				newest = fetched
+			}
+			return nil
+		})
+	})
+	return count, oldest, newest, err
+}
+
+// responseFetched returns the time the given encoded cached response was
+// fetched as given by its Date header. The zero time is returned if the time
+// is unknown.
+func responseFetched(value []byte) (time.Time, error) {
+	var cached cachedResponse
+	if err := json.Unmarshal(value, &cached); err != nil {
+		return time.Time{}, err
+	}
+	fetched, err := http.ParseTime(cached.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return fetched, nil
+}
+
+// matchesRepository returns true iff the given name of a repository in
+// 'owner/name' notation is one of the given repositories, or if none are
+// given. Like names of repositories, the comparison is case-insensitive.
+func matchesRepository(name string, repositories []string) bool {
+	if len(repositories) == 0 {
+		return true
+	}
+	for _, repository := range repositories {
+		if strings.EqualFold(name, repository) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseOfRepository returns true iff the cached response with the given
+// key (see conditionalTransport.cacheKey) is a resource of one of the given
+// repositories, or if none are given.
+func responseOfRepository(key []byte, repositories []string) bool {
+	if len(repositories) == 0 {
+		return true
+	}
+	rawURL := string(key)
+	if i := strings.LastIndex(rawURL, " "); i >= 0 {
+		rawURL = rawURL[i+1:]
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	p := strings.ToLower(u.Path)
+	for _, repository := range repositories {
+		prefix := "/repos/" + strings.ToLower(repository)
+		if strings.HasSuffix(p, prefix) || strings.Contains(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// evict removes the issues, reviews, and responses of the given repositories
+// from the cache. Returns the number of evicted repositories.
+func (c *apiCache) evict(repositories []string) (int, error) {
+	var evicted int
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		var names [][]byte
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if !bytes.Equal(name, cacheResponsesBucket) && matchesRepository(string(name), repositories) {
+				names = append(names, append([]byte{}, name...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		evicted = len(names)
+		_, err = deleteResponses(tx, func(key []byte, _ []byte) (bool, error) {
+			return responseOfRepository(key, repositories), nil
+		})
+		return err
+	})
+	return evicted, err
+}
+
+// pruneStats counts the entries removed from the cache by pruning.
+type pruneStats struct {
+	issues    int
+	reviews   int
+	responses int
+}
+
+// prune removes the issues and pull requests last updated before the given
+// time along with their reviews, and the responses fetched before the given
+// time from the cache. Only entries of the given repositories are removed if
+// any are given. The issues of the affected repositories are considered cached
+// only since the given time afterwards, so that pruned issues are fetched
+// again when needed.
+func (c *apiCache) prune(before time.Time, repositories []string) (pruneStats, error) {
+	var stats pruneStats
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		err := tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if bytes.Equal(name, cacheResponsesBucket) || !matchesRepository(string(name), repositories) {
+				return nil
+			}
+			issues, reviews, err := pruneRepository(b, before)
+			stats.issues += issues
+			stats.reviews += reviews
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		stats.responses, err = deleteResponses(tx, func(key []byte, value []byte) (bool, error) {
+			if !responseOfRepository(key, repositories) {
+				return false, nil
+			}
+			fetched, err := responseFetched(value)
+			return fetched.Before(before), err
+		})
+		return err
+	})
+	return stats, err
+}
+
+// pruneRepository removes the issues and pull requests last updated before the
+// given time along with their reviews from the given bucket of a repository.
+// Returns the number of removed issues and reviews.
+func pruneRepository(b *bolt.Bucket, before time.Time) (int, int, error) {
+	var pruned [][]byte
+	if issues := b.Bucket(cacheIssuesBucket); issues != nil {
+		err := issues.ForEach(func(key, value []byte) error {
+			var issue github.Issue
+			if err := json.Unmarshal(value, &issue); err != nil {
+				return err
+			}
+			if issue.GetUpdatedAt().Before(before) {
+				pruned = append(pruned, append([]byte{}, key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, key := range pruned {
+			if err := issues.Delete(key); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	var reviews int
+	if reviewsBucket := b.Bucket(cacheReviewsBucket); reviewsBucket != nil {
+		for _, key := range pruned {
+			if reviewsBucket.Get(key) == nil {
+				continue
+			}
+			if err := reviewsBucket.Delete(key); err != nil {
+				return 0, 0, err
+			}
+			reviews++
+		}
+	}
+	if value := b.Get(cacheSinceKey); value != nil {
+		var since time.Time
+		if err := since.UnmarshalText(value); err != nil {
+			return 0, 0, err
+		}
+		if since.Before(before) {
+			value, err := before.MarshalText()
+			if err != nil {
+				return 0, 0, err
+			}
+			if err := b.Put(cacheSinceKey, value); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return len(pruned), reviews, nil
+}
+
+// deleteResponses removes the cached responses selected by the given function
+// within the given transaction. Returns the number of removed responses.
+func deleteResponses(tx *bolt.Tx, selected func(key []byte, value []byte) (bool, error)) (int, error) {
+	b := tx.Bucket(cacheResponsesBucket)
+	if b == nil {
+		return 0, nil
+	}
+	var keys [][]byte
+	err := b.ForEach(func(key, value []byte) error {
+		ok, err := selected(key, value)
+		if ok {
+			keys = append(keys, append([]byte{}, key...))
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := b.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+// conditionalTransport is a http.RoundTripper that requests resources whose
+// responses have been cached before conditionally by means of their ETag and
+// Last-Modified validators. The cached response is served if the resource is
+// unchanged. Such requests don't count against the rate limit of the GitHub
+// API.
+type conditionalTransport struct {
+	base  http.RoundTripper
+	cache *apiCache
+}
+
+// cacheKey returns the key of the response to the given request. The media
+// type is included as it selects the representation of the resource.
+func (t *conditionalTransport) cacheKey(req *http.Request) string {
+	return req.Header.Get("Accept") + " " + req.URL.String()
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+	key := t.cacheKey(req)
+	cached, ok, err := t.cache.response(key)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache failed: %w", err)
+	}
+	if ok {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ok && resp.StatusCode == http.StatusNotModified:
+		_ = resp.Body.Close()
+		// The headers of the 304 response (e.g., the rate limit) take precedence
+		header := cached.Header.Clone()
+		for name, values := range resp.Header {
+			header[name] = values
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Status = fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK))
+		resp.Header = header
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		logger.Debugw("Serving unchanged response from cache", "url", req.URL.String())
+	case resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""):
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		err = t.cache.storeResponse(key, &cachedResponse{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Header:       resp.Header,
+			Body:         body,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("writing cache failed: %w", err)
+		}
+	}
+	return resp, nil
+}
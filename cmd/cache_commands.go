@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/araddon/dateparse"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"time"
+)
+
+// Configuration keys for the cache commands. They don't live below the cache
+// key as it holds the name of the cache file.
+const (
+	// The day before which cache entries are pruned
+	cachePruneBeforeCfgKey = "cache-prune.before"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manages the cache of the GitHub API",
+	Long: `Inspects and evicts the entries of the cache configured by --cache, which
+holds the issues, pull requests, and reviews as well as the responses fetched
+from the GitHub API by previous runs.`,
+	Args: cobra.NoArgs,
+}
+
+// cacheInfoCmd represents the cache info command
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Reports the size and age of the cache",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheInfo,
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [owner/repository]...",
+	Short: "Removes the cache or the entries of the given repositories",
+	RunE:  runCacheClear,
+}
+
+// cachePruneCmd represents the cache prune command
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune [owner/repository]...",
+	Short: "Removes the cache entries older than a given day",
+	Long: `Removes the issues and pull requests last updated before the given day along
+with their reviews, and the responses fetched before the given day from the
+cache. Only the entries of the given repositories are removed if any are given.
+Pruned issues and pull requests are fetched again by runs analyzing periods
+before the given day.`,
+	RunE: runCachePrune,
+}
+
+// getCacheFilename returns the name of the configured cache file. Fails if no
+// cache is configured.
+func getCacheFilename() (string, error) {
+	filename := viper.GetString(cacheCfgKey)
+	if filename == "" {
+		return "", classify(exitConfigError, errors.New("no cache configured (see --cache)"))
+	}
+	return filename, nil
+}
+
+// openConfiguredCache opens the configured cache file. Returns nil if the file
+// doesn't exist.
+func openConfiguredCache() (*apiCache, error) {
+	filename, err := getCacheFilename()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return openAPICache(filename)
+}
+
+func runCacheInfo(cmd *cobra.Command, args []string) error {
+	cache, err := openConfiguredCache()
+	if err != nil || cache == nil {
+		if err == nil {
+			cmd.Printf("Cache '%s' doesn't exist\n", viper.GetString(cacheCfgKey))
+		}
+		return err
+	}
+	defer cache.Close()
+	return writeCacheInfo(cmd.OutOrStdout(), cache, time.Now())
+}
+
+// writeCacheInfo writes the size of the given cache and the number and age of
+// its entries at the given time to the given writer.
+func writeCacheInfo(w io.Writer, cache *apiCache, now time.Time) error {
+	info, err := os.Stat(cache.db.Path())
+	if err != nil {
+		return err
+	}
+	repositories, err := cache.cachedRepositories()
+	if err != nil {
+		return fmt.Errorf("reading cache failed: %w", err)
+	}
+	responses, oldest, newest, err := cache.cachedResponses()
+	if err != nil {
+		return fmt.Errorf("reading cache failed: %w", err)
+	}
+	_, _ = fmt.Fprintf(w, "Cache '%s' (%d KB)\n\n", cache.db.Path(), info.Size()/1024)
+	_, _ = fmt.Fprintf(w, "%-40s %7s %7s  %-10s  %s\n", "Repository", "Issues", "Reviews", "Since", "Synced")
+	for _, r := range repositories {
+		since, synced := "-", "never"
+		if !r.synced.IsZero() {
+			since = r.since.Format("2006-01-02")
+			synced = fmt.Sprintf("%s ago", now.Sub(r.synced).Round(time.Minute))
+		}
+		_, _ = fmt.Fprintf(w, "%-40s %7d %7d  %-10s  %s\n", r.name, r.issues, r.reviews, since, synced)
+	}
+	_, _ = fmt.Fprintf(w, "\n%d responses", responses)
+	if responses > 0 {
+		_, _ = fmt.Fprintf(w, " fetched from %s until %s", oldest.Format("2006-01-02"), newest.Format("2006-01-02"))
+	}
+	_, _ = fmt.Fprintln(w)
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		filename, err := getCacheFilename()
+		if err != nil {
+			return err
+		}
+		// Ensures that no run uses the cache while it's removed
+		cache, err := openConfiguredCache()
+		if err != nil || cache == nil {
+			if err == nil {
+				cmd.Printf("Cache '%s' doesn't exist\n", filename)
+			}
+			return err
+		}
+		if err := cache.Close(); err != nil {
+			return err
+		}
+		if err := os.Remove(filename); err != nil {
+			return fmt.Errorf("removing cache failed: %w", err)
+		}
+		cmd.Printf("Cache '%s' removed\n", filename)
+		return nil
+	}
+	cache, err := openConfiguredCache()
+	if err != nil || cache == nil {
+		if err == nil {
+			cmd.Printf("Cache '%s' doesn't exist\n", viper.GetString(cacheCfgKey))
+		}
+		return err
+	}
+	defer cache.Close()
+	evicted, err := cache.evict(args)
+	if err != nil {
+		return fmt.Errorf("writing cache failed: %w", err)
+	}
+	cmd.Printf("Evicted %d repositories from the cache\n", evicted)
+	return nil
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	s := viper.GetString(cachePruneBeforeCfgKey)
+	if s == "" {
+		return classify(exitConfigError, errors.New("the day before which entries are pruned is required (see --before)"))
+	}
+	day, err := dateparse.ParseIn(s, time.UTC)
+	if err != nil {
+		return classify(exitConfigError, fmt.Errorf("invalid date '%s': %w", s, err))
+	}
+	before := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	cache, err := openConfiguredCache()
+	if err != nil || cache == nil {
+		if err == nil {
+			cmd.Printf("Cache '%s' doesn't exist\n", viper.GetString(cacheCfgKey))
+		}
+		return err
+	}
+	defer cache.Close()
+	stats, err := cache.prune(before, args)
+	if err != nil {
+		return fmt.Errorf("writing cache failed: %w", err)
+	}
+	cmd.Printf("Pruned %d issues and pull requests, %d reviews, and %d responses from before %s\n",
+		stats.issues, stats.reviews, stats.responses, before.Format("2006-01-02"))
+	return nil
+}
+
+// Initialize the 'cache' command.
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheInfoCmd, cacheClearCmd, cachePruneCmd)
+
+	// Flag to control the day before which entries are pruned
+	const beforeFlag = "before"
+	cachePruneCmd.Flags().String(
+		beforeFlag,
+		"",
+		"The day (UTC) before which entries are pruned (supports many date formats)")
+	if err := viper.BindPFlag(cachePruneBeforeCfgKey, cachePruneCmd.Flags().Lookup(beforeFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", beforeFlag, "Error", err)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"github.com/araddon/dateparse"
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("Issue caches", func() {
+	const repository = "herdstat/herdstat"
+	var cache *apiCache
+
+	issue := func(number int, updated string) *github.Issue {
+		return &github.Issue{
+			Number:    github.Int(number),
+			UpdatedAt: &github.Timestamp{Time: dateparse.MustParse(updated)},
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		cache, err = openAPICache(filepath.Join(GinkgoT().TempDir(), "cache.db"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(cache.Close)
+	})
+
+	It("has no state for unknown repositories", func() {
+		_, _, ok, err := cache.syncState(repository)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("records the synchronization state", func() {
+		synced := dateparse.MustParse("2023-03-15 12:00").UTC()
+		since := dateparse.MustParse("2022-03-15").UTC()
+		Expect(cache.storeIssues(repository, nil, synced, since)).To(Succeed())
+		cachedSynced, cachedSince, ok, err := cache.syncState(repository)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(cachedSynced).To(BeTemporally("==", synced))
+		Expect(cachedSince).To(BeTemporally("==", since))
+	})
+
+	It("merges updated issues and filters by update time", func() {
+		now := time.Now()
+		Expect(cache.storeIssues(repository, []*github.Issue{
+			issue(1, "2022-01-01"),
+			issue(2, "2023-01-01"),
+		}, now, time.Time{})).To(Succeed())
+		Expect(cache.storeIssues(repository, []*github.Issue{
+			issue(1, "2023-02-01"),
+			issue(3, "2023-03-01"),
+		}, now, time.Time{})).To(Succeed())
+		issues, err := cache.issues(repository, dateparse.MustParse("2022-06-01"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(3))
+		Expect(issues[0].GetNumber()).To(Equal(3))
+		Expect(issues[2].GetNumber()).To(Equal(1))
+		Expect(issues[2].GetUpdatedAt().Time).To(BeTemporally("==", dateparse.MustParse("2023-02-01")))
+	})
+
+	It("invalidates reviews of updated pull requests", func() {
+		reviews := []*github.PullRequestReview{{User: &github.User{Login: github.String("alice")}}}
+		Expect(cache.storeIssues(repository, []*github.Issue{issue(1, "2023-01-01")}, time.Now(), time.Time{})).To(Succeed())
+		Expect(cache.storeReviews(repository, 1, reviews)).To(Succeed())
+
+		cached, ok, err := cache.reviews(repository, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(cached[0].GetUser().GetLogin()).To(Equal("alice"))
+
+		Expect(cache.storeIssues(repository, []*github.Issue{issue(1, "2023-02-01")}, time.Now(), time.Time{})).To(Succeed())
+		_, ok, err = cache.reviews(repository, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("serves unchanged resources from the cache", func() {
+		requests, unchanged := 0, 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(100-requests))
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				unchanged++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte("content"))
+		}))
+		DeferCleanup(server.Close)
+		client := &http.Client{Transport: &conditionalTransport{base: http.DefaultTransport, cache: cache}}
+
+		for i := 1; i <= 2; i++ {
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(string(body)).To(Equal("content"))
+			Expect(resp.Header.Get("X-RateLimit-Remaining")).To(Equal(strconv.Itoa(100 - i)))
+		}
+		Expect(requests).To(Equal(2))
+		Expect(unchanged).To(Equal(1))
+	})
+
+	Describe("Managing", func() {
+		// response returns a cached response fetched on the given day.
+		response := func(day string) *cachedResponse {
+			return &cachedResponse{Header: http.Header{"Date": {dateparse.MustParse(day).UTC().Format(http.TimeFormat)}}}
+		}
+
+		BeforeEach(func() {
+			synced := dateparse.MustParse("2023-03-15 12:00").UTC()
+			since := dateparse.MustParse("2022-03-15").UTC()
+			Expect(cache.storeIssues(repository, []*github.Issue{issue(1, "2022-06-01"), issue(2, "2023-02-01")},
+				synced, since)).To(Succeed())
+			Expect(cache.storeReviews(repository, 1, []*github.PullRequestReview{{ID: github.Int64(1)}})).To(Succeed())
+			Expect(cache.storeIssues("herdstat/action", []*github.Issue{issue(1, "2022-06-01")}, synced, since)).To(Succeed())
+			Expect(cache.storeResponse(" https://api.github.com/repos/herdstat/herdstat/languages", response("2022-06-01"))).To(Succeed())
+			Expect(cache.storeResponse(" https://api.github.com/repos/herdstat/action", response("2023-03-01"))).To(Succeed())
+		})
+
+		It("reports the cached repositories and responses", func() {
+			var buf bytes.Buffer
+			Expect(writeCacheInfo(&buf, cache, dateparse.MustParse("2023-03-15 14:00").UTC())).To(Succeed())
+			Expect(buf.String()).To(MatchRegexp(`herdstat/herdstat\s+2\s+1\s+2022-03-15\s+2h0m0s ago`))
+			Expect(buf.String()).To(ContainSubstring("2 responses fetched from 2022-06-01 until 2023-03-01"))
+		})
+
+		It("evicts repositories", func() {
+			evicted, err := cache.evict([]string{"HerdStat/HerdStat"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(evicted).To(Equal(1))
+			repositories, err := cache.cachedRepositories()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repositories).To(HaveLen(1))
+			Expect(repositories[0].name).To(Equal("herdstat/action"))
+			responses, _, _, err := cache.cachedResponses()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(responses).To(Equal(1))
+		})
+
+		It("prunes entries older than a given day", func() {
+			before := dateparse.MustParse("2023-01-01").UTC()
+			stats, err := cache.prune(before, []string{repository})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats).To(Equal(pruneStats{issues: 1, reviews: 1, responses: 1}))
+			issues, err := cache.issues(repository, time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(HaveLen(1))
+			_, since, _, err := cache.syncState(repository)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(since).To(BeTemporally("==", before))
+			issues, err = cache.issues("herdstat/action", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/viper"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Stages of the collection recorded in checkpoints.
+const (
+	commitsCheckpointStage = "commits"
+	issuesCheckpointStage  = "issues"
+)
+
+// checkpoint records the contributions collected per repository, so that a
+// run that dies (e.g., because the rate limit is exceeded or the CI job times
+// out) can be resumed by a re-run with the same configuration without
+// collecting the contributions of the completed repositories again. The
+// checkpoint is removed once a run succeeds.
+type checkpoint struct {
+	filename string
+
+	// Guards contributions and the checkpoint file.
+	mutex sync.Mutex
+
+	// The contributions keyed by collection (see checkpointKey) and
+	// repository.
+	contributions map[string]map[string][]internal.Contribution
+}
+
+var (
+	// The checkpoint shared by all collectors of a run. Loaded on first use.
+	sharedCheckpoint *checkpoint
+
+	// Guards the initialization of sharedCheckpoint.
+	sharedCheckpointMutex sync.Mutex
+)
+
+// getCheckpoint returns the configured checkpoint. Returns nil if
+// checkpointing is disabled. The checkpoint is loaded on first use.
+func getCheckpoint() (*checkpoint, error) {
+	filename := viper.GetString(checkpointCfgKey)
+	if filename == "" {
+		return nil, nil
+	}
+	sharedCheckpointMutex.Lock()
+	defer sharedCheckpointMutex.Unlock()
+	if sharedCheckpoint == nil {
+		c, err := loadCheckpoint(filename)
+		if err != nil {
+			return nil, err
+		}
+		sharedCheckpoint = c
+	}
+	return sharedCheckpoint, nil
+}
+
+// loadCheckpoint loads the checkpoint stored in the file with the given name.
+// An empty checkpoint is returned if the file doesn't exist.
+func loadCheckpoint(filename string) (*checkpoint, error) {
+	c := &checkpoint{
+		filename:      filename,
+		contributions: make(map[string]map[string][]internal.Contribution),
+	}
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint '%s' failed: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &c.contributions); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint '%s' failed: %w", filename, err)
+	}
+	logger.Infow("Resuming from checkpoint", "checkpoint", filename)
+	return c, nil
+}
+
+// checkpointKey identifies the collection of the given stage for the period
+// after since until the given day.
+func checkpointKey(stage string, since time.Time, lastDay time.Time) string {
+	return fmt.Sprintf("%s %s..%s", stage, since.Format(time.RFC3339), lastDay.Format(time.RFC3339))
+}
+
+// get returns the contributions to the given repository recorded for the
+// collection identified by the given key. The second return value is false if
+// the collection hasn't been completed for the repository yet. Nothing is
+// recorded for a nil checkpoint.
+func (c *checkpoint) get(key string, repository string) ([]internal.Contribution, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	contributions, ok := c.contributions[key][repository]
+	if ok {
+		logger.Debugw("Restoring contributions from checkpoint", "repository", repository, "collection", key)
+	}
+	// Copy to keep the recorded contributions unaffected by modifications
+	return append([]internal.Contribution(nil), contributions...), ok
+}
+
+// record records the contributions to the given repository for the collection
+// identified by the given key and writes the checkpoint. Recording in a nil
+// checkpoint is a no-op.
+func (c *checkpoint) record(key string, repository string, contributions []internal.Contribution) error {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.contributions[key] == nil {
+		c.contributions[key] = make(map[string][]internal.Contribution)
+	}
+	if contributions == nil {
+		contributions = []internal.Contribution{}
+	}
+	c.contributions[key][repository] = contributions
+	data, err := json.Marshal(c.contributions)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first to not corrupt the checkpoint if the run
+	// dies while writing
+	tmp, err := os.CreateTemp(filepath.Dir(c.filename), filepath.Base(c.filename)+".*")
+	if err != nil {
+		return fmt.Errorf("writing checkpoint '%s' failed: %w", c.filename, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing checkpoint '%s' failed: %w", c.filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing checkpoint '%s' failed: %w", c.filename, err)
+	}
+	if err := os.Rename(tmp.Name(), c.filename); err != nil {
+		return fmt.Errorf("writing checkpoint '%s' failed: %w", c.filename, err)
+	}
+	return nil
+}
+
+// removeCheckpoint removes the checkpoint after a successful run. Runs of
+// commands that haven't collected through the checkpoint keep it, so that it
+// survives, e.g., diagnosing a failed run.
+func removeCheckpoint() error {
+	sharedCheckpointMutex.Lock()
+	defer sharedCheckpointMutex.Unlock()
+	if sharedCheckpoint == nil {
+		return nil
+	}
+	filename := sharedCheckpoint.filename
+	sharedCheckpoint = nil
+	if err := os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing checkpoint '%s' failed: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Checkpoints", func() {
+	var filename string
+
+	BeforeEach(func() {
+		filename = filepath.Join(GinkgoT().TempDir(), "checkpoint.json")
+		viper.Set(checkpointCfgKey, filename)
+		DeferCleanup(viper.Set, checkpointCfgKey, "")
+		DeferCleanup(removeCheckpoint)
+	})
+
+	It("resumes the collection of completed repositories", func() {
+		r, u, err := createRepository()
+		Expect(err).NotTo(HaveOccurred())
+		lastDay := time.Date(2013, time.April, 22, 23, 59, 0, 0, time.UTC)
+		Expect(createCommit(r, lastDay.AddDate(0, 0, -1))).To(Succeed())
+		repository := &github.Repository{
+			FullName: github.String("herdstat/herdstat"),
+			CloneURL: github.String(u.String()),
+		}
+		since := lastDay.AddDate(0, 0, -52*7)
+		contributions, err := collectCommitContributions(map[url.URL]*github.Repository{*u: repository}, since, lastDay, commitDetails{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contributions).To(HaveLen(1))
+		Expect(filename).To(BeAnExistingFile())
+
+		// Simulate a re-run that can't clone the repository anymore
+		sharedCheckpoint = nil
+		repository.CloneURL = github.String("file:///nonexistent")
+		contributions, err = collectCommitContributions(map[url.URL]*github.Repository{*u: repository}, since, lastDay, commitDetails{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contributions).To(HaveLen(1))
+		Expect(contributions[0].Repository).To(Equal("herdstat/herdstat"))
+		Expect(contributions[0].Date.Equal(lastDay.AddDate(0, 0, -1))).To(BeTrue())
+	})
+
+	It("is removed after a successful run", func() {
+		c, err := getCheckpoint()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.record(checkpointKey(issuesCheckpointStage, time.Time{}, time.Time{}), "herdstat/herdstat", nil)).To(Succeed())
+		Expect(filename).To(BeAnExistingFile())
+		Expect(removeCheckpoint()).To(Succeed())
+		Expect(filename).NotTo(BeAnExistingFile())
+	})
+	Context("after running a command", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = &cobra.Command{}
+			cmd.SetOut(io.Discard)
+			DeferCleanup(viper.Set, githubActionsCfgKey, viper.GetBool(githubActionsCfgKey))
+			viper.Set(githubActionsCfgKey, false)
+			results.reset()
+			DeferCleanup(results.reset)
+			Expect(os.WriteFile(filename, []byte("{}"), 0644)).To(Succeed())
+		})
+
+		It("is kept if the command hasn't collected through it", func() {
+			Expect(rootCmd.PersistentPostRunE(cmd, nil)).To(Succeed())
+			Expect(filename).To(BeAnExistingFile())
+		})
+
+		It("is kept if processing the results fails", func() {
+			_, err := getCheckpoint()
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(viper.Set, sqliteFileCfgKey, viper.GetString(sqliteFileCfgKey))
+			viper.Set(sqliteFileCfgKey, filepath.Join(filename, "herdstat.db"))
+			results.recordContributions([]internal.Contribution{{Repository: "herdstat/herdstat"}}, time.Now())
+			Expect(rootCmd.PersistentPostRunE(cmd, nil)).NotTo(Succeed())
+			Expect(filename).To(BeAnExistingFile())
+		})
+
+		It("is removed once the results have been processed", func() {
+			_, err := getCheckpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rootCmd.PersistentPostRunE(cmd, nil)).To(Succeed())
+			Expect(filename).NotTo(BeAnExistingFile())
+		})
+	})
+})
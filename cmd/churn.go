@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the churn command
+const (
+	// The format of the report
+	churnFormatCfgKey = "churn.format"
+	// The name of the output file
+	churnFilenameCfgKey = "churn.filename"
+)
+
+// churnCmd represents the churn command
+var churnCmd = &cobra.Command{
+	Use:   "churn",
+	Short: "Reports the number of lines added and removed per repository and week",
+	Long: `Diffs each commit against its parent to compute the number of lines added and
+removed per repository and week. Merge commits and binary files are not taken
+into account. As computing the diffs is expensive, the churn is only collected
+by this command.`,
+	Args: cobra.NoArgs,
+	RunE: runChurn,
+}
+
+func runChurn(cmd *cobra.Command, args []string) error {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	commits, err := collectCommitContributions(repositories, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{churn: true})
+	if err != nil {
+		return err
+	}
+	report := internal.NewChurnReport(commits, lastDay)
+	return writeReport(cmd, report, viper.GetString(churnFormatCfgKey), viper.GetString(churnFilenameCfgKey))
+}
+
+// Initialize the 'churn' command.
+func init() {
+	rootCmd.AddCommand(churnCmd)
+
+	addReportFlags(churnCmd, churnFormatCfgKey, churnFilenameCfgKey)
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/http"
+)
+
+// Configuration keys for the codeowners command
+const (
+	// The format of the report
+	codeOwnersFormatCfgKey = "codeowners.format"
+	// The name of the output file
+	codeOwnersFilenameCfgKey = "codeowners.filename"
+)
+
+// codeOwnersLocations are the locations of CODEOWNERS files in the order
+// GitHub looks for them.
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeOwnersCmd represents the codeowners command
+var codeOwnersCmd = &cobra.Command{
+	Use:   "codeowners",
+	Short: "Reports the share of changes landing in paths without code owners",
+	Long: `Parses the CODEOWNERS file of each repository and cross-references it with the
+files changed by the commits in the analyzed period. Reports the share of file
+changes landing in paths without code owners per repository. All changes to
+repositories without CODEOWNERS file are unowned.`,
+	Args: cobra.NoArgs,
+	RunE: runCodeOwners,
+}
+
+func runCodeOwners(cmd *cobra.Command, args []string) error {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	codeOwners := make(map[string]*internal.CodeOwners)
+	for _, repository := range repositories {
+		owners, err := collectCodeOwners(repository)
+		if err != nil {
+			return err
+		}
+		codeOwners[repository.GetFullName()] = owners
+	}
+	commits, err := collectCommitContributions(repositories, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{files: true})
+	if err != nil {
+		return err
+	}
+	report := internal.NewCodeOwnersReport(commits, lastDay, codeOwners)
+	return writeReport(cmd, report, viper.GetString(codeOwnersFormatCfgKey), viper.GetString(codeOwnersFilenameCfgKey))
+}
+
+// collectCodeOwners retrieves and parses the CODEOWNERS file from the default
+// branch of the given repository. Returns nil if the repository has none.
+func collectCodeOwners(repository *github.Repository) (*internal.CodeOwners, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	for _, location := range codeOwnersLocations {
+		file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, location, nil)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s for repo %s/%s failed: %w", location, owner, repo, err)
+		}
+		if file == nil {
+			continue
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, err
+		}
+		codeOwners, err := internal.ParseCodeOwners(content)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s of repo %s/%s failed: %w", location, owner, repo, err)
+		}
+		return codeOwners, nil
+	}
+	logger.Debugw("No CODEOWNERS file found", "Repository", repository.GetFullName())
+	return nil, nil
+}
+
+// Initialize the 'codeowners' command.
+func init() {
+	rootCmd.AddCommand(codeOwnersCmd)
+
+	addReportFlags(codeOwnersCmd, codeOwnersFormatCfgKey, codeOwnersFilenameCfgKey)
+}
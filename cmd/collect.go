@@ -0,0 +1,1312 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/repeale/fp-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// collectContributionRecords resolves the configured repositories and
+// collects the daily contribution records for the 52 weeks ending with the
+// configured "until" date. Returns the records and the last day covered.
+func collectContributionRecords(cmd *cobra.Command) ([]internal.ContributionRecord, time.Time, error) {
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return internal.DailyRecords(contributions, lastDay), lastDay, nil
+}
+
+// collectContributionRecordsFor resolves the given repositories and collects
+// the daily contribution records for the 52 weeks ending with the given day.
+func collectContributionRecordsFor(cmd *cobra.Command, repos []string, lastDay time.Time) ([]internal.ContributionRecord, error) {
+	contributions, err := collectContributionsFor(cmd, repos, lastDay)
+	if err != nil {
+		return nil, err
+	}
+	return internal.DailyRecords(contributions, lastDay), nil
+}
+
+// collectContributions resolves the configured repositories and collects the
+// contributions made within the 52 weeks ending with the configured "until"
+// date. Returns the contributions and the last day covered.
+func collectContributions(cmd *cobra.Command) ([]internal.Contribution, time.Time, error) {
+	return collectContributionsWithLookback(cmd, 0)
+}
+
+// collectContributionsWithLookback resolves the configured repositories and
+// collects the contributions made within the 52 weeks ending with the
+// configured "until" date as well as the given number of weeks before. Returns
+// the contributions and the last day covered.
+func collectContributionsWithLookback(cmd *cobra.Command, lookbackWeeks int) ([]internal.Contribution, time.Time, error) {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	since := lastDay.AddDate(0, 0, -(52+lookbackWeeks)*7)
+	start := time.Now()
+	contributions, err := collectContributionsBetween(cmd, viper.GetStringSlice(repositoriesCfgKey), since, lastDay)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	results.recordCollectionDuration(time.Since(start))
+	results.recordContributions(contributions, lastDay)
+	return contributions, lastDay, nil
+}
+
+// collectContributionsFor resolves the given repositories and collects the
+// contributions made within the 52 weeks ending with the given day.
+func collectContributionsFor(cmd *cobra.Command, repos []string, lastDay time.Time) ([]internal.Contribution, error) {
+	return collectContributionsBetween(cmd, repos, lastDay.AddDate(0, 0, -52*7), lastDay)
+}
+
+// collectContributionsBetween resolves the given repositories and collects the
+// contributions made after since until the given day.
+func collectContributionsBetween(cmd *cobra.Command, repos []string, since time.Time, lastDay time.Time) ([]internal.Contribution, error) {
+	repositories, err := resolveRepositories(cmd, repos)
+	if err != nil {
+		return nil, err
+	}
+	return collectRepositoryContributions(repositories, since, lastDay)
+}
+
+// collectRepositoryContributions collects the contributions made to the given
+// repositories after since until the given day. The contributions collected by
+// the configured collector plugins are added. The dates of the contributions
+// are converted to the configured timezone (see inTimezone).
+func collectRepositoryContributions(repositories map[url.URL]*github.Repository, since time.Time, lastDay time.Time) ([]internal.Contribution, error) {
+	logger.Debugw("Analyzing contributions",
+		"from", since,
+		"until", lastDay)
+
+	commits, err := collectCommitContributions(repositories, since, lastDay, commitDetails{})
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := collectIssueRelatedContributions(repositories, since, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins, err := collectPluginContributions(repositories, since, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	return inTimezone(append(append(commits, issues...), plugins...))
+}
+
+// collectCommits resolves the configured repositories and collects the
+// commits made within the 52 weeks ending with the configured "until" date.
+// Returns the commits and the last day covered.
+func collectCommits(cmd *cobra.Command) ([]internal.Contribution, time.Time, error) {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	commits, err := collectCommitContributions(repositories, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return commits, lastDay, nil
+}
+
+// resolveRepositories resolves the given repositories and reports them.
+func resolveRepositories(cmd *cobra.Command, repos []string) (map[url.URL]*github.Repository, error) {
+	repositories, err := collectRepositories(repos)
+	if err != nil {
+		return nil, err
+	}
+	l := len(repositories)
+	var s string
+	switch l {
+	case 1:
+		s = "repository"
+	default:
+		s = "repositories"
+	}
+	cmd.Printf("Processing %d %s: %v\n", l, s,
+		strings.Join(fp.Map(func(url url.URL) string { return url.String() })(internal.Keys(repositories)), ","))
+	return repositories, nil
+}
+
+// collectPerRepository collects the contributions to each of the given
+// repositories using the given function. At most the configured number of
+// repositories are collected concurrently. The contributions are restored from
+// and recorded in the checkpoint under the given key (see checkpointKey). The
+// progress is reported under the given phase name. Returns the first error
+// encountered; no further repositories are collected after an error.
+func collectPerRepository(repositories map[url.URL]*github.Repository, phase string, key string,
+	collect func(u url.URL, repository *github.Repository) ([]internal.Contribution, error)) ([]internal.Contribution, error) {
+	checkpoint, err := getCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	progress := startProgress(phase, len(repositories))
+	defer progress.Stop()
+	concurrency := viper.GetInt(maxConcurrencyCfgKey)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		// Bounds the number of repositories collected concurrently
+		slots = make(chan struct{}, concurrency)
+
+		wg sync.WaitGroup
+
+		// Guards contributions and firstErr
+		mutex         sync.Mutex
+		contributions []internal.Contribution
+		firstErr      error
+	)
+	for u, repository := range repositories {
+		slots <- struct{}{}
+		mutex.Lock()
+		failed := firstErr != nil
+		mutex.Unlock()
+		if failed {
+			<-slots
+			break
+		}
+		wg.Add(1)
+		go func(u url.URL, repository *github.Repository) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			progress.started(repository.GetFullName())
+			defer progress.finished(repository.GetFullName())
+			collected, ok := checkpoint.get(key, repository.GetFullName())
+			var err error
+			if !ok {
+				collected, err = collect(u, repository)
+				if err == nil {
+					err = checkpoint.record(key, repository.GetFullName(), collected)
+				}
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			contributions = append(contributions, collected...)
+		}(u, repository)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, classify(exitCollectionError, firstErr)
+	}
+	return contributions, nil
+}
+
+// collectCommitContributions collects commits made after since until the given
+// day from the given repositories. The logins of the commit authors are
+// resolved using the configured identities. Optional details of the commits
+// are recorded as selected.
+func collectCommitContributions(repositories map[url.URL]*github.Repository, since time.Time, lastDay time.Time, details commitDetails) ([]internal.Contribution, error) {
+	switch source := viper.GetString(commitSourceCfgKey); source {
+	case cloneCommitSource:
+	case activityCommitSource:
+		if details != (commitDetails{}) {
+			return nil, fmt.Errorf("changed files and churn can't be collected from the '%s' commit source", activityCommitSource)
+		}
+		return collectCommitActivity(repositories, since, lastDay)
+	default:
+		return nil, fmt.Errorf("unknown commit source '%s'; supported are %s and %s", source, cloneCommitSource, activityCommitSource)
+	}
+	identities, err := getIdentities()
+	if err != nil {
+		return nil, err
+	}
+	key := checkpointKey(fmt.Sprintf("%s files=%t churn=%t", commitsCheckpointStage, details.files, details.churn), since, lastDay)
+	contributions, err := collectPerRepository(repositories, "Collecting commits", key, func(u url.URL, repository *github.Repository) ([]internal.Contribution, error) {
+		logger.Debugw("Analyzing commit history", "repository", u.String())
+		return collectCommitContributionsForRepo(repository, since, lastDay, details)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if viper.GetBool(deduplicateForkCommitsCfgKey) {
+		contributions = deduplicateForkCommits(contributions, repositories)
+	}
+	identities.Resolve(contributions)
+	return contributions, nil
+}
+
+// deduplicateForkCommits removes the commits of forked repositories that are
+// contained in another of the given repositories as well, so that forks don't
+// double-count the activity of their upstream repository. Commits contained in
+// several forks only are kept once.
+func deduplicateForkCommits(contributions []internal.Contribution, repositories map[url.URL]*github.Repository) []internal.Contribution {
+	forks := make(map[string]bool)
+	for _, repository := range repositories {
+		if repository.GetFork() {
+			forks[repository.GetFullName()] = true
+		}
+	}
+	seen := make(map[string]bool)
+	for _, c := range contributions {
+		if c.Hash != "" && !forks[c.Repository] {
+			seen[c.Hash] = true
+		}
+	}
+	var deduplicated []internal.Contribution
+	for _, c := range contributions {
+		if c.Hash != "" && forks[c.Repository] {
+			if seen[c.Hash] {
+				continue
+			}
+			seen[c.Hash] = true
+		}
+		deduplicated = append(deduplicated, c)
+	}
+	logger.Infow("Removed commits duplicated in forks", "count", len(contributions)-len(deduplicated))
+	return deduplicated
+}
+
+// collectCommitContributionsForRepo collects commits made after since until the
+// given day from the given repository. Optional details of the commits are
+// recorded as selected.
+func collectCommitContributionsForRepo(repository *github.Repository, since time.Time, lastDay time.Time, details commitDetails) ([]internal.Contribution, error) {
+
+	var auth *http.BasicAuth
+	if token := getGitHubToken(); token != "" {
+		auth = &http.BasicAuth{
+			Username: "ignore",
+			Password: token,
+		}
+	}
+
+	r, remove, err := cloneDefaultBranch(repository, auth, since)
+	if errors.Is(err, errNoBranches) {
+		logger.Warnw("Repository has no branches - skipping commit analysis", "url", repository.GetCloneURL())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer remove()
+
+	ref, err := r.Head()
+	if err != nil {
+		return nil, err
+	}
+	head, err := r.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+	missing, err := missingParents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	until := lastDay
+	commits := object.NewCommitLimitIterFromIter(
+		object.NewCommitPreorderIter(head, nil, missing),
+		object.LogLimitOptions{Since: &since, Until: &until})
+
+	filters, err := compileCommitFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	var contributions []internal.Contribution
+	filteredCnt := 0
+	err = commits.ForEach(func(c *object.Commit) error {
+		filtered, err := commitFiltered(filters, c)
+		if err != nil {
+			return err
+		}
+		if !filtered {
+			contribution := internal.Contribution{
+				Type:       internal.CommitContribution,
+				Repository: repository.GetFullName(),
+				Name:       c.Author.Name,
+				Email:      c.Author.Email,
+				Date:       c.Committer.When,
+				URL:        fmt.Sprintf("%s/commit/%s", repository.GetHTMLURL(), c.Hash),
+				Hash:       c.Hash.String(),
+				Subject:    strings.SplitN(c.Message, "\n", 2)[0],
+				Signed:     c.PGPSignature != "",
+				SignedOff:  internal.HasSignOff(c.Message),
+			}
+			if details.churn {
+				contribution.Files, contribution.Additions, contribution.Deletions, err = commitChurn(c)
+				if err != nil {
+					return fmt.Errorf("computing churn of commit %s failed: %w", c.Hash, err)
+				}
+			} else if details.files {
+				contribution.Files, err = commitFiles(c)
+				if err != nil {
+					return fmt.Errorf("computing changed files of commit %s failed: %w", c.Hash, err)
+				}
+			}
+			contributions = append(contributions, contribution)
+		} else {
+			filteredCnt++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugw("Filtered commits", "count", filteredCnt)
+
+	return contributions, nil
+}
+
+// errNoBranches signals that a repository has no branches whose commits could
+// be analyzed, e.g., because it's empty.
+var errNoBranches = errors.New("repository has no branches")
+
+// defaultBranch returns the reference of the default branch of the given
+// repository as reported by the GitHub API. Returns HEAD if unknown.
+func defaultBranch(repository *github.Repository) plumbing.ReferenceName {
+	if branch := repository.GetDefaultBranch(); branch != "" {
+		return plumbing.NewBranchReferenceName(branch)
+	}
+	return plumbing.HEAD
+}
+
+// isMissingBranchError returns true iff the given error signals that the
+// cloned branch doesn't exist in the remote repository.
+func isMissingBranchError(err error) bool {
+	return errors.Is(err, plumbing.ErrReferenceNotFound) ||
+		errors.Is(err, git.NoMatchingRefSpecError{}) ||
+		errors.Is(err, transport.ErrEmptyRemoteRepository)
+}
+
+// cloneDefaultBranch clones the default branch of the given repository (see
+// cloneSince and cloneSample). If the branch is missing, e.g., because it has
+// been renamed since the repository has been resolved or because the HEAD of
+// the repository is unborn, another branch is cloned instead (see
+// fallbackBranch). Returns errNoBranches if the repository has none.
+func cloneDefaultBranch(repository *github.Repository, auth *http.BasicAuth, since time.Time) (*git.Repository, func(), error) {
+	url := repository.GetCloneURL()
+	branch := defaultBranch(repository)
+	tried := make(map[plumbing.ReferenceName]bool)
+	for {
+		var r *git.Repository
+		var remove func()
+		var err error
+		if isOversized(repository) {
+			r, remove, err = cloneSample(url, auth, branch, repository.GetSize())
+		} else {
+			r, remove, err = cloneSince(url, auth, branch, since, repository.GetSize())
+		}
+		if !isMissingBranchError(err) {
+			return r, remove, err
+		}
+		tried[branch] = true
+		logger.Debugw("Branch is missing - falling back to another branch", "url", url, "branch", branch, "Error", err)
+		if branch, err = fallbackBranch(url, auth, tried); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// fallbackBranch returns the branch of the repository with the given URL to
+// clone if the default branch is missing. The branch HEAD points to is
+// preferred, followed by 'main', 'master', and the remaining branches in
+// alphabetical order. The given branches that have been tried already are
+// skipped. Returns errNoBranches if the repository has no branches.
+func fallbackBranch(url string, auth *http.BasicAuth, tried map[plumbing.ReferenceName]bool) (plumbing.ReferenceName, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	})
+	var refs []*plumbing.Reference
+	err := retry("listing references of "+url, isTransientGitError, func() (err error) {
+		refs, err = remote.List(&git.ListOptions{Auth: auth})
+		return err
+	})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return "", errNoBranches
+	}
+	if err != nil {
+		return "", fmt.Errorf("listing references of '%s' failed: %w", url, err)
+	}
+	var head plumbing.ReferenceName
+	var branches []plumbing.ReferenceName
+	exists := make(map[plumbing.ReferenceName]bool)
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference {
+			head = ref.Target()
+		}
+		if ref.Name().IsBranch() {
+			branches = append(branches, ref.Name())
+			exists[ref.Name()] = true
+		}
+	}
+	if len(branches) == 0 {
+		return "", errNoBranches
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i] < branches[j]
+	})
+	candidates := append([]plumbing.ReferenceName{head, plumbing.NewBranchReferenceName("main"), plumbing.Master}, branches...)
+	for _, candidate := range candidates {
+		if exists[candidate] && !tried[candidate] {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("none of the branches of '%s' could be cloned", url)
+}
+
+// cloneDepthGrowth is the factor the depth of a shallow clone is increased by
+// if it doesn't cover the analyzed period.
+const cloneDepthGrowth = 4
+
+// unshallowDepth is the depth used to fetch the complete history into a
+// shallow clone.
+const unshallowDepth = math.MaxInt32
+
+// compileCommitFilters compiles the configured commit filters.
+func compileCommitFilters() ([]*vm.Program, error) {
+	rawFilters := viper.GetStringSlice(commitFiltersCfgKey)
+	var filters []*vm.Program
+	for _, fs := range rawFilters {
+		filter, err := expr.Compile(fs, expr.Env(object.Commit{}), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit filter '%s': %w", fs, err)
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) != 0 {
+		logger.Debugw("Applying commit filters", "filters", rawFilters)
+	}
+	return filters, nil
+}
+
+// commitFiltered returns true iff the given commit is matched by any of the
+// given compiled commit filters.
+func commitFiltered(filters []*vm.Program, c *object.Commit) (bool, error) {
+	for _, filter := range filters {
+		result, err := expr.Run(filter, *c)
+		if err != nil {
+			return false, fmt.Errorf("failed to apply filter '%v': %w", filter, err)
+		}
+		if result.(bool) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// cloneSince clones the given branch of the repository with the given URL and
+// size (see clone). If shallow clones are enabled, the clone is deepened
+// until all commits made after since are contained, which is assumed once the
+// commits at the shallow boundary have been committed before since. If a clone
+// cache directory is configured, the clone is kept there between runs instead
+// (see fetchCachedSince). The returned function removes temporary clones.
+func cloneSince(url string, auth *http.BasicAuth, branch plumbing.ReferenceName, since time.Time, size int) (*git.Repository, func(), error) {
+	if dir := viper.GetString(cloneCacheDirCfgKey); dir != "" {
+		r, err := fetchCachedSince(dir, url, auth, branch, since)
+		return r, func() {}, err
+	}
+	depth := viper.GetInt(cloneDepthCfgKey)
+	for {
+		r, remove, err := clone(size, &git.CloneOptions{
+			URL:           url,
+			Auth:          auth,
+			ReferenceName: branch,
+			Depth:         depth,
+			SingleBranch:  true,
+			Tags:          git.NoTags,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		covered, err := coversSince(r, since)
+		if err != nil {
+			remove()
+			return nil, nil, err
+		}
+		if covered || depth <= 0 {
+			return r, remove, nil
+		}
+		remove()
+		logger.Debugw("Shallow clone doesn't cover analyzed period", "url", url, "depth", depth)
+		depth *= cloneDepthGrowth
+	}
+}
+
+// defaultSampleDepth is the number of commits sampled from oversized
+// repositories if shallow clones are disabled.
+const defaultSampleDepth = 500
+
+// cloneSample clones the most recent commits of the given branch of the
+// oversized repository with the given URL and size (see clone) as given by the
+// configured clone depth. Unlike in cloneSince, the clone isn't deepened, so
+// that the commits made earlier within the analyzed period are missing.
+func cloneSample(url string, auth *http.BasicAuth, branch plumbing.ReferenceName, size int) (*git.Repository, func(), error) {
+	depth := viper.GetInt(cloneDepthCfgKey)
+	if depth <= 0 {
+		depth = defaultSampleDepth
+	}
+	logger.Warnw("Repository exceeds maximum size - sampling most recent commits only", "url", url,
+		"Size (MB)", size/1024, "commits", depth)
+	return clone(size, &git.CloneOptions{
+		URL:           url,
+		Auth:          auth,
+		ReferenceName: branch,
+		Depth:         depth,
+		SingleBranch:  true,
+		Tags:          git.NoTags,
+	})
+}
+
+// clone clones a repository of the given size in KB (as reported by the GitHub
+// API) as specified by the given options into memory. Repositories larger than
+// the configured threshold are cloned into a temporary directory instead to
+// bound the memory usage. The returned function removes the clone.
+func clone(size int, o *git.CloneOptions) (*git.Repository, func(), error) {
+	threshold := viper.GetInt(maxMemoryCloneSizeCfgKey)
+	if threshold <= 0 || size <= threshold*1024 {
+		var r *git.Repository
+		err := retry("cloning "+o.URL, isTransientGitError, func() (err error) {
+			r, err = git.Clone(memory.NewStorage(), nil, o)
+			return err
+		})
+		return r, func() {}, err
+	}
+	dir, err := os.MkdirTemp("", "herdstat-clone-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating temporary directory for clone failed: %w", err)
+	}
+	remove := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Warnw("Can't remove temporary clone", "path", dir, "Error", err)
+		}
+	}
+	logger.Debugw("Cloning large repository to disk", "url", o.URL, "size", size, "path", dir)
+	var r *git.Repository
+	err = retry("cloning "+o.URL, isTransientGitError, func() error {
+		// Remove what a failed attempt left behind
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		r, err = git.PlainClone(dir, true, o)
+		return err
+	})
+	if err != nil {
+		remove()
+		return nil, nil, err
+	}
+	return r, remove, nil
+}
+
+// fetchCachedSince updates the bare clone of the given branch of the
+// repository with the given URL kept in the given clone cache directory by
+// fetching only the objects added since the previous run. The clone is created
+// if it doesn't exist yet or holds another branch. Like in cloneSince, shallow
+// clones are deepened until all commits made after since are contained.
+func fetchCachedSince(dir string, url string, auth *http.BasicAuth, branch plumbing.ReferenceName, since time.Time) (*git.Repository, error) {
+	path, err := cachedClonePath(dir, url)
+	if err != nil {
+		return nil, err
+	}
+	r, err := git.PlainOpen(path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return cloneCachedSince(path, url, auth, branch, since)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening cached clone '%s' failed: %w", path, err)
+	}
+	head, err := r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return nil, err
+	}
+	// Clones of detached HEADs have no branch to fetch into
+	if head.Type() != plumbing.SymbolicReference || branch != plumbing.HEAD && head.Target() != branch {
+		logger.Debugw("Cached clone holds another branch - cloning again", "url", url, "path", path, "branch", branch)
+		return cloneCachedSince(path, url, auth, branch, since)
+	}
+	err = fetchSinceInto(r, url, auth, since)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		// go-git walks the history of the local branch when negotiating the
+		// objects to fetch, which fails at the boundary of clones shallower
+		// than the number of commits it inspects
+		logger.Debugw("Fetching into shallow cached clone failed - cloning again", "url", url, "path", path)
+		return cloneCachedSince(path, url, auth, branch, since)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// cloneCachedSince clones the given branch of the repository with the given
+// URL into a bare clone at the given path replacing any existing one. Like in
+// cloneSince, shallow clones are deepened until all commits made after since
+// are contained.
+func cloneCachedSince(path string, url string, auth *http.BasicAuth, branch plumbing.ReferenceName, since time.Time) (*git.Repository, error) {
+	depth := viper.GetInt(cloneDepthCfgKey)
+	for {
+		logger.Debugw("Cloning into clone cache", "url", url, "path", path, "depth", depth)
+		var r *git.Repository
+		err := retry("cloning "+url, isTransientGitError, func() (err error) {
+			// Remove clones with insufficient depth or left behind by failed
+			// runs or attempts
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			r, err = git.PlainClone(path, true, &git.CloneOptions{
+				URL:           url,
+				Auth:          auth,
+				ReferenceName: branch,
+				Depth:         depth,
+				SingleBranch:  true,
+				Tags:          git.NoTags,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		covered, err := coversSince(r, since)
+		if err != nil {
+			return nil, err
+		}
+		if covered || depth <= 0 {
+			return r, nil
+		}
+		logger.Debugw("Shallow clone doesn't cover analyzed period", "url", url, "depth", depth)
+		depth *= cloneDepthGrowth
+	}
+}
+
+// fetchSinceInto fetches the objects added to the branch HEAD of the given
+// bare clone points to from the repository with the given URL into the clone.
+// The clone is deepened until all commits made after since are contained.
+func fetchSinceInto(r *git.Repository, url string, auth *http.BasicAuth, since time.Time) error {
+	head, err := r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return err
+	}
+	// Update the local branch directly as there is no worktree
+	branch := head.Target()
+	refSpec := config.RefSpec(fmt.Sprintf("+%s:%s", branch, branch))
+	// Only fetch new objects first
+	depth := 0
+	for {
+		logger.Debugw("Fetching into clone cache", "url", url, "depth", depth)
+		err := retry("fetching "+url, isTransientGitError, func() error {
+			err := r.Fetch(&git.FetchOptions{
+				RemoteName: git.DefaultRemoteName,
+				RefSpecs:   []config.RefSpec{refSpec},
+				Auth:       auth,
+				Depth:      depth,
+				Tags:       git.NoTags,
+			})
+			if errors.Is(err, git.NoErrAlreadyUpToDate) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		covered, err := coversSince(r, since)
+		if err != nil {
+			return err
+		}
+		if covered || depth == unshallowDepth {
+			return nil
+		}
+		logger.Debugw("Cached clone doesn't cover analyzed period", "url", url, "depth", depth)
+		switch initial := viper.GetInt(cloneDepthCfgKey); {
+		case initial <= 0:
+			depth = unshallowDepth
+		case depth == 0:
+			depth = initial
+		default:
+			depth *= cloneDepthGrowth
+		}
+	}
+}
+
+// cachedClonePath returns the path of the clone of the repository with the
+// given URL in the given clone cache directory.
+func cachedClonePath(dir string, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing clone URL '%s' failed: %w", rawURL, err)
+	}
+	// Clean the path as an absolute one to keep it inside the directory
+	return filepath.Join(dir, u.Host, filepath.Clean("/"+filepath.FromSlash(u.Path))), nil
+}
+
+// coversSince returns true iff all commits at the shallow boundary of the
+// given repository have been committed before since. This is trivially the
+// case for complete clones.
+func coversSince(r *git.Repository, since time.Time) (bool, error) {
+	shallow, err := r.Storer.Shallow()
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range shallow {
+		c, err := r.CommitObject(hash)
+		if err != nil {
+			return false, err
+		}
+		if !c.Committer.When.Before(since) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// missingParents returns the parents of the commits at the shallow boundary of
+// the given repository, which are not contained in the clone and thus have to
+// be skipped when walking the history.
+func missingParents(r *git.Repository) ([]plumbing.Hash, error) {
+	shallow, err := r.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+	var missing []plumbing.Hash
+	for _, hash := range shallow {
+		c, err := r.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range c.ParentHashes {
+			if _, err := r.CommitObject(parent); err == plumbing.ErrObjectNotFound {
+				missing = append(missing, parent)
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return missing, nil
+}
+
+// Sources commits are collected from.
+const (
+
+	// cloneCommitSource collects commits by cloning the repositories.
+	cloneCommitSource = "clone"
+
+	// activityCommitSource collects the number of commits per day from the
+	// commit activity statistics of the GitHub API.
+	activityCommitSource = "activity"
+)
+
+// Retries of requests for commit activity statistics while GitHub computes
+// them.
+const (
+	commitActivityRetries    = 5
+	commitActivityRetryDelay = 2 * time.Second
+)
+
+// collectCommitActivity collects the commits made after since until the given
+// day to the given repositories from the commit activity statistics of the
+// GitHub API. This is considerably faster than cloning large repositories but
+// the statistics only cover the last year, don't reveal the commit authors, and
+// can't be filtered.
+func collectCommitActivity(repositories map[url.URL]*github.Repository, since time.Time, lastDay time.Time) ([]internal.Contribution, error) {
+	if len(viper.GetStringSlice(commitFiltersCfgKey)) != 0 {
+		logger.Warnw("Commit filters are not applied to commit activity statistics")
+	}
+	key := checkpointKey(commitsCheckpointStage+" "+activityCommitSource, since, lastDay)
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	return collectPerRepository(repositories, "Collecting commit activity", key, func(_ url.URL, repository *github.Repository) ([]internal.Contribution, error) {
+		activity, err := listCommitActivity(ctx, client, repository)
+		if err != nil {
+			return nil, err
+		}
+		return commitActivityContributions(repository, activity, since, lastDay), nil
+	})
+}
+
+// listCommitActivity lists the weekly commit activity of the last year of the
+// given repository. Requests are retried while GitHub computes the statistics.
+func listCommitActivity(ctx context.Context, client *github.Client, repository *github.Repository) ([]*github.WeeklyCommitActivity, error) {
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	for attempt := 0; ; attempt++ {
+		activity, resp, err := client.Repositories.ListCommitActivity(ctx, owner, repo)
+		var accepted *github.AcceptedError
+		if errors.As(err, &accepted) && attempt < commitActivityRetries {
+			logger.Debugw("Waiting for commit activity statistics", "repository", repository.GetFullName())
+			time.Sleep(commitActivityRetryDelay)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching commit activity for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		return activity, nil
+	}
+}
+
+// commitActivityContributions converts the given weekly commit activity of the
+// given repository into a commit contribution of unknown author per commit
+// made after since until the given day. The commits are dated at noon of their
+// day.
+func commitActivityContributions(repository *github.Repository, activity []*github.WeeklyCommitActivity,
+	since time.Time, lastDay time.Time) []internal.Contribution {
+	var contributions []internal.Contribution
+	for _, week := range activity {
+		start := week.GetWeek().UTC()
+		for i, count := range week.Days {
+			day := start.AddDate(0, 0, i)
+			date := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, lastDay.Location())
+			if date.Before(since) || date.After(lastDay) {
+				continue
+			}
+			for j := 0; j < count; j++ {
+				contributions = append(contributions, internal.Contribution{
+					Type:       internal.CommitContribution,
+					Repository: repository.GetFullName(),
+					Date:       date,
+				})
+			}
+		}
+	}
+	return contributions
+}
+
+// commitDetails selects the optional details recorded for commits. Recording
+// them requires diffing each commit against its parent and is therefore only
+// done on request.
+type commitDetails struct {
+
+	// Whether to record the changed files.
+	files bool
+
+	// Whether to record the changed files and the number of lines added and
+	// removed. Implies files.
+	churn bool
+}
+
+// commitChurn computes the files changed and the number of lines added and
+// removed by the given commit. Merge commits are skipped as diffing them
+// against their first parent attributes the changes of the merged branch to
+// the merge and is expensive. Only trees and blobs that differ between the
+// commit and its parent are compared, and binary files are not diffed line by
+// line.
+func commitChurn(c *object.Commit) ([]string, int, int, error) {
+	if c.NumParents() > 1 {
+		return nil, 0, 0, nil
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var files []string
+	additions, deletions := 0, 0
+	for _, s := range stats {
+		files = append(files, s.Name)
+		additions += s.Addition
+		deletions += s.Deletion
+	}
+	return files, additions, deletions, nil
+}
+
+// commitFiles computes the files changed by the given commit. In contrast to
+// commitChurn, only the trees of the commit and its parent are compared, which
+// is considerably cheaper. Merge commits are skipped for the same reasons.
+func commitFiles(c *object.Commit) ([]string, error) {
+	if c.NumParents() > 1 {
+		return nil, nil
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	parentTree := &object.Tree{}
+	if c.NumParents() == 1 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// collectIssueRelatedContributions collects issues and PRs updated after since
+// from the given repositories.
+func collectIssueRelatedContributions(repositories map[url.URL]*github.Repository, since time.Time, lastDay time.Time) ([]internal.Contribution, error) {
+	key := checkpointKey(issuesCheckpointStage, since, lastDay)
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	return collectPerRepository(repositories, "Collecting issues and pull requests", key, func(_ url.URL, repository *github.Repository) ([]internal.Contribution, error) {
+		allIssues, err := listIssues(ctx, client, repository, since)
+		if err != nil {
+			return nil, err
+		}
+		var contributions []internal.Contribution
+		for _, issue := range allIssues {
+			contributionType := internal.IssueContribution
+			if issue.IsPullRequest() {
+				contributionType = internal.PullRequestContribution
+			}
+			contributions = append(contributions, internal.Contribution{
+				Type:       contributionType,
+				Repository: repository.GetFullName(),
+				Login:      issue.GetUser().GetLogin(),
+				Date:       issue.GetCreatedAt().Time,
+				URL:        issue.GetHTMLURL(),
+			})
+		}
+		return contributions, nil
+	})
+}
+
+// listIssues lists the issues and PRs of the given repository updated after
+// since. If a cache is configured, only the issues and PRs updated since the
+// last synchronization are fetched.
+func listIssues(ctx context.Context, client *github.Client, repository *github.Repository, since time.Time) ([]*github.Issue, error) {
+	cache, err := getCache()
+	if err != nil {
+		return nil, err
+	}
+	if cache == nil {
+		return listIssuesByState(ctx, client, repository, "all", since)
+	}
+
+	name := repository.GetFullName()
+	synced, cachedSince, ok, err := cache.syncState(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache failed: %w", err)
+	}
+	// Fetch all issues updated since the last synchronization if the cache
+	// covers the requested period, and all issues updated after since otherwise
+	fetchSince, coveredSince := since, since
+	if ok && !cachedSince.After(since) {
+		fetchSince, coveredSince = synced.Add(-cacheSyncOverlap), cachedSince
+	}
+	logger.Debugw("Synchronizing cached issues", "repository", name, "since", fetchSince)
+	now := time.Now()
+	issues, err := listIssuesByState(ctx, client, repository, "all", fetchSince)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.storeIssues(name, issues, now, coveredSince); err != nil {
+		return nil, fmt.Errorf("writing cache failed: %w", err)
+	}
+	return cache.issues(name, since)
+}
+
+// issuesPerPage is the number of issues and PRs fetched per request.
+const issuesPerPage = 100
+
+// listIssuesByState lists the issues and PRs of the given repository in the
+// given state ('open', 'closed', or 'all') updated after since.
+func listIssuesByState(ctx context.Context, client *github.Client, repository *github.Repository, state string,
+	since time.Time) ([]*github.Issue, error) {
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.IssueListByRepoOptions{
+		Since:       since,
+		State:       state,
+		ListOptions: github.ListOptions{PerPage: issuesPerPage},
+	}
+	var allIssues []*github.Issue
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching issues for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		allIssues = append(allIssues, issues...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allIssues, nil
+}
+
+// collectPullRequests collects the pull requests of the given repository
+// updated after since including their reviews. Reviews by the author of a pull
+// request, by bots, and pending reviews are ignored.
+func collectPullRequests(repository *github.Repository, since time.Time) ([]internal.PullRequest, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	allIssues, err := listIssues(ctx, client, repository, since)
+	if err != nil {
+		return nil, err
+	}
+	var pullRequests []internal.PullRequest
+	for _, issue := range allIssues {
+		if !issue.IsPullRequest() {
+			continue
+		}
+		pr := internal.PullRequest{
+			Repository: repository.GetFullName(),
+			Number:     issue.GetNumber(),
+			Author:     issue.GetUser().GetLogin(),
+			Created:    issue.GetCreatedAt().Time,
+		}
+		reviews, err := listReviews(ctx, client, repository, pr.Number)
+		if err != nil {
+			return nil, err
+		}
+		for _, review := range reviews {
+			if review.GetUser().GetLogin() == pr.Author || review.GetUser().GetType() == "Bot" ||
+				review.SubmittedAt == nil {
+				continue
+			}
+			pr.Reviews = append(pr.Reviews, internal.Review{
+				Reviewer:  review.GetUser().GetLogin(),
+				Submitted: review.GetSubmittedAt().Time,
+			})
+		}
+		sort.Slice(pr.Reviews, func(i, j int) bool {
+			return pr.Reviews[i].Submitted.Before(pr.Reviews[j].Submitted)
+		})
+		pullRequests = append(pullRequests, pr)
+	}
+	return pullRequests, nil
+}
+
+// listMergedPullRequests lists the pull requests of the given repository
+// merged after since.
+func listMergedPullRequests(ctx context.Context, client *github.Client, repository *github.Repository,
+	since time.Time) ([]*github.PullRequest, error) {
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var merged []*github.PullRequest
+	for {
+		prs, resp, err := client.PullRequests.List(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching pull requests for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		for _, pr := range prs {
+			// Merging updates a pull request, so older ones can't have been merged since
+			if pr.GetUpdatedAt().Before(since) {
+				return merged, nil
+			}
+			if pr.MergedAt != nil && !pr.GetMergedAt().Before(since) {
+				merged = append(merged, pr)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return merged, nil
+}
+
+// listReviews lists the reviews of the pull request with the given number.
+// If a cache is configured, the reviews are only fetched if the pull request
+// has been updated since they were cached.
+func listReviews(ctx context.Context, client *github.Client, repository *github.Repository, number int) ([]*github.PullRequestReview, error) {
+	cache, err := getCache()
+	if err != nil {
+		return nil, err
+	}
+	if cache == nil {
+		return fetchReviews(ctx, client, repository, number)
+	}
+
+	reviews, ok, err := cache.reviews(repository.GetFullName(), number)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache failed: %w", err)
+	}
+	if ok {
+		return reviews, nil
+	}
+	reviews, err = fetchReviews(ctx, client, repository, number)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.storeReviews(repository.GetFullName(), number, reviews); err != nil {
+		return nil, fmt.Errorf("writing cache failed: %w", err)
+	}
+	return reviews, nil
+}
+
+// fetchReviews fetches the reviews of the pull request with the given number.
+func fetchReviews(ctx context.Context, client *github.Client, repository *github.Repository, number int) ([]*github.PullRequestReview, error) {
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.ListOptions{PerPage: 100}
+	var allReviews []*github.PullRequestReview
+	for {
+		reviews, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching reviews for PR %s/%s#%d failed (Statuscode: %d)", owner, repo, number, resp.StatusCode)
+		}
+		allReviews = append(allReviews, reviews...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allReviews, nil
+}
+
+// listReviewRequests lists the requests for reviews of individual users of the
+// pull request with the given number in chronological order. Requests for
+// reviews of teams are ignored.
+func listReviewRequests(ctx context.Context, client *github.Client, repository *github.Repository, number int) ([]internal.ReviewRequest, error) {
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.ListOptions{PerPage: 100}
+	var requests []internal.ReviewRequest
+	for {
+		events, resp, err := client.Issues.ListIssueEvents(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching events for PR %s/%s#%d failed (Statuscode: %d)", owner, repo, number, resp.StatusCode)
+		}
+		for _, event := range events {
+			if event.GetEvent() != "review_requested" || event.RequestedReviewer == nil {
+				continue
+			}
+			requests = append(requests, internal.ReviewRequest{
+				Reviewer:  event.GetRequestedReviewer().GetLogin(),
+				Requested: event.GetCreatedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].Requested.Before(requests[j].Requested)
+	})
+	return requests, nil
+}
+
+// collectIssueLifecycles collects the issues (excluding PRs) of the given
+// repository that have been open at some point within the 52 weeks ending with
+// the given day. These are all issues open now and all issues updated (and
+// thus potentially closed) since the beginning of the analyzed period.
+func collectIssueLifecycles(repository *github.Repository, lastDay time.Time) ([]internal.Issue, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	updated, err := listIssues(ctx, client, repository, lastDay.AddDate(0, 0, -52*7))
+	if err != nil {
+		return nil, err
+	}
+	open, err := listIssuesByState(ctx, client, repository, "open", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	var issues []internal.Issue
+	for _, issue := range append(updated, open...) {
+		if issue.IsPullRequest() || seen[issue.GetNumber()] {
+			continue
+		}
+		seen[issue.GetNumber()] = true
+		issues = append(issues, newIssue(repository, issue))
+	}
+	return issues, nil
+}
+
+// collectOpenIssues collects the issues (excluding PRs) of the given
+// repository that are open now.
+func collectOpenIssues(repository *github.Repository) ([]internal.Issue, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	open, err := listIssuesByState(ctx, client, repository, "open", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	var issues []internal.Issue
+	for _, issue := range open {
+		if !issue.IsPullRequest() {
+			issues = append(issues, newIssue(repository, issue))
+		}
+	}
+	return issues, nil
+}
+
+// newIssue converts the given issue of the given repository. The first
+// response is not determined.
+func newIssue(repository *github.Repository, issue *github.Issue) internal.Issue {
+	return internal.Issue{
+		Repository: repository.GetFullName(),
+		Number:     issue.GetNumber(),
+		Author:     issue.GetUser().GetLogin(),
+		Created:    issue.GetCreatedAt().Time,
+		Closed:     issue.GetClosedAt().Time,
+		Labels:     labelNames(issue.Labels),
+	}
+}
+
+// labelNames returns the names of the given labels.
+func labelNames(labels []*github.Label) []string {
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// commentMarker returns the hidden marker identifying the comments posted for
+// the command with the given name.
+func commentMarker(command string) string {
+	return fmt.Sprintf("<!-- herdstat:%s -->", command)
+}
+
+// commentResults posts the results of the given command as a comment to the
+// configured issue or pull request, or to the pull request triggering the
+// GitHub Actions workflow.
+func commentResults(cmd *cobra.Command) error {
+	number := viper.GetInt(commentIssueCfgKey)
+	if viper.GetBool(commentPullRequestCfgKey) {
+		var err error
+		if number, err = triggeringPullRequest(); err != nil {
+			return err
+		}
+	}
+	if number <= 0 {
+		return nil
+	}
+	body, ok := results.comment(cmd.Name())
+	if !ok {
+		logger.Warnw("No results to comment on", "number", number)
+		return nil
+	}
+	repository := viper.GetString(commentRepositoryCfgKey)
+	if repository == "" {
+		repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	owner, name, ok := strings.Cut(repository, "/")
+	if !ok {
+		return fmt.Errorf("commenting on #%d requires a repository in 'owner/repository' notation but got '%s'", number, repository)
+	}
+	client := github.NewClient(getHTTPClient())
+	u, err := postComment(context.Background(), client, owner, name, number, body, commentMarker(cmd.Name()),
+		viper.GetBool(commentUpdateCfgKey))
+	if err != nil {
+		return fmt.Errorf("commenting on #%d of '%s' failed: %w", number, repository, err)
+	}
+	cmd.Printf("Commented results on %s\n", u)
+	return nil
+}
+
+// triggeringPullRequest returns the number of the pull request triggering the
+// running GitHub Actions workflow.
+func triggeringPullRequest() (int, error) {
+	filename := os.Getenv("GITHUB_EVENT_PATH")
+	if filename == "" {
+		return 0, errors.New("commenting on the triggering pull request is only supported in GitHub Actions workflows")
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return 0, fmt.Errorf("reading workflow event failed: %w", err)
+	}
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(content, &event); err != nil {
+		return 0, fmt.Errorf("parsing workflow event failed: %w", err)
+	}
+	if event.PullRequest.Number == 0 {
+		return 0, errors.New("the workflow hasn't been triggered by a pull request")
+	}
+	return event.PullRequest.Number, nil
+}
+
+// comment renders the markdown comment on the results of the command with the
+// given name. The comment starts with the marker of the command and embeds
+// the published or uploaded SVG documents. The second return value is false
+// if there are no results to comment on.
+func (r *runResults) comment(command string) (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.summary == nil && len(r.links) == 0 {
+		return "", false
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n## herdstat %s\n\n", commentMarker(command), command)
+	if r.summary != nil {
+		writeSummaryTable(&b, r.summary)
+	}
+	for _, u := range r.links {
+		if path.Ext(u) == ".svg" {
+			fmt.Fprintf(&b, "![%s](%s)\n\n", path.Base(u), u)
+		} else {
+			fmt.Fprintf(&b, "- [%s](%s)\n", path.Base(u), u)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n"), true
+}
+
+// postComment posts the given comment to the issue or pull request with the
+// given number and returns its URL. If updating is enabled, the first comment
+// starting with the given marker is updated instead. A new comment is posted if
+// the existing one can't be edited, e.g., because it has been posted by
+// someone else.
+func postComment(ctx context.Context, client *github.Client, owner string, repo string, number int, body string,
+	marker string, update bool) (string, error) {
+	if update {
+		opt := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	pages:
+		for {
+			comments, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opt)
+			if err != nil {
+				return "", err
+			}
+			for _, c := range comments {
+				if !strings.HasPrefix(c.GetBody(), marker) {
+					continue
+				}
+				edited, resp, err := client.Issues.EditComment(ctx, owner, repo, c.GetID(), &github.IssueComment{Body: github.String(body)})
+				if err == nil {
+					return edited.GetHTMLURL(), nil
+				}
+				if resp == nil || resp.StatusCode != http.StatusForbidden {
+					return "", err
+				}
+				logger.Warnw("Can't update comment - posting a new one", "url", c.GetHTMLURL(), "Error", err)
+				break pages
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+	created, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: github.String(body)})
+	if err != nil {
+		return "", err
+	}
+	return created.GetHTMLURL(), nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Commenting", func() {
+	var (
+		requests map[string]map[string]interface{}
+		client   *github.Client
+	)
+
+	// serve starts a fake GitHub API responding to the given requests given
+	// by method and path with the given bodies. The bodies of the requests
+	// are recorded.
+	serve := func(responses map[string]string) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Method + " " + r.URL.Path
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests[key] = body
+			response, ok := responses[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				response = `{"message": "Not Found"}`
+			}
+			_, _ = w.Write([]byte(response))
+		}))
+		DeferCleanup(server.Close)
+		client = github.NewClient(nil)
+		u, err := url.Parse(server.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		client.BaseURL = u
+	}
+
+	BeforeEach(func() {
+		requests = make(map[string]map[string]interface{})
+	})
+
+	It("updates the previous comment of the command", func() {
+		serve(map[string]string{
+			"GET /repos/herdstat/herdstat/issues/42/comments": `[
+				{"id": 1, "body": "Looks good"},
+				{"id": 2, "body": "<!-- herdstat:summary -->\nOld results"}
+			]`,
+			"PATCH /repos/herdstat/herdstat/issues/comments/2": `{"html_url": "https://github.com/herdstat/herdstat/issues/42#issuecomment-2"}`,
+		})
+		u, err := postComment(context.Background(), client, "herdstat", "herdstat", 42, "new", commentMarker("summary"), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u).To(HaveSuffix("#issuecomment-2"))
+		Expect(requests["PATCH /repos/herdstat/herdstat/issues/comments/2"]["body"]).To(Equal("new"))
+	})
+
+	It("posts a new comment if there is no previous one", func() {
+		serve(map[string]string{
+			"GET /repos/herdstat/herdstat/issues/42/comments":  `[{"id": 1, "body": "<!-- herdstat:badge -->"}]`,
+			"POST /repos/herdstat/herdstat/issues/42/comments": `{"html_url": "https://github.com/herdstat/herdstat/issues/42#issuecomment-3"}`,
+		})
+		u, err := postComment(context.Background(), client, "herdstat", "herdstat", 42, "new", commentMarker("summary"), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u).To(HaveSuffix("#issuecomment-3"))
+		Expect(requests).NotTo(HaveKey("PATCH /repos/herdstat/herdstat/issues/comments/1"))
+	})
+
+	It("renders the results with embedded graphs", func() {
+		var r runResults
+		_, ok := r.comment("contribution-graph")
+		Expect(ok).To(BeFalse())
+		lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+		r.recordContributions([]internal.Contribution{{Type: internal.CommitContribution, Login: "jane", Date: lastDay}}, lastDay)
+		r.recordLink("https://example.com/contribution-graph.svg")
+		r.recordLink("https://example.com/report.json")
+		body, ok := r.comment("contribution-graph")
+		Expect(ok).To(BeTrue())
+		Expect(body).To(HavePrefix("<!-- herdstat:contribution-graph -->\n## herdstat contribution-graph\n\n| Period |"))
+		Expect(body).To(HaveSuffix("![contribution-graph.svg](https://example.com/contribution-graph.svg)\n\n" +
+			"- [report.json](https://example.com/report.json)"))
+	})
+
+	It("finds the pull request triggering the workflow", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "event.json")
+		Expect(os.WriteFile(filename, []byte(`{"action": "opened", "pull_request": {"number": 7}}`), 0o644)).To(Succeed())
+		GinkgoT().Setenv("GITHUB_EVENT_PATH", filename)
+		Expect(triggeringPullRequest()).To(Equal(7))
+
+		Expect(os.WriteFile(filename, []byte(`{"ref": "refs/heads/main"}`), 0o644)).To(Succeed())
+		_, err := triggeringPullRequest()
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the commit-types command
+const (
+	// The format of the report
+	commitTypesFormatCfgKey = "commit-types.format"
+	// The name of the output file
+	commitTypesFilenameCfgKey = "commit-types.filename"
+	// The name of the chart SVG file
+	commitTypesChartCfgKey = "commit-types.chart"
+	// The color of the chart bars
+	commitTypesColorCfgKey = "commit-types.color"
+)
+
+// commitTypesCmd represents the commit-types command
+var commitTypesCmd = &cobra.Command{
+	Use:   "commit-types",
+	Short: "Reports the distribution of commits across conventional commit types",
+	Long: `Classifies commits by the type prefixing their subject according to the
+Conventional Commits specification (e.g., 'feat', 'fix', or 'docs') and reports
+the number of commits per type. The distribution is optionally rendered as an
+SVG bar chart.`,
+	Args: cobra.NoArgs,
+	RunE: runCommitTypes,
+}
+
+func runCommitTypes(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(commitTypesColorCfgKey)
+	barColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	commits, lastDay, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewCommitTypeReport(commits, lastDay)
+
+	if chartFilename := viper.GetString(commitTypesChartCfgKey); chartFilename != "" {
+		chartFilename, err = writeSVG(cmd, internal.NewCommitTypeChart(report, barColor), chartFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Commit type chart written to '%s'\n", chartFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(commitTypesFormatCfgKey), viper.GetString(commitTypesFilenameCfgKey))
+}
+
+// Initialize the 'commit-types' command.
+func init() {
+	rootCmd.AddCommand(commitTypesCmd)
+
+	// Flag to control the chart output file
+	const chartFlag = "chart"
+	commitTypesCmd.Flags().String(
+		chartFlag,
+		"",
+		"The name of the SVG file the commit type chart is written to (no chart if empty)")
+	if err := viper.BindPFlag(commitTypesChartCfgKey, commitTypesCmd.Flags().Lookup(chartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", chartFlag, "Error", err)
+	}
+
+	// Flag to control the chart color
+	const colorFlag = "color"
+	commitTypesCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the chart bars")
+	if err := viper.BindPFlag(commitTypesColorCfgKey, commitTypesCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(commitTypesCmd, commitTypesFormatCfgKey, commitTypesFilenameCfgKey)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the companies command
+const (
+	// The number of organizations listed
+	companiesTopCfgKey = "companies.top"
+	// The format of the report
+	companiesFormatCfgKey = "companies.format"
+	// The name of the output file
+	companiesFilenameCfgKey = "companies.filename"
+	// The name of the chart SVG file
+	companiesChartCfgKey = "companies.chart"
+	// The color of the chart bars
+	companiesColorCfgKey = "companies.color"
+)
+
+// companiesCmd represents the companies command
+var companiesCmd = &cobra.Command{
+	Use:   "companies",
+	Short: "Ranks the organizations contributing most",
+	Long: `Ranks the organizations (e.g., employers) by the number of contributions made
+by their affiliated contributors. Contributors are affiliated with organizations
+by means of the configured affiliations or the domain of their email address.
+The leaderboard is optionally rendered as an SVG bar chart.`,
+	Args: cobra.NoArgs,
+	RunE: runCompanies,
+}
+
+func runCompanies(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(companiesColorCfgKey)
+	barColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+	affiliations, err := getAffiliations()
+	if err != nil {
+		return err
+	}
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewCompanyReport(contributions, lastDay, affiliations, viper.GetInt(companiesTopCfgKey))
+
+	if chartFilename := viper.GetString(companiesChartCfgKey); chartFilename != "" {
+		chartFilename, err = writeSVG(cmd, internal.NewCompanyChart(report, barColor), chartFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Company chart written to '%s'\n", chartFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(companiesFormatCfgKey), viper.GetString(companiesFilenameCfgKey))
+}
+
+// Initialize the 'companies' command.
+func init() {
+	rootCmd.AddCommand(companiesCmd)
+
+	// Flag to control the number of organizations listed
+	const topFlag = "top"
+	companiesCmd.Flags().Int(topFlag, 10,
+		"The number of organizations with the most contributions listed (all if not positive)")
+	if err := viper.BindPFlag(companiesTopCfgKey, companiesCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	// Flag to control the chart output file
+	const chartFlag = "chart"
+	companiesCmd.Flags().String(
+		chartFlag,
+		"",
+		"The name of the SVG file the company chart is written to (no chart if empty)")
+	if err := viper.BindPFlag(companiesChartCfgKey, companiesCmd.Flags().Lookup(chartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", chartFlag, "Error", err)
+	}
+
+	// Flag to control the chart color
+	const colorFlag = "color"
+	companiesCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the chart bars")
+	if err := viper.BindPFlag(companiesColorCfgKey, companiesCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(companiesCmd, companiesFormatCfgKey, companiesFilenameCfgKey)
+}
@@ -8,30 +8,15 @@
 package cmd
 
 import (
-	"bytes"
-	"context"
-	"encoding/xml"
+	"errors"
 	"fmt"
-	"github.com/antonmedv/expr"
-	"github.com/antonmedv/expr/vm"
 	"github.com/araddon/dateparse"
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
-	"github.com/go-git/go-git/v5/storage/memory"
-	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
 	"github.com/icza/gox/imagex/colorx"
-	"github.com/repeale/fp-go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/tdewolff/minify/v2"
-	"github.com/tdewolff/minify/v2/svg"
-	"herdstat/internal"
 	"image/color"
-	"io"
 	"math"
-	"net/url"
-	"os"
 	"strings"
 	"time"
 )
@@ -40,16 +25,54 @@ import (
 const (
 	// Whether the output SVG should be minified
 	minifyOutputCfgKey = "contribution-graph.minify"
+	// Whether the output SVG should be gzip-compressed
+	gzipOutputCfgKey = "contribution-graph.gzip"
 	// The name of the output SVG file
 	filenameCfgKey = "contribution-graph.filename"
 	// The primary color used to color the daily contribution cells
 	colorCfgKey = "contribution-graph.color"
 	// The number of color levels used for coloring contribution cells
 	levelsCfgKey = "contribution-graph.levels"
+	// The color space used to interpolate between the ends of the color spectrum
+	interpolationCfgKey = "contribution-graph.interpolation"
 	// The filters used to exclude commits
 	commitFiltersCfgKey = "contribution-graph.filters.commits"
-	// The date of the last day to visualize
-	untilCfgKey = "until"
+	// Whether the cell representing today should be highlighted
+	highlightTodayCfgKey = "contribution-graph.highlight-today"
+	// The background color used in light mode
+	lightBackgroundCfgKey = "contribution-graph.background.light"
+	// The background color used in dark mode
+	darkBackgroundCfgKey = "contribution-graph.background.dark"
+	// Whether to omit the tooltip overlay
+	noTooltipsCfgKey = "contribution-graph.no-tooltips"
+	// Events rendered as markers on the timeline
+	annotationsCfgKey = "contribution-graph.annotations"
+	// Whether to render the weekly totals bar chart
+	weeklyTotalsCfgKey = "contribution-graph.weekly-totals"
+	// Whether to render the velocity trend
+	showVelocityCfgKey = "contribution-graph.show-velocity"
+	// Whether to annotate days with unusual activity
+	anomaliesCfgKey = "contribution-graph.anomalies.enabled"
+	// The number of standard deviations above the rolling mean a day is considered unusual at
+	anomalyThresholdCfgKey = "contribution-graph.anomalies.threshold"
+	// Whether to label all days of the week
+	allWeekdaysCfgKey = "contribution-graph.all-weekdays"
+	// The arrangement of the daily cells
+	layoutCfgKey = "contribution-graph.layout"
+	// Repositories whose contributions are compared with the analyzed ones
+	compareRepositoriesCfgKey = "contribution-graph.compare.repositories"
+	// Whether to compare the contributions with those of the previous year
+	comparePreviousYearCfgKey = "contribution-graph.compare.previous-year"
+	// The contributor whose contributions are visualized
+	contributorCfgKey = "contribution-graph.contributor"
+	// The number of top contributors an individual graph is generated for
+	topContributorsCfgKey = "contribution-graph.top-contributors"
+	// The locale of the labels and dates of the graph
+	localeCfgKey = "contribution-graph.locale"
+	// The mapping of contribution counts to color intensities
+	scalingCfgKey = "contribution-graph.scaling"
+	// The percentile counts are capped at by the winsorized scaling
+	capPercentileCfgKey = "contribution-graph.cap-percentile"
 )
 
 // contributionGraphCmd represents the contribution-graph command
@@ -60,22 +83,6 @@ var contributionGraphCmd = &cobra.Command{
 	RunE:  run,
 }
 
-// getUntilDate retrieves the "until" parameter as a time.Time instance by
-// parsing the respective configuration entry. The date is converted to
-// last nanosecond of the day.
-func getUntilDate() (time.Time, error) {
-	s := viper.GetString(untilCfgKey)
-	date, err := dateparse.ParseStrict(s)
-	if err != nil {
-		return time.Time{}, err
-	}
-	return time.Date(
-		date.Year(), date.Month(), date.Day(),
-		23, 59, 59,
-		int((1*time.Second).Nanoseconds()-1),
-		date.Location()), nil
-}
-
 // getColorScheme constructs a color scheme with spectra going from shades
 // of grey to the given color.
 func getColorScheme(color color.RGBA) internal.ColorScheme {
@@ -90,235 +97,286 @@ func getColorScheme(color color.RGBA) internal.ColorScheme {
 	}}
 }
 
-func run(cmd *cobra.Command, args []string) error {
+// transparent is the value used to configure a transparent background.
+const transparent = "transparent"
 
-	colorStr := viper.GetString(colorCfgKey)
-	primaryColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+// getBackgroundColor parses the background color stored under the given
+// configuration key. Returns nil if the background is transparent.
+func getBackgroundColor(key string) (*color.RGBA, error) {
+	s := viper.GetString(key)
+	if s == "" || s == transparent {
+		return nil, nil
+	}
+	c, err := colorx.ParseHexColor(fmt.Sprintf("#%s", s))
 	if err != nil {
-		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+		return nil, fmt.Errorf("invalid background color specification '%s': %w", s, err)
 	}
+	return &c, nil
+}
 
-	levels := viper.GetUint(levelsCfgKey)
-	if levels < 5 || levels > math.MaxUint8 {
-		return fmt.Errorf("invalid number of color levels; allowed range is [5..%d]", math.MaxUint8)
+// getBackground constructs the graph background from the configuration.
+func getBackground() (internal.Background, error) {
+	light, err := getBackgroundColor(lightBackgroundCfgKey)
+	if err != nil {
+		return internal.Background{}, err
 	}
-
-	repositories, err := collectRepositories()
+	dark, err := getBackgroundColor(darkBackgroundCfgKey)
 	if err != nil {
-		return err
+		return internal.Background{}, err
 	}
-	l := len(repositories)
-	var s string
-	switch l {
-	case 1:
-		s = "repository"
-	default:
-		s = "repositories"
+	return internal.Background{Light: light, Dark: dark}, nil
+}
+
+// annotationConfig is the configuration of a single annotation.
+type annotationConfig struct {
+	Date  string `mapstructure:"date"`
+	Label string `mapstructure:"label"`
+}
+
+// getAnnotations retrieves the annotations from the configuration.
+func getAnnotations() ([]internal.Annotation, error) {
+	var configs []annotationConfig
+	if err := viper.UnmarshalKey(annotationsCfgKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid annotations: %w", err)
+	}
+	var annotations []internal.Annotation
+	for _, config := range configs {
+		date, err := dateparse.ParseStrict(config.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date '%s' of annotation '%s': %w", config.Date, config.Label, err)
+		}
+		annotations = append(annotations, internal.Annotation{
+			Date:  date,
+			Label: config.Label,
+		})
 	}
-	cmd.Printf("Processing %d %s: %v\n", l, s,
-		strings.Join(fp.Map(func(url url.URL) string { return url.String() })(internal.Keys(repositories)), ","))
+	return annotations, nil
+}
+
+// periodTitle returns a title describing the 52 weeks ending with the given
+// day.
+func periodTitle(lastDay time.Time) string {
+	return fmt.Sprintf("%s – %s", lastDay.AddDate(0, 0, -52*7+1).Format("Jan 2, 2006"), lastDay.Format("Jan 2, 2006"))
+}
 
-	lastDay, err := getUntilDate()
+// parsePrimaryColor parses the given hex-encoded RGB color without leading
+// '#'.
+func parsePrimaryColor(s string) (color.RGBA, error) {
+	c, err := colorx.ParseHexColor(fmt.Sprintf("#%s", s))
 	if err != nil {
-		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", lastDay, err)
+		return color.RGBA{}, fmt.Errorf("invalid color specification '%s': %w", s, err)
 	}
-	logger.Debugw("Analyzing contributions",
-		"from", lastDay.AddDate(0, 0, -52*7+1),
-		"until", lastDay)
+	return c, nil
+}
 
-	data := make([]internal.ContributionRecord, 52*7)
-	for i := 0; i < 52*7; i++ {
-		data[i] = internal.ContributionRecord{
-			Date:  lastDay.AddDate(0, 0, -(52*7 - 1 - i)),
-			Count: 0,
-		}
+// checkLevels checks that the given number of color levels is supported.
+func checkLevels(levels uint) (uint8, error) {
+	if levels < 5 || levels > math.MaxUint8 {
+		return 0, fmt.Errorf("invalid number of color levels; allowed range is [5..%d]", math.MaxUint8)
 	}
+	return uint8(levels), nil
+}
 
-	if err := addCommitContributions(repositories, lastDay, &data); err != nil {
-		return err
+// checkCapPercentile checks that the given percentile counts are capped at is
+// within (0..100].
+func checkCapPercentile(percentile float64) (float64, error) {
+	if percentile <= 0 || percentile > 100 {
+		return 0, fmt.Errorf("invalid cap percentile %g; allowed range is (0..100]", percentile)
 	}
+	return percentile, nil
+}
 
-	if err := addIssueRelatedContributions(repositories, lastDay, &data); err != nil {
-		return err
-	}
+// graphStyle is the styling of contribution graphs.
+type graphStyle struct {
+	primaryColor  color.RGBA
+	interpolation internal.Interpolation
+	background    internal.Background
+	annotations   []internal.Annotation
+	layout        internal.Layout
+	levels        uint8
+	scaling       internal.Scaling
+	capPercentile float64
+	locale        string
+	text          internal.GraphText
+}
 
-	var buf bytes.Buffer
-	enc := xml.NewEncoder(&buf)
-	am := internal.NewContributionMap(data, lastDay, internal.GetColoring(getColorScheme(primaryColor)), uint8(levels))
-	err = am.Render(enc)
-	if err != nil {
-		return fmt.Errorf("rending SVG failed: %w", err)
+// getGraphStyle retrieves the styling of contribution graphs from the
+// configuration.
+func getGraphStyle() (graphStyle, error) {
+	var style graphStyle
+	var err error
+	if style.primaryColor, err = parsePrimaryColor(viper.GetString(colorCfgKey)); err != nil {
+		return style, err
 	}
-	err = enc.Flush()
-	if err != nil {
-		return fmt.Errorf("flushing SVG encoder failed: %w", err)
+	if style.interpolation, err = internal.ParseInterpolation(viper.GetString(interpolationCfgKey)); err != nil {
+		return style, err
 	}
-
-	filename := viper.GetString(filenameCfgKey)
-	f, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("can't create output file: %w", err)
-	}
-	defer f.Close()
-	if viper.GetBool(minifyOutputCfgKey) {
-		cmd.Printf("Minifying output\n")
-		m := minify.New()
-		m.AddFunc("image/svg+xml", svg.Minify)
-		if err := m.Minify("image/svg+xml", f, &buf); err != nil {
-			return fmt.Errorf("output minification failed: %w", err)
-		}
-	} else {
-		_, err := io.Copy(f, &buf)
-		if err != nil {
-			return fmt.Errorf("writing SVG to file failed: %w", err)
-		}
+	if style.background, err = getBackground(); err != nil {
+		return style, err
 	}
-	cmd.Printf("Contribution graph written to '%s'\n", filename)
-
-	return nil
+	if style.annotations, err = getAnnotations(); err != nil {
+		return style, err
+	}
+	if style.layout, err = internal.ParseLayout(viper.GetString(layoutCfgKey)); err != nil {
+		return style, err
+	}
+	if style.scaling, err = internal.ParseScaling(viper.GetString(scalingCfgKey)); err != nil {
+		return style, err
+	}
+	if style.capPercentile, err = checkCapPercentile(viper.GetFloat64(capPercentileCfgKey)); err != nil {
+		return style, err
+	}
+	style.locale = viper.GetString(localeCfgKey)
+	if style.text, err = internal.ParseGraphLocale(style.locale); err != nil {
+		return style, err
+	}
+	style.levels, err = checkLevels(viper.GetUint(levelsCfgKey))
+	return style, err
 }
 
-// addCommitContributions collects commits from the given repositories into the given contribution records.
-func addCommitContributions(repositories map[url.URL]*github.Repository, lastDay time.Time, records *[]internal.ContributionRecord) error {
-	for url, repository := range repositories {
-		logger.Debugw("Analyzing commit history", "repository", url.String())
-		if err := addCommitContributionsForRepo(repository, lastDay, records); err != nil {
-			return err
-		}
+// newGraph creates a contribution graph in the given style from the given
+// daily records of the 52 weeks ending with the given day.
+func (s graphStyle) newGraph(data []internal.ContributionRecord, lastDay time.Time) *internal.ContributionGraph {
+	am := internal.NewContributionMap(data, lastDay, internal.GetColoring(getColorScheme(s.primaryColor), s.interpolation), s.levels)
+	if now := time.Now().In(lastDay.Location()); viper.GetBool(highlightTodayCfgKey) && lastDay.Format("2006-01-02") == now.Format("2006-01-02") {
+		am.Highlighted = now
+	}
+	am.Background = s.background
+	am.NoTooltips = viper.GetBool(noTooltipsCfgKey)
+	am.Annotations = s.annotations
+	if viper.GetBool(anomaliesCfgKey) {
+		anomalies := internal.DetectAnomalies(data, viper.GetFloat64(anomalyThresholdCfgKey))
+		am.Annotations = append(internal.AnomalyAnnotations(anomalies), s.annotations...)
 	}
-	return nil
+	am.WeeklyTotals = viper.GetBool(weeklyTotalsCfgKey)
+	if viper.GetBool(showVelocityCfgKey) {
+		velocity := internal.NewVelocity(data)
+		am.Velocity = &velocity
+	}
+	am.AllWeekdays = viper.GetBool(allWeekdaysCfgKey)
+	am.Layout = s.layout
+	am.Scaling = s.scaling
+	am.CapPercentile = s.capPercentile
+	am.Text = s.text
+	return am
 }
 
-// addCommitContributionsForRepo collects commits from the given repository into the given contribution records.
-func addCommitContributionsForRepo(repository *github.Repository, lastDay time.Time, records *[]internal.ContributionRecord) error {
+func run(cmd *cobra.Command, args []string) error {
 
-	var auth *http.BasicAuth
-	if viper.IsSet(gitHubTokenCfgKey) {
-		auth = &http.BasicAuth{
-			Username: "ignore",
-			Password: viper.GetString(gitHubTokenCfgKey),
-		}
+	style, err := getGraphStyle()
+	if err != nil {
+		return classify(exitConfigError, err)
 	}
 
-	r, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
-		URL:  *repository.CloneURL,
-		Auth: auth,
-	})
-	if err != nil {
-		return err
+	compareRepos := viper.GetStringSlice(compareRepositoriesCfgKey)
+	comparePreviousYear := viper.GetBool(comparePreviousYearCfgKey)
+	if len(compareRepos) != 0 && comparePreviousYear {
+		return classify(exitConfigError,
+			errors.New("comparing with other repositories and the previous year at the same time is not supported"))
 	}
 
-	ref, err := r.Head()
+	contributor := viper.GetString(contributorCfgKey)
+	topContributors := viper.GetInt(topContributorsCfgKey)
+	if topContributors < 0 {
+		return classify(exitConfigError, fmt.Errorf("number of top contributors must not be negative but is %d", topContributors))
+	}
+	if contributor != "" && topContributors > 0 {
+		return classify(exitConfigError,
+			errors.New("generating graphs for a contributor and the top contributors at the same time is not supported"))
+	}
+	if (contributor != "" || topContributors > 0) && (len(compareRepos) != 0 || comparePreviousYear) {
+		return classify(exitConfigError, errors.New("comparisons are not supported for individual contributors"))
+	}
+
+	profiles, err := getProfiles(style, viper.GetString(filenameCfgKey))
 	if err != nil {
-		return err
+		return classify(exitConfigError, err)
+	}
+	if len(profiles) != 0 && (topContributors > 0 || len(compareRepos) != 0 || comparePreviousYear) {
+		return classify(exitConfigError, errors.New("profiles are not supported for top contributors and comparisons"))
 	}
 
-	since := lastDay.AddDate(0, 0, -52*7)
-	until := lastDay
-	commits, err := r.Log(&git.LogOptions{From: ref.Hash(), Since: &since, Until: &until})
+	var contributions []internal.Contribution
+	var lastDay time.Time
+	if len(profiles) != 0 {
+		contributions, lastDay, err = collectProfileContributions(cmd, profiles)
+	} else {
+		contributions, lastDay, err = collectContributions(cmd)
+	}
 	if err != nil {
 		return err
 	}
+	if contributor != "" {
+		contributions = internal.ByContributor(contributions, contributor)
+	}
+	data := internal.DailyRecords(contributions, lastDay)
 
-	// Parse commit filters
-	rawFilters := viper.GetStringSlice(commitFiltersCfgKey)
-	var filters []*vm.Program
-	for _, fs := range rawFilters {
-		filter, err := expr.Compile(fs, expr.Env(object.Commit{}), expr.AsBool())
+	newGraph := style.newGraph
+
+	write := func(renderer internal.Renderer, filename string) error {
+		filename, err := writeSVG(cmd, renderer, filename, viper.GetBool(minifyOutputCfgKey), viper.GetBool(gzipOutputCfgKey))
 		if err != nil {
-			return fmt.Errorf("invalid commit filter '%s': %w", fs, err)
+			return err
 		}
-		filters = append(filters, filter)
-	}
-	if len(filters) != 0 {
-		logger.Debugw("Applying commit filters", "filters", rawFilters)
+		cmd.Printf("Contribution graph written to '%s'\n", filename)
+		return nil
 	}
 
-	filteredCnt := 0
-	err = commits.ForEach(func(c *object.Commit) error {
-
-		// Apply commit filters
-		filtered := false
-		for _, filter := range filters {
-			result, err := expr.Run(filter, *c)
-			if err != nil {
-				return fmt.Errorf("failed to apply filter '%v': %w", filter, err)
-			}
-			if result.(bool) {
-				filtered = true
-				break
+	if len(profiles) != 0 {
+		for _, p := range profiles {
+			graph := p.style.newGraph(internal.DailyRecords(p.contributions(contributions), lastDay), lastDay)
+			if err := write(graph, p.filename); err != nil {
+				return err
 			}
 		}
+		return nil
+	}
 
-		if !filtered {
-			i := 52*7 - 1 - internal.DaysBetween(c.Committer.When, lastDay)
-			(*records)[i].Count++
-		} else {
-			filteredCnt++
+	if topContributors > 0 {
+		for _, c := range internal.TopContributors(contributions, lastDay, topContributors) {
+			graph := newGraph(internal.DailyRecords(internal.ByContributor(contributions, c), lastDay), lastDay)
+			if err := write(graph, suffixedFilename(viper.GetString(filenameCfgKey), c)); err != nil {
+				return err
+			}
 		}
 		return nil
-	})
-	if err != nil {
-		return err
 	}
-	logger.Debugw("Filtered commits", "count", filteredCnt)
 
-	return nil
-}
-
-// addIssueRelatedContributions adds opened issues and PRs to the contribution records.
-func addIssueRelatedContributions(repositories map[url.URL]*github.Repository, lastDay time.Time, records *[]internal.ContributionRecord) error {
-	ctx := context.Background()
-	client := github.NewClient(getHTTPClient())
-	for _, repository := range repositories {
-		owner := repository.GetOwner().GetLogin()
-		repo := repository.GetName()
-		opt := &github.IssueListByRepoOptions{
-			Since:       lastDay.AddDate(0, 0, -52*7),
-			State:       "all",
-			ListOptions: github.ListOptions{PerPage: 100},
+	graph := newGraph(data, lastDay)
+	var renderer internal.Renderer = graph
+	switch {
+	case len(compareRepos) != 0:
+		otherData, err := collectContributionRecordsFor(cmd, compareRepos, lastDay)
+		if err != nil {
+			return err
 		}
-		var allIssues []*github.Issue
-		for {
-			issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opt)
-			if err != nil {
-				return err
-			}
-			if resp.StatusCode != 200 {
-				return fmt.Errorf("fetching issues for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
-			}
-			allIssues = append(allIssues, issues...)
-			if resp.NextPage == 0 {
-				break
-			}
-			opt.Page = resp.NextPage
+		renderer, err = internal.NewComparison(
+			[]*internal.ContributionGraph{graph, newGraph(otherData, lastDay)},
+			[]string{strings.Join(viper.GetStringSlice(repositoriesCfgKey), ", "), strings.Join(compareRepos, ", ")})
+		if err != nil {
+			return err
 		}
-		for _, issue := range allIssues {
-			idx := 52*7 - 1 - internal.DaysBetween(issue.CreatedAt.Time, lastDay)
-			if idx < 0 {
-				continue
-			}
-			(*records)[idx].Count++
+	case comparePreviousYear:
+		previousLastDay := lastDay.AddDate(0, 0, -52*7)
+		otherData, err := collectContributionRecordsFor(cmd, viper.GetStringSlice(repositoriesCfgKey), previousLastDay)
+		if err != nil {
+			return err
+		}
+		renderer, err = internal.NewComparison(
+			[]*internal.ContributionGraph{graph, newGraph(otherData, previousLastDay)},
+			[]string{periodTitle(lastDay), periodTitle(previousLastDay)})
+		if err != nil {
+			return err
 		}
 	}
-	return nil
+
+	return write(renderer, viper.GetString(filenameCfgKey))
 }
 
 // Initialize the 'contribution-graph' command.
 func init() {
 	rootCmd.AddCommand(contributionGraphCmd)
 
-	// Flag to set the last day for which data is visualized
-	const untilFlag = "until"
-	contributionGraphCmd.Flags().StringP(
-		untilFlag,
-		"u",
-		time.Now().Format("2006-01-02"),
-		"Date of last day for which data is visualized")
-	if err := viper.BindPFlag(untilCfgKey, contributionGraphCmd.Flags().Lookup(untilFlag)); err != nil {
-		logger.Fatalw("Can't bind to flag", "Flag", untilFlag, "Error", err)
-	}
-
 	// Flag to control output minification
 	const minifyOutputFlag = "minify"
 	contributionGraphCmd.Flags().BoolP(
@@ -330,6 +388,16 @@ func init() {
 		logger.Fatalw("Can't bind to flag", "Flag", minifyOutputFlag, "Error", err)
 	}
 
+	// Flag to control output compression
+	const gzipOutputFlag = "gzip"
+	contributionGraphCmd.Flags().Bool(
+		gzipOutputFlag,
+		false,
+		"Flag to toggle writing a gzip-compressed SVG document (.svgz)")
+	if err := viper.BindPFlag(gzipOutputCfgKey, contributionGraphCmd.Flags().Lookup(gzipOutputFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", gzipOutputFlag, "Error", err)
+	}
+
 	// Flag to control the primary cell color
 	const colorFlag = "color"
 	contributionGraphCmd.Flags().String(
@@ -351,6 +419,16 @@ func init() {
 		logger.Fatalw("Can't bind to flag", "Flag", levelsFlag, "Error", err)
 	}
 
+	// Flag to control the color space used for interpolating cell colors
+	const interpolationFlag = "interpolation"
+	contributionGraphCmd.Flags().String(
+		interpolationFlag,
+		internal.RGBInterpolationName,
+		"The color space used for interpolating cell colors (rgb, hsl, or lab)")
+	if err := viper.BindPFlag(interpolationCfgKey, contributionGraphCmd.Flags().Lookup(interpolationFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", interpolationFlag, "Error", err)
+	}
+
 	// Flag to control commit filters used to exclude them from the contributions
 	const commitFiltersFlag = "commit-filters"
 	contributionGraphCmd.Flags().StringSlice(
@@ -361,6 +439,170 @@ func init() {
 		logger.Fatalw("Can't bind to flag", "Flag", commitFiltersFlag, "Error", err)
 	}
 
+	// Flag to control highlighting of the cell representing today
+	const highlightTodayFlag = "highlight-today"
+	contributionGraphCmd.Flags().Bool(
+		highlightTodayFlag,
+		true,
+		"Flag to toggle outlining the cell of today if it is part of the graph")
+	if err := viper.BindPFlag(highlightTodayCfgKey, contributionGraphCmd.Flags().Lookup(highlightTodayFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", highlightTodayFlag, "Error", err)
+	}
+
+	// Flags to control the background colors
+	const lightBackgroundFlag = "background-light"
+	contributionGraphCmd.Flags().String(
+		lightBackgroundFlag,
+		transparent,
+		"The background color used in light mode (hex-encoded RGB or 'transparent')")
+	if err := viper.BindPFlag(lightBackgroundCfgKey, contributionGraphCmd.Flags().Lookup(lightBackgroundFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", lightBackgroundFlag, "Error", err)
+	}
+	const darkBackgroundFlag = "background-dark"
+	contributionGraphCmd.Flags().String(
+		darkBackgroundFlag,
+		transparent,
+		"The background color used in dark mode (hex-encoded RGB or 'transparent')")
+	if err := viper.BindPFlag(darkBackgroundCfgKey, contributionGraphCmd.Flags().Lookup(darkBackgroundFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", darkBackgroundFlag, "Error", err)
+	}
+
+	// Flag to omit the tooltip overlay
+	const noTooltipsFlag = "no-tooltips"
+	contributionGraphCmd.Flags().Bool(
+		noTooltipsFlag,
+		false,
+		"Flag to omit the tooltip overlay for static embeddings")
+	if err := viper.BindPFlag(noTooltipsCfgKey, contributionGraphCmd.Flags().Lookup(noTooltipsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", noTooltipsFlag, "Error", err)
+	}
+
+	// Flag to toggle the weekly totals bar chart
+	const weeklyTotalsFlag = "weekly-totals"
+	contributionGraphCmd.Flags().Bool(
+		weeklyTotalsFlag,
+		false,
+		"Flag to toggle a bar chart of weekly contribution totals beneath the heatmap")
+	if err := viper.BindPFlag(weeklyTotalsCfgKey, contributionGraphCmd.Flags().Lookup(weeklyTotalsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", weeklyTotalsFlag, "Error", err)
+	}
+
+	// Flag to toggle rendering the velocity trend
+	const showVelocityFlag = "show-velocity"
+	contributionGraphCmd.Flags().Bool(
+		showVelocityFlag,
+		false,
+		"Flag to toggle rendering the trend of the weekly contribution volume next to the overall number of contributions")
+	if err := viper.BindPFlag(showVelocityCfgKey, contributionGraphCmd.Flags().Lookup(showVelocityFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", showVelocityFlag, "Error", err)
+	}
+
+	// Flag to toggle annotating days with unusual activity
+	const anomaliesFlag = "anomalies"
+	contributionGraphCmd.Flags().Bool(
+		anomaliesFlag,
+		false,
+		"Flag to toggle annotating days with an unusually high number of contributions")
+	if err := viper.BindPFlag(anomaliesCfgKey, contributionGraphCmd.Flags().Lookup(anomaliesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", anomaliesFlag, "Error", err)
+	}
+
+	// Flag to control the anomaly threshold
+	const anomalyThresholdFlag = "anomaly-threshold"
+	contributionGraphCmd.Flags().Float64(
+		anomalyThresholdFlag,
+		internal.DefaultAnomalyThreshold,
+		"The number of standard deviations above the mean of the preceding four weeks a day is considered unusual at")
+	if err := viper.BindPFlag(anomalyThresholdCfgKey, contributionGraphCmd.Flags().Lookup(anomalyThresholdFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", anomalyThresholdFlag, "Error", err)
+	}
+
+	// Flag to toggle labeling all weekdays
+	const allWeekdaysFlag = "all-weekdays"
+	contributionGraphCmd.Flags().Bool(
+		allWeekdaysFlag,
+		false,
+		"Flag to toggle labeling all days of the week instead of Mon, Wed, and Fri only")
+	if err := viper.BindPFlag(allWeekdaysCfgKey, contributionGraphCmd.Flags().Lookup(allWeekdaysFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", allWeekdaysFlag, "Error", err)
+	}
+
+	// Flag to control the arrangement of the daily cells
+	const layoutFlag = "layout"
+	contributionGraphCmd.Flags().String(
+		layoutFlag,
+		internal.HeatmapLayoutName,
+		"The arrangement of the daily cells (heatmap or calendar)")
+	if err := viper.BindPFlag(layoutCfgKey, contributionGraphCmd.Flags().Lookup(layoutFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", layoutFlag, "Error", err)
+	}
+
+	// Flags to control the mapping of counts to color intensities
+	const scalingFlag = "scaling"
+	contributionGraphCmd.Flags().String(
+		scalingFlag,
+		internal.LinearScalingName,
+		"The mapping of contribution counts to color intensities (linear or winsorized)")
+	if err := viper.BindPFlag(scalingCfgKey, contributionGraphCmd.Flags().Lookup(scalingFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", scalingFlag, "Error", err)
+	}
+	const capPercentileFlag = "cap-percentile"
+	contributionGraphCmd.Flags().Float64(
+		capPercentileFlag,
+		internal.DefaultCapPercentile,
+		"The percentile of the non-zero daily counts the counts are capped at by the winsorized scaling")
+	if err := viper.BindPFlag(capPercentileCfgKey, contributionGraphCmd.Flags().Lookup(capPercentileFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", capPercentileFlag, "Error", err)
+	}
+
+	// Flag to control the locale
+	const localeFlag = "locale"
+	contributionGraphCmd.Flags().String(
+		localeFlag,
+		internal.EnglishLocaleName,
+		"The locale of the labels and dates of the graph (en, de, fr, or es)")
+	if err := viper.BindPFlag(localeCfgKey, contributionGraphCmd.Flags().Lookup(localeFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", localeFlag, "Error", err)
+	}
+
+	// Flags to render a comparison with another dataset
+	const compareRepositoriesFlag = "compare-repositories"
+	contributionGraphCmd.Flags().StringSlice(
+		compareRepositoriesFlag,
+		nil,
+		"Repositories whose contributions are compared with the analyzed ones")
+	if err := viper.BindPFlag(compareRepositoriesCfgKey, contributionGraphCmd.Flags().Lookup(compareRepositoriesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", compareRepositoriesFlag, "Error", err)
+	}
+	const comparePreviousYearFlag = "compare-previous-year"
+	contributionGraphCmd.Flags().Bool(
+		comparePreviousYearFlag,
+		false,
+		"Flag to toggle comparing the contributions with those of the previous year")
+	if err := viper.BindPFlag(comparePreviousYearCfgKey, contributionGraphCmd.Flags().Lookup(comparePreviousYearFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", comparePreviousYearFlag, "Error", err)
+	}
+
+	// Flag to select a single contributor
+	const contributorFlag = "contributor"
+	contributionGraphCmd.Flags().String(
+		contributorFlag,
+		"",
+		"The GitHub login (or commit email address) of the contributor whose contributions are visualized")
+	if err := viper.BindPFlag(contributorCfgKey, contributionGraphCmd.Flags().Lookup(contributorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", contributorFlag, "Error", err)
+	}
+
+	// Flag to generate graphs for the top contributors
+	const topContributorsFlag = "top-contributors"
+	contributionGraphCmd.Flags().Int(
+		topContributorsFlag,
+		0,
+		"The number of top contributors an individual graph is generated for")
+	if err := viper.BindPFlag(topContributorsCfgKey, contributionGraphCmd.Flags().Lookup(topContributorsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topContributorsFlag, "Error", err)
+	}
+
 	const outputFilenameFlag = "output-filename"
 	contributionGraphCmd.Flags().StringP(
 		outputFilenameFlag,
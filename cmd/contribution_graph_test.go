@@ -11,14 +11,18 @@ import (
 	"fmt"
 	"github.com/araddon/dateparse"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 	"golang.org/x/exp/rand"
-	"herdstat/internal"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sync/atomic"
 	"time"
 )
 
@@ -72,7 +76,7 @@ func createCommit(r *git.Repository, time time.Time) error {
 
 var _ = Describe("Analyzing commits", func() {
 
-	logger = configureLogger()
+	logger, _ = configureLogger()
 
 	When("given a repo with a commit on a specific day", func() {
 		It("updates the contribution records accordingly", func() {
@@ -86,16 +90,340 @@ var _ = Describe("Analyzing commits", func() {
 			}
 			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
 			Expect(err).NotTo(HaveOccurred())
-			data := make([]internal.ContributionRecord, 52*7)
-			for i := 0; i < 52*7; i++ {
-				data[i] = internal.ContributionRecord{
-					Date:  lastDay.AddDate(0, 0, -(52*7 - 1 - i)),
-					Count: 0,
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			data := internal.DailyRecords(contributions, lastDay)
+			Expect(data[52*7-1].Count).To(Equal(1))
+		})
+	})
+
+	When("collecting churn", func() {
+		It("records the number of added and removed lines", func() {
+			r, url, err := createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			w, err := r.Worktree()
+			Expect(err).NotTo(HaveOccurred())
+			commitTime := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+			for i, content := range []string{"a\nb\nc\n", "a\nx\nc\n"} {
+				Expect(os.WriteFile(w.Filesystem.Root()+"/file", []byte(content), 0644)).To(Succeed())
+				_, err = w.Add("file")
+				Expect(err).NotTo(HaveOccurred())
+				sig := signature(commitTime.Add(time.Duration(i) * time.Hour))
+				_, err = w.Commit("Lorem ipsum", &git.CommitOptions{Author: sig, Committer: sig})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			repo := &github.Repository{
+				CloneURL: github.String(url.String()),
+			}
+			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{churn: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(2))
+			churn := internal.NewChurnReport(contributions, lastDay).Overall
+			Expect(churn.Additions).To(Equal(4))
+			Expect(churn.Deletions).To(Equal(1))
+			Expect(contributions[0].Files).To(Equal([]string{"file"}))
+		})
+	})
+
+	When("collecting changed files", func() {
+		It("records the paths of the changed files", func() {
+			r, url, err := createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			commitTime := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+			Expect(createCommit(r, commitTime)).To(Succeed())
+			repo := &github.Repository{
+				CloneURL: github.String(url.String()),
+			}
+			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{files: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(1))
+			Expect(contributions[0].Files).To(HaveLen(1))
+		})
+	})
+
+	When("cloning shallowly", func() {
+		It("deepens the clone until the analyzed period is covered", func() {
+			r, url, err := createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			commitTime := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+			for i := 10; i > 0; i-- {
+				Expect(createCommit(r, commitTime.AddDate(0, 0, -i*30))).To(Succeed())
+			}
+			repo := &github.Repository{
+				CloneURL: github.String(url.String()),
+			}
+			depth := viper.GetInt(cloneDepthCfgKey)
+			viper.Set(cloneDepthCfgKey, 2)
+			DeferCleanup(viper.Set, cloneDepthCfgKey, depth)
+			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -200), lastDay, commitDetails{files: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(6))
+		})
+	})
+
+	When("cloning large repositories", func() {
+		It("clones into a temporary directory removed afterwards", func() {
+			r, url, err := createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			commitTime := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+			Expect(createCommit(r, commitTime)).To(Succeed())
+			repo := &github.Repository{
+				CloneURL: github.String(url.String()),
+				Size:     github.Int(2048),
+			}
+			threshold := viper.GetInt(maxMemoryCloneSizeCfgKey)
+			viper.Set(maxMemoryCloneSizeCfgKey, 1)
+			DeferCleanup(viper.Set, maxMemoryCloneSizeCfgKey, threshold)
+			tmp := GinkgoT().TempDir()
+			GinkgoT().Setenv("TMPDIR", tmp)
+			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -7), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(1))
+			entries, err := os.ReadDir(tmp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	When("sampling oversized repositories", func() {
+		It("only collects the most recent commits", func() {
+			r, url, err := createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			commitTime := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+			for i := 5; i > 0; i-- {
+				Expect(createCommit(r, commitTime.AddDate(0, 0, -i))).To(Succeed())
+			}
+			repo := &github.Repository{
+				CloneURL: github.String(url.String()),
+				Size:     github.Int(2048),
+			}
+			for key, value := range map[string]interface{}{
+				maxRepositorySizeCfgKey:     1,
+				oversizedRepositoriesCfgKey: sampleOversized,
+				cloneDepthCfgKey:            2,
+			} {
+				DeferCleanup(viper.Set, key, viper.Get(key))
+				viper.Set(key, value)
+			}
+			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -7), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(2))
+		})
+	})
+
+	When("the default branch is missing", func() {
+		var (
+			r       *git.Repository
+			repo    *github.Repository
+			lastDay time.Time
+		)
+
+		BeforeEach(func() {
+			var url *url.URL
+			var err error
+			r, url, err = createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			repo = &github.Repository{
+				CloneURL: github.String(url.String()),
+			}
+			lastDay, err = dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("falls back to an existing branch if the reported one doesn't exist", func() {
+			Expect(createCommit(r, lastDay.Add(-time.Hour))).To(Succeed())
+			repo.DefaultBranch = github.String("main")
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -7), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(1))
+		})
+
+		It("falls back to an existing branch if HEAD is unborn", func() {
+			Expect(createCommit(r, lastDay.Add(-time.Hour))).To(Succeed())
+			master, err := r.Reference(plumbing.Master, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Storer.SetReference(plumbing.NewHashReference("refs/heads/develop", master.Hash()))).To(Succeed())
+			Expect(r.Storer.RemoveReference(plumbing.Master)).To(Succeed())
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -7), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(1))
+		})
+
+		It("skips repositories without branches", func() {
+			repo.DefaultBranch = github.String("main")
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -7), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(BeEmpty())
+		})
+	})
+
+	When("using a clone cache", func() {
+		var (
+			r       *git.Repository
+			repo    *github.Repository
+			lastDay time.Time
+		)
+		commitTime := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			var url *url.URL
+			var err error
+			r, url, err = createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			for i := 10; i > 0; i-- {
+				Expect(createCommit(r, commitTime.AddDate(0, 0, -i*30))).To(Succeed())
+			}
+			repo = &github.Repository{
+				CloneURL: github.String(url.String()),
+			}
+			viper.Set(cloneCacheDirCfgKey, GinkgoT().TempDir())
+			DeferCleanup(viper.Set, cloneCacheDirCfgKey, "")
+			lastDay, err = dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		// Populate the cache after the clone depth has been set
+		JustBeforeEach(func() {
+			contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -200), lastDay, commitDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(6))
+			path, err := cachedClonePath(viper.GetString(cloneCacheDirCfgKey), repo.GetCloneURL())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(path, "HEAD")).To(BeAnExistingFile())
+		})
+
+		When("cloning completely", func() {
+			BeforeEach(func() {
+				depth := viper.GetInt(cloneDepthCfgKey)
+				viper.Set(cloneDepthCfgKey, 0)
+				DeferCleanup(viper.Set, cloneDepthCfgKey, depth)
+			})
+
+			It("fetches new commits into the cached clone", func() {
+				Expect(createCommit(r, commitTime)).To(Succeed())
+				contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -200), lastDay, commitDetails{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contributions).To(HaveLen(7))
+			})
+		})
+
+		When("cloning shallowly", func() {
+			BeforeEach(func() {
+				depth := viper.GetInt(cloneDepthCfgKey)
+				viper.Set(cloneDepthCfgKey, 2)
+				DeferCleanup(viper.Set, cloneDepthCfgKey, depth)
+			})
+
+			It("clones the default branch again once it has changed", func() {
+				master, err := r.Reference(plumbing.Master, false)
+				Expect(err).NotTo(HaveOccurred())
+				develop := plumbing.NewBranchReferenceName("develop")
+				Expect(r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, develop))).To(Succeed())
+				Expect(r.Storer.SetReference(plumbing.NewHashReference(develop, master.Hash()))).To(Succeed())
+				Expect(createCommit(r, commitTime)).To(Succeed())
+				repo.DefaultBranch = github.String("develop")
+				contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -200), lastDay, commitDetails{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contributions).To(HaveLen(7))
+			})
+
+			It("covers new commits and longer periods", func() {
+				Expect(createCommit(r, commitTime)).To(Succeed())
+				contributions, err := collectCommitContributionsForRepo(repo, lastDay.AddDate(0, 0, -290), lastDay, commitDetails{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contributions).To(HaveLen(10))
+			})
+		})
+	})
+
+	When("using commit activity statistics", func() {
+		It("creates a contribution per commit within the analyzed period", func() {
+			repo := &github.Repository{FullName: github.String("herdstat/herdstat")}
+			activity := []*github.WeeklyCommitActivity{
+				{
+					Week: &github.Timestamp{Time: time.Date(2013, time.April, 14, 0, 0, 0, 0, time.UTC)},
+					Days: []int{1, 0, 0, 0, 0, 0, 2},
+				},
+				{
+					Week: &github.Timestamp{Time: time.Date(2013, time.April, 21, 0, 0, 0, 0, time.UTC)},
+					Days: []int{0, 3, 0, 4, 0, 0, 0},
+				},
+			}
+			lastDay, err := dateparse.ParseStrict("2013-04-22 23:59")
+			Expect(err).NotTo(HaveOccurred())
+			contributions := commitActivityContributions(repo, activity, lastDay.AddDate(0, 0, -7), lastDay)
+			Expect(contributions).To(HaveLen(5))
+			data := internal.DailyRecords(contributions, lastDay)
+			Expect(data[52*7-1].Count).To(Equal(3))
+			Expect(data[52*7-3].Count).To(Equal(2))
+			Expect(contributions[0].Repository).To(Equal("herdstat/herdstat"))
+		})
+	})
+
+	When("collecting multiple repositories", func() {
+		It("collects at most the configured number of repositories concurrently", func() {
+			concurrency := viper.GetInt(maxConcurrencyCfgKey)
+			viper.Set(maxConcurrencyCfgKey, 2)
+			DeferCleanup(viper.Set, maxConcurrencyCfgKey, concurrency)
+			repositories := make(map[url.URL]*github.Repository)
+			for i := 0; i < 6; i++ {
+				name := fmt.Sprintf("herdstat/repo-%d", i)
+				repositories[url.URL{Path: name}] = &github.Repository{FullName: github.String(name)}
+			}
+			var running, maxRunning atomic.Int32
+			contributions, err := collectPerRepository(repositories, "Testing", "test", func(_ url.URL, repository *github.Repository) ([]internal.Contribution, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
 				}
+				time.Sleep(10 * time.Millisecond)
+				return []internal.Contribution{{Repository: repository.GetFullName()}}, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(6))
+			Expect(maxRunning.Load()).To(BeNumerically("<=", 2))
+		})
+
+		It("counts commits contained in both a fork and its upstream repository once if deduplicating", func() {
+			viper.Set(deduplicateForkCommitsCfgKey, true)
+			DeferCleanup(viper.Set, deduplicateForkCommitsCfgKey, false)
+			r, u, err := createRepository()
+			Expect(err).NotTo(HaveOccurred())
+			lastDay := time.Date(2013, time.April, 22, 23, 59, 0, 0, time.UTC)
+			for i := 3; i > 0; i-- {
+				Expect(createCommit(r, lastDay.AddDate(0, 0, -i))).To(Succeed())
 			}
-			err = addCommitContributionsForRepo(repo, lastDay, &data)
+			repositories := map[url.URL]*github.Repository{
+				{Path: "herdstat/herdstat"}: {
+					FullName: github.String("herdstat/herdstat"),
+					CloneURL: github.String(u.String()),
+				},
+				{Path: "herdstat/herdstat-fork"}: {
+					FullName: github.String("herdstat/herdstat-fork"),
+					CloneURL: github.String(u.String()),
+					Fork:     github.Bool(true),
+				},
+			}
+			contributions, err := collectCommitContributions(repositories, lastDay.AddDate(0, 0, -7), lastDay, commitDetails{})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(data[52*7-1].Count).To(Equal(1))
+			Expect(contributions).To(HaveLen(3))
+			for _, c := range contributions {
+				Expect(c.Repository).To(Equal("herdstat/herdstat"))
+			}
 		})
 	})
 })
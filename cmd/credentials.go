@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ghHost is the host whose gh CLI credentials are used.
+const ghHost = "github.com"
+
+var (
+	// Guards looking up the token of the gh CLI once per run
+	ghTokenOnce sync.Once
+
+	// The token of the gh CLI, empty if unavailable
+	ghToken string
+)
+
+// getGitHubToken returns the token used to access GitHub. This is the
+// configured token if given. Otherwise, the token the gh CLI is logged in
+// with is used if enabled (see lookupGhToken). An empty string is returned if
+// no token is available.
+func getGitHubToken() string {
+	if token := viper.GetString(gitHubTokenCfgKey); token != "" {
+		return token
+	}
+	if !viper.GetBool(ghCredentialsCfgKey) {
+		return ""
+	}
+	ghTokenOnce.Do(func() {
+		ghToken = lookupGhToken()
+		if ghToken != "" {
+			logger.Debug("Using the token of the gh CLI")
+		}
+	})
+	return ghToken
+}
+
+// lookupGhToken returns the token the gh CLI is logged in with. It's asked
+// for by 'gh auth token', which covers tokens stored in the OS keyring. If gh
+// isn't installed, the token is read from the gh configuration instead. An
+// empty string is returned if gh isn't logged in.
+func lookupGhToken() string {
+	out, err := exec.Command("gh", "auth", "token", "--hostname", ghHost).Output()
+	if err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	logger.Debugw("Can't get token from gh CLI - reading its configuration", "Error", err)
+	token, err := readGhHostsToken(filepath.Join(ghConfigDir(), "hosts.yml"))
+	if err != nil {
+		logger.Debugw("Can't read token from gh configuration", "Error", err)
+		return ""
+	}
+	return token
+}
+
+// ghConfigDir returns the configuration directory of the gh CLI.
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if dir := os.Getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// readGhHostsToken reads the token of the GitHub host from the given hosts
+// file of the gh CLI. An empty string is returned if the host has no token,
+// e.g., because it's stored in the OS keyring.
+func readGhHostsToken(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", err
+	}
+	return hosts[ghHost].OAuthToken, nil
+}
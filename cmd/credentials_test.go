@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var _ = Describe("Credentials", func() {
+	It("prefers the configured token", func() {
+		viper.Set(gitHubTokenCfgKey, "configured")
+		DeferCleanup(viper.Set, gitHubTokenCfgKey, "")
+		Expect(getGitHubToken()).To(Equal("configured"))
+	})
+
+	It("reads the token of the gh CLI from its configuration directory", func() {
+		dir := GinkgoT().TempDir()
+		GinkgoT().Setenv("GH_CONFIG_DIR", dir)
+		hosts := "github.com:\n  user: herdstat\n  oauth_token: gho_xxx\n  git_protocol: https\n"
+		Expect(os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600)).To(Succeed())
+		Expect(readGhHostsToken(filepath.Join(ghConfigDir(), "hosts.yml"))).To(Equal("gho_xxx"))
+	})
+
+	It("reads no token for hosts whose token is stored in the OS keyring", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "hosts.yml")
+		Expect(os.WriteFile(filename, []byte("github.com:\n  user: herdstat\n"), 0o600)).To(Succeed())
+		Expect(readGhHostsToken(filename)).To(BeEmpty())
+	})
+
+	Context("resolving the token", func() {
+		const gitHubTokenFlag = "github-token"
+
+		BeforeEach(func() {
+			// Tokens set directly take precedence over all other sources
+			viper.Set(gitHubTokenCfgKey, nil)
+			DeferCleanup(viper.Set, gitHubTokenCfgKey, "")
+			DeferCleanup(viper.Set, ghCredentialsCfgKey, viper.GetBool(ghCredentialsCfgKey))
+			viper.Set(ghCredentialsCfgKey, true)
+			GinkgoT().Setenv("GH_TOKEN", "")
+			GinkgoT().Setenv("GITHUB_TOKEN", "")
+			// Fall back to the gh configuration as there is no gh CLI on the path
+			GinkgoT().Setenv("PATH", GinkgoT().TempDir())
+			dir := GinkgoT().TempDir()
+			GinkgoT().Setenv("GH_CONFIG_DIR", dir)
+			hosts := "github.com:\n  user: herdstat\n  oauth_token: gho_xxx\n"
+			Expect(os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600)).To(Succeed())
+			ghTokenOnce, ghToken = sync.Once{}, ""
+			DeferCleanup(func() {
+				ghTokenOnce, ghToken = sync.Once{}, ""
+			})
+		})
+
+		It("prefers the token given by flag", func() {
+			flag := rootCmd.PersistentFlags().Lookup(gitHubTokenFlag)
+			Expect(flag.Value.Set("ghp_flag")).To(Succeed())
+			flag.Changed = true
+			DeferCleanup(func() {
+				Expect(flag.Value.Set("")).To(Succeed())
+				flag.Changed = false
+			})
+			GinkgoT().Setenv("GH_TOKEN", "ghp_gh")
+			GinkgoT().Setenv("GITHUB_TOKEN", "ghp_github")
+			Expect(getGitHubToken()).To(Equal("ghp_flag"))
+		})
+		It("prefers GH_TOKEN over GITHUB_TOKEN", func() {
+			GinkgoT().Setenv("GH_TOKEN", "ghp_gh")
+			GinkgoT().Setenv("GITHUB_TOKEN", "ghp_github")
+			Expect(getGitHubToken()).To(Equal("ghp_gh"))
+		})
+		It("prefers GITHUB_TOKEN over the token of the gh CLI", func() {
+			GinkgoT().Setenv("GITHUB_TOKEN", "ghp_github")
+			Expect(getGitHubToken()).To(Equal("ghp_github"))
+		})
+		It("falls back to the token of the gh CLI", func() {
+			Expect(getGitHubToken()).To(Equal("gho_xxx"))
+		})
+		It("uses no token if the token of the gh CLI is disabled", func() {
+			viper.Set(ghCredentialsCfgKey, false)
+			Expect(getGitHubToken()).To(BeEmpty())
+		})
+	})
+})
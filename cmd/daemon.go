@@ -0,0 +1,342 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Configuration keys for the daemon command
+const (
+
+	// The cron expression giving the schedule of the runs
+	daemonScheduleCfgKey = "daemon.schedule"
+
+	// The commands run on schedule
+	daemonCommandsCfgKey = "daemon.commands"
+
+	// The address the health and metrics endpoints are served on
+	daemonAddressCfgKey = "daemon.address"
+
+	// Whether to run the commands immediately on start
+	daemonRunOnStartCfgKey = "daemon.run-on-start"
+)
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Regenerates the configured outputs on a schedule",
+	Long: `Runs the configured commands on the schedule given by a cron expression
+(e.g., '0 3 * * *' or '@daily', evaluated in local time) until interrupted, for
+running herdstat as a long-running service, e.g., in Kubernetes. The commands
+are run one after another with the settings of the configuration file, and
+their outputs are published and uploaded after each run as configured. Unless
+the 'until' parameter is given explicitly, each run analyzes the data up to the
+day of the run.
+
+The following endpoints are served on the configured address:
+
+  /healthz  responds with 200 while the daemon is running
+  /readyz   responds with 200 once every command has succeeded at least once
+  /metrics  exposes run statistics and the community metrics of the last run
+            in the Prometheus text format`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	schedule, err := internal.ParseSchedule(viper.GetString(daemonScheduleCfgKey))
+	if err != nil {
+		return err
+	}
+	commands, err := scheduledCommands()
+	if err != nil {
+		return err
+	}
+	status := newDaemonStatus(commands)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if address := viper.GetString(daemonAddressCfgKey); address != "" {
+		httpServer := &http.Server{
+			Addr:              address,
+			Handler:           status,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Warnw("Serving health and metrics endpoints failed", "address", address, "Error", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+			defer cancel()
+			_ = httpServer.Shutdown(shutdownCtx)
+		}()
+	}
+
+	// Analyze up to the day of each run unless the last day is given
+	followToday := !viper.IsSet(untilCfgKey)
+	runNow := viper.GetBool(daemonRunOnStartCfgKey)
+	for {
+		if !runNow {
+			next := schedule.Next(time.Now())
+			if next.IsZero() {
+				return fmt.Errorf("schedule '%s' never matches", viper.GetString(daemonScheduleCfgKey))
+			}
+			status.setNextRun(next)
+			cmd.Printf("Next run scheduled for %s\n", next.Format(time.RFC1123))
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+		}
+		runNow = false
+		if followToday {
+			viper.Set(untilCfgKey, today())
+		}
+		for _, c := range commands {
+			if ctx.Err() != nil {
+				return nil
+			}
+			start := time.Now()
+			metrics, err := runScheduledCommand(c)
+			status.record(c.Name(), start, metrics, err)
+			if err != nil {
+				logger.Warnw("Scheduled command failed", "command", c.Name(), "Error", err)
+			}
+		}
+	}
+}
+
+// scheduledCommands returns the configured commands to run on schedule.
+func scheduledCommands() ([]*cobra.Command, error) {
+	names := viper.GetStringSlice(daemonCommandsCfgKey)
+	if len(names) == 0 {
+		return nil, classify(exitConfigError, errors.New("no commands to run on schedule configured"))
+	}
+	var commands []*cobra.Command
+	for _, name := range names {
+		c, rest, err := rootCmd.Find([]string{name})
+		// Long-running commands never complete and can't be scheduled
+		if err != nil || c == rootCmd || len(rest) != 0 || c.RunE == nil ||
+			c.Name() == "daemon" || c.Name() == "serve" {
+			return nil, fmt.Errorf("'%s' is not a command that can be run on schedule", name)
+		}
+		commands = append(commands, c)
+	}
+	return commands, nil
+}
+
+// runScheduledCommand runs the given command and processes its results like
+// after a regular invocation (e.g., publishing generated files). Returns the
+// community metrics of the run (see runResults.writeMetrics), nil if none are
+// available.
+func runScheduledCommand(c *cobra.Command) ([]byte, error) {
+	results.reset()
+	// Each run gets the full budget of GitHub API requests
+	apiRequests.Store(0)
+	// Don't process the results of the last run again when the daemon exits
+	defer results.reset()
+	if err := c.RunE(c, nil); err != nil {
+		return nil, err
+	}
+	if err := rootCmd.PersistentPostRunE(c, nil); err != nil {
+		return nil, err
+	}
+	var metrics bytes.Buffer
+	if !results.writeMetrics(&metrics) {
+		return nil, nil
+	}
+	return metrics.Bytes(), nil
+}
+
+// commandStatus is the status of a command run on schedule.
+type commandStatus struct {
+	name        string
+	successes   int
+	failures    int
+	lastRun     time.Time
+	lastSuccess time.Time
+	duration    time.Duration
+}
+
+// daemonStatus is the status of the daemon served by the health and metrics
+// endpoints.
+type daemonStatus struct {
+	mutex    sync.Mutex
+	commands []*commandStatus
+	nextRun  time.Time
+
+	// The community metrics of the last succeeded run providing them
+	metrics []byte
+}
+
+// newDaemonStatus creates the status of a daemon running the given commands.
+func newDaemonStatus(commands []*cobra.Command) *daemonStatus {
+	status := &daemonStatus{}
+	for _, c := range commands {
+		status.commands = append(status.commands, &commandStatus{name: c.Name()})
+	}
+	return status
+}
+
+// setNextRun records the point in time of the next run.
+func (s *daemonStatus) setNextRun(next time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nextRun = next
+}
+
+// record records the run of the command with the given name started at the
+// given time that provided the given community metrics, if any, and ended with
+// the given error, nil if it succeeded.
+func (s *daemonStatus) record(name string, start time.Time, metrics []byte, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if metrics != nil {
+		s.metrics = metrics
+	}
+	for _, c := range s.commands {
+		if c.name != name {
+			continue
+		}
+		c.lastRun = start
+		c.duration = time.Since(start)
+		if err != nil {
+			c.failures++
+		} else {
+			c.successes++
+			c.lastSuccess = start
+		}
+	}
+}
+
+// ready returns true iff every command has succeeded at least once.
+func (s *daemonStatus) ready() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, c := range s.commands {
+		if c.successes == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ServeHTTP serves the health and metrics endpoints.
+func (s *daemonStatus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/healthz":
+		_, _ = io.WriteString(w, "ok\n")
+	case "/readyz":
+		if !s.ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok\n")
+	case "/metrics":
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.writeMetrics(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeMetrics writes the run statistics to the given writer in the
+// Prometheus text exposition format.
+func (s *daemonStatus) writeMetrics(w io.Writer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	metric := func(name string, kind string, help string, value func(c *commandStatus) string) {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, c := range s.commands {
+			_, _ = fmt.Fprintf(w, "%s{command=%q} %s\n", name, c.name, value(c))
+		}
+	}
+	timestamp := func(t time.Time) string {
+		if t.IsZero() {
+			return "0"
+		}
+		return fmt.Sprintf("%d", t.Unix())
+	}
+	metric("herdstat_scheduled_runs_succeeded_total", "counter", "Number of succeeded scheduled runs.",
+		func(c *commandStatus) string { return fmt.Sprintf("%d", c.successes) })
+	metric("herdstat_scheduled_runs_failed_total", "counter", "Number of failed scheduled runs.",
+		func(c *commandStatus) string { return fmt.Sprintf("%d", c.failures) })
+	metric("herdstat_last_run_timestamp_seconds", "gauge", "Start time of the last run.",
+		func(c *commandStatus) string { return timestamp(c.lastRun) })
+	metric("herdstat_last_success_timestamp_seconds", "gauge", "Start time of the last succeeded run.",
+		func(c *commandStatus) string { return timestamp(c.lastSuccess) })
+	metric("herdstat_last_run_duration_seconds", "gauge", "Duration of the last run.",
+		func(c *commandStatus) string { return fmt.Sprintf("%g", c.duration.Seconds()) })
+	_, _ = fmt.Fprintf(w, "# HELP herdstat_next_run_timestamp_seconds Scheduled start time of the next run.\n"+
+		"# TYPE herdstat_next_run_timestamp_seconds gauge\nherdstat_next_run_timestamp_seconds %s\n", timestamp(s.nextRun))
+	_, _ = w.Write(s.metrics)
+}
+
+// Initialize the 'daemon' command.
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	// Flag to set the schedule of the runs
+	const scheduleFlag = "schedule"
+	daemonCmd.Flags().String(
+		scheduleFlag,
+		"@daily",
+		"The cron expression giving the schedule of the runs (minute, hour, day of month, month, and day of week)")
+	if err := viper.BindPFlag(daemonScheduleCfgKey, daemonCmd.Flags().Lookup(scheduleFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", scheduleFlag, "Error", err)
+	}
+
+	// Flag to set the commands run on schedule
+	const commandsFlag = "commands"
+	daemonCmd.Flags().StringSlice(
+		commandsFlag,
+		nil,
+		"The commands run on schedule (e.g., 'contribution-graph,dashboard')")
+	if err := viper.BindPFlag(daemonCommandsCfgKey, daemonCmd.Flags().Lookup(commandsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", commandsFlag, "Error", err)
+	}
+
+	// Flag to set the address of the health and metrics endpoints
+	const addressFlag = "address"
+	daemonCmd.Flags().String(
+		addressFlag,
+		":8080",
+		"The address the health and metrics endpoints are served on (disabled if empty)")
+	if err := viper.BindPFlag(daemonAddressCfgKey, daemonCmd.Flags().Lookup(addressFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", addressFlag, "Error", err)
+	}
+
+	// Flag to run the commands immediately on start
+	const runOnStartFlag = "run-on-start"
+	daemonCmd.Flags().Bool(
+		runOnStartFlag,
+		true,
+		"Whether to run the commands immediately on start instead of waiting for the first scheduled run")
+	if err := viper.BindPFlag(daemonRunOnStartCfgKey, daemonCmd.Flags().Lookup(runOnStartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", runOnStartFlag, "Error", err)
+	}
+}
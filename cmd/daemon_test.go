@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("Daemon mode", func() {
+
+	It("resolves the commands to run on schedule", func() {
+		old := viper.Get(daemonCommandsCfgKey)
+		DeferCleanup(viper.Set, daemonCommandsCfgKey, old)
+
+		viper.Set(daemonCommandsCfgKey, []string{"contribution-graph", "badge"})
+		commands, err := scheduledCommands()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(commands).To(HaveLen(2))
+		Expect(commands[0].Name()).To(Equal("contribution-graph"))
+
+		for _, name := range []string{"serve", "daemon", "unknown"} {
+			viper.Set(daemonCommandsCfgKey, []string{name})
+			_, err = scheduledCommands()
+			Expect(err).To(HaveOccurred(), name)
+		}
+	})
+
+	It("reports readiness and metrics", func() {
+		status := newDaemonStatus([]*cobra.Command{{Use: "contribution-graph"}, {Use: "badge"}})
+		get := func(path string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			status.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			return recorder
+		}
+		Expect(get("/healthz").Code).To(Equal(http.StatusOK))
+		Expect(get("/readyz").Code).To(Equal(http.StatusServiceUnavailable))
+
+		start := time.Unix(1680350400, 0)
+		status.record("contribution-graph", start, []byte("herdstat_unique_contributors 7\n"), nil)
+		status.record("badge", start, nil, errors.New("rate limited"))
+		Expect(get("/readyz").Code).To(Equal(http.StatusServiceUnavailable))
+		status.record("badge", start, nil, nil)
+		Expect(get("/readyz").Code).To(Equal(http.StatusOK))
+
+		metrics := get("/metrics").Body.String()
+		Expect(metrics).To(ContainSubstring(`herdstat_scheduled_runs_succeeded_total{command="badge"} 1`))
+		Expect(metrics).To(ContainSubstring(`herdstat_scheduled_runs_failed_total{command="badge"} 1`))
+		Expect(metrics).To(ContainSubstring(`herdstat_last_success_timestamp_seconds{command="contribution-graph"} 1680350400`))
+		Expect(metrics).To(ContainSubstring("herdstat_next_run_timestamp_seconds 0"))
+		Expect(metrics).To(ContainSubstring("herdstat_unique_contributors 7"))
+	})
+
+	It("forgets the results of scheduled runs", func() {
+		old := viper.Get(githubActionsCfgKey)
+		DeferCleanup(viper.Set, githubActionsCfgKey, old)
+		viper.Set(githubActionsCfgKey, false)
+
+		c := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error {
+			results.recordFile("contribution-graph.svg")
+			Expect(results.generatedFiles()).To(HaveLen(1))
+			return nil
+		}}
+		metrics, err := runScheduledCommand(c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metrics).To(BeNil())
+		Expect(results.generatedFiles()).To(BeEmpty())
+	})
+	It("grants each scheduled run the full request budget", func() {
+		DeferCleanup(apiRequests.Store, apiRequests.Load())
+		apiRequests.Store(0)
+
+		var counts []int64
+		c := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error {
+			counts = append(counts, apiRequests.Add(3))
+			return nil
+		}}
+		for i := 0; i < 2; i++ {
+			_, err := runScheduledCommand(c)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(counts).To(Equal([]int64{3, 3}))
+	})
+})
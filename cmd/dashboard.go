@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"html/template"
+	"image/color"
+	"os"
+	"time"
+)
+
+// Configuration keys for the dashboard command
+const (
+	// The name of the output file
+	dashboardFilenameCfgKey = "dashboard.filename"
+	// The title of the dashboard
+	dashboardTitleCfgKey = "dashboard.title"
+	// The primary color of the charts
+	dashboardColorCfgKey = "dashboard.color"
+	// The color space used for interpolating the contribution graph cell colors
+	dashboardInterpolationCfgKey = "dashboard.interpolation"
+	// The number of contributors listed on the leaderboard
+	dashboardTopCfgKey = "dashboard.top"
+)
+
+// dashboardCmd represents the dashboard command
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Generates a self-contained HTML dashboard of the contribution activity",
+	Long: `Generates a single HTML page embedding the contribution graph, the weekly
+contribution trend, a leaderboard of the most active contributors, and the
+summary statistics. The page adapts to the light and dark color scheme of the
+browser and has no external dependencies, so that it can be published as is,
+e.g., via GitHub Pages.`,
+	Args: cobra.NoArgs,
+	RunE: runDashboard,
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(dashboardColorCfgKey)
+	primaryColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	interpolation, err := internal.ParseInterpolation(viper.GetString(dashboardInterpolationCfgKey))
+	if err != nil {
+		return err
+	}
+
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	dashboard := internal.NewDashboard(viper.GetString(dashboardTitleCfgKey), contributions, lastDay, viper.GetInt(dashboardTopCfgKey))
+
+	if err := addDashboardCharts(dashboard, contributions, lastDay, primaryColor, interpolation); err != nil {
+		return err
+	}
+
+	html, err := dashboard.HTML()
+	if err != nil {
+		return fmt.Errorf("rendering dashboard failed: %w", err)
+	}
+	filename := viper.GetString(dashboardFilenameCfgKey)
+	if err := os.WriteFile(filename, []byte(html), 0644); err != nil {
+		return fmt.Errorf("writing dashboard to file failed: %w", err)
+	}
+	results.recordFile(filename)
+	cmd.Printf("Dashboard written to '%s'\n", filename)
+
+	return nil
+}
+
+// addDashboardCharts renders the contribution graph and the weekly trend
+// chart of the given contributions made within the 52 weeks ending with the
+// given day and embeds them into the given dashboard.
+func addDashboardCharts(dashboard *internal.Dashboard, contributions []internal.Contribution, lastDay time.Time,
+	primaryColor color.RGBA, interpolation internal.Interpolation) error {
+	records := internal.DailyRecords(contributions, lastDay)
+	heatmap, err := renderSVG(internal.NewContributionMap(records, lastDay,
+		internal.GetColoring(getColorScheme(primaryColor), interpolation), 5))
+	if err != nil {
+		return err
+	}
+	dashboard.Heatmap = template.HTML(heatmap.String())
+	trend, err := renderSVG(internal.NewTrendChart(records, internal.WeeklyGranularity, primaryColor, true))
+	if err != nil {
+		return err
+	}
+	dashboard.Trend = template.HTML(trend.String())
+	return nil
+}
+
+// Initialize the 'dashboard' command.
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+
+	// Flag to control the title
+	const titleFlag = "title"
+	dashboardCmd.Flags().String(
+		titleFlag,
+		"Community Dashboard",
+		"The title of the dashboard")
+	if err := viper.BindPFlag(dashboardTitleCfgKey, dashboardCmd.Flags().Lookup(titleFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", titleFlag, "Error", err)
+	}
+
+	// Flag to control the primary color
+	const colorFlag = "color"
+	dashboardCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The primary color of the charts")
+	if err := viper.BindPFlag(dashboardColorCfgKey, dashboardCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	// Flag to control the color interpolation
+	const interpolationFlag = "interpolation"
+	dashboardCmd.Flags().String(
+		interpolationFlag,
+		internal.RGBInterpolationName,
+		"The color space used for interpolating the contribution graph cell colors (rgb, hsl, or lab)")
+	if err := viper.BindPFlag(dashboardInterpolationCfgKey, dashboardCmd.Flags().Lookup(interpolationFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", interpolationFlag, "Error", err)
+	}
+
+	// Flag to control the size of the leaderboard
+	const topFlag = "top"
+	dashboardCmd.Flags().Int(
+		topFlag,
+		10,
+		"The number of contributors listed on the leaderboard")
+	if err := viper.BindPFlag(dashboardTopCfgKey, dashboardCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	dashboardCmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"index.html",
+		"The name of the generated HTML file")
+	if err := viper.BindPFlag(dashboardFilenameCfgKey, dashboardCmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
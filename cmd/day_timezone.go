@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// authorTimezone is the time zone setting attributing commits to the day in
+// the time zone of their author as recorded with the commit, like GitHub does.
+// Other contributions are attributed to the day in UTC.
+const authorTimezone = "author"
+
+// getDayTimezone returns the location contributions are attributed to days
+// in. UTC is returned if commits are attributed to days in the time zone of
+// their author.
+func getDayTimezone() (*time.Location, error) {
+	if viper.GetString(timezoneCfgKey) == authorTimezone {
+		return time.UTC, nil
+	}
+	location, err := getTimezone(timezoneCfgKey)
+	if err != nil {
+		return nil, classify(exitConfigError, err)
+	}
+	if location == nil {
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
+// today returns the current date in the configured time zone. The local date
+// is returned if the time zone is invalid.
+func today() string {
+	location, err := getDayTimezone()
+	if err != nil {
+		location = time.Local
+	}
+	return time.Now().In(location).Format("2006-01-02")
+}
+
+// inTimezone converts the dates of the given contributions to the configured
+// time zone, so that they are attributed to the days in that time zone. If
+// commits are attributed to days in the time zone of their author, the dates
+// of commits are kept.
+func inTimezone(contributions []internal.Contribution) ([]internal.Contribution, error) {
+	location, err := getDayTimezone()
+	if err != nil {
+		return nil, err
+	}
+	author := viper.GetString(timezoneCfgKey) == authorTimezone
+	for i, c := range contributions {
+		if author && c.Type == internal.CommitContribution {
+			continue
+		}
+		contributions[i].Date = c.Date.In(location)
+	}
+	return contributions, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"time"
+)
+
+var _ = Describe("Attributing contributions to days", func() {
+	lastDay := time.Date(2023, time.March, 15, 23, 59, 59, 999999999, time.UTC)
+	// Made on March 15 in New York, i.e., on March 16 in UTC
+	commitDate := time.Date(2023, time.March, 15, 22, 0, 0, 0, time.FixedZone("EDT", -4*60*60))
+
+	BeforeEach(func() {
+		DeferCleanup(viper.Set, timezoneCfgKey, viper.GetString(timezoneCfgKey))
+	})
+
+	contributions := func() []internal.Contribution {
+		return []internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Date: commitDate},
+			{Type: internal.IssueContribution, Repository: "herdstat/herdstat", Date: commitDate},
+		}
+	}
+
+	It("attributes contributions to days in UTC by default", func() {
+		viper.Set(timezoneCfgKey, "UTC")
+		converted, err := inTimezone(contributions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(converted[0].Date.Location()).To(Equal(time.UTC))
+		Expect(converted[0].Date).To(BeTemporally("==", commitDate))
+		Expect(internal.InPeriod(converted[0].Date, lastDay)).To(BeFalse())
+	})
+
+	It("attributes commits to days in the time zone of their author", func() {
+		viper.Set(timezoneCfgKey, authorTimezone)
+		converted, err := inTimezone(contributions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(internal.InPeriod(converted[0].Date, lastDay)).To(BeTrue())
+		Expect(internal.InPeriod(converted[1].Date, lastDay)).To(BeFalse())
+	})
+
+	It("interprets the last day in the configured time zone", func() {
+		viper.Set(timezoneCfgKey, "America/New_York")
+		until, err := parseUntilDate("2023-03-15")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(until.Location().String()).To(Equal("America/New_York"))
+		Expect(until.Format(time.RFC3339)).To(Equal("2023-03-15T23:59:59-04:00"))
+		converted, err := inTimezone(contributions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(internal.InPeriod(converted[0].Date, until)).To(BeTrue())
+	})
+
+	It("rejects unknown time zones", func() {
+		viper.Set(timezoneCfgKey, "Mars/Olympus_Mons")
+		_, err := parseUntilDate("2023-03-15")
+		Expect(err).To(MatchError(ContainSubstring("invalid time zone")))
+		Expect(exitCodeOf(err)).To(Equal(exitConfigError))
+	})
+})
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// Configuration keys for the digest command
+const (
+	// The length of the digest period
+	digestGranularityCfgKey = "digest.granularity"
+	// The number of top contributors listed
+	digestTopCfgKey = "digest.top"
+	// The URL of the embedded contribution graph
+	digestHeatmapURLCfgKey = "digest.heatmap-url"
+	// The format of the digest
+	digestFormatCfgKey = "digest.format"
+	// The name of the output file
+	digestFilenameCfgKey = "digest.filename"
+)
+
+// digestCmd represents the digest command
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Generates a weekly or monthly activity digest",
+	Long: `Generates a newsletter-style digest of the last week or month listing the top
+contributors, the merged pull requests, the new issues, and the published
+releases. The digest optionally embeds a previously generated contribution
+graph and is rendered as markdown by default for posting to GitHub Discussions
+or mailing lists.`,
+	Args: cobra.NoArgs,
+	RunE: runDigest,
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	granularity, err := internal.ParseGranularity(viper.GetString(digestGranularityCfgKey))
+	if err != nil {
+		return err
+	}
+	top := viper.GetInt(digestTopCfgKey)
+	if top < 0 {
+		return fmt.Errorf("number of top contributors must not be negative but is %d", top)
+	}
+
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	firstDay := internal.DigestStart(lastDay, granularity)
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	contributions, err := collectRepositoryContributions(repositories, firstDay, lastDay)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	var merged, issues, releases []internal.DigestItem
+	for _, repository := range repositories {
+		prs, err := listMergedPullRequests(ctx, client, repository, firstDay)
+		if err != nil {
+			return err
+		}
+		for _, pr := range prs {
+			merged = append(merged, internal.DigestItem{
+				Repository: repository.GetFullName(),
+				Title:      pr.GetTitle(),
+				URL:        pr.GetHTMLURL(),
+				Author:     pr.GetUser().GetLogin(),
+				Date:       pr.GetMergedAt().Time,
+			})
+		}
+		allIssues, err := listIssues(ctx, client, repository, firstDay)
+		if err != nil {
+			return err
+		}
+		for _, issue := range allIssues {
+			if !issue.IsPullRequest() {
+				issues = append(issues, internal.DigestItem{
+					Repository: repository.GetFullName(),
+					Title:      issue.GetTitle(),
+					URL:        issue.GetHTMLURL(),
+					Author:     issue.GetUser().GetLogin(),
+					Date:       issue.GetCreatedAt().Time,
+				})
+			}
+		}
+		r, err := listReleases(ctx, client, repository, firstDay)
+		if err != nil {
+			return err
+		}
+		releases = append(releases, r...)
+	}
+
+	digest := internal.NewDigest(firstDay, lastDay, contributions, merged, issues, releases, top,
+		viper.GetString(digestHeatmapURLCfgKey))
+	return writeReport(cmd, digest, viper.GetString(digestFormatCfgKey), viper.GetString(digestFilenameCfgKey))
+}
+
+// listReleases lists the releases of the given repository published after
+// since. Drafts are ignored.
+func listReleases(ctx context.Context, client *github.Client, repository *github.Repository,
+	since time.Time) ([]internal.DigestItem, error) {
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.ListOptions{PerPage: 100}
+	var items []internal.DigestItem
+	for {
+		releases, resp, err := client.Repositories.ListReleases(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching releases for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		for _, release := range releases {
+			if release.GetDraft() || release.GetPublishedAt().Before(since) {
+				continue
+			}
+			title := release.GetName()
+			if title == "" {
+				title = release.GetTagName()
+			}
+			items = append(items, internal.DigestItem{
+				Repository: repository.GetFullName(),
+				Title:      title,
+				URL:        release.GetHTMLURL(),
+				Author:     release.GetAuthor().GetLogin(),
+				Date:       release.GetPublishedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return items, nil
+}
+
+// Initialize the 'digest' command.
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	// Flag to control the length of the digest period
+	const granularityFlag = "granularity"
+	digestCmd.Flags().String(
+		granularityFlag,
+		internal.WeeklyGranularityName,
+		fmt.Sprintf("The length of the digest period (%s, %s, or %s)",
+			internal.DailyGranularityName, internal.WeeklyGranularityName, internal.MonthlyGranularityName))
+	if err := viper.BindPFlag(digestGranularityCfgKey, digestCmd.Flags().Lookup(granularityFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", granularityFlag, "Error", err)
+	}
+
+	// Flag to control the number of top contributors
+	const topFlag = "top"
+	digestCmd.Flags().Int(topFlag, 5,
+		"The number of contributors with the most contributions listed")
+	if err := viper.BindPFlag(digestTopCfgKey, digestCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	// Flag to control the embedded contribution graph
+	const heatmapURLFlag = "heatmap-url"
+	digestCmd.Flags().String(
+		heatmapURLFlag,
+		"",
+		"The URL of the contribution graph embedded into the digest (nothing embedded if empty)")
+	if err := viper.BindPFlag(digestHeatmapURLCfgKey, digestCmd.Flags().Lookup(heatmapURLFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", heatmapURLFlag, "Error", err)
+	}
+
+	addReportFlagsWithDefault(digestCmd, digestFormatCfgKey, digestFilenameCfgKey, markdownFormat)
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Results of diagnostic checks.
+const (
+	// The check passed
+	checkPassed = "ok"
+
+	// The check passed, but the run may be impaired
+	checkWarning = "warning"
+
+	// The check failed, so that runs will fail
+	checkFailed = "failed"
+)
+
+// minRateLimitHeadroom is the share of the rate limit of the GitHub API below
+// which the remaining requests are reported as low.
+const minRateLimitHeadroom = 0.1
+
+// diagnosis is the result of a diagnostic check. Unless the check passed, the
+// hint tells how to remedy the problem.
+type diagnosis struct {
+	check   string
+	status  string
+	message string
+	hint    string
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnoses the configuration and the access to GitHub",
+	Long: `Checks the validity of the configuration, the presence and scopes of the
+GitHub token, the connectivity to and the remaining rate limit of the GitHub
+API, and the permissions to write the configured outputs. Problems are
+reported with hints on how to remedy them.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	diagnoses := diagnose(context.Background(), github.NewClient(getHTTPClient()))
+	failed := writeDiagnoses(cmd.OutOrStdout(), diagnoses)
+	if failed > 0 {
+		// The diagnoses explain the failure better than the usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d checks failed", failed, len(diagnoses))
+	}
+	return nil
+}
+
+// diagnose runs all diagnostic checks using the given client to access the
+// GitHub API.
+func diagnose(ctx context.Context, client *github.Client) []diagnosis {
+	diagnoses := []diagnosis{checkConfiguration(), checkToken()}
+	limits, resp, err := client.RateLimits(ctx)
+	diagnoses = append(diagnoses, checkConnectivity(client, err))
+	if err == nil {
+		diagnoses = append(diagnoses, checkScopes(resp), checkRateLimit(limits.GetCore()))
+	}
+	return append(diagnoses, checkOutputs()...)
+}
+
+// writeDiagnoses writes the given diagnoses to the given writer and returns
+// the number of failed checks.
+func writeDiagnoses(w io.Writer, diagnoses []diagnosis) int {
+	var failed int
+	for _, d := range diagnoses {
+		_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", d.status, d.check, d.message)
+		if d.status != checkPassed && d.hint != "" {
+			_, _ = fmt.Fprintf(w, "    Hint: %s\n", d.hint)
+		}
+		if d.status == checkFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// checkConfiguration checks whether the configuration is valid, i.e., whether
+// the options of the analyzed repositories, the analyzed period, the styling
+// of contribution graphs, and the plugins can be parsed.
+func checkConfiguration() diagnosis {
+	d := diagnosis{check: "Configuration", status: checkPassed, message: "valid"}
+	if f := viper.ConfigFileUsed(); f != "" {
+		d.message = fmt.Sprintf("'%s' is valid", f)
+	}
+	fail := func(err error) diagnosis {
+		d.status = checkFailed
+		d.message = err.Error()
+		d.hint = "fix the option in the configuration file or the flags (see .herdstat.reference.yaml)"
+		return d
+	}
+	repos := viper.GetStringSlice(repositoriesCfgKey)
+	if len(repos) == 0 && len(viper.GetStringSlice(teamsCfgKey)) == 0 {
+		d.status = checkWarning
+		d.message = "no repositories configured"
+		d.hint = "give the repositories to analyze by --repositories, --repositories-file, or --team"
+	}
+	for _, repo := range repos {
+		owner, _, _ := strings.Cut(repo, "/")
+		if ownerOrRepoIDPattern.FindString(owner) != owner {
+			return fail(fmt.Errorf("'%s' is not a valid owner or owner/repository", repo))
+		}
+	}
+	for _, team := range viper.GetStringSlice(teamsCfgKey) {
+		if _, _, err := parseTeam(team); err != nil {
+			return fail(err)
+		}
+	}
+	if _, err := getUntilDate(); err != nil {
+		return fail(err)
+	}
+	if _, err := getGraphStyle(); err != nil {
+		return fail(err)
+	}
+	if _, err := getPlugins(collectorPlugin); err != nil {
+		return fail(err)
+	}
+	return d
+}
+
+// checkToken checks whether a GitHub token is available.
+func checkToken() diagnosis {
+	d := diagnosis{check: "GitHub token", status: checkPassed, message: "present"}
+	if getGitHubToken() == "" {
+		d.status = checkWarning
+		d.message = "none available - the GitHub API is accessed anonymously with a rate limit of 60 requests per hour"
+		d.hint = "set GITHUB_TOKEN, pass --github-token, or log in with 'gh auth login' and pass --gh-credentials"
+		if viper.GetBool(includePrivateCfgKey) || len(viper.GetStringSlice(teamsCfgKey)) != 0 {
+			d.status = checkFailed
+			d.message = "none available, but required to analyze private repositories and teams"
+		}
+	}
+	return d
+}
+
+// checkConnectivity checks whether the GitHub API could be reached with the
+// given client given the error of the request.
+func checkConnectivity(client *github.Client, err error) diagnosis {
+	d := diagnosis{check: "GitHub API", status: checkPassed, message: fmt.Sprintf("'%s' is reachable", client.BaseURL)}
+	if err == nil {
+		return d
+	}
+	d.status = checkFailed
+	d.message = fmt.Sprintf("'%s' can't be reached: %v", client.BaseURL, err)
+	if exitCodeOf(err) == exitAuthError {
+		d.hint = "check whether the GitHub token is valid and hasn't expired"
+	} else {
+		d.hint = "check the network connection and the proxy settings (HTTPS_PROXY)"
+	}
+	return d
+}
+
+// checkScopes checks whether the classic token the given response was
+// requested with has the scopes required by the configuration. Fine-grained
+// tokens don't report their permissions, so they are assumed to be sufficient.
+func checkScopes(resp *github.Response) diagnosis {
+	d := diagnosis{check: "Token scopes", status: checkPassed}
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if getGitHubToken() == "" || resp.Header.Values("X-OAuth-Scopes") == nil {
+		d.message = "not applicable"
+		return d
+	}
+	scopes := make(map[string]bool)
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes[scope] = true
+		}
+	}
+	var missing []string
+	if viper.GetBool(includePrivateCfgKey) && !scopes["repo"] {
+		missing = append(missing, "repo")
+	}
+	if len(viper.GetStringSlice(teamsCfgKey)) != 0 && !scopes["read:org"] && !scopes["admin:org"] {
+		missing = append(missing, "read:org")
+	}
+	if len(missing) == 0 {
+		d.message = fmt.Sprintf("'%s' cover the configuration", header)
+		if header == "" {
+			d.message = "none required by the configuration"
+		}
+		return d
+	}
+	d.status = checkFailed
+	d.message = fmt.Sprintf("missing %s", strings.Join(missing, ", "))
+	d.hint = fmt.Sprintf("grant the token the scopes %s, e.g., by 'gh auth refresh --scopes %s'",
+		strings.Join(missing, ", "), strings.Join(missing, ","))
+	return d
+}
+
+// checkRateLimit checks whether the given rate limit of the GitHub API leaves
+// enough headroom for a run.
+func checkRateLimit(limit *github.Rate) diagnosis {
+	if limit == nil {
+		return diagnosis{check: "Rate limit", status: checkWarning, message: "not reported by the GitHub API"}
+	}
+	d := diagnosis{
+		check:   "Rate limit",
+		status:  checkPassed,
+		message: fmt.Sprintf("%d of %d requests remaining", limit.Remaining, limit.Limit),
+	}
+	if limit.Limit > 0 && float64(limit.Remaining) < minRateLimitHeadroom*float64(limit.Limit) {
+		d.status = checkWarning
+		d.message = fmt.Sprintf("%s until %s", d.message, limit.Reset.Format("15:04:05 MST"))
+		d.hint = "wait for the reset, pass --wait-for-rate-limit, or configure a cache to make requests conditional"
+		if limit.Remaining == 0 {
+			d.status = checkFailed
+		}
+	}
+	return d
+}
+
+// outputDirectory is a directory outputs are written to.
+type outputDirectory struct {
+	path string
+
+	// Whether the directory is created if missing
+	created bool
+}
+
+// checkOutputs checks whether the configured outputs can be written.
+func checkOutputs() []diagnosis {
+	var diagnoses []diagnosis
+	for _, dir := range outputDirectories() {
+		d := diagnosis{check: "Output", status: checkPassed, message: fmt.Sprintf("'%s' is writable", dir.path)}
+		if err := checkWritable(dir.path, dir.created); err != nil {
+			d.status = checkFailed
+			d.message = fmt.Sprintf("'%s' is not writable: %v", dir.path, err)
+			d.hint = "create the directory or grant write permissions, or configure another output path"
+		}
+		diagnoses = append(diagnoses, d)
+	}
+	return diagnoses
+}
+
+// outputDirectories returns the distinct directories the configured outputs
+// are written to.
+func outputDirectories() []outputDirectory {
+	var dirs []outputDirectory
+	seen := make(map[string]bool)
+	add := func(path string, created bool) {
+		if path = filepath.Clean(path); !seen[path] {
+			seen[path] = true
+			dirs = append(dirs, outputDirectory{path: path, created: created})
+		}
+	}
+	if filename := viper.GetString(filenameCfgKey); filename != "" {
+		add(filepath.Dir(filename), false)
+	}
+	if filename := viper.GetString(sqliteFileCfgKey); filename != "" {
+		add(filepath.Dir(filename), false)
+	}
+	if dir := viper.GetString(parquetDirectoryCfgKey); dir != "" {
+		add(dir, true)
+	}
+	return dirs
+}
+
+// checkWritable checks whether files can be created in the given directory by
+// creating and removing a temporary file. If the directory is missing but
+// created when writing outputs, its closest existing ancestor is checked
+// instead.
+func checkWritable(dir string, created bool) error {
+	info, err := os.Stat(dir)
+	if created && errors.Is(err, os.ErrNotExist) && filepath.Dir(dir) != dir {
+		return checkWritable(filepath.Dir(dir), created)
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", dir)
+	}
+	f, err := os.CreateTemp(dir, ".herdstat-doctor-*")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// Initialize the 'doctor' command.
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
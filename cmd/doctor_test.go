@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Diagnosing problems", func() {
+
+	// serve starts a fake GitHub API reporting the given remaining requests
+	// and, unless nil, the given scopes of the token.
+	serve := func(remaining int, scopes *string) *github.Client {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if scopes != nil {
+				w.Header().Set("X-OAuth-Scopes", *scopes)
+			}
+			_, _ = fmt.Fprintf(w, `{"resources": {"core": {"limit": 5000, "remaining": %d, "reset": 1700000000}}}`, remaining)
+		}))
+		DeferCleanup(server.Close)
+		client := github.NewClient(nil)
+		u, err := url.Parse(server.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		client.BaseURL = u
+		return client
+	}
+
+	// find returns the diagnosis of the given check.
+	find := func(diagnoses []diagnosis, check string) diagnosis {
+		for _, d := range diagnoses {
+			if d.check == check {
+				return d
+			}
+		}
+		Fail("no diagnosis of " + check)
+		return diagnosis{}
+	}
+
+	BeforeEach(func() {
+		for _, key := range []string{gitHubTokenCfgKey, includePrivateCfgKey, filenameCfgKey, sqliteFileCfgKey,
+			parquetDirectoryCfgKey} {
+			DeferCleanup(viper.Set, key, viper.Get(key))
+		}
+		DeferCleanup(viper.Set, teamsCfgKey, viper.GetStringSlice(teamsCfgKey))
+		viper.Set(gitHubTokenCfgKey, "token")
+		viper.Set(includePrivateCfgKey, true)
+		viper.Set(teamsCfgKey, []string{})
+		viper.Set(filenameCfgKey, filepath.Join(GinkgoT().TempDir(), "contribution-graph.svg"))
+	})
+
+	It("reports scopes of classic tokens missing for private repositories", func() {
+		scopes := "public_repo, gist"
+		d := find(diagnose(context.Background(), serve(4000, &scopes)), "Token scopes")
+		Expect(d.status).To(Equal(checkFailed))
+		Expect(d.message).To(Equal("missing repo"))
+		Expect(d.hint).To(ContainSubstring("gh auth refresh --scopes repo"))
+	})
+
+	It("accepts fine-grained tokens", func() {
+		d := find(diagnose(context.Background(), serve(4000, nil)), "Token scopes")
+		Expect(d.status).To(Equal(checkPassed))
+	})
+
+	It("warns about a low rate limit", func() {
+		scopes := "repo"
+		diagnoses := diagnose(context.Background(), serve(100, &scopes))
+		Expect(find(diagnoses, "Token scopes").status).To(Equal(checkPassed))
+		d := find(diagnoses, "Rate limit")
+		Expect(d.status).To(Equal(checkWarning))
+		Expect(d.message).To(HavePrefix("100 of 5000 requests remaining"))
+	})
+
+	It("reports an unreachable GitHub API", func() {
+		client := serve(4000, nil)
+		client.BaseURL, _ = url.Parse("http://127.0.0.1:1/")
+		diagnoses := diagnose(context.Background(), client)
+		Expect(find(diagnoses, "GitHub API").status).To(Equal(checkFailed))
+		for _, d := range diagnoses {
+			Expect(d.check).NotTo(Equal("Rate limit"))
+		}
+	})
+
+	It("fails if a token is required but missing", func() {
+		viper.Set(gitHubTokenCfgKey, "")
+		Expect(checkToken().status).To(Equal(checkFailed))
+	})
+
+	It("checks whether outputs can be written", func() {
+		dir := GinkgoT().TempDir()
+		viper.Set(filenameCfgKey, filepath.Join(dir, "missing", "contribution-graph.svg"))
+		viper.Set(parquetDirectoryCfgKey, filepath.Join(dir, "parquet", "tables"))
+		diagnoses := checkOutputs()
+		Expect(diagnoses).To(HaveLen(2))
+		Expect(diagnoses[0].status).To(Equal(checkFailed))
+		Expect(diagnoses[1].status).To(Equal(checkPassed))
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+})
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the elephant-factor command
+const (
+	// The format of the report
+	elephantFactorFormatCfgKey = "elephant-factor.format"
+	// The name of the output file
+	elephantFactorFilenameCfgKey = "elephant-factor.filename"
+)
+
+// elephantFactorCmd represents the elephant-factor command
+var elephantFactorCmd = &cobra.Command{
+	Use:   "elephant-factor",
+	Short: "Computes how concentrated contributions are among organizations",
+	Long: `Computes the number of organizations accounting for 50% of the affiliated
+contributions per repository and across all repositories. Contributors are
+affiliated with organizations by means of the configured affiliations or the
+domain of their email address.`,
+	Args: cobra.NoArgs,
+	RunE: runElephantFactor,
+}
+
+func runElephantFactor(cmd *cobra.Command, args []string) error {
+	affiliations, err := getAffiliations()
+	if err != nil {
+		return err
+	}
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewElephantFactorReport(contributions, lastDay, affiliations)
+	return writeReport(cmd, report, viper.GetString(elephantFactorFormatCfgKey), viper.GetString(elephantFactorFilenameCfgKey))
+}
+
+// Initialize the 'elephant-factor' command.
+func init() {
+	rootCmd.AddCommand(elephantFactorCmd)
+
+	addReportFlags(elephantFactorCmd, elephantFactorFormatCfgKey, elephantFactorFilenameCfgKey)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the emeritus command
+const (
+	// The logins of the maintainers
+	emeritusMaintainersCfgKey = "emeritus.maintainers"
+	// The number of months without contributions
+	emeritusMonthsCfgKey = "emeritus.months"
+	// The format of the report
+	emeritusFormatCfgKey = "emeritus.format"
+	// The name of the output file
+	emeritusFilenameCfgKey = "emeritus.filename"
+)
+
+// emeritusCmd represents the emeritus command
+var emeritusCmd = &cobra.Command{
+	Use:   "emeritus",
+	Short: "Flags maintainers without recent contributions",
+	Long: `Flags the configured maintainers who made no contributions to any of the
+analyzed repositories within the given number of months ending with the
+"until" date. This supports emeritus processes defined in governance
+documents. Commits are attributed to maintainers only if their logins are
+known, e.g., by means of the configured identities.`,
+	Args: cobra.NoArgs,
+	RunE: runEmeritus,
+}
+
+func runEmeritus(cmd *cobra.Command, args []string) error {
+	maintainers := viper.GetStringSlice(emeritusMaintainersCfgKey)
+	if len(maintainers) == 0 {
+		return classify(exitConfigError, errors.New("no maintainers configured"))
+	}
+	months := viper.GetInt(emeritusMonthsCfgKey)
+	if months <= 0 {
+		return fmt.Errorf("number of months must be positive, got %d", months)
+	}
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	since := lastDay.AddDate(0, -months, 0)
+	contributions, err := collectContributionsBetween(cmd, viper.GetStringSlice(repositoriesCfgKey), since, lastDay)
+	if err != nil {
+		return err
+	}
+	report := internal.NewEmeritusReport(contributions, maintainers, since, lastDay)
+	return writeReport(cmd, report, viper.GetString(emeritusFormatCfgKey), viper.GetString(emeritusFilenameCfgKey))
+}
+
+// Initialize the 'emeritus' command.
+func init() {
+	rootCmd.AddCommand(emeritusCmd)
+
+	// Flag to control the maintainers
+	const maintainersFlag = "maintainers"
+	emeritusCmd.Flags().StringSlice(
+		maintainersFlag,
+		nil,
+		"The GitHub logins of the maintainers")
+	if err := viper.BindPFlag(emeritusMaintainersCfgKey, emeritusCmd.Flags().Lookup(maintainersFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", maintainersFlag, "Error", err)
+	}
+
+	// Flag to control the number of months without contributions
+	const monthsFlag = "months"
+	emeritusCmd.Flags().Int(monthsFlag, 12,
+		"The number of months without contributions after which maintainers are flagged")
+	if err := viper.BindPFlag(emeritusMonthsCfgKey, emeritusCmd.Flags().Lookup(monthsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", monthsFlag, "Error", err)
+	}
+
+	addReportFlags(emeritusCmd, emeritusFormatCfgKey, emeritusFilenameCfgKey)
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the engagement command
+const (
+	// The format of the report
+	engagementFormatCfgKey = "engagement.format"
+	// The name of the output file
+	engagementFilenameCfgKey = "engagement.filename"
+)
+
+// engagementCmd represents the engagement command
+var engagementCmd = &cobra.Command{
+	Use:   "engagement",
+	Short: "Classifies contributors into drive-by, occasional, and regular ones",
+	Long: `Classifies contributors by their number of contributions into drive-by (1),
+occasional (2-5), and regular (6+) contributors and reports the distribution.
+This helps communities to understand whether they convert drive-by
+contributors into regulars.`,
+	Args: cobra.NoArgs,
+	RunE: runEngagement,
+}
+
+func runEngagement(cmd *cobra.Command, args []string) error {
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewEngagementReport(contributions, lastDay)
+	return writeReport(cmd, report, viper.GetString(engagementFormatCfgKey), viper.GetString(engagementFilenameCfgKey))
+}
+
+// Initialize the 'engagement' command.
+func init() {
+	rootCmd.AddCommand(engagementCmd)
+
+	addReportFlags(engagementCmd, engagementFormatCfgKey, engagementFilenameCfgKey)
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"io"
+	"net/http"
+)
+
+// Exit codes denoting the class of failure a run failed with, so that CI
+// pipelines can branch on it.
+const (
+	// Failures not belonging to any of the classes below
+	exitFailure = 1
+
+	// Invalid flags or configuration
+	exitConfigError = 2
+
+	// Missing or rejected credentials, or exhausted limits of the GitHub API
+	exitAuthError = 3
+
+	// Collecting the contributions to a repository failed
+	exitCollectionError = 4
+
+	// Rendering or writing an output failed
+	exitRenderError = 5
+)
+
+// errorClasses names the classes of failures in error reports.
+var errorClasses = map[int]string{
+	exitFailure:         "failure",
+	exitConfigError:     "config",
+	exitAuthError:       "auth",
+	exitCollectionError: "collection",
+	exitRenderError:     "render",
+}
+
+// Supported formats of errors a run failed with.
+const (
+	// The error message is written as is
+	textErrorFormat = "text"
+
+	// A JSON error report is written (see errorReport)
+	jsonErrorFormat = "json"
+)
+
+// classifiedError is an error belonging to the class of failures denoted by
+// its exit code.
+type classifiedError struct {
+	exitCode int
+	err      error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// classify assigns the given error to the class of failures denoted by the
+// given exit code unless it belongs to a class already.
+func classify(exitCode int, err error) error {
+	if err == nil || exitCodeOf(err) != exitFailure {
+		return err
+	}
+	return &classifiedError{exitCode: exitCode, err: err}
+}
+
+// exitCodeOf returns the exit code of the class of failures the given error
+// belongs to. Rate limit errors and requests rejected by the GitHub API as
+// unauthorized or forbidden are authentication failures.
+func exitCodeOf(err error) int {
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.exitCode
+	}
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	var twoFactorAuthErr *github.TwoFactorAuthError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) || errors.As(err, &twoFactorAuthErr) {
+		return exitAuthError
+	}
+	var responseErr *github.ErrorResponse
+	if errors.As(err, &responseErr) && responseErr.Response != nil &&
+		(responseErr.Response.StatusCode == http.StatusUnauthorized || responseErr.Response.StatusCode == http.StatusForbidden) {
+		return exitAuthError
+	}
+	return exitFailure
+}
+
+// errorReport is the machine-readable report of the error a run failed with.
+type errorReport struct {
+	Error    string `json:"error"`
+	Class    string `json:"class"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// writeError writes the given error in the given format to the given writer
+// and returns the exit code of its class.
+func writeError(w io.Writer, err error, format string) int {
+	exitCode := exitCodeOf(err)
+	if format != jsonErrorFormat {
+		_, _ = fmt.Fprintln(w, "Error:", err)
+		return exitCode
+	}
+	report, marshalErr := json.Marshal(errorReport{
+		Error:    err.Error(),
+		Class:    errorClasses[exitCode],
+		ExitCode: exitCode,
+	})
+	if marshalErr != nil {
+		_, _ = fmt.Fprintln(w, "Error:", err)
+		return exitCode
+	}
+	_, _ = fmt.Fprintln(w, string(report))
+	return exitCode
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"net/http"
+)
+
+var _ = Describe("Errors", func() {
+	It("exits with a generic code for unclassified errors", func() {
+		Expect(exitCodeOf(errors.New("boom"))).To(Equal(exitFailure))
+	})
+
+	It("keeps the class of wrapped errors", func() {
+		err := fmt.Errorf("context: %w", classify(exitRenderError, errors.New("boom")))
+		Expect(exitCodeOf(err)).To(Equal(exitRenderError))
+		Expect(err).To(MatchError("context: boom"))
+	})
+
+	It("doesn't reclassify errors", func() {
+		err := classify(exitCollectionError, classify(exitConfigError, errors.New("boom")))
+		Expect(exitCodeOf(err)).To(Equal(exitConfigError))
+		rateLimitErr := &github.RateLimitError{Message: "API rate limit exceeded"}
+		Expect(exitCodeOf(classify(exitCollectionError, rateLimitErr))).To(Equal(exitAuthError))
+	})
+
+	It("treats rejected requests as authentication failures", func() {
+		for status, exitCode := range map[int]int{
+			http.StatusUnauthorized: exitAuthError,
+			http.StatusForbidden:    exitAuthError,
+			http.StatusNotFound:     exitFailure,
+		} {
+			err := &github.ErrorResponse{Response: &http.Response{StatusCode: status, Request: &http.Request{}}}
+			Expect(exitCodeOf(err)).To(Equal(exitCode), "status %d", status)
+		}
+	})
+
+	It("writes errors as text", func() {
+		var out bytes.Buffer
+		Expect(writeError(&out, classify(exitConfigError, errors.New("boom")), textErrorFormat)).To(Equal(exitConfigError))
+		Expect(out.String()).To(Equal("Error: boom\n"))
+	})
+
+	It("writes machine-readable error reports", func() {
+		var out bytes.Buffer
+		Expect(writeError(&out, classify(exitCollectionError, errors.New("boom")), jsonErrorFormat)).To(Equal(exitCollectionError))
+		var report errorReport
+		Expect(json.Unmarshal(out.Bytes(), &report)).To(Succeed())
+		Expect(report).To(Equal(errorReport{Error: "boom", Class: "collection", ExitCode: exitCollectionError}))
+	})
+})
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the export command
+const (
+	// The format of the export
+	exportFormatCfgKey = "export.format"
+	// The name of the output file
+	exportFilenameCfgKey = "export.filename"
+	// The length of the buckets contributions are aggregated over
+	exportBucketCfgKey = "export.bucket"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the contribution activity as anonymous aggregate counts",
+	Long: `Exports the number of commits, issues, pull requests, and distinct
+contributors per repository and day, week, or month. The export contains neither logins,
+names, nor email addresses, so that organizations can publish their activity
+data without exposing individuals.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	granularity, err := internal.ParseBucket(viper.GetString(exportBucketCfgKey))
+	if err != nil {
+		return err
+	}
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	export := internal.NewAggregateExport(contributions, lastDay, granularity)
+	return writeReport(cmd, export, viper.GetString(exportFormatCfgKey), viper.GetString(exportFilenameCfgKey))
+}
+
+// Initialize the 'export' command.
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	addReportFlags(exportCmd, exportFormatCfgKey, exportFilenameCfgKey)
+
+	// Flag to control the bucket length
+	const bucketFlag = "bucket"
+	exportCmd.Flags().String(
+		bucketFlag,
+		internal.DayBucketName,
+		fmt.Sprintf("The length of the buckets contributions are aggregated over (%s, %s, or %s)",
+			internal.DayBucketName, internal.WeekBucketName, internal.MonthBucketName))
+	if err := viper.BindPFlag(exportBucketCfgKey, exportCmd.Flags().Lookup(bucketFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", bucketFlag, "Error", err)
+	}
+}
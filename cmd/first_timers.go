@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the first-timers command
+const (
+	// The number of weeks before the analyzed period searched for prior contributions
+	firstTimersLookbackCfgKey = "first-timers.lookback"
+	// The format of the report
+	firstTimersFormatCfgKey = "first-timers.format"
+	// The name of the output file
+	firstTimersFilenameCfgKey = "first-timers.filename"
+)
+
+// firstTimersCmd represents the first-timers command
+var firstTimersCmd = &cobra.Command{
+	Use:   "first-timers",
+	Short: "Lists the contributors who made their first contribution within the analyzed period",
+	Long: `Lists the contributors without contributions within the configured number of
+weeks before the analyzed period along with a link to their first contribution.
+The markdown output is suitable for welcoming them in release notes and
+community calls.`,
+	Args: cobra.NoArgs,
+	RunE: runFirstTimers,
+}
+
+func runFirstTimers(cmd *cobra.Command, args []string) error {
+	lookback := viper.GetInt(firstTimersLookbackCfgKey)
+	if lookback < 0 {
+		return fmt.Errorf("lookback must not be negative but is %d", lookback)
+	}
+	contributions, lastDay, err := collectContributionsWithLookback(cmd, lookback)
+	if err != nil {
+		return err
+	}
+	lookbackFrom := lastDay.AddDate(0, 0, -(52+lookback)*7+1)
+	report := internal.NewFirstTimersReport(contributions, lastDay, lookbackFrom)
+	return writeReport(cmd, report, viper.GetString(firstTimersFormatCfgKey), viper.GetString(firstTimersFilenameCfgKey))
+}
+
+// Initialize the 'first-timers' command.
+func init() {
+	rootCmd.AddCommand(firstTimersCmd)
+
+	// Flag to control the lookback period
+	const lookbackFlag = "lookback"
+	firstTimersCmd.Flags().Int(lookbackFlag, 104,
+		"The number of weeks before the analyzed period searched for prior contributions")
+	if err := viper.BindPFlag(firstTimersLookbackCfgKey, firstTimersCmd.Flags().Lookup(lookbackFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", lookbackFlag, "Error", err)
+	}
+
+	addReportFlags(firstTimersCmd, firstTimersFormatCfgKey, firstTimersFilenameCfgKey)
+}
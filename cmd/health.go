@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the health command
+const (
+	// The minimal change of activity in percent
+	healthActivityTrendCfgKey = "health.thresholds.activity-trend"
+	// The minimal 50% bus factor
+	healthBusFactorCfgKey = "health.thresholds.bus-factor"
+	// The maximal median time to first response in hours
+	healthResponseTimeCfgKey = "health.thresholds.response-time"
+	// The minimal share of first-time contributors in percent
+	healthNewContributorRateCfgKey = "health.thresholds.new-contributor-rate"
+	// The number of weeks before the analyzed period searched for prior contributions
+	healthLookbackCfgKey = "health.lookback"
+	// The format of the report
+	healthFormatCfgKey = "health.format"
+	// The name of the output file
+	healthFilenameCfgKey = "health.filename"
+)
+
+// healthCmd represents the health command
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Generates a scored community health report",
+	Long: `Assesses the health of a community by means of the activity trend, the bus
+factor, the responsiveness to issues, and the rate of new contributors. Each
+metric is compared to a configurable threshold, and the share of metrics
+meeting their thresholds forms the overall health score.`,
+	Args: cobra.NoArgs,
+	RunE: runHealth,
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	thresholds := internal.HealthThresholds{
+		ActivityTrend:      viper.GetFloat64(healthActivityTrendCfgKey),
+		BusFactor:          viper.GetInt(healthBusFactorCfgKey),
+		ResponseTime:       viper.GetFloat64(healthResponseTimeCfgKey),
+		NewContributorRate: viper.GetFloat64(healthNewContributorRateCfgKey),
+	}
+	lookback := viper.GetInt(healthLookbackCfgKey)
+	if lookback < 0 {
+		return fmt.Errorf("lookback must not be negative but is %d", lookback)
+	}
+
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	contributions, err := collectRepositoryContributions(repositories, lastDay.AddDate(0, 0, -(52+lookback)*7), lastDay)
+	if err != nil {
+		return err
+	}
+	var issues []internal.Issue
+	for _, repository := range repositories {
+		i, err := collectIssues(repository, lastDay)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, i...)
+	}
+
+	lookbackFrom := lastDay.AddDate(0, 0, -(52+lookback)*7+1)
+	report := internal.NewHealthReport(contributions, issues, lastDay, lookbackFrom, thresholds)
+	return writeReport(cmd, report, viper.GetString(healthFormatCfgKey), viper.GetString(healthFilenameCfgKey))
+}
+
+// Initialize the 'health' command.
+func init() {
+	rootCmd.AddCommand(healthCmd)
+
+	// Flag to control the activity trend threshold
+	const activityTrendFlag = "activity-trend-threshold"
+	healthCmd.Flags().Float64(
+		activityTrendFlag,
+		-10,
+		"The minimal change in percent of contributions in the last 13 weeks compared to the 13 weeks before")
+	if err := viper.BindPFlag(healthActivityTrendCfgKey, healthCmd.Flags().Lookup(activityTrendFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", activityTrendFlag, "Error", err)
+	}
+
+	// Flag to control the bus factor threshold
+	const busFactorFlag = "bus-factor-threshold"
+	healthCmd.Flags().Int(
+		busFactorFlag,
+		2,
+		"The minimal 50% bus factor")
+	if err := viper.BindPFlag(healthBusFactorCfgKey, healthCmd.Flags().Lookup(busFactorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", busFactorFlag, "Error", err)
+	}
+
+	// Flag to control the response time threshold
+	const responseTimeFlag = "response-time-threshold"
+	healthCmd.Flags().Float64(
+		responseTimeFlag,
+		48,
+		"The maximal median time to first response to issues in hours")
+	if err := viper.BindPFlag(healthResponseTimeCfgKey, healthCmd.Flags().Lookup(responseTimeFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", responseTimeFlag, "Error", err)
+	}
+
+	// Flag to control the new contributor rate threshold
+	const newContributorRateFlag = "new-contributor-rate-threshold"
+	healthCmd.Flags().Float64(
+		newContributorRateFlag,
+		10,
+		"The minimal share of first-time contributors among the active contributors in percent")
+	if err := viper.BindPFlag(healthNewContributorRateCfgKey, healthCmd.Flags().Lookup(newContributorRateFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", newContributorRateFlag, "Error", err)
+	}
+
+	// Flag to control the lookback period
+	const lookbackFlag = "lookback"
+	healthCmd.Flags().Int(
+		lookbackFlag,
+		104,
+		"The number of weeks before the analyzed period searched for prior contributions")
+	if err := viper.BindPFlag(healthLookbackCfgKey, healthCmd.Flags().Lookup(lookbackFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", lookbackFlag, "Error", err)
+	}
+
+	addReportFlags(healthCmd, healthFormatCfgKey, healthFilenameCfgKey)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the hotspots command
+const (
+	// The number of files and directories listed
+	hotspotsTopCfgKey = "hotspots.top"
+	// The maximal depth of the directories considered
+	hotspotsDepthCfgKey = "hotspots.depth"
+	// The format of the report
+	hotspotsFormatCfgKey = "hotspots.format"
+	// The name of the output file
+	hotspotsFilenameCfgKey = "hotspots.filename"
+)
+
+// hotspotsCmd represents the hotspots command
+var hotspotsCmd = &cobra.Command{
+	Use:   "hotspots",
+	Short: "Identifies the most frequently changed files and directories",
+	Long: `Identifies the files and directories changed by the most commits across the
+analyzed repositories. Such hotspots point to risky code or to documentation
+that churns constantly. Merge commits are not taken into account.`,
+	Args: cobra.NoArgs,
+	RunE: runHotspots,
+}
+
+func runHotspots(cmd *cobra.Command, args []string) error {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	commits, err := collectCommitContributions(repositories, lastDay.AddDate(0, 0, -52*7), lastDay, commitDetails{files: true})
+	if err != nil {
+		return err
+	}
+	report := internal.NewHotspotReport(commits, lastDay, viper.GetInt(hotspotsTopCfgKey), viper.GetInt(hotspotsDepthCfgKey))
+	return writeReport(cmd, report, viper.GetString(hotspotsFormatCfgKey), viper.GetString(hotspotsFilenameCfgKey))
+}
+
+// Initialize the 'hotspots' command.
+func init() {
+	rootCmd.AddCommand(hotspotsCmd)
+
+	// Flag to control the number of files and directories listed
+	const topFlag = "top"
+	hotspotsCmd.Flags().Int(topFlag, 10,
+		"The number of most frequently changed files and directories listed")
+	if err := viper.BindPFlag(hotspotsTopCfgKey, hotspotsCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	// Flag to control the depth of the directories considered
+	const depthFlag = "depth"
+	hotspotsCmd.Flags().Int(depthFlag, 2,
+		"The maximal depth of the directories considered (all if not positive)")
+	if err := viper.BindPFlag(hotspotsDepthCfgKey, hotspotsCmd.Flags().Lookup(depthFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", depthFlag, "Error", err)
+	}
+
+	addReportFlags(hotspotsCmd, hotspotsFormatCfgKey, hotspotsFilenameCfgKey)
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Configuration keys for the issue-metrics command
+const (
+	// The format of the report
+	issueMetricsFormatCfgKey = "issue-metrics.format"
+	// The name of the output file
+	issueMetricsFilenameCfgKey = "issue-metrics.filename"
+)
+
+// issueMetricsCmd represents the issue-metrics command
+var issueMetricsCmd = &cobra.Command{
+	Use:   "issue-metrics",
+	Short: "Reports how quickly issues are responded to and closed",
+	Long: `Reports the median and 90th percentile of the time to first response and the
+time to close for issues opened within the analyzed period per repository and
+across all repositories. Responses by the issue author and by bots are
+ignored.`,
+	Args: cobra.NoArgs,
+	RunE: runIssueMetrics,
+}
+
+func runIssueMetrics(cmd *cobra.Command, args []string) error {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := collectRepositories(viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var issues []internal.Issue
+	for _, repository := range repositories {
+		cmd.Printf("Processing issues of %s\n", repository.GetFullName())
+		i, err := collectIssues(repository, lastDay)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, i...)
+	}
+	report := internal.NewIssueMetricsReport(issues, lastDay)
+	return writeReport(cmd, report, viper.GetString(issueMetricsFormatCfgKey), viper.GetString(issueMetricsFilenameCfgKey))
+}
+
+// collectIssues collects the issues (excluding PRs) opened in the given
+// repository within the 52 weeks ending with the given day including their
+// responses.
+func collectIssues(repository *github.Repository, lastDay time.Time) ([]internal.Issue, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	since := lastDay.AddDate(0, 0, -52*7)
+	allIssues, err := listIssues(ctx, client, repository, since)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := collectResponses(ctx, client, repository, allIssues, since)
+	if err != nil {
+		return nil, err
+	}
+	var issues []internal.Issue
+	for _, issue := range allIssues {
+		if issue.IsPullRequest() || !internal.InPeriod(issue.GetCreatedAt().Time, lastDay) {
+			continue
+		}
+		i := internal.Issue{
+			Repository: repository.GetFullName(),
+			Number:     issue.GetNumber(),
+			Author:     issue.GetUser().GetLogin(),
+			Created:    issue.GetCreatedAt().Time,
+			Closed:     issue.GetClosedAt().Time,
+			Labels:     labelNames(issue.Labels),
+			Responses:  responses[issue.GetNumber()],
+		}
+		if len(i.Responses) > 0 {
+			i.FirstResponse = i.Responses[0].Time
+		}
+		issues = append(issues, i)
+	}
+	return issues, nil
+}
+
+// collectResponses determines the first comment of each commenter other than
+// the issue author on each of the given issues in chronological order. Comments
+// by bots are ignored.
+func collectResponses(ctx context.Context, client *github.Client, repository *github.Repository,
+	issues []*github.Issue, since time.Time) (map[int][]internal.Response, error) {
+	authors := make(map[int]string)
+	for _, issue := range issues {
+		authors[issue.GetNumber()] = issue.GetUser().GetLogin()
+	}
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.IssueListCommentsOptions{
+		Sort:        github.String("created"),
+		Direction:   github.String("asc"),
+		Since:       &since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	responses := make(map[int][]internal.Response)
+	responded := make(map[int]map[string]bool)
+	for {
+		// Issue number 0 lists the comments of all issues of the repository
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repo, 0, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching issue comments for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		for _, comment := range comments {
+			number, err := issueNumber(comment.GetIssueURL())
+			if err != nil {
+				return nil, err
+			}
+			author, ok := authors[number]
+			if !ok || comment.GetUser().GetLogin() == author || comment.GetUser().GetType() == "Bot" {
+				continue
+			}
+			responder := comment.GetUser().GetLogin()
+			if responded[number] == nil {
+				responded[number] = make(map[string]bool)
+			}
+			if responded[number][responder] {
+				continue
+			}
+			responded[number][responder] = true
+			responses[number] = append(responses[number], internal.Response{
+				Responder: responder,
+				Time:      comment.GetCreatedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return responses, nil
+}
+
+// issueNumber extracts the issue number from the given issue API URL.
+func issueNumber(issueURL string) (int, error) {
+	number, err := strconv.Atoi(issueURL[strings.LastIndex(issueURL, "/")+1:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid issue URL '%s': %w", issueURL, err)
+	}
+	return number, nil
+}
+
+// Initialize the 'issue-metrics' command.
+func init() {
+	rootCmd.AddCommand(issueMetricsCmd)
+
+	addReportFlags(issueMetricsCmd, issueMetricsFormatCfgKey, issueMetricsFilenameCfgKey)
+}
@@ -0,0 +1,25 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extracting issue numbers", func() {
+	It("parses the last path segment of issue URLs", func() {
+		number, err := issueNumber("https://api.github.com/repos/herdstat/herdstat/issues/42")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(number).To(Equal(42))
+	})
+	It("rejects malformed issue URLs", func() {
+		_, err := issueNumber("https://api.github.com/repos/herdstat/herdstat/issues")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the labels command
+const (
+	// The format of the report
+	labelsFormatCfgKey = "labels.format"
+	// The name of the output file
+	labelsFilenameCfgKey = "labels.filename"
+	// The name of the chart SVG file
+	labelsChartCfgKey = "labels.chart"
+	// The color of the chart bars
+	labelsColorCfgKey = "labels.color"
+)
+
+// labelsCmd represents the labels command
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Reports the number of open and closed issues per label",
+	Long: `Counts the issues open at the end of the analyzed period and the issues closed
+within the analyzed period per label across all repositories. The open issues
+of the most used labels are optionally rendered as an SVG bar chart.`,
+	Args: cobra.NoArgs,
+	RunE: runLabels,
+}
+
+func runLabels(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(labelsColorCfgKey)
+	barColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var issues []internal.Issue
+	for _, repository := range repositories {
+		i, err := collectIssueLifecycles(repository, lastDay)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, i...)
+	}
+	report := internal.NewLabelReport(issues, lastDay)
+
+	if chartFilename := viper.GetString(labelsChartCfgKey); chartFilename != "" {
+		chartFilename, err = writeSVG(cmd, internal.NewLabelChart(report, barColor), chartFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Label chart written to '%s'\n", chartFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(labelsFormatCfgKey), viper.GetString(labelsFilenameCfgKey))
+}
+
+// Initialize the 'labels' command.
+func init() {
+	rootCmd.AddCommand(labelsCmd)
+
+	// Flag to control the chart output file
+	const chartFlag = "chart"
+	labelsCmd.Flags().String(
+		chartFlag,
+		"",
+		"The name of the SVG file the label chart is written to (no chart if empty)")
+	if err := viper.BindPFlag(labelsChartCfgKey, labelsCmd.Flags().Lookup(chartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", chartFlag, "Error", err)
+	}
+
+	// Flag to control the chart color
+	const colorFlag = "color"
+	labelsCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the chart bars")
+	if err := viper.BindPFlag(labelsColorCfgKey, labelsCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(labelsCmd, labelsFormatCfgKey, labelsFilenameCfgKey)
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the languages command
+const (
+	// The format of the report
+	languagesFormatCfgKey = "languages.format"
+	// The name of the output file
+	languagesFilenameCfgKey = "languages.filename"
+	// The name of the chart SVG file
+	languagesChartCfgKey = "languages.chart"
+	// The color of the chart bars
+	languagesColorCfgKey = "languages.color"
+)
+
+// languagesCmd represents the languages command
+var languagesCmd = &cobra.Command{
+	Use:   "languages",
+	Short: "Reports the composition of the code by programming language",
+	Long: `Aggregates the language statistics GitHub computes for each of the analyzed
+repositories and reports the amount and share of code per language. The
+breakdown is optionally rendered as an SVG bar chart.`,
+	Args: cobra.NoArgs,
+	RunE: runLanguages,
+}
+
+func runLanguages(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(languagesColorCfgKey)
+	barColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	languages := make(map[string]map[string]int)
+	for _, repository := range repositories {
+		owner := repository.GetOwner().GetLogin()
+		repo := repository.GetName()
+		l, resp, err := client.Repositories.ListLanguages(ctx, owner, repo)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("fetching languages for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		languages[repository.GetFullName()] = l
+	}
+	report := internal.NewLanguageReport(languages)
+
+	if chartFilename := viper.GetString(languagesChartCfgKey); chartFilename != "" {
+		chartFilename, err = writeSVG(cmd, internal.NewLanguageChart(report, barColor), chartFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Language chart written to '%s'\n", chartFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(languagesFormatCfgKey), viper.GetString(languagesFilenameCfgKey))
+}
+
+// Initialize the 'languages' command.
+func init() {
+	rootCmd.AddCommand(languagesCmd)
+
+	// Flag to control the chart output file
+	const chartFlag = "chart"
+	languagesCmd.Flags().String(
+		chartFlag,
+		"",
+		"The name of the SVG file the language chart is written to (no chart if empty)")
+	if err := viper.BindPFlag(languagesChartCfgKey, languagesCmd.Flags().Lookup(chartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", chartFlag, "Error", err)
+	}
+
+	// Flag to control the chart color
+	const colorFlag = "color"
+	languagesCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the chart bars")
+	if err := viper.BindPFlag(languagesColorCfgKey, languagesCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(languagesCmd, languagesFormatCfgKey, languagesFilenameCfgKey)
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestVersion is the version of the format of manifests. It is
+// incremented on incompatible changes.
+const manifestVersion = 1
+
+// redactedValue replaces the values of secret parameters in manifests.
+const redactedValue = "<redacted>"
+
+// secretCfgKeys are the configuration keys whose values are redacted in
+// manifests.
+var secretCfgKeys = map[string]bool{
+	gitHubTokenCfgKey:           true,
+	uploadAccessKeyIDCfgKey:     true,
+	uploadSecretAccessKeyCfgKey: true,
+	uploadSessionTokenCfgKey:    true,
+	uploadSASTokenCfgKey:        true,
+	postgresDSNCfgKey:           true,
+	notifySlackWebhookCfgKey:    true,
+	notifyDiscordWebhookCfgKey:  true,
+	serveWebhookSecretCfgKey:    true,
+}
+
+// urlCfgKeys are the configuration keys holding URLs whose user information,
+// e.g., basic auth credentials, is removed in manifests.
+var urlCfgKeys = map[string]bool{
+	metricsPushgatewayCfgKey: true,
+	uploadEndpointCfgKey:     true,
+	digestHeatmapURLCfgKey:   true,
+}
+
+// artifact describes a file generated by a run.
+type artifact struct {
+
+	// The path of the file as given by the configuration.
+	Path string `json:"path"`
+
+	// The format of the file derived from its extension, e.g., 'svg'.
+	Format string `json:"format"`
+
+	// The size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// The hex-encoded SHA-256 checksum of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// manifest lists the files generated by a run along with the parameters of the
+// run, so that downstream steps can act on the outputs programmatically.
+type manifest struct {
+	Version int `json:"version"`
+
+	// The command run, e.g., 'contribution-graph'.
+	Command string `json:"command"`
+
+	// The time the manifest was written.
+	Generated time.Time `json:"generated"`
+
+	// The first and last day of the analyzed period. Empty if no
+	// contributions have been collected.
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+
+	// The global options and the options of the command by configuration
+	// key. Secrets are redacted.
+	Parameters map[string]any `json:"parameters"`
+
+	// The generated files in the order they have been written.
+	Artifacts []artifact `json:"artifacts"`
+}
+
+// formatAliases maps file extensions to the names of the formats they denote
+// unless they are the same.
+var formatAliases = map[string]string{
+	"md":  markdownFormat,
+	"yml": yamlFormat,
+	"htm": htmlFormat,
+	"db":  "sqlite",
+}
+
+// artifactFormat returns the format of the file with the given name derived
+// from its extension.
+func artifactFormat(filename string) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if format, ok := formatAliases[ext]; ok {
+		return format
+	}
+	if ext == "" {
+		return "unknown"
+	}
+	return ext
+}
+
+// newArtifact describes the generated file with the given name.
+func newArtifact(filename string) (artifact, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return artifact{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return artifact{}, err
+	}
+	return artifact{
+		Path:   filename,
+		Format: artifactFormat(filename),
+		Size:   size,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// commandName returns the name of the given command without the name of the
+// root command, e.g., 'repos list'.
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// manifestParameters returns the global options and the options of the given
+// command by configuration key. The values of secrets are redacted, URLs are
+// stripped of user information, and the arguments of plugins are left out as
+// they often carry credentials.
+func manifestParameters(command string) map[string]any {
+	section := strings.ReplaceAll(command, " ", ".") + "."
+	parameters := make(map[string]any)
+	for _, key := range viper.AllKeys() {
+		if strings.Contains(key, ".") && !strings.HasPrefix(key, section) {
+			continue
+		}
+		value := viper.Get(key)
+		switch {
+		case secretCfgKeys[key] && viper.GetString(key) != "":
+			value = redactedValue
+		case urlCfgKeys[key] && viper.GetString(key) != "":
+			value = withoutUserinfo(viper.GetString(key))
+		case key == pluginsCfgKey:
+			value = manifestPlugins()
+		}
+		parameters[key] = value
+	}
+	return parameters
+}
+
+// withoutUserinfo removes the user information from the given URL. URLs that
+// can't be parsed are redacted entirely.
+func withoutUserinfo(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactedValue
+	}
+	u.User = nil
+	return u.String()
+}
+
+// manifestPlugins describes the configured plugins without their arguments.
+func manifestPlugins() []map[string]string {
+	var configs []pluginConfig
+	if err := viper.UnmarshalKey(pluginsCfgKey, &configs); err != nil {
+		return nil
+	}
+	plugins := make([]map[string]string, 0, len(configs))
+	for _, c := range configs {
+		plugins = append(plugins, map[string]string{
+			"name":    c.Name,
+			"kind":    c.Kind,
+			"command": c.Command,
+		})
+	}
+	return plugins
+}
+
+// newManifest creates the manifest of the files generated so far by a run of
+// the given command. The manifest with the given name isn't listed itself.
+func newManifest(command string, filename string) (*manifest, error) {
+	m := &manifest{
+		Version:    manifestVersion,
+		Command:    command,
+		Generated:  time.Now().UTC(),
+		Parameters: manifestParameters(command),
+		Artifacts:  []artifact{},
+	}
+	if _, lastDay := results.collectedContributions(); !lastDay.IsZero() {
+		m.From = lastDay.AddDate(0, 0, -52*7+1).Format("2006-01-02")
+		m.Until = lastDay.Format("2006-01-02")
+	}
+	seen := map[string]bool{filename: true}
+	for _, generated := range results.generatedFiles() {
+		if seen[generated] {
+			continue
+		}
+		seen[generated] = true
+		a, err := newArtifact(generated)
+		if err != nil {
+			return nil, fmt.Errorf("describing generated file '%s' failed: %w", generated, err)
+		}
+		m.Artifacts = append(m.Artifacts, a)
+	}
+	return m, nil
+}
+
+// writeManifest writes the manifest of the files generated by the run of the
+// given command to the configured file. The manifest is recorded as generated
+// file itself, so that it's published along with the files it lists.
+func writeManifest(cmd *cobra.Command) error {
+	filename := viper.GetString(manifestCfgKey)
+	if filename == "" {
+		return nil
+	}
+	m, err := newManifest(commandName(cmd), filename)
+	if err != nil {
+		return classify(exitRenderError, err)
+	}
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return classify(exitRenderError, fmt.Errorf("encoding manifest failed: %w", err))
+	}
+	if err := os.WriteFile(filename, append(content, '\n'), 0644); err != nil {
+		return classify(exitRenderError, fmt.Errorf("writing manifest failed: %w", err))
+	}
+	results.recordFile(filename)
+	cmd.Printf("Manifest of %d generated files written to '%s'\n", len(m.Artifacts), filename)
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// writeGauge writes the gauge with the given name and help text to the given
+// writer in the Prometheus text exposition format. The samples are given by
+// their labels (e.g., 'type="commit"'), an empty string denotes a sample
+// without labels.
+func writeGauge(w io.Writer, name string, help string, samples map[string]float64) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	labels := make([]string, 0, len(samples))
+	for l := range samples {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		if l == "" {
+			_, _ = fmt.Fprintf(w, "%s %g\n", name, samples[l])
+		} else {
+			_, _ = fmt.Fprintf(w, "%s{%s} %g\n", name, l, samples[l])
+		}
+	}
+}
+
+// writeMetrics writes the community metrics of the run to the given writer in
+// the Prometheus text exposition format. Returns false if no metrics are
+// available, e.g., because no contributions have been collected.
+func (r *runResults) writeMetrics(w io.Writer) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	written := false
+	if s := r.summary; s != nil {
+		byType := make(map[string]float64)
+		for _, t := range []internal.ContributionType{internal.CommitContribution, internal.IssueContribution, internal.PullRequestContribution} {
+			byType[fmt.Sprintf("type=%q", t)] = float64(s.ContributionsByType[t])
+		}
+		writeGauge(w, "herdstat_contributions", "Number of contributions made within the 52 weeks analyzed by type.", byType)
+		writeGauge(w, "herdstat_last_day_contributions", "Number of contributions made on the last day analyzed.",
+			map[string]float64{"": float64(r.lastDayContributions)})
+		writeGauge(w, "herdstat_unique_contributors", "Number of distinct contributors within the 52 weeks analyzed.",
+			map[string]float64{"": float64(s.UniqueContributors)})
+		writeGauge(w, "herdstat_active_repositories", "Number of repositories with contributions within the 52 weeks analyzed.",
+			map[string]float64{"": float64(s.ActiveRepositories)})
+		writeGauge(w, "herdstat_collection_duration_seconds", "Time spent collecting contributions.",
+			map[string]float64{"": r.collectionDuration.Seconds()})
+		written = true
+	}
+	if len(r.rateLimitRemaining) != 0 {
+		remaining := make(map[string]float64)
+		for resource, n := range r.rateLimitRemaining {
+			remaining[fmt.Sprintf("resource=%q", resource)] = float64(n)
+		}
+		writeGauge(w, "herdstat_rate_limit_remaining", "Remaining requests of the GitHub API rate limit.", remaining)
+		written = true
+	}
+	return written
+}
+
+// pushMetrics pushes the community metrics of the run to the configured
+// Prometheus Pushgateway, replacing the metrics pushed before for the
+// configured job.
+func pushMetrics() error {
+	gateway := strings.TrimSuffix(viper.GetString(metricsPushgatewayCfgKey), "/")
+	if gateway == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	if !results.writeMetrics(&buf) {
+		logger.Debugw("No metrics available - nothing to push")
+		return nil
+	}
+	job := viper.GetString(metricsJobCfgKey)
+	req, err := http.NewRequest(http.MethodPut, gateway+"/metrics/job/"+url.PathEscape(job), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, viper.GetInt(retriesCfgKey), viper.GetDuration(retryDelayCfgKey))}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("pushing metrics failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("pushing metrics failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	logger.Debugw("Pushed metrics", "gateway", gateway, "job", job)
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("Metrics", func() {
+	lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+	contributions := []internal.Contribution{
+		{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "a", Date: lastDay},
+		{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "b", Date: lastDay.AddDate(0, 0, -1)},
+		{Type: internal.IssueContribution, Repository: "herdstat/other", Login: "a", Date: lastDay},
+	}
+
+	It("exposes the community metrics of the run", func() {
+		var r runResults
+		var buf bytes.Buffer
+		Expect(r.writeMetrics(&buf)).To(BeFalse())
+		r.recordContributions(contributions, lastDay)
+		r.recordCollectionDuration(1500 * time.Millisecond)
+		r.recordRateLimit("core", 4711)
+		Expect(r.writeMetrics(&buf)).To(BeTrue())
+		Expect(buf.String()).To(ContainSubstring("# TYPE herdstat_contributions gauge\n" +
+			"herdstat_contributions{type=\"commit\"} 2\n" +
+			"herdstat_contributions{type=\"issue\"} 1\n" +
+			"herdstat_contributions{type=\"pull-request\"} 0\n"))
+		Expect(buf.String()).To(ContainSubstring("herdstat_last_day_contributions 2\n"))
+		Expect(buf.String()).To(ContainSubstring("herdstat_unique_contributors 2\n"))
+		Expect(buf.String()).To(ContainSubstring("herdstat_active_repositories 2\n"))
+		Expect(buf.String()).To(ContainSubstring("herdstat_collection_duration_seconds 1.5\n"))
+		Expect(buf.String()).To(ContainSubstring("herdstat_rate_limit_remaining{resource=\"core\"} 4711\n"))
+	})
+
+	It("pushes the metrics to a Pushgateway", func() {
+		var request *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		DeferCleanup(server.Close)
+		old := viper.Get(metricsPushgatewayCfgKey)
+		DeferCleanup(viper.Set, metricsPushgatewayCfgKey, old)
+		viper.Set(metricsPushgatewayCfgKey, server.URL+"/")
+		results.reset()
+		DeferCleanup(results.reset)
+
+		results.recordContributions(contributions, lastDay)
+		Expect(pushMetrics()).To(Succeed())
+		Expect(request.Method).To(Equal(http.MethodPut))
+		Expect(request.URL.Path).To(Equal("/metrics/job/herdstat"))
+		Expect(string(body)).To(ContainSubstring("herdstat_unique_contributors 2\n"))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the new-contributors command
+const (
+	// The number of weeks before the analyzed period searched for prior contributions
+	newContributorsLookbackCfgKey = "new-contributors.lookback"
+	// The format of the report
+	newContributorsFormatCfgKey = "new-contributors.format"
+	// The name of the output file
+	newContributorsFilenameCfgKey = "new-contributors.filename"
+)
+
+// newContributorsCmd represents the new-contributors command
+var newContributorsCmd = &cobra.Command{
+	Use:   "new-contributors",
+	Short: "Classifies active contributors as first-time or returning contributors",
+	Long: `Classifies the contributors active within the analyzed period as first-time
+contributors or returning contributors based on their contributions within the
+configured number of weeks before the analyzed period. Reports the overall
+numbers as well as a monthly trend.`,
+	Args: cobra.NoArgs,
+	RunE: runNewContributors,
+}
+
+func runNewContributors(cmd *cobra.Command, args []string) error {
+	lookback := viper.GetInt(newContributorsLookbackCfgKey)
+	if lookback < 0 {
+		return fmt.Errorf("lookback must not be negative but is %d", lookback)
+	}
+	contributions, lastDay, err := collectContributionsWithLookback(cmd, lookback)
+	if err != nil {
+		return err
+	}
+	lookbackFrom := lastDay.AddDate(0, 0, -(52+lookback)*7+1)
+	report := internal.NewNewContributorsReport(contributions, lastDay, lookbackFrom)
+	return writeReport(cmd, report, viper.GetString(newContributorsFormatCfgKey), viper.GetString(newContributorsFilenameCfgKey))
+}
+
+// Initialize the 'new-contributors' command.
+func init() {
+	rootCmd.AddCommand(newContributorsCmd)
+
+	// Flag to control the lookback period
+	const lookbackFlag = "lookback"
+	newContributorsCmd.Flags().Int(lookbackFlag, 104,
+		"The number of weeks before the analyzed period searched for prior contributions")
+	if err := viper.BindPFlag(newContributorsLookbackCfgKey, newContributorsCmd.Flags().Lookup(lookbackFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", lookbackFlag, "Error", err)
+	}
+
+	addReportFlags(newContributorsCmd, newContributorsFormatCfgKey, newContributorsFilenameCfgKey)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// weeklySummary returns the summary of the contributions of the week ending
+// with the last day analyzed listing up to top contributors. The given link is
+// included, or the first SVG document published or uploaded if no link is
+// given. Returns nil if no contributions have been collected.
+func (r *runResults) weeklySummary(top int, link string) *internal.WeeklySummary {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.summary == nil {
+		return nil
+	}
+	if link == "" {
+		for _, u := range r.links {
+			if ext := path.Ext(u); ext == ".svg" || ext == svgzExtension {
+				link = u
+				break
+			}
+		}
+	}
+	return internal.NewWeeklySummary(r.contributions, r.lastDay, top, link)
+}
+
+// notifyResults posts the weekly summary of the run to the configured Slack
+// and Discord webhooks.
+func notifyResults() error {
+	slack := viper.GetString(notifySlackWebhookCfgKey)
+	discord := viper.GetString(notifyDiscordWebhookCfgKey)
+	if slack == "" && discord == "" {
+		return nil
+	}
+	top := viper.GetInt(notifyTopCfgKey)
+	if top < 0 {
+		return fmt.Errorf("number of top contributors must not be negative but is %d", top)
+	}
+	summary := results.weeklySummary(top, viper.GetString(notifyLinkCfgKey))
+	if summary == nil {
+		logger.Warnw("No contributions collected - nothing to notify about")
+		return nil
+	}
+	client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, viper.GetInt(retriesCfgKey), viper.GetDuration(retryDelayCfgKey))}
+	if slack != "" {
+		if err := postJSON(client, slack, map[string]interface{}{"text": summary.SlackText()}); err != nil {
+			return fmt.Errorf("notifying Slack failed: %w", err)
+		}
+	}
+	if discord != "" {
+		// Don't let contributor names ping anyone
+		if err := postJSON(client, discord, map[string]interface{}{
+			"content":          summary.DiscordText(),
+			"allowed_mentions": map[string]interface{}{"parse": []string{}},
+		}); err != nil {
+			return fmt.Errorf("notifying Discord failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// postJSON posts the given payload encoded as JSON to the given URL. As
+// webhook URLs are secrets, errors don't report the URL.
+func postJSON(client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return withoutURL(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return withoutURL(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
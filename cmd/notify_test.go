@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("Notifications", func() {
+	var payloads map[string]map[string]interface{}
+
+	BeforeEach(func() {
+		payloads = make(map[string]map[string]interface{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]interface{}
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			payloads[r.URL.Path] = payload
+		}))
+		DeferCleanup(server.Close)
+		for key, value := range map[string]string{
+			notifySlackWebhookCfgKey:   server.URL + "/slack",
+			notifyDiscordWebhookCfgKey: server.URL + "/discord",
+		} {
+			DeferCleanup(viper.Set, key, viper.GetString(key))
+			viper.Set(key, value)
+		}
+		results.reset()
+		DeferCleanup(results.reset)
+	})
+
+	It("posts the weekly summary linking the uploaded graph", func() {
+		lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+		results.recordContributions([]internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+		}, lastDay)
+		results.recordLink("https://example.com/stats/report.json")
+		results.recordLink("https://example.com/stats/contribution-graph.svg")
+		Expect(notifyResults()).To(Succeed())
+		Expect(payloads["/slack"]["text"]).To(ContainSubstring("Top contributors: jane (1)"))
+		Expect(payloads["/slack"]["text"]).To(ContainSubstring("<https://example.com/stats/contribution-graph.svg|Contribution graph>"))
+		Expect(payloads["/discord"]["content"]).To(ContainSubstring("1 contributions (1 commits, 0 issues, 0 pull requests) by 1 contributors"))
+		Expect(payloads["/discord"]["allowed_mentions"]).To(HaveKeyWithValue("parse", BeEmpty()))
+	})
+
+	It("doesn't notify if no contributions have been collected", func() {
+		Expect(notifyResults()).To(Succeed())
+		Expect(payloads).To(BeEmpty())
+	})
+	It("doesn't report the secret webhook URL in errors", func() {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		for key, value := range map[string]interface{}{
+			notifySlackWebhookCfgKey: closed.URL + "/services/T000/B000/secret",
+			retriesCfgKey:            0,
+		} {
+			DeferCleanup(viper.Set, key, viper.Get(key))
+			viper.Set(key, value)
+		}
+		lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+		results.recordContributions([]internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+		}, lastDay)
+		err := notifyResults()
+		Expect(err).To(MatchError(HavePrefix("notifying Slack failed: Post request failed")))
+		Expect(err.Error()).NotTo(ContainSubstring("secret"))
+	})
+})
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tdewolff/minify/v2"
+	"github.com/tdewolff/minify/v2/svg"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Supported formats of machine-readable reports.
+const (
+	jsonFormat     = "json"
+	yamlFormat     = "yaml"
+	markdownFormat = "markdown"
+	csvFormat      = "csv"
+	htmlFormat     = "html"
+)
+
+// markdownReport is implemented by reports that can be rendered as markdown.
+type markdownReport interface {
+
+	// Markdown renders the report as markdown.
+	Markdown() (string, error)
+}
+
+// csvReport is implemented by reports that can be rendered as CSV.
+type csvReport interface {
+
+	// CSV renders the report as CSV records.
+	CSV() [][]string
+}
+
+// htmlReport is implemented by reports that can be rendered as HTML.
+type htmlReport interface {
+
+	// HTML renders the report as a standalone HTML document.
+	HTML() (string, error)
+}
+
+// formatReport serializes the given report in the given format.
+func formatReport(report any, format string) ([]byte, error) {
+	switch format {
+	case jsonFormat:
+		return json.MarshalIndent(report, "", "  ")
+	case yamlFormat:
+		return yaml.Marshal(report)
+	case markdownFormat:
+		r, ok := report.(markdownReport)
+		if !ok {
+			return nil, fmt.Errorf("report can't be rendered as %s", markdownFormat)
+		}
+		md, err := r.Markdown()
+		return []byte(md), err
+	case csvFormat:
+		r, ok := report.(csvReport)
+		if !ok {
+			return nil, fmt.Errorf("report can't be rendered as %s", csvFormat)
+		}
+		var buf bytes.Buffer
+		if err := csv.NewWriter(&buf).WriteAll(r.CSV()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case htmlFormat:
+		r, ok := report.(htmlReport)
+		if !ok {
+			return nil, fmt.Errorf("report can't be rendered as %s", htmlFormat)
+		}
+		html, err := r.HTML()
+		return []byte(html), err
+	}
+	return nil, fmt.Errorf("unknown report format '%s'; supported are %s, %s, %s, %s, and %s",
+		format, jsonFormat, yamlFormat, markdownFormat, csvFormat, htmlFormat)
+}
+
+// writeReport serializes the given report in the given format and writes it
+// to the file with the given name. The report is written to stdout if no
+// filename is given.
+func writeReport(cmd *cobra.Command, report any, format string, filename string) error {
+	content, err := formatReport(report, format)
+	if err != nil {
+		return classify(exitRenderError, fmt.Errorf("formatting report failed: %w", err))
+	}
+	if filename == "" {
+		_, err := cmd.OutOrStdout().Write(append(bytes.TrimRight(content, "\n"), '\n'))
+		return classify(exitRenderError, err)
+	}
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return classify(exitRenderError, fmt.Errorf("writing report to file failed: %w", err))
+	}
+	results.recordFile(filename)
+	cmd.Printf("Report written to '%s'\n", filename)
+	return nil
+}
+
+// addReportFlags adds the flags controlling the format and the output file
+// of a report to the given command and binds them to the given configuration
+// keys.
+func addReportFlags(cmd *cobra.Command, formatCfgKey string, filenameCfgKey string) {
+	addReportFlagsWithDefault(cmd, formatCfgKey, filenameCfgKey, jsonFormat)
+}
+
+// addReportFlagsWithDefault adds the report flags like addReportFlags but
+// uses the given default format.
+func addReportFlagsWithDefault(cmd *cobra.Command, formatCfgKey string, filenameCfgKey string, defaultFormat string) {
+
+	// Flag to control the output format
+	const formatFlag = "format"
+	cmd.Flags().StringP(
+		formatFlag,
+		"f",
+		defaultFormat,
+		"The format of the report (json, yaml, markdown, csv, or html)")
+	if err := viper.BindPFlag(formatCfgKey, cmd.Flags().Lookup(formatFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", formatFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	cmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"",
+		"The name of the generated file (default is stdout)")
+	if err := viper.BindPFlag(filenameCfgKey, cmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
+
+// renderSVG renders the SVG document of the given renderer into a buffer,
+// e.g., for embedding it into other documents.
+func renderSVG(renderer internal.Renderer) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	if err := streamSVG(&buf, renderer); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// streamSVG renders the SVG document of the given renderer to the given
+// writer without buffering the whole document.
+func streamSVG(w io.Writer, renderer internal.Renderer) error {
+	enc := xml.NewEncoder(w)
+	if err := renderer.Render(enc); err != nil {
+		return classify(exitRenderError, fmt.Errorf("rending SVG failed: %w", err))
+	}
+	if err := enc.Flush(); err != nil {
+		return classify(exitRenderError, fmt.Errorf("flushing SVG encoder failed: %w", err))
+	}
+	return nil
+}
+
+// svgzExtension is the file extension of gzip-compressed SVG documents.
+const svgzExtension = ".svgz"
+
+// writeSVG streams the SVG document of the given renderer to the file with
+// the given name. The document is minified and/or gzip-compressed on the fly
+// if requested. Compression is also enabled for files having the .svgz
+// extension, and the .svg extension of compressed files is replaced
+// accordingly. Returns the name of the file written.
+func writeSVG(cmd *cobra.Command, renderer internal.Renderer, filename string, minifyOutput bool, gzipOutput bool) (string, error) {
+	gzipOutput = gzipOutput || strings.HasSuffix(filename, svgzExtension)
+	if gzipOutput && strings.HasSuffix(filename, ".svg") {
+		filename = strings.TrimSuffix(filename, ".svg") + svgzExtension
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return "", classify(exitRenderError, fmt.Errorf("can't create output file: %w", err))
+	}
+	defer f.Close()
+
+	if gzipOutput {
+		cmd.Printf("Compressing output\n")
+	}
+	if minifyOutput {
+		cmd.Printf("Minifying output\n")
+	}
+	if err := encodeSVG(f, renderer, minifyOutput, gzipOutput); err != nil {
+		return "", classify(exitRenderError, err)
+	}
+	results.recordFile(filename)
+	return filename, nil
+}
+
+// encodeSVG streams the SVG document of the given renderer to the given
+// writer. The document is minified and/or gzip-compressed on the fly if
+// requested.
+func encodeSVG(out io.Writer, renderer internal.Renderer, minifyOutput bool, gzipOutput bool) error {
+	w := out
+	var zw *gzip.Writer
+	if gzipOutput {
+		zw = gzip.NewWriter(out)
+		w = zw
+	}
+
+	var mw io.WriteCloser
+	if minifyOutput {
+		m := minify.New()
+		m.AddFunc("image/svg+xml", svg.Minify)
+		mw = m.Writer("image/svg+xml", w)
+		// Stops the minifier if rendering fails
+		defer mw.Close()
+		w = mw
+	}
+
+	if err := streamSVG(w, renderer); err != nil {
+		return err
+	}
+	if mw != nil {
+		if err := mw.Close(); err != nil {
+			return fmt.Errorf("output minification failed: %w", err)
+		}
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("compressing SVG failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// unsafeFilenameCharacters matches characters not to be used in filenames.
+var unsafeFilenameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// suffixedFilename derives the name of a file storing a variant of an output
+// (e.g., the graph of a single contributor) by inserting the given suffix
+// before the extension of the given filename.
+func suffixedFilename(filename string, suffix string) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(filename, ext), unsafeFilenameCharacters.ReplaceAllString(suffix, "_"), ext)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// testSVGRenderer renders an empty SVG document.
+type testSVGRenderer struct{}
+
+func (testSVGRenderer) Render(e *xml.Encoder) error {
+	svg := xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "http://www.w3.org/2000/svg"}},
+	}
+	if err := e.EncodeToken(svg); err != nil {
+		return err
+	}
+	return e.EncodeToken(svg.End())
+}
+
+var _ = Describe("Writing SVG documents", func() {
+
+	const document = `<svg xmlns="http://www.w3.org/2000/svg"></svg>`
+
+	When("compression is requested", func() {
+		It("writes a gzip-compressed file with the .svgz extension", func() {
+			dir, err := os.MkdirTemp("", "test-*")
+			Expect(err).NotTo(HaveOccurred())
+			filename, err := writeSVG(&cobra.Command{}, testSVGRenderer{}, filepath.Join(dir, "graph.svg"), false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filename).To(Equal(filepath.Join(dir, "graph.svgz")))
+			f, err := os.Open(filename)
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+			zr, err := gzip.NewReader(f)
+			Expect(err).NotTo(HaveOccurred())
+			content, err := io.ReadAll(zr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(document))
+		})
+	})
+
+	When("minification is requested", func() {
+		It("streams the minified document", func() {
+			dir, err := os.MkdirTemp("", "test-*")
+			Expect(err).NotTo(HaveOccurred())
+			filename, err := writeSVG(&cobra.Command{}, testSVGRenderer{}, filepath.Join(dir, "graph.svg"), true, false)
+			Expect(err).NotTo(HaveOccurred())
+			content, err := os.ReadFile(filename)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(`<svg xmlns="http://www.w3.org/2000/svg"/>`))
+		})
+	})
+
+	When("the filename has the .svgz extension", func() {
+		It("compresses the output", func() {
+			dir, err := os.MkdirTemp("", "test-*")
+			Expect(err).NotTo(HaveOccurred())
+			filename, err := writeSVG(&cobra.Command{}, testSVGRenderer{}, filepath.Join(dir, "graph.svgz"), false, false)
+			Expect(err).NotTo(HaveOccurred())
+			f, err := os.Open(filename)
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+			_, err = gzip.NewReader(f)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+// testCSVReport is a minimal report supporting the CSV format.
+type testCSVReport struct{}
+
+func (testCSVReport) CSV() [][]string {
+	return [][]string{{"a", "b"}, {"1", "2"}}
+}
+
+var _ = Describe("Formatting reports", func() {
+
+	When("the CSV format is requested", func() {
+		It("renders reports supporting CSV", func() {
+			content, err := formatReport(testCSVReport{}, csvFormat)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("a,b\n1,2\n"))
+		})
+		It("rejects reports not supporting CSV", func() {
+			_, err := formatReport(struct{}{}, csvFormat)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Naming output variants", func() {
+	It("inserts the suffix before the extension", func() {
+		Expect(suffixedFilename("graphs/contribution-graph.svg", "jdoe")).To(Equal("graphs/contribution-graph-jdoe.svg"))
+	})
+	It("replaces characters unsafe for filenames", func() {
+		Expect(suffixedFilename("graph.svg", "j doe@herdstat.com")).To(Equal("graph-j_doe_herdstat.com.svg"))
+	})
+})
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the overlap command
+const (
+	// The number of connectors listed
+	overlapConnectorsCfgKey = "overlap.connectors"
+	// The format of the report
+	overlapFormatCfgKey = "overlap.format"
+	// The name of the output file
+	overlapFilenameCfgKey = "overlap.filename"
+	// The name of the heatmap SVG file
+	overlapHeatmapCfgKey = "overlap.heatmap"
+	// The primary color used for coloring heatmap cells
+	overlapColorCfgKey = "overlap.color"
+)
+
+// overlapCmd represents the overlap command
+var overlapCmd = &cobra.Command{
+	Use:   "overlap",
+	Short: "Computes how many contributors are shared between repositories",
+	Long: `Computes for each pair of repositories the number of contributors active in
+both of them and lists the contributors active in the most repositories. Helps
+to identify silos and the people connecting them. The overlap matrix is
+optionally rendered as an SVG heatmap.`,
+	Args: cobra.NoArgs,
+	RunE: runOverlap,
+}
+
+func runOverlap(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(overlapColorCfgKey)
+	primaryColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+	connectors := viper.GetInt(overlapConnectorsCfgKey)
+	if connectors < 0 {
+		return fmt.Errorf("number of connectors must not be negative but is %d", connectors)
+	}
+
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewOverlapReport(contributions, lastDay, connectors)
+
+	if heatmapFilename := viper.GetString(overlapHeatmapCfgKey); heatmapFilename != "" {
+		interpolation, err := internal.ParseInterpolation(internal.RGBInterpolationName)
+		if err != nil {
+			return err
+		}
+		coloring := internal.GetColoring(getColorScheme(primaryColor), interpolation)
+		heatmapFilename, err = writeSVG(cmd, internal.NewOverlapHeatmap(report, coloring, 5, false), heatmapFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Overlap heatmap written to '%s'\n", heatmapFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(overlapFormatCfgKey), viper.GetString(overlapFilenameCfgKey))
+}
+
+// Initialize the 'overlap' command.
+func init() {
+	rootCmd.AddCommand(overlapCmd)
+
+	// Flag to control the number of connectors
+	const connectorsFlag = "connectors"
+	overlapCmd.Flags().Int(connectorsFlag, 10,
+		"The number of contributors active in the most repositories listed")
+	if err := viper.BindPFlag(overlapConnectorsCfgKey, overlapCmd.Flags().Lookup(connectorsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", connectorsFlag, "Error", err)
+	}
+
+	// Flag to control the heatmap output file
+	const heatmapFlag = "heatmap"
+	overlapCmd.Flags().String(
+		heatmapFlag,
+		"",
+		"The name of the SVG file the overlap heatmap is written to (no heatmap if empty)")
+	if err := viper.BindPFlag(overlapHeatmapCfgKey, overlapCmd.Flags().Lookup(heatmapFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", heatmapFlag, "Error", err)
+	}
+
+	// Flag to control the heatmap color
+	const colorFlag = "color"
+	overlapCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The primary color used for coloring heatmap cells")
+	if err := viper.BindPFlag(overlapColorCfgKey, overlapCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(overlapCmd, overlapFormatCfgKey, overlapFilenameCfgKey)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+)
+
+const (
+
+	// The name of the Parquet file containing the collected contributions
+	contributionsParquetFilename = "contributions.parquet"
+
+	// The name of the Parquet file containing the daily aggregates
+	dailyContributionsParquetFilename = "daily-contributions.parquet"
+)
+
+// exportParquet writes the collected contributions and their daily aggregates
+// as Parquet files to the configured directory.
+func exportParquet(cmd *cobra.Command) error {
+	directory := viper.GetString(parquetDirectoryCfgKey)
+	if directory == "" {
+		return nil
+	}
+	contributions, lastDay := results.collectedContributions()
+	if contributions == nil {
+		logger.Warnw("No contributions collected - nothing to export to Parquet", "directory", directory)
+		return nil
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return fmt.Errorf("creating Parquet directory failed: %w", err)
+	}
+	records := internal.NewAggregateExport(contributions, lastDay, internal.DailyGranularity).Records
+	for _, file := range []struct {
+		name  string
+		write func(b *bytes.Buffer) error
+	}{
+		{contributionsParquetFilename, func(b *bytes.Buffer) error {
+			return internal.WriteContributionsParquet(b, contributions)
+		}},
+		{dailyContributionsParquetFilename, func(b *bytes.Buffer) error {
+			return internal.WriteDailyContributionsParquet(b, records)
+		}},
+	} {
+		var b bytes.Buffer
+		if err := file.write(&b); err != nil {
+			return fmt.Errorf("encoding '%s' failed: %w", file.name, err)
+		}
+		path := filepath.Join(directory, file.name)
+		if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+			return fmt.Errorf("writing Parquet file failed: %w", err)
+		}
+		results.recordFile(path)
+	}
+	cmd.Printf("Contributions exported to Parquet files in '%s'\n", directory)
+	return nil
+}
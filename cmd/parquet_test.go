@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Parquet export", func() {
+	It("writes the contributions and daily aggregates", func() {
+		cmd := &cobra.Command{}
+		cmd.SetOut(io.Discard)
+		directory := filepath.Join(GinkgoT().TempDir(), "parquet")
+		DeferCleanup(viper.Set, parquetDirectoryCfgKey, viper.GetString(parquetDirectoryCfgKey))
+		viper.Set(parquetDirectoryCfgKey, directory)
+		results.reset()
+		DeferCleanup(results.reset)
+		Expect(exportParquet(cmd)).To(Succeed())
+		Expect(results.generatedFiles()).To(BeEmpty())
+
+		lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+		results.recordContributions([]internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+		}, lastDay)
+		Expect(exportParquet(cmd)).To(Succeed())
+		Expect(results.generatedFiles()).To(Equal([]string{
+			filepath.Join(directory, contributionsParquetFilename),
+			filepath.Join(directory, dailyContributionsParquetFilename),
+		}))
+	})
+})
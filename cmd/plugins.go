@@ -0,0 +1,278 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/viper"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The external programs adding contribution sources and output sinks
+const pluginsCfgKey = "plugins"
+
+// Kinds of plugins.
+const (
+	// Collectors contribute further contributions, e.g., from other forges
+	collectorPlugin = "collector"
+
+	// Sinks receive the collected contributions after a run, e.g., to push
+	// them to a data warehouse
+	sinkPlugin = "sink"
+)
+
+// pluginProtocolVersion is the version of the messages exchanged with
+// plugins. It is incremented on incompatible changes.
+const pluginProtocolVersion = 1
+
+// pluginConfig is the configuration of a single plugin. Plugins are programs
+// run once per run that receive a JSON request on stdin. Collectors respond
+// with the contributions they collected as JSON on stdout.
+type pluginConfig struct {
+	Name    string   `mapstructure:"name"`
+	Kind    string   `mapstructure:"kind"`
+	Command string   `mapstructure:"command"`
+	Args    []string `mapstructure:"args"`
+}
+
+// pluginContribution is a contribution as exchanged with plugins.
+type pluginContribution struct {
+	Type       string    `json:"type"`
+	Repository string    `json:"repository"`
+	Login      string    `json:"login,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Email      string    `json:"email,omitempty"`
+	Date       time.Time `json:"date"`
+	URL        string    `json:"url,omitempty"`
+	Hash       string    `json:"hash,omitempty"`
+	Subject    string    `json:"subject,omitempty"`
+	Signed     bool      `json:"signed,omitempty"`
+	SignedOff  bool      `json:"signedOff,omitempty"`
+	Files      []string  `json:"files,omitempty"`
+	Additions  int       `json:"additions,omitempty"`
+	Deletions  int       `json:"deletions,omitempty"`
+}
+
+// collectorRequest is the request sent to collectors. It gives the analyzed
+// period and the repositories resolved from the configuration.
+type collectorRequest struct {
+	Version      int       `json:"version"`
+	Since        time.Time `json:"since"`
+	Until        time.Time `json:"until"`
+	Repositories []string  `json:"repositories"`
+}
+
+// collectorResponse is the response of collectors.
+type collectorResponse struct {
+	Contributions []pluginContribution `json:"contributions"`
+}
+
+// sinkRequest is the request sent to sinks. It gives the first and last day
+// of the analyzed period, the collected contributions, and the files
+// generated by the run.
+type sinkRequest struct {
+	Version       int                  `json:"version"`
+	From          string               `json:"from"`
+	Until         string               `json:"until"`
+	Generated     time.Time            `json:"generated"`
+	Contributions []pluginContribution `json:"contributions"`
+	Files         []string             `json:"files"`
+}
+
+// toPluginContribution converts the given contribution for the exchange with
+// plugins.
+func toPluginContribution(c internal.Contribution) pluginContribution {
+	return pluginContribution{
+		Type:       string(c.Type),
+		Repository: c.Repository,
+		Login:      c.Login,
+		Name:       c.Name,
+		Email:      c.Email,
+		Date:       c.Date,
+		URL:        c.URL,
+		Hash:       c.Hash,
+		Subject:    c.Subject,
+		Signed:     c.Signed,
+		SignedOff:  c.SignedOff,
+		Files:      c.Files,
+		Additions:  c.Additions,
+		Deletions:  c.Deletions,
+	}
+}
+
+// toContribution converts the given contribution received from a plugin. It
+// fails if the contribution lacks its type, repository, or date.
+func (c pluginContribution) toContribution() (internal.Contribution, error) {
+	switch t := internal.ContributionType(c.Type); t {
+	case internal.CommitContribution, internal.IssueContribution, internal.PullRequestContribution:
+	default:
+		return internal.Contribution{}, fmt.Errorf("unknown contribution type '%s'", c.Type)
+	}
+	if c.Repository == "" {
+		return internal.Contribution{}, errors.New("contribution without repository")
+	}
+	if c.Date.IsZero() {
+		return internal.Contribution{}, fmt.Errorf("contribution to '%s' without date", c.Repository)
+	}
+	return internal.Contribution{
+		Type:       internal.ContributionType(c.Type),
+		Repository: c.Repository,
+		Login:      c.Login,
+		Name:       c.Name,
+		Email:      c.Email,
+		Date:       c.Date,
+		URL:        c.URL,
+		Hash:       c.Hash,
+		Subject:    c.Subject,
+		Signed:     c.Signed,
+		SignedOff:  c.SignedOff,
+		Files:      c.Files,
+		Additions:  c.Additions,
+		Deletions:  c.Deletions,
+	}, nil
+}
+
+// getPlugins retrieves the configured plugins of the given kind.
+func getPlugins(kind string) ([]pluginConfig, error) {
+	var configs []pluginConfig
+	if err := viper.UnmarshalKey(pluginsCfgKey, &configs); err != nil {
+		return nil, classify(exitConfigError, fmt.Errorf("invalid plugins: %w", err))
+	}
+	var plugins []pluginConfig
+	for _, config := range configs {
+		if config.Name == "" {
+			return nil, classify(exitConfigError, errors.New("plugins require a name"))
+		}
+		if config.Command == "" {
+			return nil, classify(exitConfigError, fmt.Errorf("plugin '%s' requires a command", config.Name))
+		}
+		switch config.Kind {
+		case collectorPlugin, sinkPlugin:
+		default:
+			return nil, classify(exitConfigError, fmt.Errorf("unknown kind '%s' of plugin '%s'; supported are %s and %s",
+				config.Kind, config.Name, collectorPlugin, sinkPlugin))
+		}
+		if config.Kind == kind {
+			plugins = append(plugins, config)
+		}
+	}
+	return plugins, nil
+}
+
+// runPlugin runs the given plugin with the given request encoded as JSON on
+// stdin. The output on stdout is decoded into the given response unless it is
+// nil.
+func runPlugin(plugin pluginConfig, request any, response any) error {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(plugin.Command, plugin.Args...)
+	c.Stdin = bytes.NewReader(input)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	logger.Debugw("Running plugin", "Plugin", plugin.Name, "Command", plugin.Command)
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin '%s' failed: %w: %s", plugin.Name, err, msg)
+		}
+		return fmt.Errorf("plugin '%s' failed: %w", plugin.Name, err)
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return fmt.Errorf("plugin '%s' responded with invalid JSON: %w", plugin.Name, err)
+	}
+	return nil
+}
+
+// collectPluginContributions collects the contributions made after since until
+// the given day from the configured collectors. The collectors are given the
+// names of the resolved repositories. Contributions outside the period are
+// left out.
+func collectPluginContributions(repositories map[url.URL]*github.Repository, since time.Time,
+	lastDay time.Time) ([]internal.Contribution, error) {
+	plugins, err := getPlugins(collectorPlugin)
+	if err != nil || len(plugins) == 0 {
+		return nil, err
+	}
+	request := collectorRequest{
+		Version: pluginProtocolVersion,
+		Since:   since,
+		Until:   lastDay,
+	}
+	for _, repository := range repositories {
+		request.Repositories = append(request.Repositories, repository.GetFullName())
+	}
+	sort.Strings(request.Repositories)
+	var contributions []internal.Contribution
+	for _, plugin := range plugins {
+		var response collectorResponse
+		if err := runPlugin(plugin, request, &response); err != nil {
+			return nil, classify(exitCollectionError, err)
+		}
+		var skipped int
+		for _, pc := range response.Contributions {
+			c, err := pc.toContribution()
+			if err != nil {
+				return nil, classify(exitCollectionError, fmt.Errorf("plugin '%s': %w", plugin.Name, err))
+			}
+			if !c.Date.After(since) || c.Date.After(lastDay) {
+				skipped++
+				continue
+			}
+			contributions = append(contributions, c)
+		}
+		logger.Infow("Collected contributions from plugin", "Plugin", plugin.Name,
+			"Count", len(response.Contributions)-skipped, "Outside period", skipped)
+	}
+	return contributions, nil
+}
+
+// runSinkPlugins hands the collected contributions and the generated files
+// over to the configured sinks.
+func runSinkPlugins() error {
+	plugins, err := getPlugins(sinkPlugin)
+	if err != nil || len(plugins) == 0 {
+		return err
+	}
+	contributions, lastDay := results.collectedContributions()
+	if contributions == nil {
+		logger.Warnw("No contributions collected - nothing to hand over to sink plugins")
+		return nil
+	}
+	request := sinkRequest{
+		Version:       pluginProtocolVersion,
+		From:          lastDay.AddDate(0, 0, -52*7+1).Format("2006-01-02"),
+		Until:         lastDay.Format("2006-01-02"),
+		Generated:     time.Now().UTC(),
+		Contributions: make([]pluginContribution, len(contributions)),
+		Files:         results.generatedFiles(),
+	}
+	for i, c := range contributions {
+		request.Contributions[i] = toPluginContribution(c)
+	}
+	for _, plugin := range plugins {
+		if err := runPlugin(plugin, request, nil); err != nil {
+			return classify(exitRenderError, err)
+		}
+		logger.Infow("Contributions handed over to plugin", "Plugin", plugin.Name, "Count", len(contributions))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// configurePlugins sets the given plugins for the current spec.
+func configurePlugins(plugins ...map[string]interface{}) {
+	DeferCleanup(viper.Set, pluginsCfgKey, []map[string]interface{}{})
+	viper.Set(pluginsCfgKey, append([]map[string]interface{}{}, plugins...))
+}
+
+// shellPlugin returns the configuration of a plugin of the given kind running
+// the given shell script.
+func shellPlugin(name string, kind string, script string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":    name,
+		"kind":    kind,
+		"command": "sh",
+		"args":    []string{"-c", script},
+	}
+}
+
+var _ = Describe("Plugins", func() {
+	lastDay := time.Date(2023, time.April, 1, 23, 59, 59, 0, time.UTC)
+	since := lastDay.AddDate(0, 0, -52*7)
+
+	It("rejects invalid plugins", func() {
+		configurePlugins(map[string]interface{}{"name": "broken", "kind": "exporter", "command": "true"})
+		_, err := getPlugins(collectorPlugin)
+		Expect(err).To(MatchError(ContainSubstring("unknown kind 'exporter'")))
+		Expect(exitCodeOf(err)).To(Equal(exitConfigError))
+	})
+
+	It("adds the contributions of collectors within the period", func() {
+		request := filepath.Join(GinkgoT().TempDir(), "request.json")
+		configurePlugins(shellPlugin("gitlab", collectorPlugin, `cat > `+request+`; echo '{"contributions": [
+			{"type": "commit", "repository": "gitlab.com/herdstat/mirror", "login": "jane", "date": "2023-03-01T12:00:00Z"},
+			{"type": "issue", "repository": "gitlab.com/herdstat/mirror", "login": "joe", "date": "2020-01-01T12:00:00Z"}
+		]}'`))
+		u, _ := url.Parse("https://github.com/herdstat/herdstat")
+		repositories := map[url.URL]*github.Repository{*u: {FullName: github.String("herdstat/herdstat")}}
+		contributions, err := collectPluginContributions(repositories, since, lastDay)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contributions).To(Equal([]internal.Contribution{{
+			Type:       internal.CommitContribution,
+			Repository: "gitlab.com/herdstat/mirror",
+			Login:      "jane",
+			Date:       time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC),
+		}}))
+		content, err := os.ReadFile(request)
+		Expect(err).NotTo(HaveOccurred())
+		var received collectorRequest
+		Expect(json.Unmarshal(content, &received)).To(Succeed())
+		Expect(received.Version).To(Equal(pluginProtocolVersion))
+		Expect(received.Repositories).To(Equal([]string{"herdstat/herdstat"}))
+		Expect(received.Until).To(BeTemporally("==", lastDay))
+	})
+
+	It("rejects invalid contributions of collectors", func() {
+		configurePlugins(shellPlugin("broken", collectorPlugin,
+			`cat > /dev/null; echo '{"contributions": [{"type": "review", "repository": "a/b", "date": "2023-03-01T12:00:00Z"}]}'`))
+		_, err := collectPluginContributions(nil, since, lastDay)
+		Expect(err).To(MatchError(ContainSubstring("unknown contribution type 'review'")))
+		Expect(exitCodeOf(err)).To(Equal(exitCollectionError))
+	})
+
+	It("reports the errors of failing plugins", func() {
+		configurePlugins(shellPlugin("failing", collectorPlugin, `echo "no access" >&2; exit 3`))
+		_, err := collectPluginContributions(nil, since, lastDay)
+		Expect(err).To(MatchError(ContainSubstring("plugin 'failing' failed: exit status 3: no access")))
+	})
+
+	It("hands the collected contributions over to sinks", func() {
+		output := filepath.Join(GinkgoT().TempDir(), "sink.json")
+		configurePlugins(shellPlugin("warehouse", sinkPlugin, `cat > `+output))
+		results.reset()
+		DeferCleanup(results.reset)
+		Expect(runSinkPlugins()).To(Succeed())
+		Expect(output).NotTo(BeAnExistingFile())
+
+		results.recordContributions([]internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+		}, lastDay)
+		results.recordFile("contribution-graph.svg")
+		Expect(runSinkPlugins()).To(Succeed())
+		content, err := os.ReadFile(output)
+		Expect(err).NotTo(HaveOccurred())
+		var received sinkRequest
+		Expect(json.Unmarshal(content, &received)).To(Succeed())
+		Expect(received.From).To(Equal("2022-04-03"))
+		Expect(received.Until).To(Equal("2023-04-01"))
+		Expect(received.Files).To(Equal([]string{"contribution-graph.svg"}))
+		Expect(received.Contributions).To(HaveLen(1))
+		Expect(received.Contributions[0].Login).To(Equal("jane"))
+	})
+})
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// postgresSchema creates the tables contributions are upserted into unless
+// they exist already.
+var postgresSchema = []string{
+	`CREATE TABLE IF NOT EXISTS herdstat_contributions (
+		key TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		repository TEXT NOT NULL,
+		contributor TEXT,
+		login TEXT,
+		name TEXT,
+		email TEXT,
+		date TIMESTAMPTZ NOT NULL,
+		url TEXT,
+		hash TEXT,
+		subject TEXT,
+		signed BOOLEAN NOT NULL,
+		signed_off BOOLEAN NOT NULL,
+		files TEXT[],
+		additions INTEGER NOT NULL,
+		deletions INTEGER NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS herdstat_daily_contributions (
+		date DATE NOT NULL,
+		repository TEXT NOT NULL,
+		commits INTEGER NOT NULL,
+		issues INTEGER NOT NULL,
+		pull_requests INTEGER NOT NULL,
+		contributors INTEGER NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (date, repository)
+	)`,
+}
+
+// upsertContributionStatement inserts a contribution or updates the one with
+// the same key.
+const upsertContributionStatement = `INSERT INTO herdstat_contributions
+	(key, type, repository, contributor, login, name, email, date, url, hash, subject, signed, signed_off, files,
+	 additions, deletions, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	ON CONFLICT (key) DO UPDATE SET
+		type = EXCLUDED.type, repository = EXCLUDED.repository, contributor = EXCLUDED.contributor,
+		login = EXCLUDED.login, name = EXCLUDED.name, email = EXCLUDED.email, date = EXCLUDED.date,
+		url = EXCLUDED.url, hash = EXCLUDED.hash, subject = EXCLUDED.subject, signed = EXCLUDED.signed,
+		signed_off = EXCLUDED.signed_off, files = EXCLUDED.files, additions = EXCLUDED.additions,
+		deletions = EXCLUDED.deletions, updated_at = EXCLUDED.updated_at`
+
+// upsertDailyContributionsStatement inserts the daily aggregates of a
+// repository or updates the existing ones.
+const upsertDailyContributionsStatement = `INSERT INTO herdstat_daily_contributions
+	(date, repository, commits, issues, pull_requests, contributors, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (date, repository) DO UPDATE SET
+		commits = EXCLUDED.commits, issues = EXCLUDED.issues, pull_requests = EXCLUDED.pull_requests,
+		contributors = EXCLUDED.contributors, updated_at = EXCLUDED.updated_at`
+
+// contributionKeys returns the keys identifying the given contributions
+// across runs. Contributions are identified by their commit hash or URL.
+// Commits collected from the commit activity statistics have neither and are
+// identified by repository, contributor, and date instead, numbered
+// consecutively in order of occurrence.
+func contributionKeys(contributions []internal.Contribution) []string {
+	keys := make([]string, len(contributions))
+	occurrences := make(map[string]int)
+	for i, c := range contributions {
+		switch {
+		case c.Hash != "":
+			keys[i] = fmt.Sprintf("%s@%s", c.Repository, c.Hash)
+		case c.URL != "":
+			keys[i] = c.URL
+		default:
+			key := fmt.Sprintf("%s/%s/%s", c.Repository, c.Contributor(), c.Date.UTC().Format(time.RFC3339))
+			occurrences[key]++
+			keys[i] = fmt.Sprintf("%s#%d", key, occurrences[key])
+		}
+	}
+	return keys
+}
+
+// nullString returns the given string as SQL parameter that is NULL if the
+// string is empty.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// exportPostgres upserts the collected contributions and their daily
+// aggregates into the PostgreSQL database given by the configured DSN. The
+// tables are created if they don't exist.
+func exportPostgres() error {
+	dsn := viper.GetString(postgresDSNCfgKey)
+	if dsn == "" {
+		return nil
+	}
+	contributions, lastDay := results.collectedContributions()
+	if contributions == nil {
+		logger.Warnw("No contributions collected - nothing to export to PostgreSQL")
+		return nil
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return fmt.Errorf("connecting to PostgreSQL failed: %w", err)
+	}
+	defer db.Close()
+	if err := upsertPostgres(context.Background(), db, contributions, lastDay, time.Now()); err != nil {
+		return fmt.Errorf("exporting to PostgreSQL failed: %w", err)
+	}
+	logger.Infow("Contributions exported to PostgreSQL", "contributions", len(contributions))
+	return nil
+}
+
+// upsertPostgres upserts the given contributions and their daily aggregates
+// for the 52 weeks ending with the given day in a single transaction.
+func upsertPostgres(ctx context.Context, db *sql.DB, contributions []internal.Contribution, lastDay time.Time,
+	now time.Time) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for _, statement := range postgresSchema {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("creating tables failed: %w", err)
+		}
+	}
+	upsertContribution, err := tx.PrepareContext(ctx, upsertContributionStatement)
+	if err != nil {
+		return err
+	}
+	defer upsertContribution.Close()
+	for i, key := range contributionKeys(contributions) {
+		c := contributions[i]
+		if _, err := upsertContribution.ExecContext(ctx, key, string(c.Type), c.Repository, nullString(c.Contributor()),
+			nullString(c.Login), nullString(c.Name), nullString(c.Email), c.Date, nullString(c.URL),
+			nullString(c.Hash), nullString(c.Subject), c.Signed, c.SignedOff, pq.Array(c.Files), c.Additions,
+			c.Deletions, now); err != nil {
+			return fmt.Errorf("upserting contribution '%s' failed: %w", key, err)
+		}
+	}
+	upsertDailyContributions, err := tx.PrepareContext(ctx, upsertDailyContributionsStatement)
+	if err != nil {
+		return err
+	}
+	defer upsertDailyContributions.Close()
+	for _, r := range internal.NewAggregateExport(contributions, lastDay, internal.DailyGranularity).Records {
+		if _, err := upsertDailyContributions.ExecContext(ctx, r.Date, r.Repository, r.Commits, r.Issues,
+			r.PullRequests, r.Contributors, now); err != nil {
+			return fmt.Errorf("upserting daily contributions of '%s' on %s failed: %w", r.Repository, r.Date, err)
+		}
+	}
+	return tx.Commit()
+}
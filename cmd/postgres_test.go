@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"os"
+	"time"
+)
+
+var _ = Describe("PostgreSQL export", func() {
+	lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+	contributions := []internal.Contribution{
+		{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay, Hash: "abc"},
+		{Type: internal.IssueContribution, Repository: "herdstat/herdstat", Login: "john", Date: lastDay,
+			URL: "https://github.com/herdstat/herdstat/issues/1"},
+		{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+		{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+	}
+
+	It("identifies contributions across runs", func() {
+		Expect(contributionKeys(contributions)).To(Equal([]string{
+			"herdstat/herdstat@abc",
+			"https://github.com/herdstat/herdstat/issues/1",
+			"herdstat/herdstat/jane/2023-04-01T00:00:00Z#1",
+			"herdstat/herdstat/jane/2023-04-01T00:00:00Z#2",
+		}))
+	})
+
+	It("upserts contributions and daily aggregates", func() {
+		dsn := os.Getenv("HERDSTAT_TEST_POSTGRES_DSN")
+		if dsn == "" {
+			Skip("HERDSTAT_TEST_POSTGRES_DSN isn't set")
+		}
+		db, err := sql.Open("postgres", dsn)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+		for _, table := range []string{"herdstat_contributions", "herdstat_daily_contributions"} {
+			_, err := db.Exec("DROP TABLE IF EXISTS " + table)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		// Upserting twice doesn't duplicate rows
+		Expect(upsertPostgres(context.Background(), db, contributions, lastDay, time.Now())).To(Succeed())
+		Expect(upsertPostgres(context.Background(), db, contributions, lastDay, time.Now())).To(Succeed())
+		var count, commits int
+		Expect(db.QueryRow("SELECT COUNT(*) FROM herdstat_contributions").Scan(&count)).To(Succeed())
+		Expect(count).To(Equal(4))
+		Expect(db.QueryRow("SELECT commits FROM herdstat_daily_contributions").Scan(&commits)).To(Succeed())
+		Expect(commits).To(Equal(3))
+	})
+})
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// Configuration keys for the pr-metrics command
+const (
+	// The format of the report
+	prMetricsFormatCfgKey = "pr-metrics.format"
+	// The name of the output file
+	prMetricsFilenameCfgKey = "pr-metrics.filename"
+)
+
+// prMetricsCmd represents the pr-metrics command
+var prMetricsCmd = &cobra.Command{
+	Use:   "pr-metrics",
+	Short: "Reports how quickly pull requests are reviewed",
+	Long: `Reports the median and 90th percentile of the time to first review for pull
+requests opened within the analyzed period per repository and across all
+repositories. Reviews by the pull request author and by bots are ignored.`,
+	Args: cobra.NoArgs,
+	RunE: runPullRequestMetrics,
+}
+
+func runPullRequestMetrics(cmd *cobra.Command, args []string) error {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	pullRequests, err := collectPullRequestsFor(cmd, lastDay)
+	if err != nil {
+		return err
+	}
+	report := internal.NewPullRequestMetricsReport(pullRequests, lastDay)
+	return writeReport(cmd, report, viper.GetString(prMetricsFormatCfgKey), viper.GetString(prMetricsFilenameCfgKey))
+}
+
+// collectPullRequestsFor resolves the configured repositories and collects the
+// pull requests opened within the 52 weeks ending with the given day.
+func collectPullRequestsFor(cmd *cobra.Command, lastDay time.Time) ([]internal.PullRequest, error) {
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return nil, err
+	}
+	var pullRequests []internal.PullRequest
+	for _, repository := range repositories {
+		prs, err := collectPullRequests(repository, lastDay.AddDate(0, 0, -52*7))
+		if err != nil {
+			return nil, err
+		}
+		pullRequests = append(pullRequests, prs...)
+	}
+	return pullRequests, nil
+}
+
+// Initialize the 'pr-metrics' command.
+func init() {
+	rootCmd.AddCommand(prMetricsCmd)
+
+	addReportFlags(prMetricsCmd, prMetricsFormatCfgKey, prMetricsFilenameCfgKey)
+}
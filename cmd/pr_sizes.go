@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// Configuration keys for the pr-sizes command
+const (
+	// The format of the report
+	pullRequestSizesFormatCfgKey = "pr-sizes.format"
+	// The name of the output file
+	pullRequestSizesFilenameCfgKey = "pr-sizes.filename"
+)
+
+// pullRequestSizesCmd represents the pr-sizes command
+var pullRequestSizesCmd = &cobra.Command{
+	Use:   "pr-sizes",
+	Short: "Reports the distribution of pull request sizes and their time to merge",
+	Long: `Classifies the merged pull requests by the number of lines they change and
+reports the number of pull requests and the time to merge per size class as
+well as the correlation between size and time to merge. This allows teams to
+make the case for smaller pull requests with their own data.`,
+	Args: cobra.NoArgs,
+	RunE: runPullRequestSizes,
+}
+
+func runPullRequestSizes(cmd *cobra.Command, args []string) error {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var pullRequests []internal.PullRequest
+	for _, repository := range repositories {
+		prs, err := collectMergedPullRequestSizes(repository, lastDay.AddDate(0, 0, -52*7))
+		if err != nil {
+			return err
+		}
+		pullRequests = append(pullRequests, prs...)
+	}
+	report := internal.NewPullRequestSizeReport(pullRequests, lastDay)
+	return writeReport(cmd, report, viper.GetString(pullRequestSizesFormatCfgKey), viper.GetString(pullRequestSizesFilenameCfgKey))
+}
+
+// collectMergedPullRequestSizes collects the pull requests of the given
+// repository merged after since including the number of changed files and
+// lines. As these numbers are not part of pull request listings, each pull
+// request is fetched individually.
+func collectMergedPullRequestSizes(repository *github.Repository, since time.Time) ([]internal.PullRequest, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	merged, err := listMergedPullRequests(ctx, client, repository, since)
+	if err != nil {
+		return nil, err
+	}
+	var pullRequests []internal.PullRequest
+	for _, m := range merged {
+		pr, resp, err := client.PullRequests.Get(ctx, owner, repo, m.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching PR %s/%s#%d failed (Statuscode: %d)", owner, repo, m.GetNumber(), resp.StatusCode)
+		}
+		pullRequests = append(pullRequests, internal.PullRequest{
+			Repository:   repository.GetFullName(),
+			Number:       pr.GetNumber(),
+			Author:       pr.GetUser().GetLogin(),
+			Created:      pr.GetCreatedAt().Time,
+			Merged:       pr.GetMergedAt().Time,
+			ChangedFiles: pr.GetChangedFiles(),
+			Additions:    pr.GetAdditions(),
+			Deletions:    pr.GetDeletions(),
+		})
+	}
+	return pullRequests, nil
+}
+
+// Initialize the 'pr-sizes' command.
+func init() {
+	rootCmd.AddCommand(pullRequestSizesCmd)
+
+	addReportFlags(pullRequestSizesCmd, pullRequestSizesFormatCfgKey, pullRequestSizesFilenameCfgKey)
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+// The named outputs generated from the shared contributions
+const profilesCfgKey = "contribution-graph.profiles"
+
+// profileConfig is the configuration of a single output profile. Unset
+// options default to the respective options of the contribution-graph
+// command.
+type profileConfig struct {
+	Name          string   `mapstructure:"name"`
+	Repositories  []string `mapstructure:"repositories"`
+	Color         string   `mapstructure:"color"`
+	Levels        uint     `mapstructure:"levels"`
+	Interpolation string   `mapstructure:"interpolation"`
+	Filename      string   `mapstructure:"filename"`
+}
+
+// graphProfile is a contribution graph generated from the contributions made
+// to a subset of the collected repositories.
+type graphProfile struct {
+	repositories []string
+	style        graphStyle
+	filename     string
+
+	// Selects the repositories in 'owner/name' notation of the profile, all
+	// collected repositories if nil
+	selects func(repository string) bool
+}
+
+// getProfiles retrieves the output profiles from the configuration. The given
+// style and filename are used for options not set by a profile.
+func getProfiles(style graphStyle, filename string) ([]graphProfile, error) {
+	var configs []profileConfig
+	if err := viper.UnmarshalKey(profilesCfgKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid profiles: %w", err)
+	}
+	var profiles []graphProfile
+	filenames := make(map[string]string)
+	for _, config := range configs {
+		if config.Name == "" {
+			return nil, errors.New("profiles require a name")
+		}
+		profile := graphProfile{
+			repositories: config.Repositories,
+			style:        style,
+			filename:     config.Filename,
+		}
+		if len(config.Repositories) != 0 {
+			matcher, err := newRepositoryMatcher(config.Repositories)
+			if err != nil {
+				return nil, fmt.Errorf("profile '%s': %w", config.Name, err)
+			}
+			profile.selects = matcher.matches
+		}
+		var err error
+		if config.Color != "" {
+			if profile.style.primaryColor, err = parsePrimaryColor(config.Color); err != nil {
+				return nil, fmt.Errorf("profile '%s': %w", config.Name, err)
+			}
+		}
+		if config.Levels != 0 {
+			if profile.style.levels, err = checkLevels(config.Levels); err != nil {
+				return nil, fmt.Errorf("profile '%s': %w", config.Name, err)
+			}
+		}
+		if config.Interpolation != "" {
+			if profile.style.interpolation, err = internal.ParseInterpolation(config.Interpolation); err != nil {
+				return nil, fmt.Errorf("profile '%s': %w", config.Name, err)
+			}
+		}
+		if profile.filename == "" {
+			profile.filename = suffixedFilename(filename, config.Name)
+		}
+		if other, ok := filenames[profile.filename]; ok {
+			return nil, fmt.Errorf("profiles '%s' and '%s' are both written to '%s'", other, config.Name, profile.filename)
+		}
+		filenames[profile.filename] = config.Name
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// repositoryMatcher matches repositories in 'owner/name' notation against
+// entries given like the configured repositories, i.e., owners, repositories,
+// and patterns selecting repositories of owners (see repositorySelector).
+type repositoryMatcher struct {
+	owners       map[string]bool
+	repositories map[string]bool
+	selectors    map[string]*repositorySelector
+}
+
+// newRepositoryMatcher creates a matcher for the given entries.
+func newRepositoryMatcher(entries []string) (*repositoryMatcher, error) {
+	m := &repositoryMatcher{
+		owners:       make(map[string]bool),
+		repositories: make(map[string]bool),
+		selectors:    make(map[string]*repositorySelector),
+	}
+	for _, entry := range entries {
+		if owner, name, ok := strings.Cut(entry, "/"); ok && isRepositoryPattern(name) {
+			if ownerOrRepoIDPattern.FindString(owner) != owner {
+				return nil, fmt.Errorf("'%s' is not a valid owner", owner)
+			}
+			owner = strings.ToLower(owner)
+			selector, ok := m.selectors[owner]
+			if !ok {
+				selector = &repositorySelector{}
+				m.selectors[owner] = selector
+			}
+			if err := selector.add(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if ownerOrRepoIDPattern.FindString(entry) != entry {
+			return nil, fmt.Errorf("'%s' is not a valid owner or owner/repository", entry)
+		}
+		if strings.Contains(entry, "/") {
+			m.repositories[strings.ToLower(entry)] = true
+		} else {
+			m.owners[strings.ToLower(entry)] = true
+		}
+	}
+	return m, nil
+}
+
+// matches returns true iff the given repository in 'owner/name' notation is
+// one of the repositories, owned by one of the owners, or selected by the
+// patterns of its owner.
+func (m *repositoryMatcher) matches(repository string) bool {
+	repository = strings.ToLower(repository)
+	owner, name, _ := strings.Cut(repository, "/")
+	if m.repositories[repository] || m.owners[owner] {
+		return true
+	}
+	selector, ok := m.selectors[owner]
+	return ok && selector.selects(name)
+}
+
+// contributions returns the given contributions made to the repositories of
+// the profile.
+func (p graphProfile) contributions(contributions []internal.Contribution) []internal.Contribution {
+	if p.selects == nil {
+		return contributions
+	}
+	var selected []internal.Contribution
+	for _, c := range contributions {
+		if p.selects(c.Repository) {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// profileRepositories returns the given repositories extended by the
+// repositories of the given profiles without duplicates.
+func profileRepositories(repos []string, profiles []graphProfile) []string {
+	var union []string
+	seen := make(map[string]bool)
+	add := func(repos []string) {
+		for _, repo := range repos {
+			if !seen[strings.ToLower(repo)] {
+				seen[strings.ToLower(repo)] = true
+				union = append(union, repo)
+			}
+		}
+	}
+	add(repos)
+	for _, p := range profiles {
+		add(p.repositories)
+	}
+	return union
+}
+
+// collectProfileContributions collects the contributions made to the
+// configured repositories and the repositories of the given profiles within the
+// 52 weeks ending with the configured "until" date. Profiles without
+// repositories are restricted to the configured repositories. Returns the
+// contributions and the last day covered.
+func collectProfileContributions(cmd *cobra.Command, profiles []graphProfile) ([]internal.Contribution, time.Time, error) {
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repos := viper.GetStringSlice(repositoriesCfgKey)
+	start := time.Now()
+	repositories, err := resolveRepositories(cmd, profileRepositories(repos, profiles))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var configured map[string]bool
+	for i, p := range profiles {
+		if p.selects != nil {
+			continue
+		}
+		if configured == nil {
+			resolved, err := collectRepositories(repos)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+			configured = make(map[string]bool)
+			for _, repository := range resolved {
+				configured[strings.ToLower(repository.GetFullName())] = true
+			}
+		}
+		profiles[i].selects = func(repository string) bool {
+			return configured[strings.ToLower(repository)]
+		}
+	}
+	contributions, err := collectRepositoryContributions(repositories, lastDay.AddDate(0, 0, -52*7), lastDay)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	results.recordCollectionDuration(time.Since(start))
+	results.recordContributions(contributions, lastDay)
+	return contributions, lastDay, nil
+}
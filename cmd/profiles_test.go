@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+)
+
+var _ = Describe("Output profiles", func() {
+	var style graphStyle
+
+	BeforeEach(func() {
+		var err error
+		style, err = getGraphStyle()
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(viper.Set, profilesCfgKey, []map[string]interface{}{})
+	})
+
+	It("defaults to the options of the command", func() {
+		viper.Set(profilesCfgKey, []map[string]interface{}{
+			{"name": "org"},
+			{"name": "sig/network", "repositories": []string{"herdstat/network"}, "color": "0000FF", "levels": 7,
+				"filename": "network.svg"},
+		})
+		profiles, err := getProfiles(style, "graph.svg")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profiles).To(HaveLen(2))
+		Expect(profiles[0].style.primaryColor).To(Equal(style.primaryColor))
+		Expect(profiles[0].style.levels).To(Equal(style.levels))
+		Expect(profiles[0].filename).To(Equal("graph-org.svg"))
+		blue, err := parsePrimaryColor("0000FF")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profiles[1].style.primaryColor).To(Equal(blue))
+		Expect(profiles[1].style.levels).To(Equal(uint8(7)))
+		Expect(profiles[1].style.interpolation).NotTo(BeNil())
+		Expect(profiles[1].filename).To(Equal("network.svg"))
+	})
+
+	It("rejects profiles written to the same file", func() {
+		viper.Set(profilesCfgKey, []map[string]interface{}{
+			{"name": "a", "filename": "graph.svg"},
+			{"name": "b", "filename": "graph.svg"},
+		})
+		_, err := getProfiles(style, "graph.svg")
+		Expect(err).To(MatchError(ContainSubstring("profiles 'a' and 'b' are both written to 'graph.svg'")))
+	})
+
+	It("rejects invalid repositories of profiles", func() {
+		viper.Set(profilesCfgKey, []map[string]interface{}{
+			{"name": "a", "repositories": []string{"herdstat/^tool-("}},
+		})
+		_, err := getProfiles(style, "graph.svg")
+		Expect(err).To(MatchError(ContainSubstring("profile 'a': invalid repository pattern")))
+	})
+
+	It("selects the contributions to the repositories of the profile", func() {
+		contributions := []internal.Contribution{
+			{Repository: "herdstat/network"},
+			{Repository: "herdstat/storage"},
+			{Repository: "other/network"},
+		}
+		profile := func(repositories ...string) graphProfile {
+			matcher, err := newRepositoryMatcher(repositories)
+			Expect(err).NotTo(HaveOccurred())
+			return graphProfile{repositories: repositories, selects: matcher.matches}
+		}
+		Expect(graphProfile{}.contributions(contributions)).To(HaveLen(3))
+		Expect(profile("herdstat").contributions(contributions)).To(HaveLen(2))
+		Expect(profile("Herdstat/Network", "other").contributions(contributions)).
+			To(Equal([]internal.Contribution{{Repository: "herdstat/network"}, {Repository: "other/network"}}))
+		Expect(profile("herdstat/!net*").contributions(contributions)).
+			To(Equal([]internal.Contribution{{Repository: "herdstat/storage"}}))
+		Expect(profile("herdstat/^(network|storage)$", "other/net*").contributions(contributions)).To(HaveLen(3))
+	})
+
+	It("collects the repositories of the profiles as well", func() {
+		profiles := []graphProfile{{}, {repositories: []string{"Herdstat", "herdstat/network"}}, {repositories: []string{"other"}}}
+		Expect(profileRepositories([]string{"herdstat"}, profiles)).To(Equal([]string{"herdstat", "herdstat/network", "other"}))
+	})
+})
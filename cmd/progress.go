@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Modes of reporting the progress of collecting contributions.
+const (
+	autoProgress = "auto"
+	barProgress  = "bar"
+	logProgress  = "log"
+	noProgress   = "none"
+)
+
+// progressRefreshInterval is the interval the progress bar is redrawn at.
+const progressRefreshInterval = 100 * time.Millisecond
+
+// progressLogInterval is the interval the progress is logged at if no
+// progress bar is shown.
+const progressLogInterval = 30 * time.Second
+
+// progressBarWidth is the number of characters of the bar of the progress bar.
+const progressBarWidth = 20
+
+// spinnerFrames are the frames of the spinner animating the progress bar.
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// progress reports the progress of a collection phase covering a number of
+// repositories, either as a progress bar on terminals or by periodically
+// logging it, so that long runs don't look hung.
+type progress struct {
+	phase string
+	total int
+	start time.Time
+	mode  string
+	out   io.Writer
+
+	// Guards completed and running
+	mutex     sync.Mutex
+	completed int
+	running   []string
+
+	// Closed to stop reporting
+	stop chan struct{}
+
+	// Closed once reporting has stopped
+	done chan struct{}
+}
+
+// startProgress starts reporting the progress of the collection phase with
+// the given name covering the given number of repositories in the configured
+// mode. The progress bar is shown on the standard error stream if it is a
+// terminal. The progress is logged otherwise.
+func startProgress(phase string, total int) *progress {
+	mode := viper.GetString(progressCfgKey)
+	if mode == autoProgress {
+		mode = logProgress
+		if isTerminal(os.Stderr) {
+			mode = barProgress
+		}
+	}
+	switch mode {
+	case barProgress, logProgress, noProgress:
+	default:
+		logger.Warnw("Unknown progress mode - not reporting progress", "mode", mode)
+	}
+	p := &progress{
+		phase: phase,
+		total: total,
+		start: time.Now(),
+		mode:  mode,
+		out:   os.Stderr,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go p.report()
+	return p
+}
+
+// isTerminal returns true iff the given file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// report reports the progress until stopped.
+func (p *progress) report() {
+	defer close(p.done)
+	var interval time.Duration
+	switch p.mode {
+	case barProgress:
+		interval = progressRefreshInterval
+	case logProgress:
+		interval = progressLogInterval
+	default:
+		<-p.stop
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			if p.mode == barProgress {
+				_, _ = fmt.Fprintf(p.out, "\r\033[K%s", p.line(frame, now))
+			} else {
+				p.log(now)
+			}
+		}
+	}
+}
+
+// started records that collecting the given repository has started.
+func (p *progress) started(repository string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.running = append(p.running, repository)
+}
+
+// finished records that collecting the given repository has finished.
+func (p *progress) finished(repository string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i, r := range p.running {
+		if r == repository {
+			p.running = append(p.running[:i], p.running[i+1:]...)
+			break
+		}
+	}
+	p.completed++
+}
+
+// Stop stops reporting and reports the final state.
+func (p *progress) Stop() {
+	close(p.stop)
+	<-p.done
+	p.mutex.Lock()
+	completed := p.completed
+	p.mutex.Unlock()
+	elapsed := time.Since(p.start).Round(time.Second)
+	switch p.mode {
+	case barProgress:
+		_, _ = fmt.Fprintf(p.out, "\r\033[K✓ %s: %d/%d repositories in %s\n", p.phase, completed, p.total, elapsed)
+	case logProgress:
+		logger.Infow("Collection phase finished", "phase", p.phase, "completed", completed, "total", p.total, "elapsed", elapsed)
+	}
+}
+
+// eta estimates the time remaining at the given point in time from the time
+// taken by the repositories completed so far. The second return value is
+// false if no repository has been completed yet.
+func (p *progress) eta(now time.Time) (time.Duration, bool) {
+	if p.completed == 0 {
+		return 0, false
+	}
+	elapsed := now.Sub(p.start)
+	remaining := elapsed / time.Duration(p.completed) * time.Duration(p.total-p.completed)
+	return remaining.Round(time.Second), true
+}
+
+// line renders the progress bar showing the given frame of the spinner at the
+// given point in time.
+func (p *progress) line(frame int, now time.Time) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	filled := 0
+	if p.total > 0 {
+		filled = p.completed * progressBarWidth / p.total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	eta := "?"
+	if remaining, ok := p.eta(now); ok {
+		eta = remaining.String()
+	}
+	line := fmt.Sprintf("%c %s %s %d/%d ETA %s", spinnerFrames[frame%len(spinnerFrames)], p.phase, bar, p.completed, p.total, eta)
+	switch len(p.running) {
+	case 0:
+	case 1:
+		line += " " + p.running[0]
+	default:
+		line += fmt.Sprintf(" %s (+%d)", p.running[0], len(p.running)-1)
+	}
+	return line
+}
+
+// log logs the progress at the given point in time.
+func (p *progress) log(now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	keysAndValues := []interface{}{
+		"phase", p.phase,
+		"completed", p.completed,
+		"total", p.total,
+		"running", append([]string(nil), p.running...),
+	}
+	if remaining, ok := p.eta(now); ok {
+		keysAndValues = append(keysAndValues, "eta", remaining)
+	}
+	logger.Infow("Collection progress", keysAndValues...)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Progress reports", func() {
+	var p *progress
+	start := time.Date(2013, time.April, 22, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		p = &progress{
+			phase: "Collecting commits",
+			total: 4,
+			start: start,
+			mode:  barProgress,
+			out:   &bytes.Buffer{},
+			stop:  make(chan struct{}),
+			done:  make(chan struct{}),
+		}
+	})
+
+	It("doesn't estimate the remaining time before a repository is completed", func() {
+		p.started("herdstat/herdstat")
+		Expect(p.line(0, start.Add(time.Minute))).To(Equal("⠋ Collecting commits ░░░░░░░░░░░░░░░░░░░░ 0/4 ETA ? herdstat/herdstat"))
+	})
+
+	It("estimates the remaining time from the completed repositories", func() {
+		p.started("herdstat/a")
+		p.started("herdstat/b")
+		p.started("herdstat/c")
+		p.finished("herdstat/a")
+		Expect(p.line(1, start.Add(time.Minute))).To(Equal("⠙ Collecting commits █████░░░░░░░░░░░░░░░ 1/4 ETA 3m0s herdstat/b (+1)"))
+	})
+
+	It("reports the final state when stopped", func() {
+		go p.report()
+		p.started("herdstat/herdstat")
+		p.finished("herdstat/herdstat")
+		p.Stop()
+		Expect(p.out.(*bytes.Buffer).String()).To(ContainSubstring("✓ Collecting commits: 1/4 repositories"))
+	})
+})
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// publishCommitMessage is the message of the commits publishing generated
+// files to a branch.
+const publishCommitMessage = "Update herdstat outputs"
+
+// publishResults publishes the files generated by the given command to the
+// configured branch and/or gist.
+func publishResults(cmd *cobra.Command) error {
+	branch := viper.GetString(publishBranchCfgKey)
+	gist := viper.GetString(publishGistCfgKey)
+	if branch == "" && gist == "" {
+		return nil
+	}
+	files := results.generatedFiles()
+	if len(files) == 0 {
+		logger.Warnw("No files generated - nothing to publish")
+		return nil
+	}
+	client := github.NewClient(getHTTPClient())
+	ctx := context.Background()
+	if branch != "" {
+		repository := viper.GetString(publishRepositoryCfgKey)
+		if repository == "" {
+			repository = os.Getenv("GITHUB_REPOSITORY")
+		}
+		owner, name, ok := strings.Cut(repository, "/")
+		if !ok {
+			return fmt.Errorf("publishing to branch '%s' requires a repository in 'owner/repository' notation but got '%s'", branch, repository)
+		}
+		sha, err := publishToBranch(ctx, client, owner, name, branch, files)
+		if err != nil {
+			return fmt.Errorf("publishing to branch '%s' of '%s' failed: %w", branch, repository, err)
+		}
+		for _, filename := range files {
+			results.recordLink(fmt.Sprintf("https://github.com/%s/raw/%s/%s", repository, sha, publishedPath(filename)))
+		}
+		cmd.Printf("Published %d files to branch '%s' of '%s' (commit %s)\n", len(files), branch, repository, sha)
+	}
+	if gist != "" {
+		if err := publishToGist(ctx, client, gist, files); err != nil {
+			return fmt.Errorf("publishing to gist '%s' failed: %w", gist, err)
+		}
+		cmd.Printf("Published %d files to gist '%s'\n", len(files), gist)
+	}
+	return nil
+}
+
+// publishedPath returns the path of the file with the given name in the
+// branch generated files are published to. Files within the working directory
+// keep their relative path, other files are published at the top level.
+func publishedPath(filename string) string {
+	p := filepath.ToSlash(filepath.Clean(filename))
+	if filepath.IsAbs(filename) || p == ".." || strings.HasPrefix(p, "../") {
+		return path.Base(p)
+	}
+	return p
+}
+
+// publishToBranch commits the given files to the given branch of the given
+// repository using the Git Data API, so that no clone is required. The files
+// are added to the tree of the branch's head, i.e., other files are kept. The
+// branch is created without history if it doesn't exist. Returns the hash of
+// the commit.
+func publishToBranch(ctx context.Context, client *github.Client, owner string, repo string, branch string, files []string) (string, error) {
+	var parents []*github.Commit
+	var baseTree string
+	ref, resp, err := client.Git.GetRef(ctx, owner, repo, "heads/"+branch)
+	switch {
+	case err == nil:
+		head, _, err := client.Git.GetCommit(ctx, owner, repo, ref.GetObject().GetSHA())
+		if err != nil {
+			return "", err
+		}
+		parents = []*github.Commit{{SHA: head.SHA}}
+		baseTree = head.GetTree().GetSHA()
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		logger.Infow("Creating branch to publish to", "repository", owner+"/"+repo, "branch", branch)
+	default:
+		return "", err
+	}
+
+	var entries []*github.TreeEntry
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return "", err
+		}
+		blob, _, err := client.Git.CreateBlob(ctx, owner, repo, &github.Blob{
+			Content:  github.String(base64.StdEncoding.EncodeToString(content)),
+			Encoding: github.String("base64"),
+		})
+		if err != nil {
+			return "", fmt.Errorf("uploading '%s' failed: %w", filename, err)
+		}
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(publishedPath(filename)),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+			SHA:  blob.SHA,
+		})
+	}
+	tree, _, err := client.Git.CreateTree(ctx, owner, repo, baseTree, entries)
+	if err != nil {
+		return "", err
+	}
+	commit, _, err := client.Git.CreateCommit(ctx, owner, repo, &github.Commit{
+		Message: github.String(publishCommitMessage),
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: parents,
+	})
+	if err != nil {
+		return "", err
+	}
+	newRef := &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: commit.SHA},
+	}
+	if ref != nil {
+		_, _, err = client.Git.UpdateRef(ctx, owner, repo, newRef, false)
+	} else {
+		_, _, err = client.Git.CreateRef(ctx, owner, repo, newRef)
+	}
+	if err != nil {
+		return "", err
+	}
+	return commit.GetSHA(), nil
+}
+
+// publishToGist updates the files of the gist with the given ID with the given
+// files named by their base names. Other files of the gist are kept. As gists
+// only support text, binary files (e.g., compressed SVG documents) are
+// rejected.
+func publishToGist(ctx context.Context, client *github.Client, id string, files []string) error {
+	gistFiles := make(map[github.GistFilename]github.GistFile)
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(content) {
+			return fmt.Errorf("gists don't support binary files like '%s'", filename)
+		}
+		gistFiles[github.GistFilename(filepath.Base(filename))] = github.GistFile{Content: github.String(string(content))}
+	}
+	_, _, err := client.Gists.Edit(ctx, id, &github.Gist{Files: gistFiles})
+	return err
+}
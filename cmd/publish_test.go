@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Publishing", func() {
+	var (
+		filename string
+		requests map[string]map[string]interface{}
+		client   *github.Client
+	)
+
+	// serve starts a fake GitHub API responding to the given requests given
+	// by method and path with the given bodies. The bodies of the requests
+	// are recorded.
+	serve := func(responses map[string]string) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Method + " " + r.URL.Path
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests[key] = body
+			response, ok := responses[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				response = `{"message": "Not Found"}`
+			}
+			_, _ = w.Write([]byte(response))
+		}))
+		DeferCleanup(server.Close)
+		client = github.NewClient(nil)
+		u, err := url.Parse(server.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		client.BaseURL = u
+	}
+
+	BeforeEach(func() {
+		filename = filepath.Join(GinkgoT().TempDir(), "contribution-graph.svg")
+		Expect(os.WriteFile(filename, []byte("<svg/>"), 0o644)).To(Succeed())
+		requests = make(map[string]map[string]interface{})
+	})
+
+	It("commits the files on top of an existing branch", func() {
+		serve(map[string]string{
+			"GET /repos/herdstat/herdstat/git/ref/heads/herdstat-output":    `{"object": {"sha": "head"}}`,
+			"GET /repos/herdstat/herdstat/git/commits/head":                 `{"sha": "head", "tree": {"sha": "base"}}`,
+			"POST /repos/herdstat/herdstat/git/blobs":                       `{"sha": "blob"}`,
+			"POST /repos/herdstat/herdstat/git/trees":                       `{"sha": "tree"}`,
+			"POST /repos/herdstat/herdstat/git/commits":                     `{"sha": "commit"}`,
+			"PATCH /repos/herdstat/herdstat/git/refs/heads/herdstat-output": `{}`,
+		})
+		sha, err := publishToBranch(context.Background(), client, "herdstat", "herdstat", "herdstat-output", []string{filename})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sha).To(Equal("commit"))
+		tree := requests["POST /repos/herdstat/herdstat/git/trees"]
+		Expect(tree["base_tree"]).To(Equal("base"))
+		Expect(tree["tree"]).To(ConsistOf(HaveKeyWithValue("path", "contribution-graph.svg")))
+		Expect(requests["POST /repos/herdstat/herdstat/git/commits"]["parents"]).To(Equal([]interface{}{"head"}))
+		Expect(requests["PATCH /repos/herdstat/herdstat/git/refs/heads/herdstat-output"]["sha"]).To(Equal("commit"))
+	})
+
+	It("creates missing branches", func() {
+		serve(map[string]string{
+			"POST /repos/herdstat/herdstat/git/blobs":   `{"sha": "blob"}`,
+			"POST /repos/herdstat/herdstat/git/trees":   `{"sha": "tree"}`,
+			"POST /repos/herdstat/herdstat/git/commits": `{"sha": "commit"}`,
+			"POST /repos/herdstat/herdstat/git/refs":    `{}`,
+		})
+		_, err := publishToBranch(context.Background(), client, "herdstat", "herdstat", "herdstat-output", []string{filename})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests["POST /repos/herdstat/herdstat/git/commits"]["parents"]).To(BeNil())
+		Expect(requests["POST /repos/herdstat/herdstat/git/refs"]["ref"]).To(Equal("refs/heads/herdstat-output"))
+	})
+
+	It("updates the files of gists", func() {
+		serve(map[string]string{"PATCH /gists/abc": `{}`})
+		Expect(publishToGist(context.Background(), client, "abc", []string{filename})).To(Succeed())
+		Expect(requests["PATCH /gists/abc"]["files"]).To(HaveKeyWithValue("contribution-graph.svg", HaveKeyWithValue("content", "<svg/>")))
+	})
+
+	It("publishes files outside the working directory at the top level", func() {
+		Expect(publishedPath("out/graph.svg")).To(Equal("out/graph.svg"))
+		Expect(publishedPath("../graph.svg")).To(Equal("graph.svg"))
+		Expect(publishedPath(filename)).To(Equal("contribution-graph.svg"))
+	})
+})
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// Configuration keys for the punch-card command
+const (
+	// The name of the output file
+	punchCardFilenameCfgKey = "punch-card.filename"
+	// The primary color used for coloring the punch card
+	punchCardColorCfgKey = "punch-card.color"
+	// The time zone commit timestamps are normalized to
+	punchCardTimezoneCfgKey = "punch-card.timezone"
+	// Toggle for omitting tooltips
+	punchCardNoTooltipsCfgKey = "punch-card.no-tooltips"
+)
+
+// punchCardCmd represents the punch-card command
+var punchCardCmd = &cobra.Command{
+	Use:   "punch-card",
+	Short: "Generates a punch card visualizing commits per weekday and hour",
+	Long: `Generates a GitHub-style punch card visualizing the number of commits per
+weekday and hour of the day. By default, the time zone recorded with each
+commit is used, i.e., the punch card shows the local time of the committers.
+Timestamps can be normalized to a single time zone instead.`,
+	Args: cobra.NoArgs,
+	RunE: runPunchCard,
+}
+
+// getTimezone parses the time zone used for normalizing timestamps. Returns
+// nil if timestamps should not be normalized.
+func getTimezone(key string) (*time.Location, error) {
+	name := viper.GetString(key)
+	if name == "" {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone '%s': %w", name, err)
+	}
+	return location, nil
+}
+
+func runPunchCard(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(punchCardColorCfgKey)
+	primaryColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	location, err := getTimezone(punchCardTimezoneCfgKey)
+	if err != nil {
+		return err
+	}
+
+	interpolation, err := internal.ParseInterpolation(internal.RGBInterpolationName)
+	if err != nil {
+		return err
+	}
+
+	commits, lastDay, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+
+	punchCard := internal.NewPunchCard(commits, lastDay, location,
+		internal.GetColoring(getColorScheme(primaryColor), interpolation), 5, viper.GetBool(punchCardNoTooltipsCfgKey))
+	filename, err := writeSVG(cmd, punchCard, viper.GetString(punchCardFilenameCfgKey), true, false)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Punch card written to '%s'\n", filename)
+
+	return nil
+}
+
+// Initialize the 'punch-card' command.
+func init() {
+	rootCmd.AddCommand(punchCardCmd)
+
+	// Flag to control the time zone
+	const timezoneFlag = "timezone"
+	punchCardCmd.Flags().String(
+		timezoneFlag,
+		"",
+		"The time zone (e.g., UTC or Europe/Berlin) commit timestamps are normalized to (default is the recorded time zone)")
+	if err := viper.BindPFlag(punchCardTimezoneCfgKey, punchCardCmd.Flags().Lookup(timezoneFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", timezoneFlag, "Error", err)
+	}
+
+	// Flag to control the punch card color
+	const colorFlag = "color"
+	punchCardCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The primary color used for coloring the punch card")
+	if err := viper.BindPFlag(punchCardColorCfgKey, punchCardCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	// Flag to toggle tooltips
+	const noTooltipsFlag = "no-tooltips"
+	punchCardCmd.Flags().Bool(
+		noTooltipsFlag,
+		false,
+		"Flag to omit the tooltips")
+	if err := viper.BindPFlag(punchCardNoTooltipsCfgKey, punchCardCmd.Flags().Lookup(noTooltipsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", noTooltipsFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	punchCardCmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"punch-card.svg",
+		"The name of the generated SVG file")
+	if err := viper.BindPFlag(punchCardFilenameCfgKey, punchCardCmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
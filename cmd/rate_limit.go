@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"net/url"
+	"time"
+)
+
+// rateLimitCmd represents the rate-limit command
+var rateLimitCmd = &cobra.Command{
+	Use:   "rate-limit",
+	Short: "Reports the rate limits of the GitHub API and whether a run fits within them",
+	Long: `Reports the usage and reset times of the core, GraphQL, and search rate
+limits of the GitHub API for the configured credentials. If repositories are
+configured, they are resolved and the number of requests needed to collect
+their contributions is estimated, so that runs exceeding the remaining
+requests can be postponed.`,
+	Args: cobra.NoArgs,
+	RunE: runRateLimit,
+}
+
+func runRateLimit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	before, _, err := client.RateLimits(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching rate limits failed: %w", err)
+	}
+	writeRateLimits(cmd.OutOrStdout(), before, time.Now())
+
+	repos := viper.GetStringSlice(repositoriesCfgKey)
+	if len(repos) == 0 && len(viper.GetStringSlice(teamsCfgKey)) == 0 {
+		cmd.Println("No repositories configured - skipping the estimate of a run")
+		return nil
+	}
+	repositories, err := collectRepositories(repos)
+	if err != nil {
+		return err
+	}
+	after, _, err := client.RateLimits(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching rate limits failed: %w", err)
+	}
+	resolution := spentRequests(before.GetCore(), after.GetCore())
+	collection := estimateCollectionRequests(repositories)
+	cmd.Printf("\nResolving %d repositories took %d requests; collecting their contributions takes at least %d more\n",
+		len(repositories), resolution, collection)
+	core := after.GetCore()
+	if core == nil {
+		return nil
+	}
+	if collection <= core.Remaining {
+		cmd.Printf("A run fits within the remaining %d core requests\n", core.Remaining)
+		return nil
+	}
+	cmd.Printf("A run exceeds the remaining %d core requests; the limit is reset at %s (pass --wait-for-rate-limit to wait for it)\n",
+		core.Remaining, core.Reset.Local().Format(time.RFC3339))
+	return nil
+}
+
+// writeRateLimits writes the usage and reset times of the given rate limits
+// at the given time as a table to the given writer.
+func writeRateLimits(w io.Writer, limits *github.RateLimits, now time.Time) {
+	_, _ = fmt.Fprintf(w, "%-8s %7s %7s %10s  %s\n", "Resource", "Limit", "Used", "Remaining", "Reset")
+	for _, resource := range []struct {
+		name string
+		rate *github.Rate
+	}{
+		{"core", limits.GetCore()},
+		{"graphql", limits.GetGraphQL()},
+		{"search", limits.GetSearch()},
+	} {
+		if resource.rate == nil {
+			continue
+		}
+		r := resource.rate
+		_, _ = fmt.Fprintf(w, "%-8s %7d %7d %10d  %s (in %s)\n", resource.name, r.Limit, r.Limit-r.Remaining,
+			r.Remaining, r.Reset.Local().Format(time.RFC3339), r.Reset.Sub(now).Round(time.Second))
+	}
+}
+
+// spentRequests returns the number of requests spent between the given states
+// of a rate limit. If the limit has been reset in between, the requests spent
+// since the reset are returned.
+func spentRequests(before *github.Rate, after *github.Rate) int {
+	if before == nil || after == nil {
+		return 0
+	}
+	if !after.Reset.Equal(before.Reset) || after.Remaining > before.Remaining {
+		return after.Limit - after.Remaining
+	}
+	return before.Remaining - after.Remaining
+}
+
+// estimateCollectionRequests estimates the number of requests needed to
+// collect the contributions to the given repositories. The estimate is a lower
+// bound as the issues and pull requests closed within the analyzed period are
+// unknown before fetching them.
+func estimateCollectionRequests(repositories map[url.URL]*github.Repository) int {
+	var requests int
+	for _, repository := range repositories {
+		requests += 1 + repository.GetOpenIssuesCount()/issuesPerPage
+		if viper.GetString(commitSourceCfgKey) == activityCommitSource {
+			requests++
+		}
+	}
+	return requests
+}
+
+// Initialize the 'rate-limit' command.
+func init() {
+	rootCmd.AddCommand(rateLimitCmd)
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"net/url"
+	"time"
+)
+
+var _ = Describe("Reporting rate limits", func() {
+	now := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+	reset := github.Timestamp{Time: now.Add(42 * time.Minute)}
+
+	It("reports the usage and reset times of the rate limits", func() {
+		var buf bytes.Buffer
+		writeRateLimits(&buf, &github.RateLimits{
+			Core:    &github.Rate{Limit: 5000, Remaining: 4200, Reset: reset},
+			GraphQL: &github.Rate{Limit: 5000, Remaining: 5000, Reset: reset},
+		}, now)
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(3))
+		Expect(string(lines[1])).To(MatchRegexp(`^core\s+5000\s+800\s+4200\s+.*\(in 42m0s\)$`))
+		Expect(string(lines[2])).To(HavePrefix("graphql"))
+	})
+
+	It("computes the requests spent between two states of a rate limit", func() {
+		before := &github.Rate{Limit: 5000, Remaining: 4200, Reset: reset}
+		Expect(spentRequests(before, &github.Rate{Limit: 5000, Remaining: 4190, Reset: reset})).To(Equal(10))
+		later := github.Timestamp{Time: reset.Add(time.Hour)}
+		Expect(spentRequests(before, &github.Rate{Limit: 5000, Remaining: 4997, Reset: later})).To(Equal(3))
+	})
+
+	It("estimates the requests needed to collect contributions", func() {
+		DeferCleanup(viper.Set, commitSourceCfgKey, viper.GetString(commitSourceCfgKey))
+		repositories := map[url.URL]*github.Repository{
+			{Path: "herdstat/herdstat"}: {OpenIssuesCount: github.Int(250)},
+			{Path: "herdstat/action"}:   {},
+		}
+		viper.Set(commitSourceCfgKey, cloneCommitSource)
+		Expect(estimateCollectionRequests(repositories)).To(Equal(4))
+		viper.Set(commitSourceCfgKey, activityCommitSource)
+		Expect(estimateCollectionRequests(repositories)).To(Equal(6))
+	})
+})
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// rateLimitThrottleShare is the share of the rate limit below which the
+// remaining requests are spread evenly over the time until the limit is reset.
+const rateLimitThrottleShare = 0.1
+
+// rateLimitMaxThrottle is the maximum delay of a single throttled request.
+const rateLimitMaxThrottle = time.Minute
+
+// rateLimitResetMargin is added to the reset time of the rate limit to
+// compensate for clock skew between GitHub and the local machine.
+const rateLimitResetMargin = time.Second
+
+// secondaryRateLimitRetries is the number of times a request exceeding a
+// secondary rate limit is retried.
+const secondaryRateLimitRetries = 3
+
+// rateLimitTransport is a http.RoundTripper that inspects the rate limit
+// headers of the responses of the GitHub API. Requests are throttled when
+// the rate limit is about to be exceeded and retried after the period
+// requested by GitHub when a secondary rate limit is exceeded. If waiting
+// is enabled, requests exceeding the primary rate limit are retried after
+// the limit has been reset.
+type rateLimitTransport struct {
+	base http.RoundTripper
+	wait bool
+
+	// Function used to pause, replaceable for testing
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newRateLimitTransport creates a rateLimitTransport wrapping the given
+// transport.
+func newRateLimitTransport(base http.RoundTripper, wait bool) *rateLimitTransport {
+	return &rateLimitTransport{base: base, wait: wait, sleep: sleepContext}
+}
+
+// sleepContext pauses for the given duration or until the given context is
+// done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimit is the state of the rate limit reported by a response.
+type rateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// parseRateLimit extracts the state of the rate limit from the headers of the
+// given response. The second return value is false if the headers are absent.
+func parseRateLimit(resp *http.Response) (rateLimit, bool) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return rateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rateLimit{}, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return rateLimit{}, false
+	}
+	return rateLimit{limit: limit, remaining: remaining, reset: time.Unix(reset, 0)}, true
+}
+
+// untilReset returns the time until the rate limit is reset.
+func (r rateLimit) untilReset() time.Duration {
+	return time.Until(r.reset) + rateLimitResetMargin
+}
+
+// retryAfter returns the period to wait before retrying as requested by the
+// given response. The second return value is false if the response doesn't
+// request a retry.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		limit, hasLimit := parseRateLimit(resp)
+		if hasLimit {
+			resource := resp.Header.Get("X-RateLimit-Resource")
+			if resource == "" {
+				resource = "core"
+			}
+			results.recordRateLimit(resource, limit.remaining)
+		}
+		if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+			if hasLimit {
+				if err := t.throttle(req, limit); err != nil {
+					_ = resp.Body.Close()
+					return nil, err
+				}
+			}
+			return resp, nil
+		}
+
+		var delay time.Duration
+		if after, ok := retryAfter(resp); ok && attempt < secondaryRateLimitRetries {
+			delay = after
+			logger.Warnw("Secondary rate limit exceeded - retrying later", "url", req.URL.String(), "delay", delay)
+		} else if hasLimit && limit.remaining == 0 && t.wait {
+			delay = limit.untilReset()
+			logger.Warnw("Rate limit exceeded - waiting for its reset", "url", req.URL.String(), "reset", limit.reset)
+		} else {
+			if hasLimit && limit.remaining == 0 {
+				logger.Warnw("Rate limit exceeded - use --wait-for-rate-limit to wait for its reset", "reset", limit.reset)
+			}
+			return resp, nil
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The request can't be repeated
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// throttle delays returning a successful response to the given request if
+// the rate limit is about to be exceeded. The remaining requests are spread
+// evenly over the time until the limit is reset. If waiting is enabled and
+// the limit is exhausted, it waits for the reset, as the GitHub client
+// refuses to send further requests before.
+func (t *rateLimitTransport) throttle(req *http.Request, limit rateLimit) error {
+	if limit.remaining == 0 {
+		if !t.wait {
+			return nil
+		}
+		logger.Warnw("Rate limit exhausted - waiting for its reset", "reset", limit.reset)
+		return t.sleep(req.Context(), limit.untilReset())
+	}
+	if float64(limit.remaining) >= float64(limit.limit)*rateLimitThrottleShare {
+		return nil
+	}
+	delay := limit.untilReset() / time.Duration(limit.remaining+1)
+	if delay > rateLimitMaxThrottle {
+		delay = rateLimitMaxThrottle
+	}
+	logger.Debugw("Throttling requests to stay within the rate limit",
+		"remaining", limit.remaining, "reset", limit.reset, "delay", delay)
+	return t.sleep(req.Context(), delay)
+}
+
+// apiRequests counts the requests sent to the GitHub API during a run.
+var apiRequests atomic.Int64
+
+// budgetTransport is a http.RoundTripper that fails requests once the given
+// maximum number of requests has been sent, e.g., to bound the footprint of a
+// run against GitHub Enterprise Server instances with strict limits.
+type budgetTransport struct {
+	base http.RoundTripper
+	max  int64
+
+	// The number of requests sent, shared by all transports of a run
+	count *atomic.Int64
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.count.Add(1) > t.max {
+		return nil, classify(exitAuthError, fmt.Errorf("budget of %d GitHub API requests exhausted", t.max))
+	}
+	return t.base.RoundTrip(req)
+}
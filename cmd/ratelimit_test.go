@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var _ = Describe("Rate limit transports", func() {
+	var (
+		reset    time.Time
+		requests int
+		sleeps   []time.Duration
+	)
+
+	// get requests a resource from a server responding with the status codes,
+	// remaining rate limits, and Retry-After headers returned by the given
+	// function for the n-th request and returns the final status code.
+	get := func(wait bool, respond func(n int) (status int, remaining int, retryAfter string)) int {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			status, remaining, retryAfter := respond(requests)
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+		transport := newRateLimitTransport(http.DefaultTransport, wait)
+		transport.sleep = func(ctx context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+		client := &http.Client{Transport: transport}
+		resp, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	BeforeEach(func() {
+		reset = time.Now().Add(time.Hour)
+		requests = 0
+		sleeps = nil
+	})
+
+	It("doesn't delay requests while the rate limit is sufficient", func() {
+		Expect(get(false, func(int) (int, int, string) {
+			return http.StatusOK, 50, ""
+		})).To(Equal(http.StatusOK))
+		Expect(sleeps).To(BeEmpty())
+	})
+
+	It("spreads the remaining requests until the reset", func() {
+		reset = time.Now().Add(50 * time.Second)
+		Expect(get(false, func(int) (int, int, string) {
+			return http.StatusOK, 4, ""
+		})).To(Equal(http.StatusOK))
+		Expect(sleeps).To(HaveLen(1))
+		Expect(sleeps[0]).To(BeNumerically("~", 10*time.Second, time.Second))
+	})
+
+	It("retries requests exceeding a secondary rate limit", func() {
+		Expect(get(false, func(n int) (int, int, string) {
+			if n == 1 {
+				return http.StatusForbidden, 50, "3"
+			}
+			return http.StatusOK, 49, ""
+		})).To(Equal(http.StatusOK))
+		Expect(requests).To(Equal(2))
+		Expect(sleeps).To(Equal([]time.Duration{3 * time.Second}))
+	})
+
+	It("fails requests exceeding the rate limit if not waiting", func() {
+		Expect(get(false, func(int) (int, int, string) {
+			return http.StatusForbidden, 0, ""
+		})).To(Equal(http.StatusForbidden))
+		Expect(requests).To(Equal(1))
+		Expect(sleeps).To(BeEmpty())
+	})
+
+	It("retries requests exceeding the rate limit after its reset if waiting", func() {
+		Expect(get(true, func(n int) (int, int, string) {
+			if n == 1 {
+				return http.StatusForbidden, 0, ""
+			}
+			return http.StatusOK, 99, ""
+		})).To(Equal(http.StatusOK))
+		Expect(requests).To(Equal(2))
+		Expect(sleeps).To(HaveLen(1))
+		Expect(sleeps[0]).To(BeNumerically("~", time.Hour, 2*time.Second))
+	})
+
+	It("waits for the reset after exhausting the rate limit if waiting", func() {
+		Expect(get(true, func(int) (int, int, string) {
+			return http.StatusOK, 0, ""
+		})).To(Equal(http.StatusOK))
+		Expect(sleeps).To(HaveLen(1))
+		Expect(sleeps[0]).To(BeNumerically("~", time.Hour, 2*time.Second))
+	})
+})
+
+var _ = Describe("Budget transports", func() {
+	It("fails requests once the budget is exhausted", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		DeferCleanup(server.Close)
+		var count atomic.Int64
+		client := &http.Client{Transport: &budgetTransport{base: http.DefaultTransport, max: 2, count: &count}}
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+		}
+		_, err := client.Get(server.URL)
+		Expect(err).To(MatchError(ContainSubstring("budget of 2 GitHub API requests exhausted")))
+	})
+})
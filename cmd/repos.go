@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/url"
+)
+
+// Configuration keys for the repos list command
+const (
+	// The format of the repository list
+	reposListFormatCfgKey = "repos.list.format"
+	// The name of the output file
+	reposListFilenameCfgKey = "repos.list.filename"
+)
+
+// reposCmd represents the repos command
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: "Inspects the repositories selected for analysis",
+	Args:  cobra.NoArgs,
+}
+
+// reposListCmd represents the repos list command
+var reposListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the repositories selected for analysis",
+	Long: `Resolves the configured owners, repositories, patterns, and teams like runs
+do, i.e., after expanding owners, removing duplicates, and applying the
+exclusions and filters, and lists the resulting repositories without
+collecting contributions. Use it to verify the selection before long runs.`,
+	Args: cobra.NoArgs,
+	RunE: runReposList,
+}
+
+func runReposList(cmd *cobra.Command, args []string) error {
+	repositories, err := collectRepositories(viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	return writeReport(cmd, newRepositoryList(repositories), viper.GetString(reposListFormatCfgKey),
+		viper.GetString(reposListFilenameCfgKey))
+}
+
+// newRepositoryList creates a list of the given repositories.
+func newRepositoryList(repositories map[url.URL]*github.Repository) *internal.RepositoryList {
+	var list []internal.Repository
+	for u, repository := range repositories {
+		list = append(list, internal.Repository{
+			Name:          repository.GetFullName(),
+			URL:           u.String(),
+			DefaultBranch: repository.GetDefaultBranch(),
+			Private:       repository.GetPrivate(),
+			Archived:      repository.GetArchived(),
+			Fork:          repository.GetFork(),
+			Size:          repository.GetSize(),
+		})
+	}
+	return internal.NewRepositoryList(list)
+}
+
+// Initialize the 'repos' command.
+func init() {
+	rootCmd.AddCommand(reposCmd)
+	reposCmd.AddCommand(reposListCmd)
+
+	addReportFlagsWithDefault(reposListCmd, reposListFormatCfgKey, reposListFilenameCfgKey, markdownFormat)
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// stdinRepositories is the repository list entry standing for the
+// repositories read from stdin.
+const stdinRepositories = "-"
+
+// readRepositoryList reads the owners and repositories listed line by line
+// from the given reader. Blank lines and comments starting with '#' are
+// ignored.
+func readRepositoryList(r io.Reader) ([]string, error) {
+	var repos []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			repos = append(repos, line)
+		}
+	}
+	return repos, scanner.Err()
+}
+
+// loadRepositoryLists adds the repositories listed in the configured
+// repositories file and, if '-' is given as repository, those read from stdin
+// to the configured repositories.
+func loadRepositoryLists(cmd *cobra.Command) error {
+	var repos []string
+	var readStdin bool
+	for _, repo := range viper.GetStringSlice(repositoriesCfgKey) {
+		if repo == stdinRepositories {
+			readStdin = true
+		} else {
+			repos = append(repos, repo)
+		}
+	}
+	filename := viper.GetString(repositoriesFileCfgKey)
+	if filename == stdinRepositories {
+		filename, readStdin = "", true
+	}
+	if filename == "" && !readStdin {
+		return nil
+	}
+	if filename != "" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("reading repositories file failed: %w", err)
+		}
+		defer f.Close()
+		listed, err := readRepositoryList(f)
+		if err != nil {
+			return fmt.Errorf("reading repositories file '%s' failed: %w", filename, err)
+		}
+		repos = append(repos, listed...)
+	}
+	if readStdin {
+		listed, err := readRepositoryList(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("reading repositories from stdin failed: %w", err)
+		}
+		repos = append(repos, listed...)
+	}
+	viper.Set(repositoriesCfgKey, repos)
+	return nil
+}
+
+// repositorySelector selects repositories of an owner by their names. A
+// repository is selected if it matches any of the included patterns, or if
+// there are none, and none of the excluded patterns.
+type repositorySelector struct {
+	included []func(name string) bool
+	excluded []func(name string) bool
+}
+
+// isRepositoryPattern returns true iff the given name of a repository is a
+// pattern rather than a plain name, i.e., a glob, a regular expression
+// starting with '^', or an exclusion starting with '!'.
+func isRepositoryPattern(name string) bool {
+	return strings.HasPrefix(name, "!") || strings.HasPrefix(name, "^") || strings.ContainsAny(name, "*?[")
+}
+
+// compileRepositoryPattern compiles the given glob or regular expression
+// starting with '^' matching names of repositories. Like names of
+// repositories, patterns are case-insensitive.
+func compileRepositoryPattern(pattern string) (func(name string) bool, error) {
+	if strings.HasPrefix(pattern, "^") {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			if strings.Contains(pattern, "(?") {
+				return nil, fmt.Errorf("%w (lookarounds aren't supported - exclude repositories with '!' instead)", err)
+			}
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	pattern = strings.ToLower(pattern)
+	return func(name string) bool {
+		matched, _ := path.Match(pattern, strings.ToLower(name))
+		return matched
+	}, nil
+}
+
+// add adds the given pattern to the selector. Patterns starting with '!'
+// exclude the matching repositories.
+func (s *repositorySelector) add(pattern string) error {
+	match, err := compileRepositoryPattern(strings.TrimPrefix(pattern, "!"))
+	if err != nil {
+		return fmt.Errorf("invalid repository pattern '%s': %w", pattern, err)
+	}
+	if strings.HasPrefix(pattern, "!") {
+		s.excluded = append(s.excluded, match)
+	} else {
+		s.included = append(s.included, match)
+	}
+	return nil
+}
+
+// selects returns true iff the repository with the given name is selected.
+func (s *repositorySelector) selects(name string) bool {
+	included := len(s.included) == 0
+	for _, match := range s.included {
+		if match(name) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, match := range s.excluded {
+		if match(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// excludeRepositories removes the given repositories in 'owner/name' notation
+// from the given map. Names may be globs or regular expressions starting with
+// '^' as well.
+func excludeRepositories(repositories map[url.URL]*github.Repository, excluded []string) error {
+	matchers := make(map[string][]func(name string) bool)
+	for _, repo := range excluded {
+		owner, name, ok := strings.Cut(repo, "/")
+		if !ok {
+			return fmt.Errorf("excluded repository '%s' is not given in 'owner/repository' notation", repo)
+		}
+		match, err := compileRepositoryPattern(name)
+		if err != nil {
+			return fmt.Errorf("invalid excluded repository '%s': %w", repo, err)
+		}
+		owner = strings.ToLower(owner)
+		matchers[owner] = append(matchers[owner], match)
+	}
+	for u, repository := range repositories {
+		for _, match := range matchers[strings.ToLower(repository.GetOwner().GetLogin())] {
+			if match(repository.GetName()) {
+				logger.Infow("Excluded repository", "Repository URL", u.String())
+				delete(repositories, u)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Matches teams in 'org/team-slug' notation
+var teamPattern = regexp.MustCompile("^([A-Za-z0-9-]+)/([A-Za-z0-9_-]+)$")
+
+// parseTeam splits the given team in 'org/team-slug' notation into the
+// organization and the slug of the team.
+func parseTeam(team string) (string, string, error) {
+	matches := teamPattern.FindStringSubmatch(team)
+	if matches == nil {
+		return "", "", fmt.Errorf("'%s' is not a valid team in org/team-slug notation", team)
+	}
+	return matches[1], matches[2], nil
+}
+
+// addTeamRepositories fetches all repositories the given team in
+// 'org/team-slug' notation has access to and adds them to the given map. The
+// repositories are filtered like those of expanded owners, i.e., archived,
+// forked, and non-public repositories are left out as configured.
+func addTeamRepositories(team string, repositories *map[url.URL]*github.Repository) error {
+	org, slug, err := parseTeam(team)
+	if err != nil {
+		return classify(exitConfigError, err)
+	}
+	if getGitHubToken() == "" {
+		return classify(exitAuthError, errors.New("resolving the repositories of a team requires a GitHub token"))
+	}
+	client := github.NewClient(getHTTPClient())
+	opt := &github.ListOptions{PerPage: 100}
+	var repos []*github.Repository
+	for {
+		page, resp, err := client.Teams.ListTeamReposBySlug(context.Background(), org, slug, opt)
+		if err != nil {
+			return err
+		}
+		repos = append(repos, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	logger.Debugw("Fetched repositories of team", "Team", team, "Count", len(repos))
+	if !viper.GetBool(includePrivateCfgKey) {
+		var skipped int
+		repos, skipped = withoutRepositories(repos, (*github.Repository).GetPrivate)
+		logger.Infow("Skipped non-public repositories of team", "Team", team, "Count", skipped)
+	}
+	if viper.GetBool(excludeArchivedCfgKey) {
+		var skipped int
+		repos, skipped = withoutRepositories(repos, (*github.Repository).GetArchived)
+		logger.Infow("Skipped archived repositories of team", "Team", team, "Count", skipped)
+	}
+	if !viper.GetBool(includeForksCfgKey) {
+		var skipped int
+		repos, skipped = withoutRepositories(repos, (*github.Repository).GetFork)
+		logger.Infow("Skipped forked repositories of team", "Team", team, "Count", skipped)
+	}
+	for _, repo := range repos {
+		if err := addRepository(repo, repositories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ = Describe("Repository lists", func() {
+	var cmd *cobra.Command
+
+	BeforeEach(func() {
+		cmd = &cobra.Command{}
+		cmd.SetIn(strings.NewReader("herdstat/stdin\n"))
+		DeferCleanup(viper.Set, repositoriesCfgKey, viper.GetStringSlice(repositoriesCfgKey))
+		DeferCleanup(viper.Set, repositoriesFileCfgKey, viper.GetString(repositoriesFileCfgKey))
+	})
+
+	It("ignores blank lines and comments", func() {
+		Expect(readRepositoryList(strings.NewReader("# SIGs\nherdstat/a  # network\n\n  other\n"))).
+			To(Equal([]string{"herdstat/a", "other"}))
+	})
+
+	It("adds the repositories listed in the file", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "repos.txt")
+		Expect(os.WriteFile(filename, []byte("herdstat/file\n"), 0o644)).To(Succeed())
+		viper.Set(repositoriesCfgKey, []string{"herdstat/flag"})
+		viper.Set(repositoriesFileCfgKey, filename)
+		Expect(loadRepositoryLists(cmd)).To(Succeed())
+		Expect(viper.GetStringSlice(repositoriesCfgKey)).To(Equal([]string{"herdstat/flag", "herdstat/file"}))
+	})
+
+	It("reads the repositories from stdin", func() {
+		viper.Set(repositoriesCfgKey, []string{"herdstat/flag", "-"})
+		Expect(loadRepositoryLists(cmd)).To(Succeed())
+		Expect(viper.GetStringSlice(repositoriesCfgKey)).To(Equal([]string{"herdstat/flag", "herdstat/stdin"}))
+	})
+})
+
+var _ = Describe("Repository patterns", func() {
+	selector := func(patterns ...string) *repositorySelector {
+		s := &repositorySelector{}
+		for _, p := range patterns {
+			Expect(s.add(p)).To(Succeed())
+		}
+		return s
+	}
+
+	It("distinguishes patterns from names", func() {
+		Expect(isRepositoryPattern("herdstat")).To(BeFalse())
+		Expect(isRepositoryPattern("sdk-*")).To(BeTrue())
+		Expect(isRepositoryPattern("^sdk-")).To(BeTrue())
+		Expect(isRepositoryPattern("!archive-*")).To(BeTrue())
+	})
+
+	It("selects repositories by globs and regular expressions", func() {
+		s := selector("sdk-*", "^tool-(go|java)$")
+		Expect(s.selects("sdk-go")).To(BeTrue())
+		Expect(s.selects("tool-go")).To(BeTrue())
+		Expect(s.selects("tool-rust")).To(BeFalse())
+		Expect(s.selects("docs")).To(BeFalse())
+	})
+
+	It("excludes repositories", func() {
+		s := selector("!archive-*")
+		Expect(s.selects("sdk-go")).To(BeTrue())
+		Expect(s.selects("archive-sdk")).To(BeFalse())
+		s = selector("sdk-*", "!*-legacy")
+		Expect(s.selects("sdk-go")).To(BeTrue())
+		Expect(s.selects("sdk-legacy")).To(BeFalse())
+	})
+
+	It("ignores the case of names", func() {
+		Expect(selector("SDK-*").selects("sdk-Go")).To(BeTrue())
+		Expect(selector("^sdk-go$").selects("SDK-Go")).To(BeTrue())
+	})
+
+	It("points to exclusions for lookarounds", func() {
+		Expect((&repositorySelector{}).add("^(?!archive-)")).To(MatchError(ContainSubstring("exclude repositories with '!' instead")))
+		Expect((&repositorySelector{}).add("sdk-[")).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Excluded repositories", func() {
+	repository := func(owner string, name string) (url.URL, *github.Repository) {
+		return url.URL{Scheme: "https", Host: "github.com", Path: "/" + owner + "/" + name}, &github.Repository{
+			Owner: &github.User{Login: github.String(owner)},
+			Name:  github.String(name),
+		}
+	}
+
+	It("removes the excluded repositories after expanding owners", func() {
+		repositories := make(map[url.URL]*github.Repository)
+		for _, name := range []string{"herdstat/herdstat", "herdstat/mirror", "herdstat/test-a", "other/mirror"} {
+			owner, name, _ := strings.Cut(name, "/")
+			u, repo := repository(owner, name)
+			repositories[u] = repo
+		}
+		Expect(excludeRepositories(repositories, []string{"Herdstat/mirror", "herdstat/test-*"})).To(Succeed())
+		Expect(repositories).To(HaveLen(2))
+		Expect(repositories).To(HaveKey(url.URL{Scheme: "https", Host: "github.com", Path: "/herdstat/herdstat"}))
+		Expect(repositories).To(HaveKey(url.URL{Scheme: "https", Host: "github.com", Path: "/other/mirror"}))
+	})
+
+	It("requires repositories in 'owner/repository' notation", func() {
+		Expect(excludeRepositories(nil, []string{"herdstat"})).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Team repositories", func() {
+	It("parses teams in org/team-slug notation", func() {
+		org, slug, err := parseTeam("herdstat/core-maintainers")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(org).To(Equal("herdstat"))
+		Expect(slug).To(Equal("core-maintainers"))
+	})
+
+	It("rejects malformed teams", func() {
+		for _, team := range []string{"herdstat", "herdstat/", "/core", "herdstat/core/extra"} {
+			_, _, err := parseTeam(team)
+			Expect(err).To(MatchError(ContainSubstring("not a valid team")), team)
+		}
+	})
+
+	It("requires a token to resolve teams", func() {
+		for key, value := range map[string]interface{}{
+			gitHubTokenCfgKey:   "",
+			ghCredentialsCfgKey: false,
+		} {
+			DeferCleanup(viper.Set, key, viper.Get(key))
+			viper.Set(key, value)
+		}
+		repositories := make(map[url.URL]*github.Repository)
+		Expect(addTeamRepositories("herdstat/core", &repositories)).To(MatchError(ContainSubstring("requires a GitHub token")))
+	})
+})
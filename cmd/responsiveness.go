@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the responsiveness command
+const (
+	// The logins of the maintainers
+	responsivenessMaintainersCfgKey = "responsiveness.maintainers"
+	// The format of the report
+	responsivenessFormatCfgKey = "responsiveness.format"
+	// The name of the output file
+	responsivenessFilenameCfgKey = "responsiveness.filename"
+)
+
+// responsivenessCmd represents the responsiveness command
+var responsivenessCmd = &cobra.Command{
+	Use:   "responsiveness",
+	Short: "Reports the response times of maintainers to issues and review requests",
+	Long: `Reports the median and 90th percentile of the times the configured maintainers
+take to respond to issues and review requests. The time to respond to an issue
+is the time from opening the issue to the first comment of the maintainer. The
+time to respond to a review request is the time from the request to the next
+review submitted by the maintainer. The report is meant as a private
+accountability tool for maintainer teams.`,
+	Args: cobra.NoArgs,
+	RunE: runResponsiveness,
+}
+
+func runResponsiveness(cmd *cobra.Command, args []string) error {
+	maintainers := viper.GetStringSlice(responsivenessMaintainersCfgKey)
+	if len(maintainers) == 0 {
+		return classify(exitConfigError, errors.New("no maintainers configured"))
+	}
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	var issues []internal.Issue
+	var pullRequests []internal.PullRequest
+	for _, repository := range repositories {
+		i, err := collectIssues(repository, lastDay)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, i...)
+		prs, err := collectPullRequests(repository, lastDay.AddDate(0, 0, -52*7))
+		if err != nil {
+			return err
+		}
+		for _, pr := range prs {
+			pr.ReviewRequests, err = listReviewRequests(ctx, client, repository, pr.Number)
+			if err != nil {
+				return err
+			}
+			pullRequests = append(pullRequests, pr)
+		}
+	}
+	report := internal.NewResponsivenessReport(issues, pullRequests, maintainers, lastDay)
+	return writeReport(cmd, report, viper.GetString(responsivenessFormatCfgKey), viper.GetString(responsivenessFilenameCfgKey))
+}
+
+// Initialize the 'responsiveness' command.
+func init() {
+	rootCmd.AddCommand(responsivenessCmd)
+
+	// Flag to control the maintainers
+	const maintainersFlag = "maintainers"
+	responsivenessCmd.Flags().StringSlice(
+		maintainersFlag,
+		nil,
+		"The GitHub logins of the maintainers")
+	if err := viper.BindPFlag(responsivenessMaintainersCfgKey, responsivenessCmd.Flags().Lookup(maintainersFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", maintainersFlag, "Error", err)
+	}
+
+	addReportFlags(responsivenessCmd, responsivenessFormatCfgKey, responsivenessFilenameCfgKey)
+}
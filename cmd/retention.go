@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the retention command
+const (
+	// The format of the report
+	retentionFormatCfgKey = "retention.format"
+	// The name of the output file
+	retentionFilenameCfgKey = "retention.filename"
+	// The name of the heatmap SVG file
+	retentionHeatmapCfgKey = "retention.heatmap"
+	// The primary color used for coloring heatmap cells
+	retentionColorCfgKey = "retention.color"
+)
+
+// retentionCmd represents the retention command
+var retentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Computes the retention of monthly contributor cohorts",
+	Long: `Computes for the contributors active in each month of the analyzed period how
+many of them have still been active one, three, and six months later. The
+retention matrix is optionally rendered as an SVG heatmap.`,
+	Args: cobra.NoArgs,
+	RunE: runRetention,
+}
+
+func runRetention(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(retentionColorCfgKey)
+	primaryColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewRetentionReport(contributions, lastDay)
+
+	if heatmapFilename := viper.GetString(retentionHeatmapCfgKey); heatmapFilename != "" {
+		interpolation, err := internal.ParseInterpolation(internal.RGBInterpolationName)
+		if err != nil {
+			return err
+		}
+		coloring := internal.GetColoring(getColorScheme(primaryColor), interpolation)
+		heatmapFilename, err = writeSVG(cmd, internal.NewRetentionHeatmap(report, coloring, 5, false), heatmapFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Retention heatmap written to '%s'\n", heatmapFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(retentionFormatCfgKey), viper.GetString(retentionFilenameCfgKey))
+}
+
+// Initialize the 'retention' command.
+func init() {
+	rootCmd.AddCommand(retentionCmd)
+
+	// Flag to control the heatmap output file
+	const heatmapFlag = "heatmap"
+	retentionCmd.Flags().String(
+		heatmapFlag,
+		"",
+		"The name of the SVG file the retention heatmap is written to (no heatmap if empty)")
+	if err := viper.BindPFlag(retentionHeatmapCfgKey, retentionCmd.Flags().Lookup(heatmapFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", heatmapFlag, "Error", err)
+	}
+
+	// Flag to control the heatmap color
+	const colorFlag = "color"
+	retentionCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The primary color used for coloring heatmap cells")
+	if err := viper.BindPFlag(retentionColorCfgKey, retentionCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(retentionCmd, retentionFormatCfgKey, retentionFilenameCfgKey)
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/spf13/viper"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// retryMaxDelay is the maximum delay between two attempts.
+const retryMaxDelay = 30 * time.Second
+
+// backoff returns the delay before the given retry (starting with 0) growing
+// exponentially from the given base delay. The delay is jittered randomly
+// between half and the full delay so that concurrent collectors don't retry
+// in lockstep.
+func backoff(base time.Duration, retry int) time.Duration {
+	d := base
+	for i := 0; i < retry && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	if d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
+// isTransientNetworkError returns true iff the given error is a network error
+// that might not occur when trying again, e.g., a connection reset or timeout.
+func isTransientNetworkError(err error) bool {
+	var opErr *net.OpError
+	var netErr net.Error
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.As(err, &opErr) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// isTransientStatus returns true iff the given HTTP status code indicates a
+// server error that might not occur when trying again.
+func isTransientStatus(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransientGitError returns true iff the given error of a git operation
+// might not occur when trying again. Errors are considered transient unless
+// they are known to be permanent, as go-git doesn't classify them.
+func isTransientGitError(err error) bool {
+	for _, permanent := range []error{
+		transport.ErrRepositoryNotFound,
+		transport.ErrEmptyRemoteRepository,
+		transport.ErrAuthenticationRequired,
+		transport.ErrAuthorizationFailed,
+		transport.ErrInvalidAuthMethod,
+		git.ErrRepositoryAlreadyExists,
+		plumbing.ErrObjectNotFound,
+		plumbing.ErrReferenceNotFound,
+		git.NoMatchingRefSpecError{},
+		context.Canceled,
+	} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	return true
+}
+
+// retry calls the given operation until it succeeds, fails with an error
+// that isn't transient according to the given function, or the configured
+// number of retries is exhausted. Attempts are separated by a jittered
+// exponential backoff.
+func retry(description string, transient func(error) bool, operation func() error) error {
+	retries := viper.GetInt(retriesCfgKey)
+	for attempt := 0; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= retries || !transient(err) {
+			return err
+		}
+		delay := backoff(viper.GetDuration(retryDelayCfgKey), attempt)
+		logger.Warnw("Retrying after transient error", "operation", description, "attempt", attempt+1, "delay", delay, "Error", err)
+		time.Sleep(delay)
+	}
+}
+
+// redactURL returns the scheme and host of the given URL for logging. The
+// path, the query, and the user information are left out as they may carry
+// credentials, e.g., the SAS token of uploads or the secrets of webhooks.
+func redactURL(u *url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+}
+
+// withoutURL returns the error underlying the given error of a request
+// leaving out the URL it reports. Other errors are returned as is.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s request failed: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// retryTransport is a http.RoundTripper that retries requests failing with
+// transient network or server errors.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	delay   time.Duration
+
+	// Function used to pause, replaceable for testing
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newRetryTransport creates a retryTransport wrapping the given transport
+// that retries requests the given number of times starting with the given
+// delay.
+func newRetryTransport(base http.RoundTripper, retries int, delay time.Duration) *retryTransport {
+	return &retryTransport{base: base, retries: retries, delay: delay, sleep: sleepContext}
+}
+
+// RoundTrip executes a single HTTP transaction.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		var transient bool
+		if err != nil {
+			transient = req.Context().Err() == nil && isTransientNetworkError(err)
+		} else {
+			transient = isTransientStatus(resp.StatusCode)
+		}
+		repeatable := req.Body == nil || req.GetBody != nil
+		if !transient || !repeatable || attempt >= t.retries {
+			return resp, err
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		delay := backoff(t.delay, attempt)
+		logger.Warnw("Retrying request after transient error", "url", redactURL(req.URL), "attempt", attempt+1, "delay", delay,
+			"status", status, "Error", err)
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
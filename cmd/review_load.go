@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the review-load command
+const (
+	// The number of top reviewers listed
+	reviewLoadTopCfgKey = "review-load.top"
+	// The format of the report
+	reviewLoadFormatCfgKey = "review-load.format"
+	// The name of the output file
+	reviewLoadFilenameCfgKey = "review-load.filename"
+)
+
+// reviewLoadCmd represents the review-load command
+var reviewLoadCmd = &cobra.Command{
+	Use:   "review-load",
+	Short: "Reports how pull request reviews are distributed across reviewers",
+	Long: `Reports the reviewers that submitted the most pull request reviews within the
+analyzed period and the Gini coefficient of the number of reviews per reviewer
+to help spotting overloaded maintainers. Reviews by the pull request author and
+by bots are ignored.`,
+	Args: cobra.NoArgs,
+	RunE: runReviewLoad,
+}
+
+func runReviewLoad(cmd *cobra.Command, args []string) error {
+	top := viper.GetInt(reviewLoadTopCfgKey)
+	if top < 1 {
+		return fmt.Errorf("number of top reviewers must be positive but is %d", top)
+	}
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var pullRequests []internal.PullRequest
+	for _, repository := range repositories {
+		prs, err := collectPullRequests(repository, lastDay.AddDate(0, 0, -52*7))
+		if err != nil {
+			return err
+		}
+		pullRequests = append(pullRequests, prs...)
+	}
+	report := internal.NewReviewLoadReport(pullRequests, lastDay, top)
+	return writeReport(cmd, report, viper.GetString(reviewLoadFormatCfgKey), viper.GetString(reviewLoadFilenameCfgKey))
+}
+
+// Initialize the 'review-load' command.
+func init() {
+	rootCmd.AddCommand(reviewLoadCmd)
+
+	// Flag to control the number of top reviewers
+	const topFlag = "top"
+	reviewLoadCmd.Flags().Int(topFlag, 10,
+		"The number of reviewers with the most reviews listed")
+	if err := viper.BindPFlag(reviewLoadTopCfgKey, reviewLoadCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	addReportFlags(reviewLoadCmd, reviewLoadFormatCfgKey, reviewLoadFilenameCfgKey)
+}
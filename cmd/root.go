@@ -11,17 +11,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/araddon/dateparse"
 	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.szostok.io/version/extension"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"golang.org/x/oauth2"
 	"net/http"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // Configuration keys for the root command
@@ -30,16 +34,189 @@ const (
 	// Repositories to analyze
 	repositoriesCfgKey = "repositories"
 
+	// The file listing further repositories to analyze
+	repositoriesFileCfgKey = "repositories-file"
+
+	// Teams in 'org/team-slug' notation whose repositories are analyzed
+	teamsCfgKey = "teams"
+
+	// Repositories excluded after expanding owners
+	excludeRepositoriesCfgKey = "exclude-repositories"
+
+	// Toggle for skipping archived repositories when expanding owners
+	excludeArchivedCfgKey = "exclude-archived"
+
+	// Toggle for including forked repositories when expanding owners
+	includeForksCfgKey = "include-forks"
+
+	// Toggle for including private and internal repositories when expanding owners
+	includePrivateCfgKey = "include-private"
+
+	// Toggle for counting commits contained in both a fork and another
+	// analyzed repository only once
+	deduplicateForkCommitsCfgKey = "deduplicate-fork-commits"
+
 	// Token used to access the GitHub API
 	gitHubTokenCfgKey = "github-token"
 
+	// Toggle for using the token of the gh CLI if no token is given
+	ghCredentialsCfgKey = "gh-credentials"
+
 	// Toggle for verbose output
 	verboseCfgKey = "verbose"
+
+	// The format of log entries
+	logFormatCfgKey = "log-format"
+
+	// The minimum level of log entries
+	logLevelCfgKey = "log-level"
+
+	// The format of errors a run fails with
+	errorFormatCfgKey = "error-format"
+
+	// The date of the last day to analyze
+	untilCfgKey = "until"
+
+	// The time zone contributions are attributed to days in
+	timezoneCfgKey = "timezone"
+
+	// Mapping of contributors to the organizations they are affiliated with
+	affiliationsCfgKey = "affiliations"
+
+	// Mapping of commit email addresses to GitHub logins
+	identitiesCfgKey = "identities"
+
+	// The file caching data fetched from the GitHub API
+	cacheCfgKey = "cache"
+
+	// The initial depth of shallow clones
+	cloneDepthCfgKey = "clone-depth"
+
+	// The directory keeping clones between runs
+	cloneCacheDirCfgKey = "clone-cache-dir"
+
+	// The size in MB above which repositories are cloned to disk
+	maxMemoryCloneSizeCfgKey = "max-memory-clone-size"
+
+	// The size in MB above which repositories are considered oversized
+	maxRepositorySizeCfgKey = "max-repository-size"
+
+	// How to handle oversized repositories
+	oversizedRepositoriesCfgKey = "oversized-repositories"
+
+	// The source commits are collected from
+	commitSourceCfgKey = "commit-source"
+
+	// Toggle for waiting for the reset of an exceeded rate limit
+	waitForRateLimitCfgKey = "wait-for-rate-limit"
+
+	// The file recording the collection progress of interrupted runs
+	checkpointCfgKey = "checkpoint"
+
+	// The maximum number of repositories collected concurrently
+	maxConcurrencyCfgKey = "max-concurrency"
+
+	// The maximum number of requests sent to the GitHub API per run
+	maxAPIRequestsCfgKey = "max-api-requests"
+
+	// The mode of reporting the collection progress
+	progressCfgKey = "progress"
+
+	// The number of retries of operations failing with transient errors
+	retriesCfgKey = "retries"
+
+	// The delay before the first retry
+	retryDelayCfgKey = "retry-delay"
+
+	// Toggle for reporting to GitHub Actions
+	githubActionsCfgKey = "github-actions"
+
+	// The branch generated files are published to
+	publishBranchCfgKey = "publish-branch"
+
+	// The repository of the branch generated files are published to
+	publishRepositoryCfgKey = "publish-repository"
+
+	// The ID of the gist generated files are published to
+	publishGistCfgKey = "publish-gist"
+
+	// The object storage provider generated files are uploaded to
+	uploadProviderCfgKey = "upload-provider"
+
+	// The endpoint of the object storage
+	uploadEndpointCfgKey = "upload-endpoint"
+
+	// The bucket (or container) generated files are uploaded to
+	uploadBucketCfgKey = "upload-bucket"
+
+	// The prefix of the names of uploaded objects
+	uploadPrefixCfgKey = "upload-prefix"
+
+	// The region of S3-compatible object storage
+	uploadRegionCfgKey = "upload-region"
+
+	// The access key ID of S3-compatible object storage
+	uploadAccessKeyIDCfgKey = "upload-access-key-id"
+
+	// The secret access key of S3-compatible object storage
+	uploadSecretAccessKeyCfgKey = "upload-secret-access-key"
+
+	// The session token of temporary credentials of S3-compatible object storage
+	uploadSessionTokenCfgKey = "upload-session-token"
+
+	// The shared access signature of Azure Blob Storage
+	uploadSASTokenCfgKey = "upload-sas-token"
+
+	// The SQLite database collected contributions are exported to
+	sqliteFileCfgKey = "sqlite-file"
+
+	// The directory the collected contributions are exported to as Parquet files
+	parquetDirectoryCfgKey = "parquet-directory"
+
+	// The file the manifest of the generated files is written to
+	manifestCfgKey = "manifest"
+
+	// The DSN of the PostgreSQL database collected contributions are upserted into
+	postgresDSNCfgKey = "postgres-dsn"
+
+	// The URL of the Prometheus Pushgateway metrics are pushed to
+	metricsPushgatewayCfgKey = "metrics-pushgateway"
+
+	// The job name metrics are pushed under
+	metricsJobCfgKey = "metrics-job"
+
+	// The URL of the Slack incoming webhook the weekly summary is posted to
+	notifySlackWebhookCfgKey = "notify-slack-webhook"
+
+	// The URL of the Discord webhook the weekly summary is posted to
+	notifyDiscordWebhookCfgKey = "notify-discord-webhook"
+
+	// The number of top contributors listed in notifications
+	notifyTopCfgKey = "notify-top"
+
+	// The URL of the contribution graph linked in notifications
+	notifyLinkCfgKey = "notify-link"
+
+	// The number of the issue or pull request results are commented on
+	commentIssueCfgKey = "comment-issue"
+
+	// Whether to comment results on the pull request triggering the workflow
+	commentPullRequestCfgKey = "comment-pull-request"
+
+	// The repository of the issue or pull request results are commented on
+	commentRepositoryCfgKey = "comment-repository"
+
+	// Whether to update the previous comment instead of posting a new one
+	commentUpdateCfgKey = "comment-update"
 )
 
 var (
 	// The file to read the configuration from
 	cfgFile string
+
+	// Whether to skip forked repositories when expanding owners, overriding
+	// the include-forks key
+	excludeForks bool
 )
 
 var logger *zap.SugaredLogger
@@ -48,29 +225,129 @@ var logger *zap.SugaredLogger
 var rootCmd = &cobra.Command{
 	Use:   "herdstat",
 	Short: "stat tool for open source communities",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		logger = configureLogger()
+	// Errors are written by Execute in the configured format
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch format := viper.GetString(errorFormatCfgKey); format {
+		case textErrorFormat:
+		case jsonErrorFormat:
+			// Keeps the error report the only output on stderr
+			cmd.SilenceUsage = true
+		default:
+			return classify(exitConfigError, fmt.Errorf("unknown error format '%s'; supported are %s and %s", format,
+				textErrorFormat, jsonErrorFormat))
+		}
+		l, err := configureLogger()
+		if err != nil {
+			return classify(exitConfigError, err)
+		}
+		logger = l
+		return classify(exitConfigError, loadRepositoryLists(cmd))
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if err := exportSQLite(cmd); err != nil {
+			return err
+		}
+		if err := exportPostgres(); err != nil {
+			return err
+		}
+		if err := exportParquet(cmd); err != nil {
+			return err
+		}
+		if err := writeManifest(cmd); err != nil {
+			return err
+		}
+		if err := runSinkPlugins(); err != nil {
+			return err
+		}
+		if err := publishResults(cmd); err != nil {
+			return err
+		}
+		if err := uploadResults(cmd); err != nil {
+			return err
+		}
+		if err := pushMetrics(); err != nil {
+			return err
+		}
+		if err := notifyResults(); err != nil {
+			return err
+		}
+		if err := commentResults(cmd); err != nil {
+			return err
+		}
+		if actionsEnabled() {
+			if err := results.reportToActions(cmd); err != nil {
+				return err
+			}
+		}
+		// Keep the checkpoint until all results have been processed
+		return removeCheckpoint()
 	},
 }
 
-// configureLogger configures the logging subsystem
-func configureLogger() *zap.SugaredLogger {
+// Supported formats of log entries.
+const (
+	// Log entries are written as JSON objects, one per line
+	jsonLogFormat = "json"
+
+	// Log entries are written as human-readable lines
+	consoleLogFormat = "console"
+)
+
+// getLogConfig returns the logging configuration. Verbose output defaults to
+// console output at debug level, whereas JSON output at info level is used
+// otherwise. The configured format and level take precedence over these
+// defaults.
+func getLogConfig() (zap.Config, error) {
 	var config zap.Config
-	verbose := viper.GetBool(verboseCfgKey)
-	if verbose {
+	if viper.GetBool(verboseCfgKey) {
 		config = zap.NewDevelopmentConfig()
 	} else {
 		config = zap.NewProductionConfig()
 	}
-	l, err := config.Build()
-	logger = l.Sugar()
+	switch format := viper.GetString(logFormatCfgKey); format {
+	case "":
+	case jsonLogFormat:
+		config.Encoding = jsonLogFormat
+		config.EncoderConfig = zap.NewProductionEncoderConfig()
+	case consoleLogFormat:
+		config.Encoding = consoleLogFormat
+		config.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	default:
+		return config, fmt.Errorf("unknown log format '%s'; supported are %s and %s", format, jsonLogFormat,
+			consoleLogFormat)
+	}
+	if level := viper.GetString(logLevelCfgKey); level != "" {
+		l, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return config, fmt.Errorf("invalid log level: %w", err)
+		}
+		config.Level = zap.NewAtomicLevelAt(l)
+	}
+	return config, nil
+}
+
+// configureLogger configures the logging subsystem
+func configureLogger() (*zap.SugaredLogger, error) {
+	config, err := getLogConfig()
+	if err != nil {
+		return nil, err
+	}
+	var options []zap.Option
+	if actionsEnabled() {
+		options = append(options, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, &workflowCommandCore{out: os.Stderr})
+		}))
+	}
+	l, err := config.Build(options...)
 	if err != nil {
-		logger.Panicw("Log system initialization failed", "Error", err)
+		return nil, fmt.Errorf("log system initialization failed: %w", err)
 	}
-	if verbose {
+	logger := l.Sugar()
+	if viper.GetBool(verboseCfgKey) {
 		logger.Infow("Verbose output enabled")
 	}
-	return logger
+	return logger, nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -78,7 +355,10 @@ func configureLogger() *zap.SugaredLogger {
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		if actionsEnabled() {
+			_ = writeWorkflowCommand(os.Stderr, "error", err.Error())
+		}
+		os.Exit(writeError(rootCmd.ErrOrStderr(), err, viper.GetString(errorFormatCfgKey)))
 	}
 }
 
@@ -87,11 +367,11 @@ func Execute() {
 var ownerOrRepoIDPattern = regexp.MustCompile(fmt.Sprintf("([A-Za-z0-9-]+)(/([A-Za-z0-9_\\.-]+))?"))
 
 // getHTTPClient returns a http client that uses a GitHub token for authentication
-// if configured through viper.
+// if configured through viper. If a cache is configured, previously fetched
+// resources are requested conditionally.
 func getHTTPClient() *http.Client {
 	var httpClient *http.Client
-	if viper.IsSet(gitHubTokenCfgKey) {
-		token := viper.GetString(gitHubTokenCfgKey)
+	if token := getGitHubToken(); token != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
@@ -102,7 +382,107 @@ func getHTTPClient() *http.Client {
 		httpClient = http.DefaultClient
 		logger.Debug("No GitHub token provided - making anonymous API calls")
 	}
-	return httpClient
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if max := viper.GetInt64(maxAPIRequestsCfgKey); max > 0 {
+		base = &budgetTransport{base: base, max: max, count: &apiRequests}
+	}
+	base = newRetryTransport(base, viper.GetInt(retriesCfgKey), viper.GetDuration(retryDelayCfgKey))
+	var transport http.RoundTripper = newRateLimitTransport(base, viper.GetBool(waitForRateLimitCfgKey))
+	cache, err := getCache()
+	if err != nil {
+		logger.Warnw("Making unconditional API calls as the cache is unavailable", "Error", err)
+	}
+	if cache != nil {
+		transport = &conditionalTransport{base: transport, cache: cache}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// getUntilDate retrieves the "until" parameter as a time.Time instance by
+// parsing the respective configuration entry. The date is converted to
+// last nanosecond of the day.
+func getUntilDate() (time.Time, error) {
+	until, err := parseUntilDate(viper.GetString(untilCfgKey))
+	return until, classify(exitConfigError, err)
+}
+
+// parseUntilDate parses the given date of the last day to analyze and converts
+// it to the last nanosecond of the day in the configured time zone. An empty
+// date denotes today.
+func parseUntilDate(s string) (time.Time, error) {
+	location, err := getDayTimezone()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s == "" {
+		s = today()
+	}
+	date, err := dateparse.ParseStrict(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(
+		date.Year(), date.Month(), date.Day(),
+		23, 59, 59,
+		int((1*time.Second).Nanoseconds()-1),
+		location), nil
+}
+
+// affiliationConfig is the configuration of a single affiliation.
+type affiliationConfig struct {
+	Organization string   `mapstructure:"organization"`
+	Domains      []string `mapstructure:"domains"`
+	Contributors []string `mapstructure:"contributors"`
+}
+
+// getAffiliations retrieves the affiliations of contributors from the
+// configuration.
+func getAffiliations() (*internal.Affiliations, error) {
+	var configs []affiliationConfig
+	if err := viper.UnmarshalKey(affiliationsCfgKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid affiliations: %w", err)
+	}
+	var affiliations []internal.Affiliation
+	for _, config := range configs {
+		if config.Organization == "" {
+			return nil, errors.New("affiliations require an organization name")
+		}
+		affiliations = append(affiliations, internal.Affiliation{
+			Organization: config.Organization,
+			Domains:      config.Domains,
+			Contributors: config.Contributors,
+		})
+	}
+	return internal.NewAffiliations(affiliations), nil
+}
+
+// identityConfig is the configuration of a single identity.
+type identityConfig struct {
+	Login  string   `mapstructure:"login"`
+	Emails []string `mapstructure:"emails"`
+}
+
+// getIdentities retrieves the identities of contributors from the
+// configuration.
+func getIdentities() (*internal.Identities, error) {
+	var configs []identityConfig
+	if err := viper.UnmarshalKey(identitiesCfgKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid identities: %w", err)
+	}
+	var identities []internal.Identity
+	for _, config := range configs {
+		if config.Login == "" {
+			return nil, errors.New("identities require a login")
+		}
+		identities = append(identities, internal.Identity{
+			Login:  config.Login,
+			Emails: config.Emails,
+		})
+	}
+	return internal.NewIdentities(identities), nil
 }
 
 // addRepository adds the repository given by repository owner and name to the map of repositories.
@@ -129,17 +509,53 @@ func addRepository(repo *github.Repository, repositories *map[url.URL]*github.Re
 	return nil
 }
 
-// addOwnedRepositories fetches all repositories of the given owner and adds
-// them to the given map.
-func addOwnedRepositories(owner string, repositories *map[url.URL]*github.Repository) error {
-	client := github.NewClient(getHTTPClient())
-	opt := &github.RepositoryListByOrgOptions{Type: "public"}
-	repos, _, err := client.Repositories.ListByOrg(context.Background(), owner, opt)
+// addOwnedRepositories fetches all repositories of the given owner selected by
+// the given selector using the given client and adds them to the given map.
+// All repositories are selected if the selector is nil.
+func addOwnedRepositories(client *github.Client, owner string, selector *repositorySelector, repositories *map[url.URL]*github.Repository) error {
+	visibility := "public"
+	if viper.GetBool(includePrivateCfgKey) {
+		if getGitHubToken() == "" {
+			return classify(exitAuthError, errors.New("including private repositories requires a GitHub token"))
+		}
+		visibility = "all"
+	}
+	opt := &github.RepositoryListByOrgOptions{Type: visibility, ListOptions: github.ListOptions{PerPage: 100}}
+	var repos []*github.Repository
+	for {
+		page, resp, err := client.Repositories.ListByOrg(context.Background(), owner, opt)
+		if err != nil {
+			return err
+		}
+		repos = append(repos, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
 	logger.Debugw("Fetched repositories from owner", "Owner", owner, "Count", len(repos))
-	if err != nil {
-		return err
+	if viper.GetBool(excludeArchivedCfgKey) {
+		var skipped int
+		repos, skipped = withoutRepositories(repos, (*github.Repository).GetArchived)
+		logger.Infow("Skipped archived repositories of owner", "Owner", owner, "Count", skipped)
+	}
+	if !viper.GetBool(includeForksCfgKey) {
+		var skipped int
+		repos, skipped = withoutRepositories(repos, (*github.Repository).GetFork)
+		logger.Infow("Skipped forked repositories of owner", "Owner", owner, "Count", skipped)
+	}
+	if selector != nil {
+		var skipped int
+		repos, skipped = withoutRepositories(repos, func(repo *github.Repository) bool {
+			return !selector.selects(repo.GetName())
+		})
+		logger.Infow("Skipped repositories of owner not matching the patterns", "Owner", owner, "Count", skipped)
 	}
 	for _, repo := range repos {
+		if repo.GetPrivate() {
+			logger.Infow("Including non-public repository of owner", "Owner", owner,
+				"Repository", repo.GetFullName(), "Visibility", repo.GetVisibility())
+		}
 		if err := addRepository(repo, repositories); err != nil {
 			return err
 		}
@@ -147,19 +563,65 @@ func addOwnedRepositories(owner string, repositories *map[url.URL]*github.Reposi
 	return nil
 }
 
+// withoutRepositories returns the given repositories except for those matched
+// by the given predicate and the number of repositories left out.
+func withoutRepositories(repos []*github.Repository, exclude func(*github.Repository) bool) ([]*github.Repository, int) {
+	var kept []*github.Repository
+	for _, repo := range repos {
+		if !exclude(repo) {
+			kept = append(kept, repo)
+		}
+	}
+	return kept, len(repos) - len(kept)
+}
+
+// Ways of handling oversized repositories.
+const (
+	// Oversized repositories are not analyzed
+	skipOversized = "skip"
+
+	// Only the most recent commits of oversized repositories are analyzed
+	sampleOversized = "sample"
+)
+
+// isOversized returns true iff the given repository exceeds the configured
+// maximum size.
+func isOversized(repository *github.Repository) bool {
+	limit := viper.GetInt(maxRepositorySizeCfgKey)
+	return limit > 0 && repository.GetSize() > limit*1024
+}
+
 // collectRepositories computes the repositories to be analyzed. Performs
-// expansion of owner entries and deduplication.
-func collectRepositories() (map[url.URL]*github.Repository, error) {
-	repos := viper.GetStringSlice(repositoriesCfgKey)
+// expansion of owner entries and deduplication. Entries whose repository is a
+// pattern (see isRepositoryPattern) select the matching repositories of the
+// owner. The repositories of the configured teams are added as well.
+func collectRepositories(repos []string) (map[url.URL]*github.Repository, error) {
 	repositories := make(map[url.URL]*github.Repository)
+	var patternOwners []string
+	selectors := make(map[string]*repositorySelector)
 	for _, repo := range repos {
+		if owner, name, ok := strings.Cut(repo, "/"); ok && isRepositoryPattern(name) {
+			if ownerOrRepoIDPattern.FindString(owner) != owner {
+				return nil, classify(exitConfigError, fmt.Errorf("'%s' is not a valid owner", owner))
+			}
+			selector, ok := selectors[owner]
+			if !ok {
+				selector = &repositorySelector{}
+				selectors[owner] = selector
+				patternOwners = append(patternOwners, owner)
+			}
+			if err := selector.add(name); err != nil {
+				return nil, classify(exitConfigError, err)
+			}
+			continue
+		}
 		matches := ownerOrRepoIDPattern.FindStringSubmatch(repo)
 		if matches == nil {
-			return nil, fmt.Errorf("'%s' is not a valid owner or owner/repository", repo)
+			return nil, classify(exitConfigError, fmt.Errorf("'%s' is not a valid owner or owner/repository", repo))
 		}
 		owner := matches[1]
 		if matches[3] == "" {
-			err := addOwnedRepositories(owner, &repositories)
+			err := addOwnedRepositories(github.NewClient(getHTTPClient()), owner, nil, &repositories)
 			if err != nil {
 				return nil, fmt.Errorf("failed to collect repositories from owner '%s': %w", owner, err)
 			}
@@ -171,6 +633,33 @@ func collectRepositories() (map[url.URL]*github.Repository, error) {
 			}
 		}
 	}
+	for _, owner := range patternOwners {
+		if err := addOwnedRepositories(github.NewClient(getHTTPClient()), owner, selectors[owner], &repositories); err != nil {
+			return nil, fmt.Errorf("failed to collect repositories from owner '%s': %w", owner, err)
+		}
+	}
+	for _, team := range viper.GetStringSlice(teamsCfgKey) {
+		if err := addTeamRepositories(team, &repositories); err != nil {
+			return nil, fmt.Errorf("failed to collect repositories of team '%s': %w", team, err)
+		}
+	}
+	if err := excludeRepositories(repositories, viper.GetStringSlice(excludeRepositoriesCfgKey)); err != nil {
+		return nil, classify(exitConfigError, err)
+	}
+	switch mode := viper.GetString(oversizedRepositoriesCfgKey); mode {
+	case skipOversized:
+		for u, repository := range repositories {
+			if isOversized(repository) {
+				logger.Warnw("Repository exceeds maximum size - skipping", "Repository URL", u.String(),
+					"Size (MB)", repository.GetSize()/1024)
+				delete(repositories, u)
+			}
+		}
+	case sampleOversized:
+	default:
+		return nil, classify(exitConfigError, fmt.Errorf("unknown handling of oversized repositories '%s'; supported are %s and %s",
+			mode, skipOversized, sampleOversized))
+	}
 	if len(repositories) == 0 {
 		return nil, errors.New("resolving repositories resulted in empty set")
 	}
@@ -200,18 +689,136 @@ func init() {
 		logger.Fatalw("Can't bind to flag", "Flag", verboseFlag, "Error", err)
 	}
 
+	// Flag to specify the format of log entries
+	const logFormatFlag = "log-format"
+	rootCmd.PersistentFlags().String(
+		logFormatFlag,
+		"",
+		"format of log entries, either json or console (default console if verbose, json otherwise)")
+	if err := viper.BindPFlag(logFormatCfgKey, rootCmd.PersistentFlags().Lookup(logFormatFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", logFormatFlag, "Error", err)
+	}
+
+	// Flag to specify the minimum level of log entries
+	const logLevelFlag = "log-level"
+	rootCmd.PersistentFlags().String(
+		logLevelFlag,
+		"",
+		"minimum level of log entries, e.g., debug, info, warn, or error (default debug if verbose, info otherwise)")
+	if err := viper.BindPFlag(logLevelCfgKey, rootCmd.PersistentFlags().Lookup(logLevelFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", logLevelFlag, "Error", err)
+	}
+
+	// Flag to specify the format of errors a run fails with
+	const errorFormatFlag = "error-format"
+	rootCmd.PersistentFlags().String(
+		errorFormatFlag,
+		textErrorFormat,
+		"format of errors a run fails with, either text or json for a machine-readable error report")
+	if err := viper.BindPFlag(errorFormatCfgKey, rootCmd.PersistentFlags().Lookup(errorFormatFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", errorFormatFlag, "Error", err)
+	}
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		if viper.GetString(errorFormatCfgKey) == jsonErrorFormat {
+			cmd.SilenceUsage = true
+		}
+		return classify(exitConfigError, err)
+	})
+
 	// Flag to specify repositories to analyze
 	const repositoriesFlag = "repositories"
 	rootCmd.PersistentFlags().StringSliceP(
 		repositoriesFlag,
 		"r",
 		nil,
-		"repositories to analyze",
+		"repositories to analyze ('-' reads them from stdin)",
 	)
 	if err := viper.BindPFlag(repositoriesCfgKey, rootCmd.PersistentFlags().Lookup(repositoriesFlag)); err != nil {
 		logger.Fatalw("Can't bind to flag", "Flag", repositoriesFlag, "Error", err)
 	}
 
+	// Flag to specify a file listing repositories to analyze
+	const repositoriesFileFlag = "repositories-file"
+	rootCmd.PersistentFlags().String(
+		repositoriesFileFlag,
+		"",
+		"file listing further repositories to analyze line by line ('-' for stdin)",
+	)
+	if err := viper.BindPFlag(repositoriesFileCfgKey, rootCmd.PersistentFlags().Lookup(repositoriesFileFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", repositoriesFileFlag, "Error", err)
+	}
+
+	// Flag to specify teams whose repositories are analyzed
+	const teamFlag = "team"
+	rootCmd.PersistentFlags().StringSlice(
+		teamFlag,
+		nil,
+		"teams in org/team-slug notation whose repositories are analyzed",
+	)
+	if err := viper.BindPFlag(teamsCfgKey, rootCmd.PersistentFlags().Lookup(teamFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", teamFlag, "Error", err)
+	}
+
+	// Flag to specify repositories excluded after expanding owners
+	const excludeRepositoriesFlag = "exclude-repositories"
+	rootCmd.PersistentFlags().StringSlice(
+		excludeRepositoriesFlag,
+		nil,
+		"repositories excluded from the analysis after expanding owners",
+	)
+	if err := viper.BindPFlag(excludeRepositoriesCfgKey, rootCmd.PersistentFlags().Lookup(excludeRepositoriesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", excludeRepositoriesFlag, "Error", err)
+	}
+
+	// Flag to control whether to skip archived repositories when expanding owners
+	const excludeArchivedFlag = "exclude-archived"
+	rootCmd.PersistentFlags().Bool(
+		excludeArchivedFlag,
+		false,
+		"skip archived repositories when expanding owners to their repositories")
+	if err := viper.BindPFlag(excludeArchivedCfgKey, rootCmd.PersistentFlags().Lookup(excludeArchivedFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", excludeArchivedFlag, "Error", err)
+	}
+
+	// Flag to control whether to include forked repositories when expanding owners
+	const includeForksFlag = "include-forks"
+	rootCmd.PersistentFlags().Bool(
+		includeForksFlag,
+		true,
+		"include forked repositories when expanding owners to their repositories")
+	if err := viper.BindPFlag(includeForksCfgKey, rootCmd.PersistentFlags().Lookup(includeForksFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", includeForksFlag, "Error", err)
+	}
+
+	// Flag to control whether to include private and internal repositories when expanding owners
+	const includePrivateFlag = "include-private"
+	rootCmd.PersistentFlags().Bool(
+		includePrivateFlag,
+		false,
+		"include private and internal repositories visible to the GitHub token when expanding owners to their repositories")
+	if err := viper.BindPFlag(includePrivateCfgKey, rootCmd.PersistentFlags().Lookup(includePrivateFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", includePrivateFlag, "Error", err)
+	}
+
+	// Flag to skip forked repositories when expanding owners
+	const excludeForksFlag = "exclude-forks"
+	rootCmd.PersistentFlags().BoolVar(
+		&excludeForks,
+		excludeForksFlag,
+		false,
+		"skip forked repositories when expanding owners to their repositories (shorthand for --include-forks=false)")
+	rootCmd.MarkFlagsMutuallyExclusive(includeForksFlag, excludeForksFlag)
+
+	// Flag to control whether to count commits contained in both a fork and another analyzed repository only once
+	const deduplicateForkCommitsFlag = "deduplicate-fork-commits"
+	rootCmd.PersistentFlags().Bool(
+		deduplicateForkCommitsFlag,
+		false,
+		"count commits contained in both a forked repository and another analyzed repository only once")
+	if err := viper.BindPFlag(deduplicateForkCommitsCfgKey, rootCmd.PersistentFlags().Lookup(deduplicateForkCommitsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", deduplicateForkCommitsFlag, "Error", err)
+	}
+
 	// Flag to set the access token used for making GitHub API calls
 	const gitHubTokenFlag = "github-token"
 	rootCmd.PersistentFlags().StringP(
@@ -223,6 +830,413 @@ func init() {
 	if err := viper.BindPFlag(gitHubTokenCfgKey, rootCmd.PersistentFlags().Lookup(gitHubTokenFlag)); err != nil {
 		logger.Fatalw("Can't bind to flag", "Flag", gitHubTokenFlag, "Error", err)
 	}
+	// Pick up the token from the environment variables used by most GitHub tooling
+	if err := viper.BindEnv(gitHubTokenCfgKey, "GH_TOKEN", "GITHUB_TOKEN"); err != nil {
+		logger.Fatalw("Can't bind to environment variables", "Key", gitHubTokenCfgKey, "Error", err)
+	}
+
+	// Flag to control whether to use the token of the gh CLI if no token is given
+	const ghCredentialsFlag = "gh-credentials"
+	rootCmd.PersistentFlags().Bool(
+		ghCredentialsFlag,
+		true,
+		"use the token the gh CLI is logged in with if no token is given")
+	if err := viper.BindPFlag(ghCredentialsCfgKey, rootCmd.PersistentFlags().Lookup(ghCredentialsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", ghCredentialsFlag, "Error", err)
+	}
+
+	// Flag to set the last day for which data is analyzed
+	const untilFlag = "until"
+	rootCmd.PersistentFlags().StringP(
+		untilFlag,
+		"u",
+		"",
+		"Date of last day for which data is analyzed (default today in the configured time zone)")
+	if err := viper.BindPFlag(untilCfgKey, rootCmd.PersistentFlags().Lookup(untilFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", untilFlag, "Error", err)
+	}
+
+	// Flag to set the time zone contributions are attributed to days in
+	const timezoneFlag = "timezone"
+	rootCmd.PersistentFlags().String(
+		timezoneFlag,
+		"UTC",
+		"time zone contributions are attributed to days in, e.g., Europe/Berlin, UTC, Local, or author to use the "+
+			"time zone of commit authors")
+	if err := viper.BindPFlag(timezoneCfgKey, rootCmd.PersistentFlags().Lookup(timezoneFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", timezoneFlag, "Error", err)
+	}
+
+	// Flag to set the file caching data fetched from the GitHub API
+	const cacheFlag = "cache"
+	rootCmd.PersistentFlags().String(
+		cacheFlag,
+		"",
+		"file caching issues, pull requests, and reviews between runs (no caching if empty)")
+	if err := viper.BindPFlag(cacheCfgKey, rootCmd.PersistentFlags().Lookup(cacheFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", cacheFlag, "Error", err)
+	}
+
+	// Flag to set the initial depth of shallow clones
+	const cloneDepthFlag = "clone-depth"
+	rootCmd.PersistentFlags().Int(
+		cloneDepthFlag,
+		500,
+		"initial number of commits fetched when cloning, deepened until the analyzed period is covered (full clones if not positive)")
+	if err := viper.BindPFlag(cloneDepthCfgKey, rootCmd.PersistentFlags().Lookup(cloneDepthFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", cloneDepthFlag, "Error", err)
+	}
+
+	// Flag to set the directory keeping clones between runs
+	const cloneCacheDirFlag = "clone-cache-dir"
+	rootCmd.PersistentFlags().String(
+		cloneCacheDirFlag,
+		"",
+		"directory keeping bare clones between runs, so that only new objects are fetched (cloning into memory if empty)")
+	if err := viper.BindPFlag(cloneCacheDirCfgKey, rootCmd.PersistentFlags().Lookup(cloneCacheDirFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", cloneCacheDirFlag, "Error", err)
+	}
+
+	// Flag to set the size above which repositories are cloned to disk
+	const maxMemoryCloneSizeFlag = "max-memory-clone-size"
+	rootCmd.PersistentFlags().Int(
+		maxMemoryCloneSizeFlag,
+		1024,
+		"size in MB as reported by GitHub above which repositories are cloned into a temporary directory instead of memory (always in memory if not positive)")
+	if err := viper.BindPFlag(maxMemoryCloneSizeCfgKey, rootCmd.PersistentFlags().Lookup(maxMemoryCloneSizeFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", maxMemoryCloneSizeFlag, "Error", err)
+	}
+
+	// Flag to set the size above which repositories are considered oversized
+	const maxRepositorySizeFlag = "max-repository-size"
+	rootCmd.PersistentFlags().Int(
+		maxRepositorySizeFlag,
+		0,
+		"size in MB as reported by GitHub above which repositories are skipped or sampled (unlimited if not positive)")
+	if err := viper.BindPFlag(maxRepositorySizeCfgKey, rootCmd.PersistentFlags().Lookup(maxRepositorySizeFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", maxRepositorySizeFlag, "Error", err)
+	}
+
+	// Flag to set how to handle oversized repositories
+	const oversizedRepositoriesFlag = "oversized-repositories"
+	rootCmd.PersistentFlags().String(
+		oversizedRepositoriesFlag,
+		skipOversized,
+		fmt.Sprintf("how to handle repositories exceeding the maximum size (%s, or %s to only analyze the most recent commits as given by the clone depth)",
+			skipOversized, sampleOversized))
+	if err := viper.BindPFlag(oversizedRepositoriesCfgKey, rootCmd.PersistentFlags().Lookup(oversizedRepositoriesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", oversizedRepositoriesFlag, "Error", err)
+	}
+
+	// Flag to set the source commits are collected from
+	const commitSourceFlag = "commit-source"
+	rootCmd.PersistentFlags().String(
+		commitSourceFlag,
+		cloneCommitSource,
+		fmt.Sprintf("source commits are collected from (%s or %s, the latter being faster for large repositories "+
+			"but limited to the last year, anonymous, and unfiltered)", cloneCommitSource, activityCommitSource))
+	if err := viper.BindPFlag(commitSourceCfgKey, rootCmd.PersistentFlags().Lookup(commitSourceFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", commitSourceFlag, "Error", err)
+	}
+
+	// Flag to control whether to wait for the reset of an exceeded rate limit
+	const waitForRateLimitFlag = "wait-for-rate-limit"
+	rootCmd.PersistentFlags().Bool(
+		waitForRateLimitFlag,
+		false,
+		"wait for the reset of an exceeded GitHub API rate limit instead of failing")
+	if err := viper.BindPFlag(waitForRateLimitCfgKey, rootCmd.PersistentFlags().Lookup(waitForRateLimitFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", waitForRateLimitFlag, "Error", err)
+	}
+
+	// Flag to set the file recording the collection progress of interrupted runs
+	const checkpointFlag = "checkpoint"
+	rootCmd.PersistentFlags().String(
+		checkpointFlag,
+		"",
+		"file recording the contributions collected per repository, so that a failed run is resumed by a re-run; removed after a successful run (no checkpoints if empty)")
+	if err := viper.BindPFlag(checkpointCfgKey, rootCmd.PersistentFlags().Lookup(checkpointFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", checkpointFlag, "Error", err)
+	}
+
+	// Flag to set the maximum number of repositories collected concurrently
+	const maxConcurrencyFlag = "max-concurrency"
+	rootCmd.PersistentFlags().Int(
+		maxConcurrencyFlag,
+		4,
+		"maximum number of repositories whose contributions are collected concurrently")
+	if err := viper.BindPFlag(maxConcurrencyCfgKey, rootCmd.PersistentFlags().Lookup(maxConcurrencyFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", maxConcurrencyFlag, "Error", err)
+	}
+
+	// Flag to set the maximum number of requests sent to the GitHub API per run
+	const maxAPIRequestsFlag = "max-api-requests"
+	rootCmd.PersistentFlags().Int(
+		maxAPIRequestsFlag,
+		0,
+		"maximum number of requests sent to the GitHub API per run, failing the run once exhausted (unlimited if not positive)")
+	if err := viper.BindPFlag(maxAPIRequestsCfgKey, rootCmd.PersistentFlags().Lookup(maxAPIRequestsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", maxAPIRequestsFlag, "Error", err)
+	}
+
+	// Flag to set the mode of reporting the collection progress
+	const progressFlag = "progress"
+	rootCmd.PersistentFlags().String(
+		progressFlag,
+		autoProgress,
+		fmt.Sprintf("mode of reporting the collection progress (%s, %s, %s, or %s, the first showing a progress bar on terminals and logging periodically otherwise)",
+			autoProgress, barProgress, logProgress, noProgress))
+	if err := viper.BindPFlag(progressCfgKey, rootCmd.PersistentFlags().Lookup(progressFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", progressFlag, "Error", err)
+	}
+
+	// Flag to set the number of retries of operations failing with transient errors
+	const retriesFlag = "retries"
+	rootCmd.PersistentFlags().Int(
+		retriesFlag,
+		3,
+		"number of times GitHub API requests and git operations failing with transient errors are retried")
+	if err := viper.BindPFlag(retriesCfgKey, rootCmd.PersistentFlags().Lookup(retriesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", retriesFlag, "Error", err)
+	}
+
+	// Flag to set the delay before the first retry
+	const retryDelayFlag = "retry-delay"
+	rootCmd.PersistentFlags().Duration(
+		retryDelayFlag,
+		time.Second,
+		"delay before the first retry, doubled for each further retry and jittered randomly")
+	if err := viper.BindPFlag(retryDelayCfgKey, rootCmd.PersistentFlags().Lookup(retryDelayFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", retryDelayFlag, "Error", err)
+	}
+
+	// Flag to control whether to report to GitHub Actions
+	const githubActionsFlag = "github-actions"
+	rootCmd.PersistentFlags().Bool(
+		githubActionsFlag,
+		false,
+		"report warnings as workflow commands and generated files and totals as step outputs and job summary of GitHub Actions (enabled on GitHub Actions runners)")
+	if err := viper.BindPFlag(githubActionsCfgKey, rootCmd.PersistentFlags().Lookup(githubActionsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", githubActionsFlag, "Error", err)
+	}
+
+	// Flag to set the branch generated files are published to
+	const publishBranchFlag = "publish-branch"
+	rootCmd.PersistentFlags().String(
+		publishBranchFlag,
+		"",
+		"branch generated files are committed to, created if missing (not published if empty)")
+	if err := viper.BindPFlag(publishBranchCfgKey, rootCmd.PersistentFlags().Lookup(publishBranchFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", publishBranchFlag, "Error", err)
+	}
+
+	// Flag to set the repository of the branch generated files are published to
+	const publishRepositoryFlag = "publish-repository"
+	rootCmd.PersistentFlags().String(
+		publishRepositoryFlag,
+		"",
+		"repository in owner/repository notation of the branch generated files are published to (default is $GITHUB_REPOSITORY)")
+	if err := viper.BindPFlag(publishRepositoryCfgKey, rootCmd.PersistentFlags().Lookup(publishRepositoryFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", publishRepositoryFlag, "Error", err)
+	}
+
+	// Flag to set the ID of the gist generated files are published to
+	const publishGistFlag = "publish-gist"
+	rootCmd.PersistentFlags().String(
+		publishGistFlag,
+		"",
+		"ID of the gist whose files are updated with the generated files (not published if empty)")
+	if err := viper.BindPFlag(publishGistCfgKey, rootCmd.PersistentFlags().Lookup(publishGistFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", publishGistFlag, "Error", err)
+	}
+
+	// Flag to set the object storage provider generated files are uploaded to
+	const uploadProviderFlag = "upload-provider"
+	rootCmd.PersistentFlags().String(
+		uploadProviderFlag,
+		"",
+		fmt.Sprintf("object storage provider generated files are uploaded to (%s, %s, or %s; not uploaded if empty)", s3Provider, gcsProvider, azureProvider))
+	if err := viper.BindPFlag(uploadProviderCfgKey, rootCmd.PersistentFlags().Lookup(uploadProviderFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", uploadProviderFlag, "Error", err)
+	}
+
+	// Flag to set the endpoint of the object storage
+	const uploadEndpointFlag = "upload-endpoint"
+	rootCmd.PersistentFlags().String(
+		uploadEndpointFlag,
+		"",
+		"endpoint URL of the object storage (default is the endpoint of the provider's region; required for azure)")
+	if err := viper.BindPFlag(uploadEndpointCfgKey, rootCmd.PersistentFlags().Lookup(uploadEndpointFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", uploadEndpointFlag, "Error", err)
+	}
+
+	// Flag to set the bucket generated files are uploaded to
+	const uploadBucketFlag = "upload-bucket"
+	rootCmd.PersistentFlags().String(
+		uploadBucketFlag,
+		"",
+		"bucket (or container for azure) generated files are uploaded to")
+	if err := viper.BindPFlag(uploadBucketCfgKey, rootCmd.PersistentFlags().Lookup(uploadBucketFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", uploadBucketFlag, "Error", err)
+	}
+
+	// Flag to set the prefix of the names of uploaded objects
+	const uploadPrefixFlag = "upload-prefix"
+	rootCmd.PersistentFlags().String(
+		uploadPrefixFlag,
+		"",
+		"prefix of the names of uploaded objects (e.g., a directory)")
+	if err := viper.BindPFlag(uploadPrefixCfgKey, rootCmd.PersistentFlags().Lookup(uploadPrefixFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", uploadPrefixFlag, "Error", err)
+	}
+
+	// Flag to set the region of S3-compatible object storage
+	const uploadRegionFlag = "upload-region"
+	rootCmd.PersistentFlags().String(
+		uploadRegionFlag,
+		"us-east-1",
+		"region of S3-compatible object storage (auto for gcs)")
+	if err := viper.BindPFlag(uploadRegionCfgKey, rootCmd.PersistentFlags().Lookup(uploadRegionFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", uploadRegionFlag, "Error", err)
+	}
+
+	// Flag to set the SQLite database collected contributions are exported to
+	const sqliteFileFlag = "sqlite-file"
+	rootCmd.PersistentFlags().String(
+		sqliteFileFlag,
+		"",
+		"SQLite database the collected contributions and daily aggregates are exported to ('.sql' writes the SQL script instead)")
+	if err := viper.BindPFlag(sqliteFileCfgKey, rootCmd.PersistentFlags().Lookup(sqliteFileFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", sqliteFileFlag, "Error", err)
+	}
+
+	// Flag to set the directory the collected contributions are exported to as Parquet files
+	const parquetDirectoryFlag = "parquet-directory"
+	rootCmd.PersistentFlags().String(
+		parquetDirectoryFlag,
+		"",
+		"directory the collected contributions and daily aggregates are exported to as Parquet files")
+	if err := viper.BindPFlag(parquetDirectoryCfgKey, rootCmd.PersistentFlags().Lookup(parquetDirectoryFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", parquetDirectoryFlag, "Error", err)
+	}
+
+	// Flag to set the file the manifest of the generated files is written to
+	const manifestFlag = "manifest"
+	rootCmd.PersistentFlags().String(
+		manifestFlag,
+		"",
+		"JSON file listing the generated files with their formats and checksums and the parameters of the run (no manifest if empty)")
+	if err := viper.BindPFlag(manifestCfgKey, rootCmd.PersistentFlags().Lookup(manifestFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", manifestFlag, "Error", err)
+	}
+
+	// Flag to set the Prometheus Pushgateway metrics are pushed to
+	const metricsPushgatewayFlag = "metrics-pushgateway"
+	rootCmd.PersistentFlags().String(
+		metricsPushgatewayFlag,
+		"",
+		"URL of a Prometheus Pushgateway the community metrics are pushed to after the run")
+	if err := viper.BindPFlag(metricsPushgatewayCfgKey, rootCmd.PersistentFlags().Lookup(metricsPushgatewayFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", metricsPushgatewayFlag, "Error", err)
+	}
+
+	// Flag to set the job name metrics are pushed under
+	const metricsJobFlag = "metrics-job"
+	rootCmd.PersistentFlags().String(
+		metricsJobFlag,
+		"herdstat",
+		"job name the metrics are pushed under")
+	if err := viper.BindPFlag(metricsJobCfgKey, rootCmd.PersistentFlags().Lookup(metricsJobFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", metricsJobFlag, "Error", err)
+	}
+
+	// Flag to set the number of top contributors listed in notifications
+	const notifyTopFlag = "notify-top"
+	rootCmd.PersistentFlags().Int(
+		notifyTopFlag,
+		3,
+		"number of top contributors listed in Slack and Discord notifications")
+	if err := viper.BindPFlag(notifyTopCfgKey, rootCmd.PersistentFlags().Lookup(notifyTopFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", notifyTopFlag, "Error", err)
+	}
+
+	// Flag to set the contribution graph linked in notifications
+	const notifyLinkFlag = "notify-link"
+	rootCmd.PersistentFlags().String(
+		notifyLinkFlag,
+		"",
+		"URL of the contribution graph linked in Slack and Discord notifications (default is the first published or uploaded SVG document)")
+	if err := viper.BindPFlag(notifyLinkCfgKey, rootCmd.PersistentFlags().Lookup(notifyLinkFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", notifyLinkFlag, "Error", err)
+	}
+
+	// Flag to set the issue or pull request results are commented on
+	const commentIssueFlag = "comment-issue"
+	rootCmd.PersistentFlags().Int(
+		commentIssueFlag,
+		0,
+		"number of an issue or pull request the results are commented on (not commented if not positive)")
+	if err := viper.BindPFlag(commentIssueCfgKey, rootCmd.PersistentFlags().Lookup(commentIssueFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", commentIssueFlag, "Error", err)
+	}
+
+	// Flag to comment results on the pull request triggering the workflow
+	const commentPullRequestFlag = "comment-pull-request"
+	rootCmd.PersistentFlags().Bool(
+		commentPullRequestFlag,
+		false,
+		"comment the results on the pull request triggering the GitHub Actions workflow")
+	if err := viper.BindPFlag(commentPullRequestCfgKey, rootCmd.PersistentFlags().Lookup(commentPullRequestFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", commentPullRequestFlag, "Error", err)
+	}
+	rootCmd.MarkFlagsMutuallyExclusive(commentIssueFlag, commentPullRequestFlag)
+
+	// Flag to set the repository of the issue or pull request results are commented on
+	const commentRepositoryFlag = "comment-repository"
+	rootCmd.PersistentFlags().String(
+		commentRepositoryFlag,
+		"",
+		"repository in owner/repository notation of the issue or pull request results are commented on (default is $GITHUB_REPOSITORY)")
+	if err := viper.BindPFlag(commentRepositoryCfgKey, rootCmd.PersistentFlags().Lookup(commentRepositoryFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", commentRepositoryFlag, "Error", err)
+	}
+
+	// Flag to control whether the previous comment is updated
+	const commentUpdateFlag = "comment-update"
+	rootCmd.PersistentFlags().Bool(
+		commentUpdateFlag,
+		true,
+		"update the previous comment on the results of the command instead of posting a new one")
+	if err := viper.BindPFlag(commentUpdateCfgKey, rootCmd.PersistentFlags().Lookup(commentUpdateFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", commentUpdateFlag, "Error", err)
+	}
+
+	// Pick up object storage credentials from the environment variables used by the providers' tooling
+	for key, names := range map[string][]string{
+		uploadAccessKeyIDCfgKey:     {"UPLOAD_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"},
+		uploadSecretAccessKeyCfgKey: {"UPLOAD_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"},
+		uploadSessionTokenCfgKey:    {"UPLOAD_SESSION_TOKEN", "AWS_SESSION_TOKEN"},
+		uploadSASTokenCfgKey:        {"UPLOAD_SAS_TOKEN", "AZURE_STORAGE_SAS_TOKEN"},
+	} {
+		if err := viper.BindEnv(append([]string{key}, names...)...); err != nil {
+			logger.Fatalw("Can't bind to environment variables", "Key", key, "Error", err)
+		}
+	}
+
+	// Pick up the PostgreSQL DSN, which embeds credentials, from the environment
+	if err := viper.BindEnv(postgresDSNCfgKey, "POSTGRES_DSN", "DATABASE_URL"); err != nil {
+		logger.Fatalw("Can't bind to environment variables", "Key", postgresDSNCfgKey, "Error", err)
+	}
+
+	// Pick up notification webhooks, which embed credentials, from the environment
+	for key, names := range map[string][]string{
+		notifySlackWebhookCfgKey:   {"NOTIFY_SLACK_WEBHOOK", "SLACK_WEBHOOK_URL"},
+		notifyDiscordWebhookCfgKey: {"NOTIFY_DISCORD_WEBHOOK", "DISCORD_WEBHOOK_URL"},
+	} {
+		if err := viper.BindEnv(append([]string{key}, names...)...); err != nil {
+			logger.Fatalw("Can't bind to environment variables", "Key", key, "Error", err)
+		}
+	}
 
 	rootCmd.AddCommand(
 		extension.NewVersionCobraCmd(
@@ -243,6 +1257,9 @@ func initConfig() {
 		viper.SetConfigType("yaml")
 		viper.SetConfigName(".herdstat")
 	}
+	if excludeForks {
+		viper.Set(includeForksCfgKey, false)
+	}
 	replacer := strings.NewReplacer("-", "_")
 	viper.SetEnvKeyReplacer(replacer)
 	viper.AutomaticEnv()
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+var _ = Describe("Owned repositories", func() {
+	It("leaves out archived repositories", func() {
+		active := &github.Repository{Name: github.String("active")}
+		archived := &github.Repository{Name: github.String("archived"), Archived: github.Bool(true)}
+		repos, skipped := withoutRepositories([]*github.Repository{active, archived}, (*github.Repository).GetArchived)
+		Expect(repos).To(ConsistOf(active))
+		Expect(skipped).To(Equal(1))
+	})
+
+	It("requires a token to include private repositories", func() {
+		for key, value := range map[string]interface{}{
+			includePrivateCfgKey: true,
+			gitHubTokenCfgKey:    "",
+			ghCredentialsCfgKey:  false,
+		} {
+			DeferCleanup(viper.Set, key, viper.Get(key))
+			viper.Set(key, value)
+		}
+		repositories := make(map[url.URL]*github.Repository)
+		Expect(addOwnedRepositories(github.NewClient(nil), "herdstat", nil, &repositories)).To(MatchError(ContainSubstring("requires a GitHub token")))
+	})
+
+	It("collects the repositories of all pages", func() {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/orgs/herdstat/repos"))
+			page := r.URL.Query().Get("page")
+			if page == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/herdstat/repos?page=2>; rel="next"`, server.URL))
+			}
+			name := "herdstat"
+			if page == "2" {
+				name = "action"
+			}
+			_, _ = fmt.Fprintf(w, `[{"name": "%s", "full_name": "herdstat/%[1]s", "html_url": "https://github.com/herdstat/%[1]s"}]`, name)
+		}))
+		DeferCleanup(server.Close)
+		client := github.NewClient(nil)
+		u, err := url.Parse(server.URL + "/")
+		Expect(err).NotTo(HaveOccurred())
+		client.BaseURL = u
+		DeferCleanup(viper.Set, includePrivateCfgKey, viper.GetBool(includePrivateCfgKey))
+		viper.Set(includePrivateCfgKey, false)
+
+		repositories := make(map[url.URL]*github.Repository)
+		Expect(addOwnedRepositories(client, "herdstat", nil, &repositories)).To(Succeed())
+		var names []string
+		for _, repo := range repositories {
+			names = append(names, repo.GetFullName())
+		}
+		Expect(names).To(ConsistOf("herdstat/herdstat", "herdstat/action"))
+	})
+
+	It("collects private repositories of owners only if enabled", func() {
+		Expect(ownerCollects(true, false, false)).To(BeFalse())
+		DeferCleanup(viper.Set, includePrivateCfgKey, false)
+		viper.Set(includePrivateCfgKey, true)
+		Expect(ownerCollects(true, false, false)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Logging", func() {
+	BeforeEach(func() {
+		for _, key := range []string{verboseCfgKey, logFormatCfgKey, logLevelCfgKey} {
+			DeferCleanup(viper.Set, key, viper.Get(key))
+		}
+		viper.Set(verboseCfgKey, false)
+		viper.Set(logFormatCfgKey, "")
+		viper.Set(logLevelCfgKey, "")
+	})
+
+	It("defaults to JSON at info level", func() {
+		config, err := getLogConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Encoding).To(Equal(jsonLogFormat))
+		Expect(config.Level.Level()).To(Equal(zapcore.InfoLevel))
+	})
+
+	It("defaults to console output at debug level if verbose", func() {
+		viper.Set(verboseCfgKey, true)
+		config, err := getLogConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Encoding).To(Equal(consoleLogFormat))
+		Expect(config.Level.Level()).To(Equal(zapcore.DebugLevel))
+	})
+
+	It("uses the configured format and level", func() {
+		viper.Set(verboseCfgKey, true)
+		viper.Set(logFormatCfgKey, jsonLogFormat)
+		viper.Set(logLevelCfgKey, "warn")
+		config, err := getLogConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Encoding).To(Equal(jsonLogFormat))
+		Expect(config.Level.Level()).To(Equal(zapcore.WarnLevel))
+	})
+
+	It("rejects unknown formats and levels", func() {
+		viper.Set(logFormatCfgKey, "xml")
+		_, err := getLogConfig()
+		Expect(err).To(MatchError(ContainSubstring("unknown log format")))
+		viper.Set(logFormatCfgKey, "")
+		viper.Set(logLevelCfgKey, "chatty")
+		_, err = getLogConfig()
+		Expect(err).To(MatchError(ContainSubstring("invalid log level")))
+	})
+})
@@ -0,0 +1,456 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Configuration keys for the serve command
+const (
+
+	// The address the server listens on
+	serveAddressCfgKey = "serve.address"
+
+	// The period generated graphs and collected contributions are cached for
+	serveCacheTTLCfgKey = "serve.cache-ttl"
+
+	// The owners whose repositories graphs are served for
+	serveOwnersCfgKey = "serve.owners"
+
+	// The secret GitHub webhook deliveries are signed with
+	serveWebhookSecretCfgKey = "serve.webhook-secret"
+
+	// The number of days before today the last day of served graphs may lie
+	serveUntilWindowCfgKey = "serve.until-window"
+)
+
+// serveShutdownTimeout is the time given to requests in progress to complete
+// when the server is stopped.
+const serveShutdownTimeout = 10 * time.Second
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves contribution graphs generated on demand via HTTP",
+	Long: `Serves contribution graphs generated on demand, so that they can be
+hot-linked instead of being regenerated periodically. Graphs are available at
+
+  /orgs/{owner}/contribution-graph.svg
+  /repos/{owner}/{repository}/contribution-graph.svg
+
+and styled as configured for the contribution-graph command. The 'color',
+'levels', and 'until' query parameters override the primary color, the number
+of color levels, and the last day of the 52 weeks shown (default is today).
+The last day may lie up to the configured number of days before today.
+Collected contributions and generated graphs are cached for the configured
+period.
+
+If a webhook secret is configured, GitHub webhooks for push, issues,
+pull_request, and pull_request_review events delivered to /webhook update the
+cached contributions and invalidate the affected graphs, so that graphs are
+kept up to date without waiting for the cache to expire.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	server, err := newGraphServer(cmd)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{
+		Addr:              viper.GetString(serveAddressCfgKey),
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	cmd.Printf("Serving contribution graphs on '%s'\n", httpServer.Addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// graphServer is a http.Handler serving contribution graphs generated on
+// demand.
+type graphServer struct {
+	style  graphStyle
+	minify bool
+	ttl    time.Duration
+
+	// The number of days before today the last day of served graphs may lie,
+	// so that requests can't trigger collections for arbitrary periods
+	untilWindow int
+
+	// The owners graphs are served for, nil if unrestricted
+	owners map[string]bool
+
+	// The secret webhook deliveries are signed with, nil if webhooks are
+	// disabled
+	webhookSecret []byte
+
+	// Function collecting the contributions to the given repositories made
+	// within the 52 weeks ending with the given day, replaceable for testing
+	collect func(repos []string, lastDay time.Time) ([]internal.Contribution, error)
+
+	// Guards contributions, graphs, and generation
+	mutex sync.Mutex
+
+	// Collected contributions by repositories and last day
+	contributions map[string]*cachedContributions
+
+	// Generated graphs by request path and parameters
+	graphs map[string]cachedGraph
+
+	// Incremented whenever cached contributions are updated, so that graphs
+	// generated from outdated contributions aren't cached
+	generation uint64
+}
+
+// cachedContributions are the contributions collected for a request.
+type cachedContributions struct {
+
+	// Closed once the contributions have been collected
+	ready chan struct{}
+
+	// The repositories and owners the contributions have been collected from
+	repos []string
+
+	contributions []internal.Contribution
+	err           error
+	expires       time.Time
+}
+
+// cachedGraph is a generated graph.
+type cachedGraph struct {
+	repos   []string
+	svg     []byte
+	expires time.Time
+}
+
+// newGraphServer creates a graphServer for the given command using the
+// configuration of the contribution-graph command. Graphs are served for the
+// configured owners or the owners of the configured repositories. Graphs of
+// all owners are served if neither is configured.
+func newGraphServer(cmd *cobra.Command) (*graphServer, error) {
+	style, err := getGraphStyle()
+	if err != nil {
+		return nil, err
+	}
+	owners := viper.GetStringSlice(serveOwnersCfgKey)
+	if len(owners) == 0 {
+		for _, repo := range viper.GetStringSlice(repositoriesCfgKey) {
+			owner, _, _ := strings.Cut(repo, "/")
+			owners = append(owners, owner)
+		}
+	}
+	var allowed map[string]bool
+	if len(owners) == 0 {
+		logger.Warnw("Serving graphs of all owners as neither owners nor repositories are configured")
+	} else {
+		allowed = make(map[string]bool)
+		for _, owner := range owners {
+			allowed[strings.ToLower(owner)] = true
+		}
+	}
+	var webhookSecret []byte
+	if secret := viper.GetString(serveWebhookSecretCfgKey); secret != "" {
+		webhookSecret = []byte(secret)
+	} else {
+		logger.Infow("Webhooks are disabled as no webhook secret is configured")
+	}
+	untilWindow := viper.GetInt(serveUntilWindowCfgKey)
+	if untilWindow < 0 {
+		return nil, fmt.Errorf("the until window must not be negative but is %d", untilWindow)
+	}
+	return &graphServer{
+		style:         style,
+		minify:        viper.GetBool(minifyOutputCfgKey),
+		ttl:           viper.GetDuration(serveCacheTTLCfgKey),
+		untilWindow:   untilWindow,
+		owners:        allowed,
+		webhookSecret: webhookSecret,
+		collect: func(repos []string, lastDay time.Time) ([]internal.Contribution, error) {
+			return collectContributionsFor(cmd, repos, lastDay)
+		},
+		contributions: make(map[string]*cachedContributions),
+		graphs:        make(map[string]cachedGraph),
+	}, nil
+}
+
+// graphRepositories returns the repositories whose graph is served at the
+// given path. The second return value is false if no graph is served at the
+// path.
+func graphRepositories(path string) ([]string, bool) {
+	const suffix = "/contribution-graph.svg"
+	if !strings.HasSuffix(path, suffix) {
+		return nil, false
+	}
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSuffix(path, suffix), "/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "orgs":
+		return []string{parts[1]}, true
+	case len(parts) == 3 && parts[0] == "repos":
+		return []string{parts[1] + "/" + parts[2]}, true
+	}
+	return nil, false
+}
+
+// ServeHTTP serves the contribution graph requested by the given request.
+func (s *graphServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == webhookPath {
+		s.serveWebhook(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	repos, ok := graphRepositories(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	for _, repo := range repos {
+		owner, _, _ := strings.Cut(repo, "/")
+		if ownerOrRepoIDPattern.FindString(repo) != repo || (s.owners != nil && !s.owners[strings.ToLower(owner)]) {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	style := s.style
+	query := r.URL.Query()
+	if c := query.Get("color"); c != "" {
+		var err error
+		if style.primaryColor, err = parsePrimaryColor(c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if l := query.Get("levels"); l != "" {
+		levels, err := strconv.ParseUint(l, 10, 8)
+		if err == nil {
+			style.levels, err = checkLevels(uint(levels))
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid number of color levels '%s'", l), http.StatusBadRequest)
+			return
+		}
+	}
+	if l := query.Get("locale"); l != "" {
+		var err error
+		if style.text, err = internal.ParseGraphLocale(l); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		style.locale = l
+	}
+	latest, err := parseUntilDate(today())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	lastDay := latest
+	if until := query.Get("until"); until != "" {
+		if lastDay, err = parseUntilDate(until); err != nil {
+			http.Error(w, fmt.Sprintf("invalid date '%s'", until), http.StatusBadRequest)
+			return
+		}
+		if lastDay.After(latest) {
+			http.Error(w, fmt.Sprintf("date '%s' lies in the future", until), http.StatusBadRequest)
+			return
+		}
+		if lastDay.Before(latest.AddDate(0, 0, -s.untilWindow)) {
+			http.Error(w, fmt.Sprintf("date '%s' lies more than %d days in the past", until, s.untilWindow),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	key := fmt.Sprintf("%s?color=%v&levels=%d&locale=%s&until=%s", r.URL.Path, style.primaryColor, style.levels,
+		style.locale, lastDay.Format("2006-01-02"))
+	svg, err := s.graph(key, repos, lastDay, style)
+	if err != nil {
+		logger.Warnw("Generating contribution graph failed", "path", r.URL.Path, "Error", err)
+		http.Error(w, "generating contribution graph failed", http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.ttl.Seconds())))
+	_, _ = w.Write(svg)
+}
+
+// graph returns the graph with the given cache key of the given repositories
+// for the 52 weeks ending with the given day in the given style. The graph
+// is generated if it isn't cached. Expired graphs and contributions are
+// evicted, so that requests varying the parameters don't exhaust memory.
+func (s *graphServer) graph(key string, repos []string, lastDay time.Time, style graphStyle) ([]byte, error) {
+	s.mutex.Lock()
+	cached, ok := s.graphs[key]
+	generation := s.generation
+	s.mutex.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.svg, nil
+	}
+	contributions, err := s.getContributions(repos, lastDay)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := encodeSVG(&buf, style.newGraph(internal.DailyRecords(contributions, lastDay), lastDay), s.minify, false); err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	now := time.Now()
+	s.evictExpired(now)
+	if s.generation == generation {
+		s.graphs[key] = cachedGraph{repos: repos, svg: buf.Bytes(), expires: now.Add(s.ttl)}
+	}
+	s.mutex.Unlock()
+	return buf.Bytes(), nil
+}
+
+// evictExpired evicts the graphs and the collected contributions expired at
+// the given time. Contributions still being collected are kept. The caller
+// must hold the mutex.
+func (s *graphServer) evictExpired(now time.Time) {
+	for k, g := range s.graphs {
+		if now.After(g.expires) {
+			delete(s.graphs, k)
+		}
+	}
+	for k, entry := range s.contributions {
+		select {
+		case <-entry.ready:
+			if now.After(entry.expires) {
+				delete(s.contributions, k)
+			}
+		default:
+		}
+	}
+}
+
+// getContributions returns the contributions to the given repositories made
+// within the 52 weeks ending with the given day. They are collected if they
+// aren't cached. Concurrent requests for the same contributions wait for a
+// single collection.
+func (s *graphServer) getContributions(repos []string, lastDay time.Time) ([]internal.Contribution, error) {
+	key := strings.ToLower(strings.Join(repos, ",")) + "@" + lastDay.Format("2006-01-02")
+	s.mutex.Lock()
+	entry, ok := s.contributions[key]
+	if ok {
+		select {
+		case <-entry.ready:
+			ok = time.Now().Before(entry.expires)
+		default:
+		}
+	}
+	if ok {
+		s.mutex.Unlock()
+		<-entry.ready
+		// Contributions may have been updated by webhooks meanwhile
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return entry.contributions, entry.err
+	}
+	s.evictExpired(time.Now())
+	entry = &cachedContributions{ready: make(chan struct{}), repos: repos}
+	s.contributions[key] = entry
+	s.mutex.Unlock()
+
+	contributions, err := s.collect(repos, lastDay)
+	entry.contributions, entry.err = contributions, err
+	entry.expires = time.Now().Add(s.ttl)
+	close(entry.ready)
+	if err != nil {
+		// Don't cache failures
+		s.mutex.Lock()
+		if s.contributions[key] == entry {
+			delete(s.contributions, key)
+		}
+		s.mutex.Unlock()
+	}
+	// The entry may be updated by webhooks once it's ready
+	return contributions, err
+}
+
+// Initialize the 'serve' command.
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	// Flag to set the address the server listens on
+	const addressFlag = "address"
+	serveCmd.Flags().String(
+		addressFlag,
+		":8080",
+		"The address the server listens on")
+	if err := viper.BindPFlag(serveAddressCfgKey, serveCmd.Flags().Lookup(addressFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", addressFlag, "Error", err)
+	}
+
+	// Flag to set the period generated graphs are cached for
+	const cacheTTLFlag = "cache-ttl"
+	serveCmd.Flags().Duration(
+		cacheTTLFlag,
+		time.Hour,
+		"The period collected contributions and generated graphs are cached for")
+	if err := viper.BindPFlag(serveCacheTTLCfgKey, serveCmd.Flags().Lookup(cacheTTLFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", cacheTTLFlag, "Error", err)
+	}
+
+	// Flag to set the owners graphs are served for
+	const ownersFlag = "owners"
+	serveCmd.Flags().StringSlice(
+		ownersFlag,
+		nil,
+		"The owners whose graphs are served (default is the owners of the configured repositories)")
+	if err := viper.BindPFlag(serveOwnersCfgKey, serveCmd.Flags().Lookup(ownersFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", ownersFlag, "Error", err)
+	}
+
+	// Flag to set the number of days before today the last day of served
+	// graphs may lie
+	const untilWindowFlag = "until-window"
+	serveCmd.Flags().Int(
+		untilWindowFlag,
+		7,
+		"The number of days before today the last day of served graphs may lie")
+	if err := viper.BindPFlag(serveUntilWindowCfgKey, serveCmd.Flags().Lookup(untilWindowFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", untilWindowFlag, "Error", err)
+	}
+
+	// Pick up the webhook secret from the environment as it is a credential
+	if err := viper.BindEnv(serveWebhookSecretCfgKey, "SERVE_WEBHOOK_SECRET"); err != nil {
+		logger.Fatalw("Can't bind to environment variables", "Key", serveWebhookSecretCfgKey, "Error", err)
+	}
+}
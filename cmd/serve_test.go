@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+var _ = Describe("Serving contribution graphs", func() {
+	var (
+		server    *graphServer
+		collected [][]string
+	)
+
+	BeforeEach(func() {
+		style, err := getGraphStyle()
+		Expect(err).NotTo(HaveOccurred())
+		collected = nil
+		server = &graphServer{
+			style:       style,
+			ttl:         time.Hour,
+			untilWindow: 7,
+			owners:      map[string]bool{"herdstat": true},
+			collect: func(repos []string, lastDay time.Time) ([]internal.Contribution, error) {
+				collected = append(collected, repos)
+				return []internal.Contribution{{Repository: "herdstat/herdstat", Date: lastDay}}, nil
+			},
+			contributions: make(map[string]*cachedContributions),
+			graphs:        make(map[string]cachedGraph),
+		}
+	})
+
+	// get sends a GET request for the given target to the server.
+	get := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder
+	}
+
+	It("serves graphs of organizations and repositories", func() {
+		resp := get("/orgs/herdstat/contribution-graph.svg")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("image/svg+xml"))
+		Expect(resp.Body.String()).To(ContainSubstring("<svg"))
+		Expect(get("/repos/herdstat/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+		Expect(collected).To(Equal([][]string{{"herdstat"}, {"herdstat/herdstat"}}))
+	})
+
+	It("caches collected contributions and generated graphs", func() {
+		Expect(get("/orgs/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+		Expect(get("/orgs/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+		Expect(get("/orgs/herdstat/contribution-graph.svg?color=ff0000&levels=7").Code).To(Equal(http.StatusOK))
+		Expect(server.graphs).To(HaveLen(2))
+		Expect(collected).To(HaveLen(1))
+	})
+
+	It("rejects invalid query parameters", func() {
+		Expect(get("/orgs/herdstat/contribution-graph.svg?levels=3").Code).To(Equal(http.StatusBadRequest))
+		Expect(get("/orgs/herdstat/contribution-graph.svg?color=nocolor").Code).To(Equal(http.StatusBadRequest))
+		Expect(get("/orgs/herdstat/contribution-graph.svg?until=yesterday").Code).To(Equal(http.StatusBadRequest))
+		Expect(collected).To(BeEmpty())
+	})
+
+	It("serves graphs of recent periods only", func() {
+		latest, err := parseUntilDate(today())
+		Expect(err).NotTo(HaveOccurred())
+		at := func(days int) string {
+			return "/orgs/herdstat/contribution-graph.svg?until=" + latest.AddDate(0, 0, days).Format("2006-01-02")
+		}
+		Expect(get(at(1)).Code).To(Equal(http.StatusBadRequest))
+		Expect(get(at(-8)).Code).To(Equal(http.StatusBadRequest))
+		Expect(collected).To(BeEmpty())
+		Expect(get(at(-7)).Code).To(Equal(http.StatusOK))
+	})
+
+	It("evicts expired contributions", func() {
+		latest, err := parseUntilDate(today())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get("/orgs/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+		for _, entry := range server.contributions {
+			entry.expires = time.Now().Add(-time.Second)
+		}
+		Expect(get("/orgs/herdstat/contribution-graph.svg?until=" + latest.AddDate(0, 0, -1).Format("2006-01-02")).Code).
+			To(Equal(http.StatusOK))
+		Expect(collected).To(HaveLen(2))
+		Expect(server.contributions).To(HaveLen(1))
+	})
+
+	It("serves graphs of the configured owners only", func() {
+		Expect(get("/orgs/other/contribution-graph.svg").Code).To(Equal(http.StatusNotFound))
+		Expect(get("/orgs/herdstat/graph.svg").Code).To(Equal(http.StatusNotFound))
+		Expect(get("/repos/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusNotFound))
+		Expect(collected).To(BeEmpty())
+	})
+
+	Context("with webhooks", func() {
+
+		const pushPayload = `{
+			"ref": "refs/heads/main",
+			"repository": {"full_name": "herdstat/herdstat", "html_url": "https://github.com/herdstat/herdstat", "default_branch": "main"},
+			"commits": [{"id": "0123456789abcdef0123456789abcdef01234567", "message": "Fix typo", "timestamp": "2023-04-01T12:00:00Z",
+				"author": {"name": "Jane", "email": "jane@example.com"}}]
+		}`
+
+		// deliver delivers the given webhook event with the given payload
+		// signed with the given secret to the server.
+		deliver := func(event string, payload string, secret string) *httptest.ResponseRecorder {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte(payload))
+			req := httptest.NewRequest(http.MethodPost, webhookPath, strings.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-GitHub-Event", event)
+			req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		BeforeEach(func() {
+			server.webhookSecret = []byte("secret")
+		})
+
+		It("updates cached contributions and evicts affected graphs", func() {
+			lastDay, err := parseUntilDate(today())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get("/orgs/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+			Expect(get("/repos/herdstat/other/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+			Expect(deliver("push", pushPayload, "secret").Code).To(Equal(http.StatusNoContent))
+			Expect(deliver("push", pushPayload, "secret").Code).To(Equal(http.StatusNoContent))
+			Expect(server.graphs).To(HaveLen(1))
+
+			Expect(get("/orgs/herdstat/contribution-graph.svg").Code).To(Equal(http.StatusOK))
+			Expect(collected).To(HaveLen(2))
+			contributions, err := server.getContributions([]string{"herdstat"}, lastDay)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contributions).To(HaveLen(2))
+			Expect(contributions[1].URL).To(Equal("https://github.com/herdstat/herdstat/commit/0123456789abcdef0123456789abcdef01234567"))
+			Expect(contributions[1].Name).To(Equal("Jane"))
+		})
+
+		It("updates contributions while they are handed out", func() {
+			lastDay, err := parseUntilDate(today())
+			Expect(err).NotTo(HaveOccurred())
+			started := make(chan struct{})
+			release := make(chan struct{})
+			server.collect = func(repos []string, lastDay time.Time) ([]internal.Contribution, error) {
+				close(started)
+				<-release
+				return []internal.Contribution{{Repository: "herdstat/herdstat", Date: lastDay}}, nil
+			}
+			done := make(chan []internal.Contribution)
+			go func() {
+				defer GinkgoRecover()
+				contributions, err := server.getContributions([]string{"herdstat"}, lastDay)
+				Expect(err).NotTo(HaveOccurred())
+				done <- contributions
+			}()
+			<-started
+			close(release)
+			// Deliver once the contributions are ready but possibly not yet
+			// handed out
+			Eventually(func() bool {
+				server.mutex.Lock()
+				defer server.mutex.Unlock()
+				for _, entry := range server.contributions {
+					select {
+					case <-entry.ready:
+						return true
+					default:
+					}
+				}
+				return false
+			}).Should(BeTrue())
+			Expect(deliver("push", pushPayload, "secret").Code).To(Equal(http.StatusNoContent))
+			var contributions []internal.Contribution
+			Eventually(done).Should(Receive(&contributions))
+			Expect(contributions).NotTo(BeEmpty())
+			Expect(contributions[0].Repository).To(Equal("herdstat/herdstat"))
+		})
+
+		It("ignores pushes to other branches", func() {
+			update, ok, err := webhookUpdateOf(&github.PushEvent{
+				Ref:  github.String("refs/heads/feature"),
+				Repo: &github.PushEventRepository{DefaultBranch: github.String("main")},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+			Expect(update.contributions).To(BeEmpty())
+		})
+
+		It("rejects deliveries with invalid signatures", func() {
+			Expect(deliver("push", pushPayload, "guessed").Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("is disabled without a secret", func() {
+			server.webhookSecret = nil
+			Expect(deliver("push", pushPayload, "").Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
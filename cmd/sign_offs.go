@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the sign-offs command
+const (
+	// The format of the report
+	signOffsFormatCfgKey = "sign-offs.format"
+	// The name of the output file
+	signOffsFilenameCfgKey = "sign-offs.filename"
+)
+
+// signOffsCmd represents the sign-offs command
+var signOffsCmd = &cobra.Command{
+	Use:   "sign-offs",
+	Short: "Reports the share of commits carrying a DCO sign-off",
+	Long: `Reports the share of commits whose message carries a 'Signed-off-by:' trailer
+as required by the Developer Certificate of Origin (DCO) per repository, per
+month, and overall.`,
+	Args: cobra.NoArgs,
+	RunE: runSignOffs,
+}
+
+func runSignOffs(cmd *cobra.Command, args []string) error {
+	commits, lastDay, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewSignOffReport(commits, lastDay)
+	return writeReport(cmd, report, viper.GetString(signOffsFormatCfgKey), viper.GetString(signOffsFilenameCfgKey))
+}
+
+// Initialize the 'sign-offs' command.
+func init() {
+	rootCmd.AddCommand(signOffsCmd)
+
+	addReportFlags(signOffsCmd, signOffsFormatCfgKey, signOffsFilenameCfgKey)
+}
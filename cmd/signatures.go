@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the signatures command
+const (
+	// The format of the report
+	signaturesFormatCfgKey = "signatures.format"
+	// The name of the output file
+	signaturesFilenameCfgKey = "signatures.filename"
+)
+
+// signaturesCmd represents the signatures command
+var signaturesCmd = &cobra.Command{
+	Use:   "signatures",
+	Short: "Reports the share of GPG or SSH signed commits",
+	Long: `Reports the share of commits signed with a GPG or SSH key per repository, per
+month, and overall. Signatures are not verified.`,
+	Args: cobra.NoArgs,
+	RunE: runSignatures,
+}
+
+func runSignatures(cmd *cobra.Command, args []string) error {
+	commits, lastDay, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewSignatureReport(commits, lastDay)
+	return writeReport(cmd, report, viper.GetString(signaturesFormatCfgKey), viper.GetString(signaturesFilenameCfgKey))
+}
+
+// Initialize the 'signatures' command.
+func init() {
+	rootCmd.AddCommand(signaturesCmd)
+
+	addReportFlags(signaturesCmd, signaturesFormatCfgKey, signaturesFilenameCfgKey)
+}
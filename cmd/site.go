@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Configuration keys for the site command
+const (
+	// The directory the site is written to
+	siteDirectoryCfgKey = "site.directory"
+	// The directory containing templates overriding the default ones
+	siteTemplatesCfgKey = "site.templates"
+	// The title of the site
+	siteTitleCfgKey = "site.title"
+	// The primary color of the charts
+	siteColorCfgKey = "site.color"
+	// The color space used for interpolating the contribution graph cell colors
+	siteInterpolationCfgKey = "site.interpolation"
+	// The number of contributors listed on the leaderboards
+	siteTopCfgKey = "site.top"
+)
+
+// siteDashboardTemplate is the name of the file in the template directory
+// overriding the template of the site pages.
+const siteDashboardTemplate = "dashboard.gohtml"
+
+// siteCmd represents the site command
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Generates a static site of the contribution activity",
+	Long: `Generates a static site consisting of an index page covering all
+repositories and a page per repository. Each page is a dashboard embedding the
+contribution graph, the weekly contribution trend, a leaderboard of the most
+active contributors, and the summary statistics. The layout of the pages can
+be customized by placing a 'dashboard.gohtml' Go template in the template
+directory.`,
+	Args: cobra.NoArgs,
+	RunE: runSite,
+}
+
+func runSite(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(siteColorCfgKey)
+	primaryColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	interpolation, err := internal.ParseInterpolation(viper.GetString(siteInterpolationCfgKey))
+	if err != nil {
+		return err
+	}
+
+	var override string
+	if dir := viper.GetString(siteTemplatesCfgKey); dir != "" {
+		content, err := os.ReadFile(filepath.Join(dir, siteDashboardTemplate))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("reading template failed: %w", err)
+		}
+		override = string(content)
+	}
+	tmpl, err := internal.ParseDashboardTemplate(override)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+
+	directory := viper.GetString(siteDirectoryCfgKey)
+	pages := internal.NewSite(viper.GetString(siteTitleCfgKey), contributions, lastDay, viper.GetInt(siteTopCfgKey))
+	for _, page := range pages {
+		if err := addDashboardCharts(page.Dashboard, page.Contributions, lastDay, primaryColor, interpolation); err != nil {
+			return err
+		}
+		html, err := page.Dashboard.Render(tmpl)
+		if err != nil {
+			return fmt.Errorf("rendering page '%s' failed: %w", page.Path, err)
+		}
+		filename := filepath.Join(directory, filepath.FromSlash(page.Path))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return fmt.Errorf("creating directory for page '%s' failed: %w", page.Path, err)
+		}
+		if err := os.WriteFile(filename, []byte(html), 0644); err != nil {
+			return fmt.Errorf("writing page '%s' failed: %w", page.Path, err)
+		}
+		results.recordFile(filename)
+	}
+	cmd.Printf("Site with %d pages written to '%s'\n", len(pages), directory)
+
+	return nil
+}
+
+// Initialize the 'site' command.
+func init() {
+	rootCmd.AddCommand(siteCmd)
+
+	// Flag to control the title
+	const titleFlag = "title"
+	siteCmd.Flags().String(
+		titleFlag,
+		"Community Dashboard",
+		"The title of the site")
+	if err := viper.BindPFlag(siteTitleCfgKey, siteCmd.Flags().Lookup(titleFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", titleFlag, "Error", err)
+	}
+
+	// Flag to control the primary color
+	const colorFlag = "color"
+	siteCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The primary color of the charts")
+	if err := viper.BindPFlag(siteColorCfgKey, siteCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	// Flag to control the color interpolation
+	const interpolationFlag = "interpolation"
+	siteCmd.Flags().String(
+		interpolationFlag,
+		internal.RGBInterpolationName,
+		"The color space used for interpolating the contribution graph cell colors (rgb, hsl, or lab)")
+	if err := viper.BindPFlag(siteInterpolationCfgKey, siteCmd.Flags().Lookup(interpolationFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", interpolationFlag, "Error", err)
+	}
+
+	// Flag to control the size of the leaderboards
+	const topFlag = "top"
+	siteCmd.Flags().Int(
+		topFlag,
+		10,
+		"The number of contributors listed on the leaderboards")
+	if err := viper.BindPFlag(siteTopCfgKey, siteCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	// Flag to control the template directory
+	const templatesFlag = "templates"
+	siteCmd.Flags().String(
+		templatesFlag,
+		"",
+		fmt.Sprintf("The directory containing a '%s' template overriding the default page layout", siteDashboardTemplate))
+	if err := viper.BindPFlag(siteTemplatesCfgKey, siteCmd.Flags().Lookup(templatesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", templatesFlag, "Error", err)
+	}
+
+	const outputDirectoryFlag = "output-directory"
+	siteCmd.Flags().StringP(
+		outputDirectoryFlag,
+		"o",
+		"site",
+		"The directory the site is written to")
+	if err := viper.BindPFlag(siteDirectoryCfgKey, siteCmd.Flags().Lookup(outputDirectoryFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputDirectoryFlag, "Error", err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	_ "modernc.org/sqlite"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// collectedContributions returns the contributions recorded first and the last
+// day of the analyzed period. The contributions are nil if none have been
+// recorded.
+func (r *runResults) collectedContributions() ([]internal.Contribution, time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.contributions, r.lastDay
+}
+
+// exportSQLite writes the collected contributions and their daily aggregates
+// into the configured SQLite database. If the filename has the '.sql'
+// extension, the SQL script creating the database is written instead.
+func exportSQLite(cmd *cobra.Command) error {
+	filename := viper.GetString(sqliteFileCfgKey)
+	if filename == "" {
+		return nil
+	}
+	contributions, lastDay := results.collectedContributions()
+	if contributions == nil {
+		logger.Warnw("No contributions collected - nothing to export to SQLite", "file", filename)
+		return nil
+	}
+	if filepath.Ext(filename) == ".sql" {
+		var script bytes.Buffer
+		if err := internal.WriteSQLiteScript(&script, contributions, lastDay, time.Now()); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, script.Bytes(), 0644); err != nil {
+			return fmt.Errorf("writing SQL script failed: %w", err)
+		}
+	} else if err := loadSQLite(filename, contributions, lastDay, time.Now()); err != nil {
+		return fmt.Errorf("exporting to SQLite database '%s' failed: %w", filename, err)
+	}
+	results.recordFile(filename)
+	cmd.Printf("Contributions exported to '%s'\n", filename)
+	return nil
+}
+
+// loadSQLite writes the given contributions and their daily aggregates for the
+// 52 weeks ending with the given day into the SQLite database with the given
+// name. The database is created if it doesn't exist.
+func loadSQLite(filename string, contributions []internal.Contribution, lastDay time.Time, generated time.Time) error {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return insertSQLite(context.Background(), db, contributions, lastDay, generated)
+}
+
+// insertSQLite replaces the tables of the SQLite export and inserts the given
+// contributions and their daily aggregates in a single transaction.
+func insertSQLite(ctx context.Context, db *sql.DB, contributions []internal.Contribution, lastDay time.Time,
+	generated time.Time) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err := tx.ExecContext(ctx, internal.SQLiteSchema); err != nil {
+		return fmt.Errorf("creating tables failed: %w", err)
+	}
+	aggregates := internal.NewAggregateExport(contributions, lastDay, internal.DailyGranularity)
+	insertMetadata, err := tx.PrepareContext(ctx, "INSERT INTO metadata VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertMetadata.Close()
+	for _, kv := range internal.SQLiteMetadata(aggregates, generated) {
+		if _, err := insertMetadata.ExecContext(ctx, kv[0], kv[1]); err != nil {
+			return fmt.Errorf("inserting metadata '%s' failed: %w", kv[0], err)
+		}
+	}
+	insertContribution, err := tx.PrepareContext(ctx,
+		"INSERT INTO contributions VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertContribution.Close()
+	insertFile, err := tx.PrepareContext(ctx, "INSERT INTO contribution_files VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertFile.Close()
+	for i, c := range contributions {
+		id := i + 1
+		if _, err := insertContribution.ExecContext(ctx, id, string(c.Type), c.Repository, nullString(c.Contributor()),
+			nullString(c.Login), nullString(c.Name), nullString(c.Email), c.Date.UTC().Format(time.RFC3339),
+			nullString(c.URL), nullString(c.Hash), nullString(c.Subject), c.Signed, c.SignedOff, c.Additions,
+			c.Deletions); err != nil {
+			return fmt.Errorf("inserting contribution %d failed: %w", id, err)
+		}
+		for _, path := range c.Files {
+			if _, err := insertFile.ExecContext(ctx, id, path); err != nil {
+				return fmt.Errorf("inserting file of contribution %d failed: %w", id, err)
+			}
+		}
+	}
+	insertDailyContributions, err := tx.PrepareContext(ctx,
+		"INSERT INTO daily_contributions VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertDailyContributions.Close()
+	for _, r := range aggregates.Records {
+		if _, err := insertDailyContributions.ExecContext(ctx, r.Date, r.Repository, r.Commits, r.Issues,
+			r.PullRequests, r.Contributors); err != nil {
+			return fmt.Errorf("inserting daily contributions of '%s' on %s failed: %w", r.Repository, r.Date, err)
+		}
+	}
+	return tx.Commit()
+}
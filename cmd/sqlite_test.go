@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"database/sql"
+	"github.com/herdstat/herdstat/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("SQLite export", func() {
+	var cmd *cobra.Command
+
+	BeforeEach(func() {
+		cmd = &cobra.Command{}
+		cmd.SetOut(io.Discard)
+		DeferCleanup(viper.Set, sqliteFileCfgKey, viper.GetString(sqliteFileCfgKey))
+		results.reset()
+		DeferCleanup(results.reset)
+		lastDay := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+		results.recordContributions([]internal.Contribution{
+			{Type: internal.CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+			{Type: internal.PullRequestContribution, Repository: "herdstat/herdstat", Login: "john", Date: lastDay},
+		}, lastDay)
+	})
+
+	It("writes the SQL script for '.sql' files", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "herdstat.sql")
+		viper.Set(sqliteFileCfgKey, filename)
+		Expect(exportSQLite(cmd)).To(Succeed())
+		content, err := os.ReadFile(filename)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("CREATE TABLE daily_contributions"))
+		Expect(results.generatedFiles()).To(ConsistOf(filename))
+	})
+
+	It("loads the contributions into an SQLite database", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "herdstat.db")
+		viper.Set(sqliteFileCfgKey, filename)
+		// Exporting twice replaces the tables
+		Expect(exportSQLite(cmd)).To(Succeed())
+		Expect(exportSQLite(cmd)).To(Succeed())
+		db, err := sql.Open("sqlite", filename)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+		var commits, pullRequests, contributors, count int
+		Expect(db.QueryRow("SELECT commits, pull_requests, contributors FROM daily_contributions").
+			Scan(&commits, &pullRequests, &contributors)).To(Succeed())
+		Expect([]int{commits, pullRequests, contributors}).To(Equal([]int{1, 1, 2}))
+		Expect(db.QueryRow("SELECT COUNT(*) FROM contributions").Scan(&count)).To(Succeed())
+		Expect(count).To(Equal(2))
+		var login string
+		var name sql.NullString
+		Expect(db.QueryRow("SELECT login, name FROM contributions WHERE type = 'pull-request'").Scan(&login, &name)).To(Succeed())
+		Expect(login).To(Equal("john"))
+		Expect(name.Valid).To(BeFalse())
+	})
+})
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sort"
+	"time"
+)
+
+// Configuration keys for the stars command
+const (
+	// The name of the output file
+	starsFilenameCfgKey = "stars.filename"
+	// The length of the periods stars are aggregated over
+	starsGranularityCfgKey = "stars.granularity"
+	// The color of the line
+	starsColorCfgKey = "stars.color"
+	// Toggle for generating a chart per repository
+	starsPerRepositoryCfgKey = "stars.per-repository"
+)
+
+// starsCmd represents the stars command
+var starsCmd = &cobra.Command{
+	Use:   "stars",
+	Short: "Generates a chart of the star history",
+	Long: `Collects the points in time the repositories have been starred and renders the
+cumulative number of stars over time across all repositories and optionally
+per repository.`,
+	Args: cobra.NoArgs,
+	RunE: runStars,
+}
+
+func runStars(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(starsColorCfgKey)
+	lineColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	granularity, err := internal.ParseGranularity(viper.GetString(starsGranularityCfgKey))
+	if err != nil {
+		return err
+	}
+
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var starredAt []time.Time
+	perRepository := make(map[string][]time.Time)
+	for _, repository := range repositories {
+		s, err := collectStars(repository)
+		if err != nil {
+			return err
+		}
+		starredAt = append(starredAt, s...)
+		perRepository[repository.GetFullName()] = s
+	}
+
+	write := func(title string, starredAt []time.Time, filename string) error {
+		filename, err = writeSVG(cmd, internal.NewStarHistoryChart(title, starredAt, lastDay, granularity, lineColor), filename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Star history written to '%s'\n", filename)
+		return nil
+	}
+
+	filename := viper.GetString(starsFilenameCfgKey)
+	if err := write("Star history", starredAt, filename); err != nil {
+		return err
+	}
+	if viper.GetBool(starsPerRepositoryCfgKey) {
+		names := internal.Keys(perRepository)
+		sort.Strings(names)
+		for _, name := range names {
+			err := write(fmt.Sprintf("Star history of %s", name), perRepository[name], suffixedFilename(filename, name))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectStars collects the points in time the given repository has been
+// starred.
+func collectStars(repository *github.Repository) ([]time.Time, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.ListOptions{PerPage: 100}
+	var starredAt []time.Time
+	for {
+		// The client requests the starring media type including the timestamps
+		stargazers, resp, err := client.Activity.ListStargazers(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("fetching stargazers for repo %s/%s failed (Statuscode: %d)", owner, repo, resp.StatusCode)
+		}
+		for _, stargazer := range stargazers {
+			starredAt = append(starredAt, stargazer.GetStarredAt().Time)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return starredAt, nil
+}
+
+// Initialize the 'stars' command.
+func init() {
+	rootCmd.AddCommand(starsCmd)
+
+	// Flag to control the granularity
+	const granularityFlag = "granularity"
+	starsCmd.Flags().String(
+		granularityFlag,
+		internal.MonthlyGranularityName,
+		fmt.Sprintf("The length of the periods stars are aggregated over (%s, %s, or %s)",
+			internal.DailyGranularityName, internal.WeeklyGranularityName, internal.MonthlyGranularityName))
+	if err := viper.BindPFlag(starsGranularityCfgKey, starsCmd.Flags().Lookup(granularityFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", granularityFlag, "Error", err)
+	}
+
+	// Flag to control the line color
+	const colorFlag = "color"
+	starsCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the line")
+	if err := viper.BindPFlag(starsColorCfgKey, starsCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	// Flag to toggle charts per repository
+	const perRepositoryFlag = "per-repository"
+	starsCmd.Flags().Bool(
+		perRepositoryFlag,
+		false,
+		"Flag to toggle generating an additional chart per repository")
+	if err := viper.BindPFlag(starsPerRepositoryCfgKey, starsCmd.Flags().Lookup(perRepositoryFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", perRepositoryFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	starsCmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"stars.svg",
+		"The name of the generated SVG file")
+	if err := viper.BindPFlag(starsFilenameCfgKey, starsCmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the starter-issues command
+const (
+	// The labels identifying starter issues
+	starterIssuesLabelsCfgKey = "starter-issues.labels"
+	// The format of the report
+	starterIssuesFormatCfgKey = "starter-issues.format"
+	// The name of the output file
+	starterIssuesFilenameCfgKey = "starter-issues.filename"
+)
+
+// starterIssuesCmd represents the starter-issues command
+var starterIssuesCmd = &cobra.Command{
+	Use:   "starter-issues",
+	Short: "Reports the availability of issues suitable for newcomers",
+	Long: `Reports the number and age of open issues labeled as suitable for newcomers
+(e.g., 'good first issue' or 'help wanted') per repository and across all
+repositories. Repositories without such issues are flagged.`,
+	Args: cobra.NoArgs,
+	RunE: runStarterIssues,
+}
+
+func runStarterIssues(cmd *cobra.Command, args []string) error {
+	labels := viper.GetStringSlice(starterIssuesLabelsCfgKey)
+	if len(labels) == 0 {
+		return classify(exitConfigError, errors.New("at least one label identifying starter issues is required"))
+	}
+	lastDay, err := getUntilDate()
+	if err != nil {
+		return fmt.Errorf("parsing 'until' parameter '%s' failed: %w", viper.GetString(untilCfgKey), err)
+	}
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var names []string
+	var issues []internal.Issue
+	for _, repository := range repositories {
+		i, err := collectOpenIssues(repository)
+		if err != nil {
+			return err
+		}
+		names = append(names, repository.GetFullName())
+		issues = append(issues, i...)
+	}
+	report := internal.NewStarterIssuesReport(names, issues, lastDay, labels)
+	return writeReport(cmd, report, viper.GetString(starterIssuesFormatCfgKey), viper.GetString(starterIssuesFilenameCfgKey))
+}
+
+// Initialize the 'starter-issues' command.
+func init() {
+	rootCmd.AddCommand(starterIssuesCmd)
+
+	// Flag to control the labels identifying starter issues
+	const labelsFlag = "labels"
+	starterIssuesCmd.Flags().StringSlice(
+		labelsFlag,
+		[]string{"good first issue", "help wanted"},
+		"The labels identifying issues suitable for newcomers")
+	if err := viper.BindPFlag(starterIssuesLabelsCfgKey, starterIssuesCmd.Flags().Lookup(labelsFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", labelsFlag, "Error", err)
+	}
+
+	addReportFlags(starterIssuesCmd, starterIssuesFormatCfgKey, starterIssuesFilenameCfgKey)
+}
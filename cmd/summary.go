@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the summary command
+const (
+	// The format of the summary
+	summaryFormatCfgKey = "summary.format"
+	// The name of the output file
+	summaryFilenameCfgKey = "summary.filename"
+	// Whether to list days with unusual activity
+	summaryAnomaliesCfgKey = "summary.anomalies.enabled"
+	// The number of standard deviations above the rolling mean a day is considered unusual at
+	summaryAnomalyThresholdCfgKey = "summary.anomalies.threshold"
+	// Whether to include the time to first review of pull requests
+	summaryTimeToFirstReviewCfgKey = "summary.time-to-first-review"
+)
+
+// summaryCmd represents the summary command
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Generates aggregate contribution statistics in a machine-readable format",
+	Args:  cobra.NoArgs,
+	RunE:  runSummary,
+}
+
+func runSummary(cmd *cobra.Command, args []string) error {
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+	summary := internal.NewSummary(contributions, lastDay)
+	if viper.GetBool(summaryAnomaliesCfgKey) {
+		summary.Anomalies = internal.NewAnomalies(contributions, lastDay, viper.GetFloat64(summaryAnomalyThresholdCfgKey))
+	}
+	if viper.GetBool(summaryTimeToFirstReviewCfgKey) {
+		pullRequests, err := collectPullRequestsFor(cmd, lastDay)
+		if err != nil {
+			return err
+		}
+		metrics := internal.NewPullRequestMetrics("", pullRequests, lastDay)
+		summary.PullRequests = &metrics
+	}
+	return writeReport(cmd, summary, viper.GetString(summaryFormatCfgKey), viper.GetString(summaryFilenameCfgKey))
+}
+
+// Initialize the 'summary' command.
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+
+	addReportFlags(summaryCmd, summaryFormatCfgKey, summaryFilenameCfgKey)
+
+	// Flag to toggle listing days with unusual activity
+	const anomaliesFlag = "anomalies"
+	summaryCmd.Flags().Bool(
+		anomaliesFlag,
+		false,
+		"Flag to toggle listing days with an unusually high number of contributions")
+	if err := viper.BindPFlag(summaryAnomaliesCfgKey, summaryCmd.Flags().Lookup(anomaliesFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", anomaliesFlag, "Error", err)
+	}
+
+	// Flag to control the anomaly threshold
+	const anomalyThresholdFlag = "anomaly-threshold"
+	summaryCmd.Flags().Float64(
+		anomalyThresholdFlag,
+		internal.DefaultAnomalyThreshold,
+		"The number of standard deviations above the mean of the preceding four weeks a day is considered unusual at")
+	if err := viper.BindPFlag(summaryAnomalyThresholdCfgKey, summaryCmd.Flags().Lookup(anomalyThresholdFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", anomalyThresholdFlag, "Error", err)
+	}
+
+	// Flag to toggle including the time to first review of pull requests
+	const timeToFirstReviewFlag = "time-to-first-review"
+	summaryCmd.Flags().Bool(
+		timeToFirstReviewFlag,
+		false,
+		"Flag to toggle including the median and 90th percentile of the time until pull requests are first reviewed")
+	if err := viper.BindPFlag(summaryTimeToFirstReviewCfgKey, summaryCmd.Flags().Lookup(timeToFirstReviewFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", timeToFirstReviewFlag, "Error", err)
+	}
+}
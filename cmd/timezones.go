@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the timezones command
+const (
+	// The format of the report
+	timezonesFormatCfgKey = "timezones.format"
+	// The name of the output file
+	timezonesFilenameCfgKey = "timezones.filename"
+	// The name of the chart SVG file
+	timezonesChartCfgKey = "timezones.chart"
+	// The color of the chart bars
+	timezonesColorCfgKey = "timezones.color"
+)
+
+// timezonesCmd represents the timezones command
+var timezonesCmd = &cobra.Command{
+	Use:   "timezones",
+	Short: "Reports the distribution of contributors across time zones",
+	Long: `Infers the time zone of each contributor from the UTC offsets recorded with the
+contributor's commits and reports how many contributors are located in each
+time zone. The distribution is optionally rendered as an SVG bar chart.`,
+	Args: cobra.NoArgs,
+	RunE: runTimezones,
+}
+
+func runTimezones(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(timezonesColorCfgKey)
+	barColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	commits, lastDay, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewTimezoneReport(commits, lastDay)
+
+	if chartFilename := viper.GetString(timezonesChartCfgKey); chartFilename != "" {
+		chartFilename, err = writeSVG(cmd, internal.NewTimezoneChart(report, barColor), chartFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Time zone chart written to '%s'\n", chartFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(timezonesFormatCfgKey), viper.GetString(timezonesFilenameCfgKey))
+}
+
+// Initialize the 'timezones' command.
+func init() {
+	rootCmd.AddCommand(timezonesCmd)
+
+	// Flag to control the chart output file
+	const chartFlag = "chart"
+	timezonesCmd.Flags().String(
+		chartFlag,
+		"",
+		"The name of the SVG file the time zone chart is written to (no chart if empty)")
+	if err := viper.BindPFlag(timezonesChartCfgKey, timezonesCmd.Flags().Lookup(chartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", chartFlag, "Error", err)
+	}
+
+	// Flag to control the chart color
+	const colorFlag = "color"
+	timezonesCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the chart bars")
+	if err := viper.BindPFlag(timezonesColorCfgKey, timezonesCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(timezonesCmd, timezonesFormatCfgKey, timezonesFilenameCfgKey)
+}
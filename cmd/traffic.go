@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"net/http"
+)
+
+// Configuration keys for the traffic command
+const (
+	// The format of the report
+	trafficFormatCfgKey = "traffic.format"
+	// The name of the output file
+	trafficFilenameCfgKey = "traffic.filename"
+	// The name of the chart SVG file
+	trafficChartCfgKey = "traffic.chart"
+	// The color of the views line
+	trafficColorCfgKey = "traffic.color"
+)
+
+// trafficCmd represents the traffic command
+var trafficCmd = &cobra.Command{
+	Use:   "traffic",
+	Short: "Reports the views and clones of the repositories",
+	Long: `Collects the page views, unique visitors, clones, and unique cloners of the
+last 14 days as reported by GitHub. Traffic data is only available for
+repositories the token has push access to; other repositories are skipped. The
+daily views and clones are optionally rendered as an SVG line chart.`,
+	Args: cobra.NoArgs,
+	RunE: runTraffic,
+}
+
+func runTraffic(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(trafficColorCfgKey)
+	lineColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	repositories, err := resolveRepositories(cmd, viper.GetStringSlice(repositoriesCfgKey))
+	if err != nil {
+		return err
+	}
+	var traffic []internal.RepositoryTraffic
+	for _, repository := range repositories {
+		t, err := collectTraffic(repository)
+		if err != nil {
+			return err
+		}
+		if t != nil {
+			traffic = append(traffic, *t)
+		}
+	}
+	report := internal.NewTrafficReport(traffic)
+
+	if chartFilename := viper.GetString(trafficChartCfgKey); chartFilename != "" {
+		chart, err := internal.NewTrafficChart(report, lineColor)
+		if err != nil {
+			return err
+		}
+		chartFilename, err = writeSVG(cmd, chart, chartFilename, true, false)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Traffic chart written to '%s'\n", chartFilename)
+	}
+
+	return writeReport(cmd, report, viper.GetString(trafficFormatCfgKey), viper.GetString(trafficFilenameCfgKey))
+}
+
+// collectTraffic collects the daily views and clones of the given repository.
+// Returns nil if the token lacks access to the traffic data of the repository.
+func collectTraffic(repository *github.Repository) (*internal.RepositoryTraffic, error) {
+	ctx := context.Background()
+	client := github.NewClient(getHTTPClient())
+	owner := repository.GetOwner().GetLogin()
+	repo := repository.GetName()
+	opt := &github.TrafficBreakdownOptions{Per: "day"}
+	forbidden := func(resp *github.Response) bool {
+		return resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound)
+	}
+
+	views, resp, err := client.Repositories.ListTrafficViews(ctx, owner, repo, opt)
+	if forbidden(resp) {
+		logger.Warnw("No access to traffic data - ignoring", "Repository", repository.GetFullName())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	clones, resp, err := client.Repositories.ListTrafficClones(ctx, owner, repo, opt)
+	if forbidden(resp) {
+		logger.Warnw("No access to traffic data - ignoring", "Repository", repository.GetFullName())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	traffic := &internal.RepositoryTraffic{
+		Repository: repository.GetFullName(),
+		Views:      views.GetCount(),
+		Visitors:   views.GetUniques(),
+		Clones:     clones.GetCount(),
+		Cloners:    clones.GetUniques(),
+	}
+	days := make(map[string]*internal.TrafficDay)
+	day := func(data *github.TrafficData) *internal.TrafficDay {
+		date := data.GetTimestamp().Format("2006-01-02")
+		d, ok := days[date]
+		if !ok {
+			d = &internal.TrafficDay{Date: date}
+			days[date] = d
+		}
+		return d
+	}
+	for _, v := range views.Views {
+		d := day(v)
+		d.Views, d.Visitors = v.GetCount(), v.GetUniques()
+	}
+	for _, c := range clones.Clones {
+		d := day(c)
+		d.Clones, d.Cloners = c.GetCount(), c.GetUniques()
+	}
+	for _, d := range days {
+		traffic.Days = append(traffic.Days, *d)
+	}
+	return traffic, nil
+}
+
+// Initialize the 'traffic' command.
+func init() {
+	rootCmd.AddCommand(trafficCmd)
+
+	// Flag to control the chart output file
+	const chartFlag = "chart"
+	trafficCmd.Flags().String(
+		chartFlag,
+		"",
+		"The name of the SVG file the traffic chart is written to (no chart if empty)")
+	if err := viper.BindPFlag(trafficChartCfgKey, trafficCmd.Flags().Lookup(chartFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", chartFlag, "Error", err)
+	}
+
+	// Flag to control the chart color
+	const colorFlag = "color"
+	trafficCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the line of views")
+	if err := viper.BindPFlag(trafficColorCfgKey, trafficCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	addReportFlags(trafficCmd, trafficFormatCfgKey, trafficFilenameCfgKey)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/icza/gox/imagex/colorx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the trend command
+const (
+	// The name of the output file
+	trendFilenameCfgKey = "trend.filename"
+	// The length of the periods contributions are aggregated over
+	trendGranularityCfgKey = "trend.granularity"
+	// The color of the line
+	trendColorCfgKey = "trend.color"
+	// Toggle for filling the area beneath the line
+	trendAreaCfgKey = "trend.area"
+	// The metric visualized
+	trendMetricCfgKey = "trend.metric"
+)
+
+// Metrics visualized by the trend command.
+const (
+	contributionsMetric = "contributions"
+	contributorsMetric  = "contributors"
+)
+
+// trendCmd represents the trend command
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Generates a line chart of the contributions or active contributors per week or month",
+	Args:  cobra.NoArgs,
+	RunE:  runTrend,
+}
+
+func runTrend(cmd *cobra.Command, args []string) error {
+	colorStr := viper.GetString(trendColorCfgKey)
+	lineColor, err := colorx.ParseHexColor(fmt.Sprintf("#%s", colorStr))
+	if err != nil {
+		return fmt.Errorf("invalid color specification '%s': %w", colorStr, err)
+	}
+
+	granularity, err := internal.ParseGranularity(viper.GetString(trendGranularityCfgKey))
+	if err != nil {
+		return err
+	}
+
+	metric := viper.GetString(trendMetricCfgKey)
+	if metric != contributionsMetric && metric != contributorsMetric {
+		return fmt.Errorf("unknown metric '%s'; supported are %s and %s", metric, contributionsMetric, contributorsMetric)
+	}
+
+	contributions, lastDay, err := collectContributions(cmd)
+	if err != nil {
+		return err
+	}
+
+	area := viper.GetBool(trendAreaCfgKey)
+	chart := internal.NewTrendChart(internal.DailyRecords(contributions, lastDay), granularity, lineColor, area)
+	if metric == contributorsMetric {
+		chart = internal.NewContributorTrendChart(contributions, lastDay, granularity, lineColor, area)
+	}
+	filename, err := writeSVG(cmd, chart, viper.GetString(trendFilenameCfgKey), true, false)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Trend chart written to '%s'\n", filename)
+
+	return nil
+}
+
+// Initialize the 'trend' command.
+func init() {
+	rootCmd.AddCommand(trendCmd)
+
+	// Flag to control the visualized metric
+	const metricFlag = "metric"
+	trendCmd.Flags().String(
+		metricFlag,
+		contributionsMetric,
+		fmt.Sprintf("The metric visualized (%s or %s)", contributionsMetric, contributorsMetric))
+	if err := viper.BindPFlag(trendMetricCfgKey, trendCmd.Flags().Lookup(metricFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", metricFlag, "Error", err)
+	}
+
+	// Flag to control the granularity
+	const granularityFlag = "granularity"
+	trendCmd.Flags().String(
+		granularityFlag,
+		internal.WeeklyGranularityName,
+		fmt.Sprintf("The length of the periods contributions are aggregated over (%s, %s, or %s)",
+			internal.DailyGranularityName, internal.WeeklyGranularityName, internal.MonthlyGranularityName))
+	if err := viper.BindPFlag(trendGranularityCfgKey, trendCmd.Flags().Lookup(granularityFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", granularityFlag, "Error", err)
+	}
+
+	// Flag to control the line color
+	const colorFlag = "color"
+	trendCmd.Flags().String(
+		colorFlag,
+		"39D352",
+		"The color of the line")
+	if err := viper.BindPFlag(trendColorCfgKey, trendCmd.Flags().Lookup(colorFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", colorFlag, "Error", err)
+	}
+
+	// Flag to toggle the area beneath the line
+	const areaFlag = "area"
+	trendCmd.Flags().Bool(
+		areaFlag,
+		true,
+		"Flag to toggle filling the area beneath the line")
+	if err := viper.BindPFlag(trendAreaCfgKey, trendCmd.Flags().Lookup(areaFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", areaFlag, "Error", err)
+	}
+
+	const outputFilenameFlag = "output-filename"
+	trendCmd.Flags().StringP(
+		outputFilenameFlag,
+		"o",
+		"trend.svg",
+		"The name of the generated SVG file")
+	if err := viper.BindPFlag(trendFilenameCfgKey, trendCmd.Flags().Lookup(outputFilenameFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", outputFilenameFlag, "Error", err)
+	}
+}
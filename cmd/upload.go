@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Object storage providers generated files can be uploaded to.
+const (
+	// Amazon S3 and compatible services (e.g., MinIO or Cloudflare R2)
+	s3Provider = "s3"
+
+	// Google Cloud Storage via its S3-compatible XML API using HMAC keys
+	gcsProvider = "gcs"
+
+	// Azure Blob Storage using a shared access signature (SAS)
+	azureProvider = "azure"
+)
+
+// objectStore is the configuration of the object storage generated files are
+// uploaded to.
+type objectStore struct {
+	provider string
+	endpoint string
+	bucket   string
+	prefix   string
+	region   string
+
+	// Credentials of S3-compatible providers
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	// Credentials of Azure Blob Storage
+	sasToken string
+
+	client *http.Client
+
+	// Function returning the current time, replaceable for testing
+	now func() time.Time
+}
+
+// getObjectStore returns the configured object storage. Returns nil if no
+// provider is configured.
+func getObjectStore() (*objectStore, error) {
+	store := &objectStore{
+		provider:        viper.GetString(uploadProviderCfgKey),
+		endpoint:        strings.TrimSuffix(viper.GetString(uploadEndpointCfgKey), "/"),
+		bucket:          viper.GetString(uploadBucketCfgKey),
+		prefix:          viper.GetString(uploadPrefixCfgKey),
+		region:          viper.GetString(uploadRegionCfgKey),
+		accessKeyID:     viper.GetString(uploadAccessKeyIDCfgKey),
+		secretAccessKey: viper.GetString(uploadSecretAccessKeyCfgKey),
+		sessionToken:    viper.GetString(uploadSessionTokenCfgKey),
+		sasToken:        strings.TrimPrefix(viper.GetString(uploadSASTokenCfgKey), "?"),
+		client:          &http.Client{Transport: newRetryTransport(http.DefaultTransport, viper.GetInt(retriesCfgKey), viper.GetDuration(retryDelayCfgKey))},
+		now:             time.Now,
+	}
+	switch store.provider {
+	case "":
+		return nil, nil
+	case s3Provider:
+		if store.endpoint == "" {
+			store.endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", store.region)
+		}
+	case gcsProvider:
+		if store.endpoint == "" {
+			store.endpoint = "https://storage.googleapis.com"
+		}
+	case azureProvider:
+		if store.endpoint == "" {
+			return nil, fmt.Errorf("uploading to %s requires the endpoint of the storage account", azureProvider)
+		}
+		if store.sasToken == "" {
+			return nil, fmt.Errorf("uploading to %s requires a shared access signature", azureProvider)
+		}
+	default:
+		return nil, fmt.Errorf("unknown upload provider '%s'; supported are %s, %s, and %s", store.provider, s3Provider, gcsProvider, azureProvider)
+	}
+	if store.bucket == "" {
+		return nil, fmt.Errorf("uploading to %s requires a bucket", store.provider)
+	}
+	if store.provider != azureProvider && (store.accessKeyID == "" || store.secretAccessKey == "") {
+		return nil, fmt.Errorf("uploading to %s requires an access key ID and a secret access key", store.provider)
+	}
+	if store.provider != azureProvider && store.region == "" {
+		return nil, fmt.Errorf("uploading to %s requires a region", store.provider)
+	}
+	return store, nil
+}
+
+// uploadResults uploads the files generated by the given command to the
+// configured object storage.
+func uploadResults(cmd *cobra.Command) error {
+	store, err := getObjectStore()
+	if err != nil || store == nil {
+		return err
+	}
+	files := results.generatedFiles()
+	if len(files) == 0 {
+		logger.Warnw("No files generated - nothing to upload")
+		return nil
+	}
+	for _, filename := range files {
+		u, err := store.upload(filename)
+		if err != nil {
+			return fmt.Errorf("uploading '%s' failed: %w", filename, err)
+		}
+		logger.Debugw("Uploaded file", "filename", filename, "url", u)
+		results.recordLink(u)
+	}
+	cmd.Printf("Uploaded %d files to %s bucket '%s'\n", len(files), store.provider, store.bucket)
+	return nil
+}
+
+// objectURL returns the URL of the object storing the file with the given
+// name. Objects are addressed in path style and named like files published
+// to a branch (see publishedPath) prefixed by the configured prefix. The path
+// is encoded like S3 expects it in signed requests (see escapeObjectPath).
+func (s *objectStore) objectURL(filename string) (*url.URL, error) {
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path += "/" + s.bucket + "/" + path.Join(s.prefix, publishedPath(filename))
+	u.RawPath = escapeObjectPath(u.Path)
+	return u, nil
+}
+
+// escapeObjectPath encodes each segment of the given path like S3 does for
+// the canonical URI of AWS Signature Version 4, i.e., all bytes except for
+// unreserved characters are percent-encoded.
+func escapeObjectPath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// upload uploads the file with the given name and returns the URL of the
+// object.
+func (s *objectStore) upload(filename string) (string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.objectURL(filename)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPut, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if filepath.Ext(filename) == svgzExtension {
+		contentType = "image/svg+xml"
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.provider == azureProvider {
+		req.URL.RawQuery = s.sasToken
+		req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	} else {
+		sum := sha256.Sum256(content)
+		s.sign(req, hex.EncodeToString(sum[:]))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The URL may carry the shared access signature
+		return "", withoutURL(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return u.String(), nil
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the given data using the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// sign signs the given request to an S3-compatible provider whose payload
+// has the given hex-encoded SHA256 hash with AWS Signature Version 4. All
+// headers of the request are signed.
+func (s *objectStore) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.TrimSpace(headers[name]))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapeObjectPath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-github/v50/github"
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/viper"
+	"net/http"
+	"strings"
+)
+
+// webhookPath is the path GitHub webhooks are delivered to in server mode.
+const webhookPath = "/webhook"
+
+// maxWebhookPayloadSize is the maximum size of webhook payloads accepted,
+// which is the maximum size of payloads delivered by GitHub.
+const maxWebhookPayloadSize = 25 << 20
+
+// webhookUpdate is an update of collected contributions derived from a
+// webhook event.
+type webhookUpdate struct {
+
+	// The repository the event occurred in in 'owner/name' notation
+	repository string
+
+	// Whether the repository is collected when collecting the repositories of
+	// its owner (see addOwnedRepositories)
+	owned bool
+
+	// The contributions made by the event
+	contributions []internal.Contribution
+}
+
+// affects returns true iff the contributions collected from the given
+// repositories and owners are affected by the update.
+func (u webhookUpdate) affects(repos []string) bool {
+	owner, _, _ := strings.Cut(u.repository, "/")
+	for _, repo := range repos {
+		if strings.EqualFold(repo, u.repository) || (u.owned && strings.EqualFold(repo, owner)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownerCollects returns true iff a repository with the given properties is
+// collected when collecting the repositories of its owner.
+func ownerCollects(private bool, fork bool, archived bool) bool {
+	return (viper.GetBool(includePrivateCfgKey) || !private) &&
+		(viper.GetBool(includeForksCfgKey) || !fork) &&
+		(!viper.GetBool(excludeArchivedCfgKey) || !archived)
+}
+
+// webhookUpdateOf returns the update of collected contributions derived from
+// the given webhook event. The second return value is false if the event
+// doesn't affect collected contributions.
+func webhookUpdateOf(event interface{}) (webhookUpdate, bool, error) {
+	switch e := event.(type) {
+	case *github.PushEvent:
+		repo := e.GetRepo()
+		if e.GetDeleted() || e.GetRef() != "refs/heads/"+repo.GetDefaultBranch() {
+			return webhookUpdate{}, false, nil
+		}
+		contributions, err := pushedContributions(e)
+		if err != nil {
+			return webhookUpdate{}, false, err
+		}
+		return webhookUpdate{
+			repository:    repo.GetFullName(),
+			owned:         ownerCollects(repo.GetPrivate(), repo.GetFork(), repo.GetArchived()),
+			contributions: contributions,
+		}, true, nil
+	case *github.IssuesEvent:
+		if e.GetAction() != "opened" {
+			return webhookUpdate{}, false, nil
+		}
+		return issueUpdate(e.GetRepo(), internal.IssueContribution, e.GetIssue().GetUser(), e.GetIssue().GetCreatedAt(), e.GetIssue().GetHTMLURL()), true, nil
+	case *github.PullRequestEvent:
+		if e.GetAction() != "opened" {
+			return webhookUpdate{}, false, nil
+		}
+		pr := e.GetPullRequest()
+		return issueUpdate(e.GetRepo(), internal.PullRequestContribution, pr.GetUser(), pr.GetCreatedAt(), pr.GetHTMLURL()), true, nil
+	case *github.PullRequestReviewEvent:
+		// Reviews aren't contributions themselves, but the reviewed pull
+		// request is recorded in case its opening has been missed
+		pr := e.GetPullRequest()
+		return issueUpdate(e.GetRepo(), internal.PullRequestContribution, pr.GetUser(), pr.GetCreatedAt(), pr.GetHTMLURL()), true, nil
+	}
+	return webhookUpdate{}, false, nil
+}
+
+// issueUpdate returns the update recording the issue or pull request with the
+// given properties opened in the given repository.
+func issueUpdate(repo *github.Repository, contributionType internal.ContributionType, user *github.User,
+	createdAt github.Timestamp, htmlURL string) webhookUpdate {
+	return webhookUpdate{
+		repository: repo.GetFullName(),
+		owned:      ownerCollects(repo.GetPrivate(), repo.GetFork(), repo.GetArchived()),
+		contributions: []internal.Contribution{{
+			Type:       contributionType,
+			Repository: repo.GetFullName(),
+			Login:      user.GetLogin(),
+			Date:       createdAt.Time,
+			URL:        htmlURL,
+		}},
+	}
+}
+
+// pushedContributions returns the contributions made by the commits pushed by
+// the given push event to the default branch, subject to the configured commit
+// filters. As push events don't carry signatures, pushed commits are
+// considered unsigned.
+func pushedContributions(e *github.PushEvent) ([]internal.Contribution, error) {
+	filters, err := compileCommitFilters()
+	if err != nil {
+		return nil, err
+	}
+	repo := e.GetRepo()
+	var contributions []internal.Contribution
+	for _, c := range e.Commits {
+		commit := &object.Commit{
+			Hash: plumbing.NewHash(c.GetID()),
+			Author: object.Signature{
+				Name:  c.GetAuthor().GetName(),
+				Email: c.GetAuthor().GetEmail(),
+				When:  c.GetAuthor().GetDate().Time,
+			},
+			Committer: object.Signature{
+				Name:  c.GetCommitter().GetName(),
+				Email: c.GetCommitter().GetEmail(),
+				When:  c.GetTimestamp().Time,
+			},
+			Message: c.GetMessage(),
+		}
+		filtered, err := commitFiltered(filters, commit)
+		if err != nil {
+			return nil, err
+		}
+		if filtered {
+			continue
+		}
+		contributions = append(contributions, internal.Contribution{
+			Type:       internal.CommitContribution,
+			Repository: repo.GetFullName(),
+			Name:       commit.Author.Name,
+			Email:      commit.Author.Email,
+			Date:       commit.Committer.When,
+			URL:        fmt.Sprintf("%s/commit/%s", repo.GetHTMLURL(), commit.Hash),
+			Hash:       commit.Hash.String(),
+			Subject:    strings.SplitN(commit.Message, "\n", 2)[0],
+			SignedOff:  internal.HasSignOff(commit.Message),
+		})
+	}
+	return contributions, nil
+}
+
+// serveWebhook handles the delivery of a GitHub webhook. Deliveries must be
+// signed with the configured secret; webhooks are disabled if no secret is
+// configured.
+func (s *graphServer) serveWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookSecret == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookPayloadSize)
+	payload, err := github.ValidatePayload(r, s.webhookSecret)
+	if err != nil {
+		logger.Warnw("Rejected webhook delivery", "delivery", github.DeliveryID(r), "Error", err)
+		http.Error(w, "invalid webhook delivery", http.StatusUnauthorized)
+		return
+	}
+	eventType := github.WebHookType(r)
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		// Deliveries of event types unknown to the client are ignored
+		logger.Debugw("Ignored webhook delivery", "delivery", github.DeliveryID(r), "event", eventType, "Error", err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	update, ok, err := webhookUpdateOf(event)
+	if err != nil {
+		logger.Warnw("Processing webhook delivery failed", "delivery", github.DeliveryID(r), "event", eventType, "Error", err)
+		http.Error(w, "processing webhook delivery failed", http.StatusInternalServerError)
+		return
+	}
+	if ok {
+		updated := s.update(update)
+		logger.Infow("Processed webhook delivery", "delivery", github.DeliveryID(r), "event", eventType,
+			"repository", update.repository, "contributions", len(update.contributions), "updated", updated)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// update applies the given update to the cached contributions and evicts the
+// graphs generated from them. Contributions already known by their URL are
+// skipped, so that redelivered events are counted once. Collections in
+// progress might miss the update and are hence not reused. Returns the number
+// of cached contribution sets updated.
+func (s *graphServer) update(u webhookUpdate) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.generation++
+	updated := 0
+	for key, entry := range s.contributions {
+		if !u.affects(entry.repos) {
+			continue
+		}
+		select {
+		case <-entry.ready:
+		default:
+			delete(s.contributions, key)
+			continue
+		}
+		known := make(map[string]bool)
+		for _, c := range entry.contributions {
+			known[c.URL] = true
+		}
+		// Copy, so that contributions handed out before aren't modified
+		contributions := entry.contributions[:len(entry.contributions):len(entry.contributions)]
+		for _, c := range u.contributions {
+			if !known[c.URL] {
+				contributions = append(contributions, c)
+			}
+		}
+		entry.contributions = contributions
+		updated++
+	}
+	for key, graph := range s.graphs {
+		if u.affects(graph.repos) {
+			delete(s.graphs, key)
+		}
+	}
+	return updated
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package cmd
+
+import (
+	"github.com/herdstat/herdstat/internal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Configuration keys for the working-hours command
+const (
+	// The number of contributors listed
+	workingHoursTopCfgKey = "working-hours.top"
+	// The format of the report
+	workingHoursFormatCfgKey = "working-hours.format"
+	// The name of the output file
+	workingHoursFilenameCfgKey = "working-hours.filename"
+)
+
+// workingHoursCmd represents the working-hours command
+var workingHoursCmd = &cobra.Command{
+	Use:   "working-hours",
+	Short: "Reports the share of commits made on weekends and outside working hours",
+	Long: `Reports the share of commits made on weekends and on weekdays outside 9:00 to
+18:00 in the local time of the respective author as inferred from the UTC offset
+recorded with each commit. A high share can be an early warning sign of
+maintainer burnout.`,
+	Args: cobra.NoArgs,
+	RunE: runWorkingHours,
+}
+
+func runWorkingHours(cmd *cobra.Command, args []string) error {
+	commits, lastDay, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+	report := internal.NewWorkingHoursReport(commits, lastDay, viper.GetInt(workingHoursTopCfgKey))
+	return writeReport(cmd, report, viper.GetString(workingHoursFormatCfgKey), viper.GetString(workingHoursFilenameCfgKey))
+}
+
+// Initialize the 'working-hours' command.
+func init() {
+	rootCmd.AddCommand(workingHoursCmd)
+
+	// Flag to control the number of contributors listed
+	const topFlag = "top"
+	workingHoursCmd.Flags().Int(topFlag, 10,
+		"The number of contributors with the highest share of commits outside working hours listed")
+	if err := viper.BindPFlag(workingHoursTopCfgKey, workingHoursCmd.Flags().Lookup(topFlag)); err != nil {
+		logger.Fatalw("Can't bind to flag", "Flag", topFlag, "Error", err)
+	}
+
+	addReportFlags(workingHoursCmd, workingHoursFormatCfgKey, workingHoursFilenameCfgKey)
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+// Package graph renders GitHub-style contribution graphs from activity data
+// provided by the caller, e.g., for web services generating graphs on
+// request. The package is the stable API of the herdstat renderer; it doesn't
+// depend on the way herdstat collects contributions.
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/herdstat/herdstat/internal"
+	"image/color"
+	"io"
+	"time"
+)
+
+// Record is the number of contributions made on a single day.
+type Record struct {
+	Date  time.Time
+	Count int
+}
+
+// Spectrum is the range of colors the cells of a graph are colored with. Days
+// without contributions are colored with Min and the most active days with
+// Max.
+type Spectrum struct {
+	Min color.RGBA
+	Max color.RGBA
+}
+
+// Names of the supported color interpolation modes.
+const (
+	RGBInterpolation = internal.RGBInterpolationName
+	HSLInterpolation = internal.HSLInterpolationName
+	LabInterpolation = internal.LabInterpolationName
+)
+
+// Theme defines the colors of a graph. Viewers preferring a dark color scheme
+// see the Dark variant.
+type Theme struct {
+	Light Spectrum
+	Dark  Spectrum
+
+	// The background colors. The background is transparent if nil.
+	LightBackground *color.RGBA
+	DarkBackground  *color.RGBA
+
+	// The name of the mode interpolating the colors between the ends of the
+	// spectra. Defaults to RGBInterpolation.
+	Interpolation string
+}
+
+// DefaultTheme is the theme used by the herdstat CLI. The spectra go from
+// shades of grey to green.
+var DefaultTheme = Theme{
+	Light: Spectrum{
+		Min: color.RGBA{R: 0xeb, G: 0xed, B: 0xf0, A: 0xff},
+		Max: color.RGBA{R: 0x39, G: 0xd3, B: 0x52, A: 0xff},
+	},
+	Dark: Spectrum{
+		Min: color.RGBA{R: 0x2d, G: 0x33, B: 0x3b, A: 0xff},
+		Max: color.RGBA{R: 0x39, G: 0xd3, B: 0x52, A: 0xff},
+	},
+}
+
+// Labels are the texts of a graph. Empty labels default to English.
+type Labels struct {
+
+	// The format of a number of contributions, e.g., "%d contributions".
+	Contributions string
+
+	// The text following the overall number of contributions, e.g., "in the
+	// last year".
+	LastYear string
+
+	// The format of the date of a day in its tooltip, e.g., "on %s".
+	OnDate string
+
+	// The labels of the lowest and the highest level of the legend, e.g.,
+	// "Less" and "More".
+	Less string
+	More string
+}
+
+// Locale defines the names of months and weekdays and the format of dates.
+// Empty names default to English.
+type Locale struct {
+
+	// The abbreviated names of the months starting with January.
+	Months [12]string
+
+	// The abbreviated names of the days of the week starting with Sunday.
+	Weekdays [7]string
+
+	// Formats dates, e.g., in tooltips. Dates are formatted like "Jan 2,
+	// 2006" using the names of the months if nil.
+	FormatDate func(date time.Time) string
+}
+
+// Options configure the rendering of a graph. The zero value renders a graph
+// at its natural size using the DefaultTheme and English labels.
+type Options struct {
+
+	// The width of the graph in pixels. The graph is scaled keeping its
+	// aspect ratio. The graph is 700 pixels wide if zero.
+	Width int
+
+	// The colors of the graph. Spectra left zero default to those of the
+	// DefaultTheme.
+	Theme Theme
+
+	// The number of color levels between 5 and 255. Defaults to 5.
+	Levels int
+
+	// The texts of the graph.
+	Labels Labels
+
+	// The names and formats used for dates.
+	Locale Locale
+
+	// The day highlighted by an outline, e.g., today. No day is highlighted
+	// if zero.
+	Highlighted time.Time
+
+	// Whether to omit the tooltips, e.g., for embeddings in emails where
+	// hovering isn't available.
+	NoTooltips bool
+}
+
+// ContributionGraph is a heatmap of the contributions made within 52 weeks.
+type ContributionGraph struct {
+	graph *internal.ContributionGraph
+}
+
+// New creates a graph of the given records for the 52 weeks ending with the
+// given day. Records outside this period are ignored and records of the same
+// day are summed up.
+func New(records []Record, lastDay time.Time, options Options) (*ContributionGraph, error) {
+	theme := options.Theme
+	if theme.Light == (Spectrum{}) {
+		theme.Light = DefaultTheme.Light
+	}
+	if theme.Dark == (Spectrum{}) {
+		theme.Dark = DefaultTheme.Dark
+	}
+	interpolationName := theme.Interpolation
+	if interpolationName == "" {
+		interpolationName = RGBInterpolation
+	}
+	interpolation, err := internal.ParseInterpolation(interpolationName)
+	if err != nil {
+		return nil, err
+	}
+	levels := options.Levels
+	if levels == 0 {
+		levels = 5
+	}
+	if levels < 5 || levels > 255 {
+		return nil, fmt.Errorf("invalid number of color levels %d; allowed range is [5..255]", levels)
+	}
+	if options.Width < 0 {
+		return nil, fmt.Errorf("invalid width %d", options.Width)
+	}
+	lastDay = time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 23, 59, 59, 999999999, lastDay.Location())
+	daily := internal.NewContributionRecords(lastDay)
+	for _, r := range records {
+		date := r.Date.In(lastDay.Location())
+		if !internal.InPeriod(date, lastDay) {
+			continue
+		}
+		daily[52*7-1-internal.CalendarDaysBetween(date, lastDay)].Count += r.Count
+	}
+	coloring := internal.GetColoring(internal.ColorScheme{
+		Light: internal.ColorSpectrum{Min: theme.Light.Min, Max: theme.Light.Max},
+		Dark:  internal.ColorSpectrum{Min: theme.Dark.Min, Max: theme.Dark.Max},
+	}, interpolation)
+	g := internal.NewContributionMap(daily, lastDay, coloring, uint8(levels))
+	g.Background = internal.Background{Light: theme.LightBackground, Dark: theme.DarkBackground}
+	g.Highlighted = options.Highlighted
+	g.NoTooltips = options.NoTooltips
+	g.Width = options.Width
+	g.Text = internal.GraphText{
+		Contributions: options.Labels.Contributions,
+		LastYear:      options.Labels.LastYear,
+		OnDate:        options.Labels.OnDate,
+		Less:          options.Labels.Less,
+		More:          options.Labels.More,
+		Months:        options.Locale.Months,
+		Weekdays:      options.Locale.Weekdays,
+		FormatDate:    options.Locale.FormatDate,
+	}
+	return &ContributionGraph{graph: g}, nil
+}
+
+// Render writes the graph as SVG document to the given writer.
+func (g *ContributionGraph) Render(w io.Writer) error {
+	e := xml.NewEncoder(w)
+	if err := g.graph.Render(e); err != nil {
+		return fmt.Errorf("rendering contribution graph failed: %w", err)
+	}
+	return e.Flush()
+}
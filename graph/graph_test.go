@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package graph_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/herdstat/herdstat/graph"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+	"time"
+)
+
+var _ = Describe("Contribution graph", func() {
+	lastDay := time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)
+	records := []graph.Record{
+		{Date: lastDay, Count: 2},
+		{Date: lastDay.Add(10 * time.Hour), Count: 1},
+		{Date: lastDay.AddDate(-2, 0, 0), Count: 100},
+	}
+
+	render := func(options graph.Options) string {
+		g, err := graph.New(records, lastDay, options)
+		Expect(err).NotTo(HaveOccurred())
+		var b bytes.Buffer
+		Expect(g.Render(&b)).To(Succeed())
+		Expect(xml.Unmarshal(b.Bytes(), new(struct{}))).To(Succeed())
+		return b.String()
+	}
+
+	It("renders the records of the period in English by default", func() {
+		svg := render(graph.Options{})
+		Expect(svg).To(HavePrefix("<svg"))
+		Expect(svg).To(ContainSubstring(`width="700"`))
+		Expect(svg).To(ContainSubstring("3 contributions "))
+		Expect(svg).To(ContainSubstring("on Mar 15, 2023"))
+		Expect(svg).To(ContainSubstring(">Less<"))
+	})
+
+	It("scales the graph to the given width", func() {
+		svg := render(graph.Options{Width: 350})
+		Expect(svg).To(ContainSubstring(`viewBox="0 0 700 150"`))
+		Expect(svg).To(ContainSubstring(`width="350" height="75"`))
+	})
+
+	It("defaults the spectra of the theme individually", func() {
+		background := color.RGBA{R: 0x01, G: 0x02, B: 0x03, A: 0xff}
+		svg := render(graph.Options{Theme: graph.Theme{
+			Dark:            graph.Spectrum{Min: color.RGBA{A: 0xff}, Max: color.RGBA{R: 0xff, A: 0xff}},
+			LightBackground: &background,
+		}})
+		Expect(svg).To(ContainSubstring("--herdstat-contribution-graph-color-bg: rgb(1, 2, 3);"))
+		Expect(svg).To(ContainSubstring("--herdstat-contribution-graph-color-cell-L0-bg: rgb(235, 237, 240);"))
+		Expect(svg).To(ContainSubstring("--herdstat-contribution-graph-color-cell-L4-bg: rgb(254, 0, 0);"))
+	})
+
+	It("renders the given labels and locale", func() {
+		svg := render(graph.Options{
+			Labels: graph.Labels{
+				Contributions: "%d Beiträge",
+				LastYear:      "im letzten Jahr",
+				OnDate:        "am %s",
+				Less:          "Weniger",
+				More:          "Mehr",
+			},
+			Locale: graph.Locale{
+				Months:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+				Weekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+				FormatDate: func(date time.Time) string {
+					return fmt.Sprintf("%d.%d.%d", date.Day(), date.Month(), date.Year())
+				},
+			},
+		})
+		Expect(svg).To(ContainSubstring("3 Beiträge "))
+		Expect(svg).To(ContainSubstring("im letzten Jahr"))
+		Expect(svg).To(ContainSubstring("am 15.3.2023"))
+		Expect(svg).To(ContainSubstring(">Mär<"))
+		Expect(svg).To(ContainSubstring(">Mo<"))
+		Expect(svg).To(ContainSubstring(">Mehr<"))
+		Expect(svg).NotTo(ContainSubstring("contributions"))
+	})
+
+	It("omits the tooltips if requested", func() {
+		Expect(render(graph.Options{})).To(ContainSubstring("herdstat-contribution-graph-cell-tooltip"))
+		svg := render(graph.Options{NoTooltips: true})
+		Expect(svg).NotTo(ContainSubstring("herdstat-contribution-graph-cell-tooltip"))
+		Expect(svg).NotTo(ContainSubstring("<title>"))
+	})
+
+	It("rejects invalid options", func() {
+		_, err := graph.New(records, lastDay, graph.Options{Levels: 3})
+		Expect(err).To(MatchError(ContainSubstring("invalid number of color levels")))
+		_, err = graph.New(records, lastDay, graph.Options{Theme: graph.Theme{Interpolation: "cmyk"}})
+		Expect(err).To(MatchError(ContainSubstring("unknown color interpolation")))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"strings"
+)
+
+// Affiliation maps contributors to the organization (e.g., their employer)
+// they are affiliated with.
+type Affiliation struct {
+
+	// The name of the organization.
+	Organization string
+
+	// The email domains of the organization's members.
+	Domains []string
+
+	// The GitHub logins or email addresses of the organization's members.
+	Contributors []string
+}
+
+// publicEmailDomains are the domains of public email providers that don't
+// indicate any affiliation.
+var publicEmailDomains = map[string]bool{
+	"gmail.com":                true,
+	"googlemail.com":           true,
+	"outlook.com":              true,
+	"hotmail.com":              true,
+	"live.com":                 true,
+	"yahoo.com":                true,
+	"icloud.com":               true,
+	"me.com":                   true,
+	"protonmail.com":           true,
+	"proton.me":                true,
+	"gmx.de":                   true,
+	"web.de":                   true,
+	"users.noreply.github.com": true,
+}
+
+// Affiliations resolves the organizations contributors are affiliated with.
+type Affiliations struct {
+	byContributor map[string]string
+	byDomain      map[string]string
+}
+
+// NewAffiliations creates Affiliations from the given explicit mappings.
+// Contributors not covered by the mappings are affiliated by means of the
+// domain of their email address unless it belongs to a public email provider.
+func NewAffiliations(affiliations []Affiliation) *Affiliations {
+	a := &Affiliations{
+		byContributor: make(map[string]string),
+		byDomain:      make(map[string]string),
+	}
+	for _, affiliation := range affiliations {
+		for _, domain := range affiliation.Domains {
+			a.byDomain[strings.ToLower(domain)] = affiliation.Organization
+		}
+		for _, contributor := range affiliation.Contributors {
+			a.byContributor[strings.ToLower(contributor)] = affiliation.Organization
+		}
+	}
+	return a
+}
+
+// Organization returns the organization the author of the given contribution
+// is affiliated with or an empty string if the affiliation is unknown.
+func (a *Affiliations) Organization(c Contribution) string {
+	for _, id := range []string{c.Login, c.Email} {
+		if organization, ok := a.byContributor[strings.ToLower(id)]; id != "" && ok {
+			return organization
+		}
+	}
+	at := strings.LastIndex(c.Email, "@")
+	if at < 0 {
+		return ""
+	}
+	domain := strings.ToLower(c.Email[at+1:])
+	if organization, ok := a.byDomain[domain]; ok {
+		return organization
+	}
+	if publicEmailDomains[domain] || strings.HasSuffix(domain, ".noreply.github.com") {
+		return ""
+	}
+	return domain
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// anomalyWindow is the number of days preceding a day the rolling mean and
+// standard deviation are computed from.
+const anomalyWindow = 28
+
+// anomalyMinStdDev is the lower bound of the standard deviation used for
+// detecting anomalies. It prevents single contributions after a quiet period
+// from being reported as anomalies.
+const anomalyMinStdDev = 1.0
+
+// anomalyMaxLinks is the maximum number of links to contributions recorded
+// per anomaly.
+const anomalyMaxLinks = 5
+
+// DefaultAnomalyThreshold is the default number of standard deviations the
+// number of contributions of a day must exceed the rolling mean by to be
+// considered an anomaly.
+const DefaultAnomalyThreshold = 3.0
+
+// Anomaly is a day with a statistically unusual number of contributions.
+type Anomaly struct {
+
+	// The day of the anomaly.
+	Date string `json:"date" yaml:"date"`
+
+	// The number of contributions made on the day.
+	Count int `json:"count" yaml:"count"`
+
+	// The mean of the daily contributions in the preceding days.
+	Mean float64 `json:"mean" yaml:"mean"`
+
+	// The number of standard deviations the number of contributions exceeds
+	// the mean by.
+	Score float64 `json:"score" yaml:"score"`
+
+	// The repository with the most contributions on the day.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// Links to the first contributions made on the day.
+	Links []string `json:"links,omitempty" yaml:"links,omitempty"`
+
+	// The day of the anomaly in the location of the analyzed period.
+	day time.Time
+}
+
+// DetectAnomalies returns the days of the given daily records whose number of
+// contributions exceeds the mean of the preceding four weeks by more than the
+// given number of standard deviations. The first four weeks are only used as
+// a baseline.
+func DetectAnomalies(records []ContributionRecord, threshold float64) []Anomaly {
+	var anomalies []Anomaly
+	for i := anomalyWindow; i < len(records); i++ {
+		var sum, sumSquares float64
+		for _, r := range records[i-anomalyWindow : i] {
+			sum += float64(r.Count)
+			sumSquares += float64(r.Count * r.Count)
+		}
+		mean := sum / anomalyWindow
+		stdDev := math.Max(math.Sqrt(math.Max(sumSquares/anomalyWindow-mean*mean, 0)), anomalyMinStdDev)
+		score := (float64(records[i].Count) - mean) / stdDev
+		if score <= threshold {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			day:   records[i].Date,
+			Date:  records[i].Date.Format(dateFormat),
+			Count: records[i].Count,
+			Mean:  math.Round(mean*10) / 10,
+			Score: math.Round(score*10) / 10,
+		})
+	}
+	return anomalies
+}
+
+// NewAnomalies detects the anomalies in the given contributions made within
+// the 52 weeks ending with the given day (see DetectAnomalies) and links each
+// of them to the contributions causing it.
+func NewAnomalies(contributions []Contribution, lastDay time.Time, threshold float64) []Anomaly {
+	anomalies := DetectAnomalies(DailyRecords(contributions, lastDay), threshold)
+	index := make(map[string]int)
+	for i, anomaly := range anomalies {
+		index[anomaly.Date] = i
+	}
+	repositories := make([]map[string]int, len(anomalies))
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		i, ok := index[c.Date.Format(dateFormat)]
+		if !ok {
+			continue
+		}
+		if repositories[i] == nil {
+			repositories[i] = make(map[string]int)
+		}
+		repositories[i][c.Repository]++
+		if c.URL != "" && len(anomalies[i].Links) < anomalyMaxLinks {
+			anomalies[i].Links = append(anomalies[i].Links, c.URL)
+		}
+	}
+	for i := range anomalies {
+		for repository, count := range repositories[i] {
+			top := anomalies[i].Repository
+			if count > repositories[i][top] || (count == repositories[i][top] && repository < top) {
+				anomalies[i].Repository = repository
+			}
+		}
+	}
+	return anomalies
+}
+
+// AnomalyAnnotations converts the given anomalies into annotations of a
+// contribution graph.
+func AnomalyAnnotations(anomalies []Anomaly) []Annotation {
+	annotations := make([]Annotation, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		annotations = append(annotations, Annotation{
+			Date:  anomaly.day,
+			Label: anomaly.String(),
+		})
+	}
+	return annotations
+}
+
+// String describes the anomaly, e.g., for labeling it on a contribution graph.
+func (a Anomaly) String() string {
+	return fmt.Sprintf("Unusual activity with %d contributions (%.1fσ above the mean of %.1f)", a.Count, a.Score, a.Mean)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Anomalies", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	spike := dateparse.MustParse("2023-03-05 12:00")
+
+	// Two contributions per day with a spike of 20 contributions on a single
+	// day split across two repositories.
+	var contributions []Contribution
+	for date := lastDay.AddDate(0, 0, -52*7+1); !date.After(lastDay); date = date.AddDate(0, 0, 1) {
+		for i := 0; i < 2; i++ {
+			contributions = append(contributions, Contribution{
+				Type:       CommitContribution,
+				Repository: "herdstat/herdstat",
+				Date:       date,
+			})
+		}
+	}
+	for i := 0; i < 18; i++ {
+		repository := "herdstat/herdstat"
+		if i%3 == 0 {
+			repository = "herdstat/other"
+		}
+		contributions = append(contributions, Contribution{
+			Type:       CommitContribution,
+			Repository: repository,
+			Date:       spike.Add(time.Duration(i) * time.Minute),
+			URL:        "https://github.com/" + repository + "/commit/" + string(rune('a'+i)),
+		})
+	}
+
+	It("detects unusual days", func() {
+		anomalies := NewAnomalies(contributions, lastDay, DefaultAnomalyThreshold)
+		Expect(anomalies).To(HaveLen(1))
+		Expect(anomalies[0].Date).To(Equal("2023-03-05"))
+		Expect(anomalies[0].Count).To(Equal(20))
+		Expect(anomalies[0].Mean).To(Equal(2.0))
+		Expect(anomalies[0].Score).To(Equal(18.0))
+		Expect(anomalies[0].Repository).To(Equal("herdstat/herdstat"))
+		Expect(anomalies[0].Links).To(HaveLen(anomalyMaxLinks))
+	})
+
+	It("respects the threshold", func() {
+		Expect(NewAnomalies(contributions, lastDay, 20)).To(BeEmpty())
+	})
+
+	It("ignores single contributions after quiet periods", func() {
+		records := NewContributionRecords(lastDay)
+		records[100].Count = 1
+		records[200].Count = 4
+		anomalies := DetectAnomalies(records, DefaultAnomalyThreshold)
+		Expect(anomalies).To(HaveLen(1))
+		Expect(anomalies[0].Count).To(Equal(4))
+	})
+
+	It("converts anomalies into annotations", func() {
+		annotations := AnomalyAnnotations(NewAnomalies(contributions, lastDay, DefaultAnomalyThreshold))
+		Expect(annotations).To(HaveLen(1))
+		Expect(annotations[0].Date.Format(dateFormat)).To(Equal("2023-03-05"))
+		Expect(annotations[0].Label).To(Equal("Unusual activity with 20 contributions (18.0σ above the mean of 2.0)"))
+	})
+
+	It("is listed in the summary", func() {
+		summary := NewSummary(contributions, lastDay)
+		summary.Anomalies = NewAnomalies(contributions, lastDay, DefaultAnomalyThreshold)
+		md, err := summary.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("- 2023-03-05: 20 contributions (18.0σ above the mean of 2.0), mostly to `herdstat/herdstat` — [1](https://github.com/herdstat/other/commit/a), [2]("))
+	})
+})
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/xml"
+	"image"
+	"image/color"
+	"strconv"
+)
+
+// Dimensions of bar charts.
+const (
+	barChartWidth        = 500
+	barChartLabelWidth   = 120
+	barChartValueWidth   = 60
+	barChartRowHeight    = 20
+	barChartBarHeight    = 14
+	barChartTopMargin    = 30
+	barChartBottomMargin = 10
+)
+
+// Bar is a single labeled bar of a bar chart.
+type Bar struct {
+
+	// The label rendered next to the bar.
+	Label string
+
+	// The value determining the length of the bar.
+	Value int
+
+	// The text rendered behind the bar. The value is used if empty.
+	Text string
+}
+
+// BarChart visualizes labeled values as horizontal bars.
+type BarChart struct {
+
+	// The title of the chart.
+	Title string
+
+	// The bars in the order to be rendered from top to bottom.
+	Bars []Bar
+
+	// The color of the bars.
+	Color color.RGBA
+}
+
+// size computes the width and height of the rendered chart.
+func (c *BarChart) size() (int, int) {
+	return barChartWidth, barChartTopMargin + len(c.Bars)*barChartRowHeight + barChartBottomMargin
+}
+
+// Render writes the bar chart to the given xml.Encoder.
+func (c *BarChart) Render(e *xml.Encoder) error {
+	width, height := c.size()
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			cssClassAttr("herdstat-chart"),
+			attr("width", strconv.Itoa(width)),
+			attr("height", strconv.Itoa(height)),
+		},
+	}, func(e *xml.Encoder) error {
+		if err := renderChartStyle(e); err != nil {
+			return err
+		}
+		fg := cssClassAttrs("herdstat-chart-fg")
+		if err := simpleText(e, image.Point{X: 10, Y: 18}, start,
+			append(fg, attr("font-weight", "800")), c.Title); err != nil {
+			return err
+		}
+		maxVal := 0
+		for _, bar := range c.Bars {
+			if bar.Value > maxVal {
+				maxVal = bar.Value
+			}
+		}
+		maxLength := barChartWidth - barChartLabelWidth - barChartValueWidth
+		for i, bar := range c.Bars {
+			y := barChartTopMargin + i*barChartRowHeight
+			if err := simpleText(e, image.Point{X: barChartLabelWidth - 8, Y: y + 11}, end, fg, bar.Label); err != nil {
+				return err
+			}
+			length := 0
+			if maxVal > 0 {
+				length = bar.Value * maxLength / maxVal
+			}
+			err := emptyElement(e, xml.StartElement{
+				Name: xml.Name{Local: "rect"},
+				Attr: []xml.Attr{
+					attr("x", strconv.Itoa(barChartLabelWidth)),
+					attr("y", strconv.Itoa(y)),
+					attr("width", strconv.Itoa(length)),
+					attr("height", strconv.Itoa(barChartBarHeight)),
+					attr("rx", "2"),
+					attr("fill", rgb(c.Color)),
+				},
+			})
+			if err != nil {
+				return err
+			}
+			text := bar.Text
+			if text == "" {
+				text = strconv.Itoa(bar.Value)
+			}
+			if err := simpleText(e, image.Point{X: barChartLabelWidth + length + 6, Y: y + 11}, start, fg, text); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"image/color"
+	"time"
+)
+
+// IssueFlow describes the evolution of the issues of one or more repositories
+// per period.
+type IssueFlow struct {
+
+	// The axis labels of the periods in chronological order.
+	Labels []string
+
+	// The number of issues open at the end of each period.
+	Open []int
+
+	// The number of issues opened within the analyzed period until the end of
+	// each period.
+	Opened []int
+
+	// The number of issues closed within the analyzed period until the end of
+	// each period.
+	Closed []int
+}
+
+// NewIssueFlow reconstructs the IssueFlow of the given issues within the 52
+// weeks ending with the given day from their creation and closing times. To
+// be accurate, the issues have to include all issues open at the beginning of
+// the analyzed period.
+func NewIssueFlow(issues []Issue, lastDay time.Time, granularity Granularity) *IssueFlow {
+	flow := &IssueFlow{}
+	var ends []time.Time
+	var current time.Time
+	for _, r := range NewContributionRecords(lastDay) {
+		if start := granularity.periodStart(r.Date); len(ends) == 0 || !start.Equal(current) {
+			current = start
+			flow.Labels = append(flow.Labels, granularity.label(start))
+			ends = append(ends, r.Date)
+		}
+		ends[len(ends)-1] = r.Date
+	}
+	flow.Open = make([]int, len(ends))
+	flow.Opened = make([]int, len(ends))
+	flow.Closed = make([]int, len(ends))
+	for _, issue := range issues {
+		closed := !issue.Closed.IsZero()
+		for i, end := range ends {
+			if issue.Created.After(end) {
+				continue
+			}
+			if !closed || issue.Closed.After(end) {
+				flow.Open[i]++
+			}
+			if InPeriod(issue.Created, lastDay) {
+				flow.Opened[i]++
+			}
+			if closed && !issue.Closed.After(end) && InPeriod(issue.Closed, lastDay) {
+				flow.Closed[i]++
+			}
+		}
+	}
+	return flow
+}
+
+// NewBurndownChart creates a LineChart visualizing the number of open issues
+// at the end of each period.
+func NewBurndownChart(title string, flow *IssueFlow, color color.RGBA, area bool) *LineChart {
+	return &LineChart{
+		Title:  title,
+		Labels: flow.Labels,
+		Series: []Series{{Name: "Open", Values: flow.Open, Color: color}},
+		Area:   area,
+	}
+}
+
+// NewBurnupChart creates a LineChart visualizing the cumulative number of
+// issues opened and closed within the analyzed period. The gap between both
+// lines is the number of issues opened but not yet closed.
+func NewBurnupChart(title string, flow *IssueFlow, color color.RGBA) *LineChart {
+	return &LineChart{
+		Title:  title,
+		Labels: flow.Labels,
+		Series: []Series{
+			{Name: "Opened", Values: flow.Opened, Color: secondarySeriesColor},
+			{Name: "Closed", Values: flow.Closed, Color: color},
+		},
+	}
+}
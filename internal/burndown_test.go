@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Issue flows", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	issues := []Issue{
+		// Opened before and still open
+		{Created: dateparse.MustParse("2021-01-01")},
+		// Opened before and closed within the analyzed period
+		{Created: dateparse.MustParse("2021-01-01"), Closed: dateparse.MustParse("2023-03-14 12:00")},
+		// Opened and closed before the analyzed period
+		{Created: dateparse.MustParse("2021-01-01"), Closed: dateparse.MustParse("2021-02-01")},
+		// Opened within the analyzed period and still open
+		{Created: dateparse.MustParse("2023-03-13 08:00")},
+	}
+	flow := NewIssueFlow(issues, lastDay, MonthlyGranularity)
+	last := len(flow.Labels) - 1
+
+	It("covers all periods of the analyzed period", func() {
+		Expect(flow.Labels).To(HaveLen(13))
+		Expect(flow.Open).To(HaveLen(13))
+		Expect(flow.Labels[last]).To(Equal("Mar '23"))
+	})
+
+	It("counts the issues open at the end of each period", func() {
+		Expect(flow.Open[0]).To(Equal(2))
+		Expect(flow.Open[last]).To(Equal(2))
+	})
+
+	It("accumulates the issues opened and closed within the analyzed period", func() {
+		Expect(flow.Opened[last-1]).To(Equal(0))
+		Expect(flow.Opened[last]).To(Equal(1))
+		Expect(flow.Closed[last]).To(Equal(1))
+	})
+
+	It("renders burnup charts with a line for opened and closed issues", func() {
+		chart := NewBurnupChart("Issue burnup", flow, color.RGBA{})
+		Expect(chart.Series).To(HaveLen(2))
+		Expect(chart.Series[0].Values).To(Equal(flow.Opened))
+		Expect(chart.Series[1].Values).To(Equal(flow.Closed))
+	})
+})
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"time"
+)
+
+// BusFactor describes how concentrated the contributions to a repository (or
+// a set of repositories) are among its contributors.
+type BusFactor struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall bus
+	// factor.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The overall number of contributions.
+	Contributions int `json:"contributions" yaml:"contributions"`
+
+	// The number of distinct contributors.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The minimal number of contributors accounting for 50% of the
+	// contributions.
+	Factor50 int `json:"factor50" yaml:"factor50"`
+
+	// The minimal number of contributors accounting for 80% of the
+	// contributions.
+	Factor80 int `json:"factor80" yaml:"factor80"`
+
+	// Whether the 50% bus factor is below the configured threshold.
+	BelowThreshold bool `json:"belowThreshold" yaml:"belowThreshold"`
+}
+
+// BusFactorReport contains the bus factors per repository and overall.
+type BusFactorReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The 50% bus factor below which repositories are flagged.
+	Threshold int `json:"threshold" yaml:"threshold"`
+
+	// The bus factor across all repositories.
+	Overall BusFactor `json:"overall" yaml:"overall"`
+
+	// The bus factors of the individual repositories sorted by ascending 50%
+	// bus factor.
+	Repositories []BusFactor `json:"repositories" yaml:"repositories"`
+}
+
+// coveringCount computes the minimal number of keys (e.g., contributors) whose
+// counts account for the given share of the overall count.
+func coveringCount(counts map[string]int, share float64) int {
+	var sorted []int
+	total := 0
+	for _, c := range counts {
+		sorted = append(sorted, c)
+		total += c
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	covered := 0
+	for i, c := range sorted {
+		covered += c
+		if float64(covered) >= share*float64(total) {
+			return i + 1
+		}
+	}
+	return len(sorted)
+}
+
+// newBusFactor computes the bus factor of the given contributions.
+func newBusFactor(repository string, contributions []Contribution, lastDay time.Time, threshold int) BusFactor {
+	counts := ContributionsPerContributor(contributions, lastDay)
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	factor50 := coveringCount(counts, 0.5)
+	return BusFactor{
+		Repository:     repository,
+		Contributions:  total,
+		Contributors:   len(counts),
+		Factor50:       factor50,
+		Factor80:       coveringCount(counts, 0.8),
+		BelowThreshold: factor50 < threshold,
+	}
+}
+
+// NewBusFactorReport computes the bus factors of the given contributions
+// made within the 52 weeks ending with the given day. Repositories with a 50%
+// bus factor below the given threshold are flagged.
+func NewBusFactorReport(contributions []Contribution, lastDay time.Time, threshold int) *BusFactorReport {
+	report := &BusFactorReport{
+		From:      lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:     lastDay.Format(dateFormat),
+		Threshold: threshold,
+		Overall:   newBusFactor("", contributions, lastDay, threshold),
+	}
+	for repository, c := range GroupByRepository(contributions) {
+		report.Repositories = append(report.Repositories, newBusFactor(repository, c, lastDay, threshold))
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		a, b := report.Repositories[i], report.Repositories[j]
+		if a.Factor50 != b.Factor50 {
+			return a.Factor50 < b.Factor50
+		}
+		return a.Repository < b.Repository
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering bus factor reports as markdown.
+	//go:embed bus-factor.gomd
+	busFactorTemplate string
+)
+
+// Markdown renders the bus factor report as markdown.
+func (r *BusFactorReport) Markdown() (string, error) {
+	return renderMarkdown("bus-factor", busFactorTemplate, r)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Computing bus factors", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contribution := func(repository string, login string, n int) []Contribution {
+		var contributions []Contribution
+		for i := 0; i < n; i++ {
+			contributions = append(contributions, Contribution{
+				Type:       IssueContribution,
+				Repository: repository,
+				Login:      login,
+				Date:       lastDay.AddDate(0, 0, -i),
+			})
+		}
+		return contributions
+	}
+	var contributions []Contribution
+	contributions = append(contributions, contribution("herdstat/a", "jdoe", 8)...)
+	contributions = append(contributions, contribution("herdstat/a", "jroe", 2)...)
+	contributions = append(contributions, contribution("herdstat/b", "jdoe", 3)...)
+	contributions = append(contributions, contribution("herdstat/b", "jroe", 3)...)
+	contributions = append(contributions, contribution("herdstat/b", "mmoe", 3)...)
+	report := NewBusFactorReport(contributions, lastDay, 2)
+
+	It("computes the bus factors per repository", func() {
+		Expect(report.Repositories).To(Equal([]BusFactor{
+			{Repository: "herdstat/a", Contributions: 10, Contributors: 2, Factor50: 1, Factor80: 1, BelowThreshold: true},
+			{Repository: "herdstat/b", Contributions: 9, Contributors: 3, Factor50: 2, Factor80: 3},
+		}))
+	})
+	It("computes the overall bus factor", func() {
+		Expect(report.Overall).To(Equal(BusFactor{Contributions: 19, Contributors: 3, Factor50: 1, Factor80: 2, BelowThreshold: true}))
+	})
+	It("renders as markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| herdstat/a | 10 | 2 | 1 | 1 | :warning: |"))
+	})
+})
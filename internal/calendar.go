@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"strconv"
+	"time"
+)
+
+// Layout determines how the daily cells of a contribution graph are arranged.
+type Layout uint8
+
+const (
+
+	// HeatmapLayout arranges the days in week columns (GitHub-style).
+	HeatmapLayout Layout = iota
+
+	// CalendarLayout arranges the days in twelve mini month calendars (like a
+	// wall calendar).
+	CalendarLayout
+)
+
+// Names of the supported layouts.
+const (
+	HeatmapLayoutName  = "heatmap"
+	CalendarLayoutName = "calendar"
+)
+
+// ParseLayout returns the Layout registered under the given name.
+func ParseLayout(name string) (Layout, error) {
+	switch name {
+	case HeatmapLayoutName:
+		return HeatmapLayout, nil
+	case CalendarLayoutName:
+		return CalendarLayout, nil
+	}
+	return 0, fmt.Errorf("unknown layout '%s'; supported are %s and %s",
+		name, HeatmapLayoutName, CalendarLayoutName)
+}
+
+// Geometry of the calendar layout. Months are arranged in a grid of
+// calendarColumns columns. Each month consists of a title and up to six week
+// rows.
+const (
+	calendarColumns     = 4
+	calendarMargin      = 10
+	calendarMonthWidth  = 7 * 12
+	calendarMonthHeight = 16 + 6*12
+	calendarGapX        = 20
+	calendarGapY        = 10
+	calendarWidth       = 2*calendarMargin + calendarColumns*calendarMonthWidth + (calendarColumns-1)*calendarGapX
+	calendarFooterY     = calendarMargin + 3*(calendarMonthHeight+calendarGapY)
+	calendarHeight      = calendarFooterY + 25
+)
+
+// record returns the ContributionRecord for the given date and whether the
+// date is covered by the graph.
+func (g *ContributionGraph) record(date time.Time) (ContributionRecord, bool) {
+	if len(g.Records) == 0 {
+		return ContributionRecord{}, false
+	}
+	i := CalendarDaysBetween(g.Records[0].Date, date)
+	if i < 0 || i >= len(g.Records) {
+		return ContributionRecord{}, false
+	}
+	return g.Records[i], true
+}
+
+// renderCalendar renders the twelve months ending with the month of the last
+// date as mini month calendars. Days not covered by the graph are omitted.
+func (g *ContributionGraph) renderCalendar(e *xml.Encoder) error {
+	for i := 0; i < 12; i++ {
+		first := time.Date(g.LastDate.Year(), g.LastDate.Month()-time.Month(11-i), 1, 12, 0, 0, 0, g.LastDate.Location())
+		location := image.Point{
+			X: calendarMargin + (i%calendarColumns)*(calendarMonthWidth+calendarGapX),
+			Y: calendarMargin + (i/calendarColumns)*(calendarMonthHeight+calendarGapY),
+		}
+		err := translated(e, location, func(e *xml.Encoder) error {
+			return g.renderMonth(e, first)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderMonth renders a single mini month calendar starting with the given
+// first day of the month.
+func (g *ContributionGraph) renderMonth(e *xml.Encoder, first time.Time) error {
+	err := simpleText(e, image.Point{Y: 10}, start,
+		cssClassAttrs("herdstat-contribution-graph-fg"), g.text().monthOfYear(first))
+	if err != nil {
+		return err
+	}
+	offset := int(first.Weekday())
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		record, ok := g.record(day)
+		if !ok {
+			continue
+		}
+		cell := day.Day() - 1 + offset
+		classes := []string{
+			"herdstat-contribution-graph-cell",
+			fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", g.level(record)),
+		}
+		if !g.Highlighted.IsZero() && sameDay(record.Date, g.Highlighted) {
+			classes = append(classes, "herdstat-contribution-graph-cell-highlighted")
+		}
+		location := image.Point{
+			X: (cell % 7) * 12,
+			Y: 16 + (cell/7)*12,
+		}
+		if g.NoTooltips {
+			err = coloredRoundedRect(e, location, cssClassAttrs(classes...))
+		} else {
+			err = nonEmptyElement(e, xml.StartElement{
+				Name: xml.Name{Local: "rect"},
+				Attr: append([]xml.Attr{
+					attr("x", strconv.Itoa(location.X)),
+					attr("y", strconv.Itoa(location.Y)),
+					attr("rx", "2"),
+				}, cssClassAttrs(classes...)...),
+			}, func(e *xml.Encoder) error {
+				return nonEmptyElement(e, xml.StartElement{
+					Name: xml.Name{Local: "title"},
+				}, func(e *xml.Encoder) error {
+					return e.EncodeToken(xml.CharData(
+						fmt.Sprintf("%s %s", g.text().contributions(record.Count), g.text().onDate(record.Date))))
+				})
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Looking up the record of a date", func() {
+	first := dateparse.MustParse("2023-01-01")
+	g := &ContributionGraph{Records: []ContributionRecord{
+		{Date: first, Count: 1},
+		{Date: first.AddDate(0, 0, 1), Count: 2},
+	}}
+
+	When("the date is covered by the graph", func() {
+		It("returns the record", func() {
+			r, ok := g.record(dateparse.MustParse("2023-01-02 18:00"))
+			Expect(ok).To(BeTrue())
+			Expect(r.Count).To(Equal(2))
+		})
+	})
+	When("the date is not covered by the graph", func() {
+		It("reports the date as missing", func() {
+			_, ok := g.record(dateparse.MustParse("2022-12-31"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Parsing layouts", func() {
+	It("supports the calendar layout", func() {
+		Expect(ParseLayout("calendar")).To(Equal(CalendarLayout))
+	})
+	It("rejects unknown layouts", func() {
+		_, err := ParseLayout("spiral")
+		Expect(err).To(HaveOccurred())
+	})
+})
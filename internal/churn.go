@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ChurnWeek is the number of commits and changed lines in the week starting
+// with the given Sunday.
+type ChurnWeek struct {
+
+	// The Sunday starting the week in '2006-01-02' notation.
+	Start string `json:"start" yaml:"start"`
+
+	// The number of commits.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The number of added lines.
+	Additions int `json:"additions" yaml:"additions"`
+
+	// The number of removed lines.
+	Deletions int `json:"deletions" yaml:"deletions"`
+}
+
+// Churn describes the amount of code changed in a repository (or a set of
+// repositories).
+type Churn struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall churn.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The overall number of commits.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The overall number of added lines.
+	Additions int `json:"additions" yaml:"additions"`
+
+	// The overall number of removed lines.
+	Deletions int `json:"deletions" yaml:"deletions"`
+
+	// The churn per week in chronological order.
+	Weeks []ChurnWeek `json:"weeks" yaml:"weeks"`
+}
+
+// ChurnReport contains the churn per repository and overall.
+type ChurnReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The churn across all repositories.
+	Overall Churn `json:"overall" yaml:"overall"`
+
+	// The churn of the individual repositories sorted by name.
+	Repositories []Churn `json:"repositories" yaml:"repositories"`
+}
+
+// newChurn computes the churn of the given commits made within the 52 weeks
+// ending with the given day.
+func newChurn(repository string, commits []Contribution, lastDay time.Time) Churn {
+	churn := Churn{Repository: repository}
+	firstDay := lastDay.AddDate(0, 0, -52*7+1)
+	weeks := make(map[string]int)
+	for start := previousSunday(firstDay); !start.After(lastDay); start = start.AddDate(0, 0, 7) {
+		weeks[start.Format(dateFormat)] = len(churn.Weeks)
+		churn.Weeks = append(churn.Weeks, ChurnWeek{Start: start.Format(dateFormat)})
+	}
+	for _, c := range commits {
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		churn.Commits++
+		churn.Additions += c.Additions
+		churn.Deletions += c.Deletions
+		if i, ok := weeks[previousSunday(c.Date).Format(dateFormat)]; ok {
+			churn.Weeks[i].Commits++
+			churn.Weeks[i].Additions += c.Additions
+			churn.Weeks[i].Deletions += c.Deletions
+		}
+	}
+	return churn
+}
+
+// NewChurnReport computes the churn of the given commits made within the 52
+// weeks ending with the given day. The commits are expected to carry the
+// number of added and removed lines.
+func NewChurnReport(commits []Contribution, lastDay time.Time) *ChurnReport {
+	report := &ChurnReport{
+		From:    lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:   lastDay.Format(dateFormat),
+		Overall: newChurn("", commits, lastDay),
+	}
+	for repository, c := range GroupByRepository(commits) {
+		report.Repositories = append(report.Repositories, newChurn(repository, c, lastDay))
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering churn reports as markdown.
+	//go:embed churn.gomd
+	churnTemplate string
+)
+
+// Markdown renders the churn report as markdown.
+func (r *ChurnReport) Markdown() (string, error) {
+	return renderMarkdown("churn", churnTemplate, r)
+}
+
+// CSV renders the weekly churn per repository as CSV records.
+func (r *ChurnReport) CSV() [][]string {
+	records := [][]string{{"repository", "week", "commits", "additions", "deletions"}}
+	for _, repository := range r.Repositories {
+		for _, w := range repository.Weeks {
+			records = append(records, []string{
+				repository.Repository,
+				w.Start,
+				strconv.Itoa(w.Commits),
+				strconv.Itoa(w.Additions),
+				strconv.Itoa(w.Deletions),
+			})
+		}
+	}
+	return records
+}
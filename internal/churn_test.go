@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Churn reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	commits := []Contribution{
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Additions: 10, Deletions: 2,
+			Date: dateparse.MustParse("2023-03-13 12:00")},
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Additions: 5, Deletions: 5,
+			Date: dateparse.MustParse("2023-03-12 12:00")},
+		{Type: CommitContribution, Repository: "herdstat/action", Additions: 1,
+			Date: dateparse.MustParse("2023-03-01 12:00")},
+		// Outside the analyzed period
+		{Type: CommitContribution, Repository: "herdstat/action", Additions: 100,
+			Date: dateparse.MustParse("2021-03-01 12:00")},
+	}
+	report := NewChurnReport(commits, lastDay)
+
+	It("computes the overall churn", func() {
+		Expect(report.Overall.Commits).To(Equal(3))
+		Expect(report.Overall.Additions).To(Equal(16))
+		Expect(report.Overall.Deletions).To(Equal(7))
+	})
+
+	It("computes the weekly churn per repository", func() {
+		Expect(report.Repositories).To(HaveLen(2))
+		herdstat := report.Repositories[1]
+		Expect(herdstat.Repository).To(Equal("herdstat/herdstat"))
+		Expect(herdstat.Weeks[len(herdstat.Weeks)-1]).To(Equal(ChurnWeek{
+			Start: "2023-03-12", Commits: 2, Additions: 15, Deletions: 7,
+		}))
+	})
+
+	It("renders CSV", func() {
+		records := report.CSV()
+		Expect(records[0]).To(Equal([]string{"repository", "week", "commits", "additions", "deletions"}))
+		Expect(records).To(ContainElement([]string{"herdstat/action", "2023-02-26", "1", "1", "0"}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| **Overall** | 3 | 16 | 7 |"))
+	})
+})
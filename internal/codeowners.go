@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// codeOwnersRule assigns owners to the paths matching a pattern.
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// CodeOwners are the rules of a CODEOWNERS file.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// codeOwnersPattern translates the given CODEOWNERS pattern, which follows the
+// gitignore syntax, into a regular expression matching the paths covered.
+func codeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	// Patterns containing a slash other than a trailing one are relative to
+	// the repository root.
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	// Patterns ending with '/*' match the files within a directory but not
+	// those in its subdirectories.
+	suffix := "(/.*)?$"
+	if strings.HasSuffix(pattern, "/*") {
+		suffix = "$"
+	}
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteString(suffix)
+	return regexp.Compile(sb.String())
+}
+
+// ParseCodeOwners parses the given content of a CODEOWNERS file.
+func ParseCodeOwners(content string) (*CodeOwners, error) {
+	codeOwners := &CodeOwners{}
+	for i, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		pattern, err := codeOwnersPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s' in line %d: %w", fields[0], i+1, err)
+		}
+		rule := codeOwnersRule{pattern: pattern}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.owners = append(rule.owners, owner)
+		}
+		codeOwners.rules = append(codeOwners.rules, rule)
+	}
+	return codeOwners, nil
+}
+
+// Owners returns the owners of the file with the given path relative to the
+// repository root. As in GitHub, the last matching rule takes precedence.
+// Returns nil if the file is not owned by anybody.
+func (c *CodeOwners) Owners(path string) []string {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// CodeOwnersCoverage describes the share of changes made to files without
+// code owners.
+type CodeOwnersCoverage struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall coverage.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// Whether the repository has a CODEOWNERS file.
+	CodeOwners bool `json:"codeOwners" yaml:"codeOwners"`
+
+	// The number of file changes, i.e., the number of files changed summed
+	// up over all commits.
+	Changes int `json:"changes" yaml:"changes"`
+
+	// The number of changes made to files without code owners.
+	Unowned int `json:"unowned" yaml:"unowned"`
+
+	// The share of changes made to files without code owners in percent.
+	UnownedPercentage float64 `json:"unownedPercentage" yaml:"unownedPercentage"`
+}
+
+// CodeOwnersReport contains the code owners coverage of the changes per
+// repository and overall.
+type CodeOwnersReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The coverage across all repositories.
+	Overall CodeOwnersCoverage `json:"overall" yaml:"overall"`
+
+	// The coverage of the individual repositories sorted by descending share
+	// of unowned changes.
+	Repositories []CodeOwnersCoverage `json:"repositories" yaml:"repositories"`
+}
+
+// NewCodeOwnersReport cross-references the files changed by the given commits
+// made within the 52 weeks ending with the given day with the code owners of
+// the respective repository. The code owners are given per repository in
+// 'owner/name' notation with nil denoting a repository without CODEOWNERS
+// file. Changes to such repositories are unowned.
+func NewCodeOwnersReport(commits []Contribution, lastDay time.Time, codeOwners map[string]*CodeOwners) *CodeOwnersReport {
+	report := &CodeOwnersReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	coverages := make(map[string]*CodeOwnersCoverage)
+	for repository, owners := range codeOwners {
+		coverages[repository] = &CodeOwnersCoverage{Repository: repository, CodeOwners: owners != nil}
+	}
+	for _, c := range commits {
+		coverage, ok := coverages[c.Repository]
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) || !ok {
+			continue
+		}
+		for _, file := range c.Files {
+			coverage.Changes++
+			if owners := codeOwners[c.Repository]; owners == nil || len(owners.Owners(file)) == 0 {
+				coverage.Unowned++
+			}
+		}
+	}
+	for _, coverage := range coverages {
+		coverage.UnownedPercentage = percentage(coverage.Unowned, coverage.Changes)
+		report.Overall.Changes += coverage.Changes
+		report.Overall.Unowned += coverage.Unowned
+		report.Overall.CodeOwners = report.Overall.CodeOwners || coverage.CodeOwners
+		report.Repositories = append(report.Repositories, *coverage)
+	}
+	report.Overall.UnownedPercentage = percentage(report.Overall.Unowned, report.Overall.Changes)
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		a, b := report.Repositories[i], report.Repositories[j]
+		if a.UnownedPercentage != b.UnownedPercentage {
+			return a.UnownedPercentage > b.UnownedPercentage
+		}
+		return a.Repository < b.Repository
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering code owners reports as
+	// markdown.
+	//go:embed codeowners.gomd
+	codeOwnersTemplate string
+)
+
+// Markdown renders the code owners report as markdown.
+func (r *CodeOwnersReport) Markdown() (string, error) {
+	return renderMarkdown("codeowners", codeOwnersTemplate, r)
+}
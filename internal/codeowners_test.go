@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Code owners", func() {
+	codeOwners, err := ParseCodeOwners(`
+# Default owners
+*       @herdstat/maintainers
+
+*.js    @js-owner # inline comment
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/   @octocat
+**/logs @logs-owner
+/scripts/generated
+`)
+
+	It("parses the rules", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(codeOwners.rules).To(HaveLen(7))
+	})
+
+	DescribeTable("resolves the owners of files",
+		func(path string, owners []string) {
+			Expect(codeOwners.Owners(path)).To(Equal(owners))
+		},
+		Entry("by the default rule", "main.go", []string{"@herdstat/maintainers"}),
+		Entry("by extension", "web/app.js", []string{"@js-owner"}),
+		Entry("by anchored directory", "build/logs/out.txt", []string{"@logs-owner"}),
+		Entry("by files in a directory", "docs/index.md", []string{"docs@example.com"}),
+		Entry("not by files in subdirectories", "docs/guides/index.md", []string{"@herdstat/maintainers"}),
+		Entry("by unanchored directory", "src/apps/main.go", []string{"@octocat"}),
+		Entry("without owners", "scripts/generated/file.sh", nil),
+	)
+
+	When("computing the coverage", func() {
+		lastDay := dateparse.MustParse("2023-03-15 23:59")
+		date := dateparse.MustParse("2023-03-01 12:00")
+		partial, err := ParseCodeOwners("/src/ @herdstat/maintainers")
+		Expect(err).NotTo(HaveOccurred())
+		commits := []Contribution{
+			{Type: CommitContribution, Repository: "herdstat/herdstat", Date: date, Files: []string{"src/main.go", "README.md"}},
+			{Type: CommitContribution, Repository: "herdstat/herdstat", Date: date, Files: []string{"src/util.go", "src/main.go"}},
+			{Type: CommitContribution, Repository: "herdstat/action", Date: date, Files: []string{"action.yml"}},
+			// Outside the analyzed period
+			{Type: CommitContribution, Repository: "herdstat/herdstat", Files: []string{"README.md"},
+				Date: dateparse.MustParse("2021-03-01 12:00")},
+		}
+		report := NewCodeOwnersReport(commits, lastDay, map[string]*CodeOwners{
+			"herdstat/herdstat": partial,
+			"herdstat/action":   nil,
+		})
+
+		It("computes the share of unowned changes per repository", func() {
+			Expect(report.Repositories).To(Equal([]CodeOwnersCoverage{
+				{Repository: "herdstat/action", Changes: 1, Unowned: 1, UnownedPercentage: 100},
+				{Repository: "herdstat/herdstat", CodeOwners: true, Changes: 4, Unowned: 1, UnownedPercentage: 25},
+			}))
+			Expect(report.Overall).To(Equal(CodeOwnersCoverage{CodeOwners: true, Changes: 5, Unowned: 2, UnownedPercentage: 40}))
+		})
+
+		It("renders a markdown table", func() {
+			md, err := report.Markdown()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(md).To(ContainSubstring("| herdstat/herdstat | yes | 4 | 1 | 25.0% |"))
+		})
+	})
+})
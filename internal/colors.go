@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Interpolation computes the color at the given intensity on the spectrum
+// between two colors. An intensity of 0 yields min and an intensity of 255
+// yields (approximately) max.
+type Interpolation func(min color.RGBA, max color.RGBA, intensity uint8) color.RGBA
+
+// Names of the supported color interpolation modes.
+const (
+	RGBInterpolationName = "rgb"
+	HSLInterpolationName = "hsl"
+	LabInterpolationName = "lab"
+)
+
+// ParseInterpolation returns the Interpolation registered under the given
+// name.
+func ParseInterpolation(name string) (Interpolation, error) {
+	switch name {
+	case RGBInterpolationName:
+		return defaultColoring, nil
+	case HSLInterpolationName:
+		return hslColoring, nil
+	case LabInterpolationName:
+		return labColoring, nil
+	}
+	return nil, fmt.Errorf("unknown color interpolation '%s'; supported are %s, %s, and %s",
+		name, RGBInterpolationName, HSLInterpolationName, LabInterpolationName)
+}
+
+// lerp linearly interpolates between a and b using the given intensity.
+func lerp(a, b float64, intensity uint8) float64 {
+	return a + (b-a)*float64(intensity)/255.0
+}
+
+// clampChannel converts a color channel value in the range [0..1] to an 8-bit
+// channel value.
+func clampChannel(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// hsl is a color in the HSL color space. Hue is given in degrees, saturation
+// and lightness in the range [0..1].
+type hsl struct {
+	H, S, L float64
+}
+
+// toHSL converts an RGB color into the HSL color space.
+func toHSL(c color.RGBA) hsl {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	l := (maxC + minC) / 2
+	if maxC == minC {
+		return hsl{0, 0, l}
+	}
+	d := maxC - minC
+	var s float64
+	if l > 0.5 {
+		s = d / (2 - maxC - minC)
+	} else {
+		s = d / (maxC + minC)
+	}
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return hsl{h * 60, s, l}
+}
+
+// toRGB converts a HSL color into the RGB color space.
+func (c hsl) toRGB() color.RGBA {
+	chroma := (1 - math.Abs(2*c.L-1)) * c.S
+	h := math.Mod(c.H+360, 360) / 60
+	x := chroma * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 1:
+		r, g, b = chroma, x, 0
+	case h < 2:
+		r, g, b = x, chroma, 0
+	case h < 3:
+		r, g, b = 0, chroma, x
+	case h < 4:
+		r, g, b = 0, x, chroma
+	case h < 5:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	m := c.L - chroma/2
+	return color.RGBA{R: clampChannel(r + m), G: clampChannel(g + m), B: clampChannel(b + m), A: 0xff}
+}
+
+// hslColoring interpolates in the HSL color space taking the shorter way
+// around the hue circle. Achromatic colors (e.g., the grey cell background)
+// adopt the hue of the other end of the spectrum.
+func hslColoring(min color.RGBA, max color.RGBA, intensity uint8) color.RGBA {
+	a, b := toHSL(min), toHSL(max)
+	if a.S == 0 {
+		a.H = b.H
+	}
+	if b.S == 0 {
+		b.H = a.H
+	}
+	dh := b.H - a.H
+	if dh > 180 {
+		dh -= 360
+	} else if dh < -180 {
+		dh += 360
+	}
+	return hsl{
+		H: a.H + dh*float64(intensity)/255.0,
+		S: lerp(a.S, b.S, intensity),
+		L: lerp(a.L, b.L, intensity),
+	}.toRGB()
+}
+
+// lab is a color in the CIELAB color space (D65 white point).
+type lab struct {
+	L, A, B float64
+}
+
+// D65 reference white used for CIELAB conversions.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// toLinear converts an 8-bit sRGB channel into linear light.
+func toLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// fromLinear converts linear light into a gamma-encoded sRGB channel in the
+// range [0..1].
+func fromLinear(c float64) float64 {
+	if c <= 0.0031308 {
+		return 12.92 * c
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// labF is the non-linear compression function used by CIELAB.
+func labF(t float64) float64 {
+	if t > 216.0/24389.0 {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27.0*t + 16) / 116
+}
+
+// labFInv is the inverse of labF.
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > 216.0/24389.0 {
+		return t3
+	}
+	return (116*t - 16) / (24389.0 / 27.0)
+}
+
+// toLab converts an RGB color into the CIELAB color space.
+func toLab(c color.RGBA) lab {
+	r, g, b := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
+	fx, fy, fz := labF(x/whiteX), labF(y/whiteY), labF(z/whiteZ)
+	return lab{
+		L: 116*fy - 16,
+		A: 500 * (fx - fy),
+		B: 200 * (fy - fz),
+	}
+}
+
+// toRGB converts a CIELAB color into the RGB color space. Out-of-gamut
+// colors are clamped.
+func (c lab) toRGB() color.RGBA {
+	fy := (c.L + 16) / 116
+	fx := fy + c.A/500
+	fz := fy - c.B/200
+	x, y, z := labFInv(fx)*whiteX, labFInv(fy)*whiteY, labFInv(fz)*whiteZ
+	r := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	g := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	b := 0.0556434*x - 0.2040259*y + 1.0572252*z
+	return color.RGBA{
+		R: clampChannel(fromLinear(r)),
+		G: clampChannel(fromLinear(g)),
+		B: clampChannel(fromLinear(b)),
+		A: 0xff,
+	}
+}
+
+// labColoring interpolates in the perceptually uniform CIELAB color space.
+func labColoring(min color.RGBA, max color.RGBA, intensity uint8) color.RGBA {
+	a, b := toLab(min), toLab(max)
+	return lab{
+		L: lerp(a.L, b.L, intensity),
+		A: lerp(a.A, b.A, intensity),
+		B: lerp(a.B, b.B, intensity),
+	}.toRGB()
+}
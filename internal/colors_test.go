@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Interpolating colors", func() {
+	light := color.RGBA{R: 0xeb, G: 0xed, B: 0xf0, A: 0xff}
+	green := color.RGBA{R: 0x39, G: 0xd3, B: 0x52, A: 0xff}
+
+	for _, name := range []string{HSLInterpolationName, LabInterpolationName} {
+		name := name
+		When("using "+name+" interpolation", func() {
+			interpolation, err := ParseInterpolation(name)
+			It("is supported", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("returns the lower end of the spectrum for intensity 0", func() {
+				Expect(interpolation(light, green, 0)).To(Equal(light))
+			})
+			It("returns the upper end of the spectrum for intensity 255", func() {
+				Expect(interpolation(light, green, 255)).To(Equal(green))
+			})
+		})
+	}
+
+	When("given an unknown interpolation name", func() {
+		It("returns an error", func() {
+			_, err := ParseInterpolation("cmyk")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
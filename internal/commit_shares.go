@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// CommitShare is the number and share of commits having a certain property
+// (e.g., being signed).
+type CommitShare struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall share
+	// and the monthly shares.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The first day of the month in '2006-01-02' notation. Empty for the
+	// overall share and the shares of the repositories.
+	Month string `json:"month,omitempty" yaml:"month,omitempty"`
+
+	// The overall number of commits.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The number of commits having the property.
+	Count int `json:"count" yaml:"count"`
+
+	// The share of commits having the property in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+}
+
+// add accounts for a commit that has the property if matching is set.
+func (s *CommitShare) add(matching bool) {
+	s.Commits++
+	if matching {
+		s.Count++
+	}
+}
+
+// CommitShareReport contains the share of commits having a certain property
+// per repository, per month, and overall.
+type CommitShareReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The share across all repositories.
+	Overall CommitShare `json:"overall" yaml:"overall"`
+
+	// The shares of the individual repositories sorted by ascending share.
+	Repositories []CommitShare `json:"repositories" yaml:"repositories"`
+
+	// The shares across all repositories per month in chronological order.
+	Months []CommitShare `json:"months" yaml:"months"`
+}
+
+// newCommitShareReport computes the share of the given commits made within the
+// 52 weeks ending with the given day for which the given predicate holds.
+func newCommitShareReport(commits []Contribution, lastDay time.Time, predicate func(Contribution) bool) CommitShareReport {
+	firstDay := lastDay.AddDate(0, 0, -52*7+1)
+	report := CommitShareReport{
+		From:  firstDay.Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	months := make(map[string]int)
+	for start := MonthlyGranularity.periodStart(firstDay); !start.After(lastDay); start = MonthlyGranularity.next(start) {
+		months[start.Format(dateFormat)] = len(report.Months)
+		report.Months = append(report.Months, CommitShare{Month: start.Format(dateFormat)})
+	}
+	repositories := make(map[string]*CommitShare)
+	for _, c := range commits {
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		matching := predicate(c)
+		report.Overall.add(matching)
+		if _, ok := repositories[c.Repository]; !ok {
+			repositories[c.Repository] = &CommitShare{Repository: c.Repository}
+		}
+		repositories[c.Repository].add(matching)
+		if i, ok := months[MonthlyGranularity.periodStart(c.Date).Format(dateFormat)]; ok {
+			report.Months[i].add(matching)
+		}
+	}
+	report.Overall.Percentage = percentage(report.Overall.Count, report.Overall.Commits)
+	for i := range report.Months {
+		report.Months[i].Percentage = percentage(report.Months[i].Count, report.Months[i].Commits)
+	}
+	for _, share := range repositories {
+		share.Percentage = percentage(share.Count, share.Commits)
+		report.Repositories = append(report.Repositories, *share)
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		a, b := report.Repositories[i], report.Repositories[j]
+		if a.Percentage != b.Percentage {
+			return a.Percentage < b.Percentage
+		}
+		return a.Repository < b.Repository
+	})
+	return report
+}
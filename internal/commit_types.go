@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"image/color"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// conventionalCommitPattern matches the subject of a commit message following
+// the Conventional Commits specification, e.g., 'feat(parser)!: add arrays'.
+var conventionalCommitPattern = regexp.MustCompile(`^([A-Za-z]+)(\([^)]*\))?!?: \S`)
+
+// ConventionalCommitType returns the lower-cased type of the given commit
+// subject (e.g., 'feat' or 'fix') or an empty string if the subject doesn't
+// follow the Conventional Commits specification.
+func ConventionalCommitType(subject string) string {
+	match := conventionalCommitPattern.FindStringSubmatch(subject)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(match[1])
+}
+
+// CommitTypeShare is the number of commits of a conventional commit type.
+type CommitTypeShare struct {
+
+	// The conventional commit type (e.g., 'feat').
+	Type string `json:"type" yaml:"type"`
+
+	// The number of commits of the type.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The share of conventional commits of the type in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+}
+
+// CommitTypeReport describes the distribution of commits across conventional
+// commit types.
+type CommitTypeReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The overall number of commits.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The number of commits following the Conventional Commits specification.
+	Conventional int `json:"conventional" yaml:"conventional"`
+
+	// The commit types ordered by descending number of commits.
+	Types []CommitTypeShare `json:"types" yaml:"types"`
+}
+
+// NewCommitTypeReport classifies the given commits made within the 52 weeks
+// ending with the given day by the conventional commit type given in their
+// subjects.
+func NewCommitTypeReport(commits []Contribution, lastDay time.Time) *CommitTypeReport {
+	report := &CommitTypeReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	counts := make(map[string]int)
+	for _, c := range commits {
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		report.Commits++
+		if t := ConventionalCommitType(c.Subject); t != "" {
+			report.Conventional++
+			counts[t]++
+		}
+	}
+	for t, count := range counts {
+		report.Types = append(report.Types, CommitTypeShare{
+			Type:       t,
+			Commits:    count,
+			Percentage: percentage(count, report.Conventional),
+		})
+	}
+	sort.Slice(report.Types, func(i, j int) bool {
+		a, b := report.Types[i], report.Types[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Type < b.Type
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering commit type reports as
+	// markdown.
+	//go:embed commit-types.gomd
+	commitTypesTemplate string
+)
+
+// Markdown renders the commit type report as markdown.
+func (r *CommitTypeReport) Markdown() (string, error) {
+	return renderMarkdown("commit-types", commitTypesTemplate, r)
+}
+
+// NewCommitTypeChart creates a BarChart visualizing the number of commits per
+// conventional commit type.
+func NewCommitTypeChart(report *CommitTypeReport, color color.RGBA) *BarChart {
+	chart := &BarChart{
+		Title: "Commits per type",
+		Color: color,
+	}
+	for _, t := range report.Types {
+		chart.Bars = append(chart.Bars, Bar{
+			Label: t.Type,
+			Value: t.Commits,
+			Text:  fmt.Sprintf("%d (%.1f%%)", t.Commits, t.Percentage),
+		})
+	}
+	return chart
+}
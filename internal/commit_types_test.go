@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Commit type reports", func() {
+	DescribeTable("classifies commit subjects",
+		func(subject string, commitType string) {
+			Expect(ConventionalCommitType(subject)).To(Equal(commitType))
+		},
+		Entry("with type", "feat: add arrays", "feat"),
+		Entry("with scope", "fix(parser): handle escapes", "fix"),
+		Entry("with breaking change", "refactor(api)!: drop v1", "refactor"),
+		Entry("with upper-case type", "Docs: fix typo", "docs"),
+		Entry("without type", "Add arrays", ""),
+		Entry("without space", "feat:add arrays", ""),
+		Entry("with merge", "Merge pull request #1 from herdstat/feat", ""),
+	)
+
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	date := dateparse.MustParse("2023-03-01 12:00")
+	commit := func(subject string) Contribution {
+		return Contribution{Type: CommitContribution, Repository: "herdstat/herdstat", Subject: subject, Date: date}
+	}
+	report := NewCommitTypeReport([]Contribution{
+		commit("feat: a"), commit("fix: b"), commit("feat(x): c"), commit("chore: d"), commit("Update README"),
+	}, lastDay)
+
+	It("computes the distribution across types", func() {
+		Expect(report.Commits).To(Equal(5))
+		Expect(report.Conventional).To(Equal(4))
+		Expect(report.Types).To(Equal([]CommitTypeShare{
+			{Type: "feat", Commits: 2, Percentage: 50},
+			{Type: "chore", Commits: 1, Percentage: 25},
+			{Type: "fix", Commits: 1, Percentage: 25},
+		}))
+		Expect(NewCommitTypeChart(report, color.RGBA{}).Bars).To(HaveLen(3))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| feat | 2 | 50.0% |"))
+	})
+})
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+)
+
+// CompanyShare is the amount of contributions made by the contributors
+// affiliated with an organization.
+type CompanyShare struct {
+
+	// The name of the organization.
+	Organization string `json:"organization" yaml:"organization"`
+
+	// The number of contributions.
+	Contributions int `json:"contributions" yaml:"contributions"`
+
+	// The share of all contributions in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+
+	// The number of distinct contributors.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The number of repositories contributed to.
+	Repositories int `json:"repositories" yaml:"repositories"`
+}
+
+// CompanyReport is a leaderboard of the organizations the contributors are
+// affiliated with.
+type CompanyReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The overall number of contributions.
+	Contributions int `json:"contributions" yaml:"contributions"`
+
+	// The number of contributions by contributors without known affiliation.
+	Unaffiliated int `json:"unaffiliated" yaml:"unaffiliated"`
+
+	// The organizations ordered by descending number of contributions.
+	Companies []CompanyShare `json:"companies" yaml:"companies"`
+}
+
+// NewCompanyReport ranks the organizations by the number of contributions
+// their affiliated contributors made within the 52 weeks ending with the given
+// day. Only the given number of organizations with the most contributions are
+// listed, or all of them if top is not positive.
+func NewCompanyReport(contributions []Contribution, lastDay time.Time, affiliations *Affiliations, top int) *CompanyReport {
+	report := &CompanyReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	shares := make(map[string]*CompanyShare)
+	contributors := make(map[string]map[string]bool)
+	repositories := make(map[string]map[string]bool)
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		report.Contributions++
+		organization := affiliations.Organization(c)
+		if organization == "" {
+			report.Unaffiliated++
+			continue
+		}
+		share, ok := shares[organization]
+		if !ok {
+			share = &CompanyShare{Organization: organization}
+			shares[organization] = share
+			contributors[organization] = make(map[string]bool)
+			repositories[organization] = make(map[string]bool)
+		}
+		share.Contributions++
+		contributors[organization][c.Contributor()] = true
+		repositories[organization][c.Repository] = true
+	}
+	for organization, share := range shares {
+		share.Percentage = percentage(share.Contributions, report.Contributions)
+		share.Contributors = len(contributors[organization])
+		share.Repositories = len(repositories[organization])
+		report.Companies = append(report.Companies, *share)
+	}
+	sort.Slice(report.Companies, func(i, j int) bool {
+		a, b := report.Companies[i], report.Companies[j]
+		if a.Contributions != b.Contributions {
+			return a.Contributions > b.Contributions
+		}
+		return a.Organization < b.Organization
+	})
+	if top > 0 && len(report.Companies) > top {
+		report.Companies = report.Companies[:top]
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering company reports as markdown.
+	//go:embed companies.gomd
+	companiesTemplate string
+)
+
+// Markdown renders the company report as markdown.
+func (r *CompanyReport) Markdown() (string, error) {
+	return renderMarkdown("companies", companiesTemplate, r)
+}
+
+// NewCompanyChart creates a BarChart visualizing the contributions per
+// organization.
+func NewCompanyChart(report *CompanyReport, color color.RGBA) *BarChart {
+	chart := &BarChart{
+		Title: "Contributions per organization",
+		Color: color,
+	}
+	for _, company := range report.Companies {
+		chart.Bars = append(chart.Bars, Bar{
+			Label: company.Organization,
+			Value: company.Contributions,
+			Text:  fmt.Sprintf("%.1f%%", company.Percentage),
+		})
+	}
+	return chart
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Company reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	date := dateparse.MustParse("2023-03-01 12:00")
+	affiliations := NewAffiliations([]Affiliation{
+		{Organization: "Herdstat", Domains: []string{"herdstat.com"}, Contributors: []string{"carol"}},
+	})
+	contributions := []Contribution{
+		{Repository: "herdstat/herdstat", Email: "alice@herdstat.com", Date: date},
+		{Repository: "herdstat/action", Email: "alice@herdstat.com", Date: date},
+		{Repository: "herdstat/herdstat", Login: "carol", Date: date},
+		{Repository: "herdstat/herdstat", Email: "bob@acme.org", Date: date},
+		{Repository: "herdstat/herdstat", Email: "dave@gmail.com", Date: date},
+		// Outside the analyzed period
+		{Repository: "herdstat/herdstat", Email: "bob@acme.org", Date: dateparse.MustParse("2021-03-01 12:00")},
+	}
+
+	It("ranks the organizations by contributions", func() {
+		report := NewCompanyReport(contributions, lastDay, affiliations, 0)
+		Expect(report.Contributions).To(Equal(5))
+		Expect(report.Unaffiliated).To(Equal(1))
+		Expect(report.Companies).To(Equal([]CompanyShare{
+			{Organization: "Herdstat", Contributions: 3, Percentage: 60, Contributors: 2, Repositories: 2},
+			{Organization: "acme.org", Contributions: 1, Percentage: 20, Contributors: 1, Repositories: 1},
+		}))
+	})
+
+	It("lists the top organizations only", func() {
+		report := NewCompanyReport(contributions, lastDay, affiliations, 1)
+		Expect(report.Companies).To(HaveLen(1))
+		Expect(NewCompanyChart(report, color.RGBA{}).Bars).To(Equal([]Bar{
+			{Label: "Herdstat", Value: 3, Text: "60.0%"},
+		}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := NewCompanyReport(contributions, lastDay, affiliations, 0).Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| 1 | Herdstat | 3 | 60.0% | 2 | 2 |"))
+	})
+})
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/xml"
+	"errors"
+	"image"
+	"strconv"
+)
+
+// comparisonTitleHeight is the height of the title row above each of the
+// compared graphs.
+const comparisonTitleHeight = 20
+
+// Comparison renders multiple contribution graphs stacked in a single SVG
+// document using a shared color scale.
+type Comparison struct {
+
+	// The graphs to be compared. The styling (colors, levels, background) of
+	// the first graph is used for all graphs.
+	Graphs []*ContributionGraph
+
+	// The titles rendered above the respective graphs.
+	Titles []string
+}
+
+// NewComparison creates a new Comparison of the given graphs and adjusts
+// their color scales such that the same count maps to the same color.
+func NewComparison(graphs []*ContributionGraph, titles []string) (*Comparison, error) {
+	if len(graphs) == 0 {
+		return nil, errors.New("at least one graph is required for a comparison")
+	}
+	if len(graphs) != len(titles) {
+		return nil, errors.New("each compared graph requires a title")
+	}
+	maxCount := 0
+	for _, g := range graphs {
+		if c := g.scaledMaxCount(); c > maxCount {
+			maxCount = c
+		}
+	}
+	for _, g := range graphs {
+		g.MaxCount = maxCount
+	}
+	return &Comparison{
+		Graphs: graphs,
+		Titles: titles,
+	}, nil
+}
+
+// size computes the width and height of the rendered comparison.
+func (c *Comparison) size() (int, int) {
+	width, height := 0, 0
+	for _, g := range c.Graphs {
+		w, h := g.size()
+		if w > width {
+			width = w
+		}
+		height += comparisonTitleHeight + h
+	}
+	return width, height
+}
+
+// Render writes the comparison to the given xml.Encoder.
+func (c *Comparison) Render(e *xml.Encoder) error {
+	width, height := c.size()
+	first := c.Graphs[0]
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			cssClassAttr("herdstat-contribution-graph", "herdstat-contribution-graph-var"),
+			attr("width", strconv.Itoa(width)),
+			attr("height", strconv.Itoa(height)),
+		},
+	}, func(e *xml.Encoder) error {
+		if err := first.renderStyle(e); err != nil {
+			return err
+		}
+		if !first.Background.isTransparent() {
+			if err := first.renderBackground(e); err != nil {
+				return err
+			}
+		}
+		y := 0
+		for i, g := range c.Graphs {
+			title := c.Titles[i]
+			err := translated(e, image.Point{Y: y}, func(e *xml.Encoder) error {
+				err := simpleText(e, image.Point{X: 10, Y: 15}, start,
+					append(cssClassAttrs("herdstat-contribution-graph-fg"), attr("font-weight", "800")), title)
+				if err != nil {
+					return err
+				}
+				return translated(e, image.Point{Y: comparisonTitleHeight}, g.renderBody)
+			})
+			if err != nil {
+				return err
+			}
+			_, h := g.size()
+			y += comparisonTitleHeight + h
+		}
+		return nil
+	})
+}
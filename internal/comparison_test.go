@@ -0,0 +1,31 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Comparing contribution graphs", func() {
+	When("the graphs have different maximum counts", func() {
+		It("uses a shared color scale", func() {
+			a := &ContributionGraph{Records: []ContributionRecord{{Count: 2}}}
+			b := &ContributionGraph{Records: []ContributionRecord{{Count: 4}}}
+			_, err := NewComparison([]*ContributionGraph{a, b}, []string{"a", "b"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.intensity(a.Records[0])).To(Equal(b.intensity(ContributionRecord{Count: 2})))
+		})
+	})
+	When("titles are missing", func() {
+		It("returns an error", func() {
+			_, err := NewComparison([]*ContributionGraph{{}}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -41,12 +41,27 @@ type ColorScheme struct {
 	Dark  ColorSpectrum
 }
 
+// Background defines the background colors of a graph per color scheme. A nil
+// color results in a transparent background.
+type Background struct {
+	Light *color.RGBA
+	Dark  *color.RGBA
+}
+
+// isTransparent returns true iff the background is transparent in both color
+// schemes.
+func (b Background) isTransparent() bool {
+	return b.Light == nil && b.Dark == nil
+}
+
 // Coloring translates an intensity into a color. It is used to compute the
 // color of graph cells and the legend.
 type Coloring func(intensity uint8, darkScheme bool) color.RGBA
 
-// GetColoring returns a linear coloring based on a single primary color.
-func GetColoring(scheme ColorScheme) Coloring {
+// GetColoring returns a coloring based on the given color scheme that uses
+// the given Interpolation to compute the colors between the ends of the
+// spectra.
+func GetColoring(scheme ColorScheme, interpolation Interpolation) Coloring {
 	return func(intensity uint8, darkScheme bool) color.RGBA {
 		var spectrum ColorSpectrum
 		if darkScheme {
@@ -54,10 +69,11 @@ func GetColoring(scheme ColorScheme) Coloring {
 		} else {
 			spectrum = scheme.Light
 		}
-		return defaultColoring(spectrum.Min, spectrum.Max, intensity)
+		return interpolation(spectrum.Min, spectrum.Max, intensity)
 	}
 }
 
+// defaultColoring interpolates linearly on each of the RGB channels.
 func defaultColoring(min color.RGBA, max color.RGBA, intensity uint8) color.RGBA {
 	m := func(a uint8, b uint8) uint8 {
 		// TODO Get rid of float64?
@@ -70,6 +86,13 @@ func defaultColoring(min color.RGBA, max color.RGBA, intensity uint8) color.RGBA
 	}
 }
 
+// Annotation marks an event (e.g., a release or a conference) on the timeline
+// of a contribution graph.
+type Annotation struct {
+	Date  time.Time
+	Label string
+}
+
 // ContributionGraph is a heatmap representation of 52 weeks of activity data.
 type ContributionGraph struct {
 
@@ -84,27 +107,112 @@ type ContributionGraph struct {
 
 	// The number of color levels
 	Levels uint8
+
+	// The date of the cell to be highlighted by an outline (typically
+	// "today"). No cell is highlighted if zero.
+	Highlighted time.Time
+
+	// The background of the graph. Transparent by default.
+	Background Background
+
+	// Whether to omit the tooltip overlay and the titles of weekly totals and
+	// annotations, e.g., for static embeddings in emails or PDFs where hover
+	// interactions are not available.
+	NoTooltips bool
+
+	// Events rendered as markers beneath the corresponding week columns.
+	Annotations []Annotation
+
+	// Whether to render a bar per week showing the weekly contribution
+	// totals beneath the cell matrix.
+	WeeklyTotals bool
+
+	// Whether to label all days of the week instead of Mondays, Wednesdays,
+	// and Fridays only.
+	AllWeekdays bool
+
+	// The count mapped to the highest intensity. Computed from the records
+	// if zero. Used to share a color scale between multiple graphs.
+	MaxCount int
+
+	// The mapping of counts to intensities. Only applies if MaxCount is zero.
+	Scaling Scaling
+
+	// The percentile of the non-zero counts the counts are capped at by the
+	// WinsorizedScaling. DefaultCapPercentile is used if zero.
+	CapPercentile float64
+
+	// The trend of the weekly contribution volume rendered next to the
+	// overall number of contributions. Not rendered if nil.
+	Velocity *Velocity
+
+	// The arrangement of the daily cells. The optional rows beneath the cell
+	// matrix (weekly totals and annotations) are supported by the
+	// HeatmapLayout only.
+	Layout Layout
+
+	// The texts of the graph. Empty texts are rendered in English.
+	Text GraphText
+
+	// The width the graph is scaled to keeping its aspect ratio. The graph is
+	// rendered at its natural size if zero.
+	Width int
 }
 
 // NewContributionMap creates a new ContributionGraph.
 func NewContributionMap(data []ContributionRecord, lastDate time.Time, coloring Coloring, levels uint8) *ContributionGraph {
 	return &ContributionGraph{
-		data,
-		lastDate,
-		coloring,
-		levels,
+		Records:  data,
+		LastDate: lastDate,
+		Coloring: coloring,
+		Levels:   levels,
 	}
 }
 
-// intensity computes the intensity of the given ContributionRecord.
-func (g *ContributionGraph) intensity(r ContributionRecord) uint8 {
-	maxCount := max(g.Records, func(a, b ContributionRecord) int {
+// text returns the texts of the graph.
+func (g *ContributionGraph) text() GraphText {
+	return g.Text.withDefaults()
+}
+
+// level computes the color level of the given ContributionRecord.
+func (g *ContributionGraph) level(r ContributionRecord) uint8 {
+	return uint8(math.Min(math.Ceil(float64(g.intensity(r))/256.0*float64(g.Levels)), float64(g.Levels-1)))
+}
+
+// maxRecordCount returns the highest count of all records.
+func (g *ContributionGraph) maxRecordCount() int {
+	return max(g.Records, func(a, b ContributionRecord) int {
 		return a.Count - b.Count
 	}).Count
+}
+
+// scaledMaxCount returns the count mapped to the highest intensity according
+// to the scaling of the graph. Higher counts are capped at this count.
+func (g *ContributionGraph) scaledMaxCount() int {
+	if g.Scaling == WinsorizedScaling {
+		percentile := g.CapPercentile
+		if percentile == 0 {
+			percentile = DefaultCapPercentile
+		}
+		return percentileCount(g.Records, percentile)
+	}
+	return g.maxRecordCount()
+}
+
+// intensity computes the intensity of the given ContributionRecord.
+func (g *ContributionGraph) intensity(r ContributionRecord) uint8 {
+	maxCount := g.MaxCount
+	if maxCount == 0 {
+		maxCount = g.scaledMaxCount()
+	}
 	if maxCount == 0 {
 		return 0
 	}
-	return uint8(255.0 / float32(maxCount) * float32(r.Count))
+	count := r.Count
+	if count > maxCount {
+		count = maxCount
+	}
+	return uint8(255.0 / float32(maxCount) * float32(count))
 }
 
 var (
@@ -115,8 +223,11 @@ var (
 
 // StyleTemplateParams are the parameters used for rendering the stylesheet template.
 type StyleTemplateParams struct {
-	DarkColors  []color.RGBA
-	LightColors []color.RGBA
+	DarkColors      []color.RGBA
+	LightColors     []color.RGBA
+	DarkBackground  *color.RGBA
+	LightBackground *color.RGBA
+	Tooltips        bool
 }
 
 // renderStyle writes the styleTemplate to the given decoder.
@@ -131,8 +242,11 @@ func (g *ContributionGraph) renderStyle(e *xml.Encoder) error {
 		darkColors = append(darkColors, g.Coloring(uint8(uint(i)*255/(uint(g.Levels)-1)), true))
 	}
 	params := StyleTemplateParams{
-		DarkColors:  darkColors,
-		LightColors: lightColors,
+		DarkColors:      darkColors,
+		LightColors:     lightColors,
+		DarkBackground:  g.Background.Dark,
+		LightBackground: g.Background.Light,
+		Tooltips:        !g.NoTooltips,
 	}
 	buf := new(bytes.Buffer)
 	if err := tmpl.Execute(buf, params); err != nil {
@@ -147,32 +261,29 @@ func (g *ContributionGraph) renderStyle(e *xml.Encoder) error {
 // Render writes the contribution map to the given xml.Encoder.
 func (g *ContributionGraph) Render(e *xml.Encoder) error {
 
+	width, height := g.size()
+	attrs := []xml.Attr{
+		{
+			Name: xml.Name{
+				Local: "xmlns",
+			},
+			Value: "http://www.w3.org/2000/svg",
+		},
+		cssClassAttr("herdstat-contribution-graph", "herdstat-contribution-graph-var"),
+	}
+	if g.Width > 0 && g.Width != width {
+		// Scales the graph drawn at its natural size to the requested width
+		attrs = append(attrs, attr("viewBox", fmt.Sprintf("0 0 %d %d", width, height)))
+		width, height = g.Width, int(math.Round(float64(height)*float64(g.Width)/float64(width)))
+	}
+	attrs = append(attrs, attr("width", strconv.Itoa(width)), attr("height", strconv.Itoa(height)))
+
 	// Write SVG opening tag
 	err := e.EncodeToken(xml.StartElement{
 		Name: xml.Name{
 			Local: "svg",
 		},
-		Attr: []xml.Attr{
-			{
-				Name: xml.Name{
-					Local: "xmlns",
-				},
-				Value: "http://www.w3.org/2000/svg",
-			},
-			cssClassAttr("herdstat-contribution-graph", "herdstat-contribution-graph-var"),
-			{
-				Name: xml.Name{
-					Local: "width",
-				},
-				Value: "700",
-			},
-			{
-				Name: xml.Name{
-					Local: "height",
-				},
-				Value: "150",
-			},
-		},
+		Attr: attrs,
 	})
 	if err != nil {
 		return err
@@ -182,25 +293,13 @@ func (g *ContributionGraph) Render(e *xml.Encoder) error {
 		return err
 	}
 
-	if err = g.renderContributionCellMatrix(e); err != nil {
-		return err
-	}
-
-	count := 0
-	for _, record := range g.Records {
-		count += record.Count
-	}
-	if err = g.renderOverallContributions(e, image.Point{
-		X: 65,
-		Y: 125,
-	}, count); err != nil {
-		return err
+	if !g.Background.isTransparent() {
+		if err = g.renderBackground(e); err != nil {
+			return err
+		}
 	}
 
-	if err = g.renderLegend(e, image.Point{
-		X: 565,
-		Y: 125,
-	}); err != nil {
+	if err = g.renderBody(e); err != nil {
 		return err
 	}
 
@@ -217,11 +316,105 @@ func (g *ContributionGraph) Render(e *xml.Encoder) error {
 	return err
 }
 
-func (g *ContributionGraph) renderContributionCellMatrix(e *xml.Encoder) error {
-	if err := g.renderWeekdayAxis(e); err != nil {
+// size computes the width and height of the rendered graph.
+func (g *ContributionGraph) size() (int, int) {
+	if g.Layout == CalendarLayout {
+		return calendarWidth, calendarHeight
+	}
+	return 700, 150 + g.extraHeight()
+}
+
+// renderBody renders the cells according to the configured layout followed
+// by the footer consisting of the overall number of contributions and the
+// legend.
+func (g *ContributionGraph) renderBody(e *xml.Encoder) error {
+	width, _ := g.size()
+	footerX := 65
+	var footerY int
+	var err error
+	switch g.Layout {
+	case CalendarLayout:
+		if err = g.renderCalendar(e); err != nil {
+			return err
+		}
+		footerX = calendarMargin
+		footerY = calendarFooterY
+	default:
+		if footerY, err = g.renderHeatmap(e); err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	for _, record := range g.Records {
+		count += record.Count
+	}
+	if err = g.renderOverallContributions(e, image.Point{
+		X: footerX,
+		Y: footerY,
+	}, count); err != nil {
 		return err
 	}
 
+	return g.renderLegend(e, image.Point{
+		X: width - 135,
+		Y: footerY,
+	})
+}
+
+// renderHeatmap renders the cell matrix with week columns followed by the
+// optional rows beneath it. Returns the vertical position of the footer.
+func (g *ContributionGraph) renderHeatmap(e *xml.Encoder) (int, error) {
+	if err := g.renderContributionCellMatrix(e); err != nil {
+		return 0, err
+	}
+
+	// Optional rows between the cell matrix and the footer
+	rowY := 116
+	if g.WeeklyTotals {
+		if err := g.renderWeeklyTotals(e, rowY); err != nil {
+			return 0, err
+		}
+		rowY += weeklyTotalsRowHeight
+	}
+	if len(g.Annotations) != 0 {
+		if err := g.renderAnnotations(e, rowY); err != nil {
+			return 0, err
+		}
+		rowY += annotationRowHeight
+	}
+	return rowY + 9, nil
+}
+
+// renderBackground renders a rectangle covering the whole graph that is
+// filled with the background color of the active color scheme.
+func (g *ContributionGraph) renderBackground(e *xml.Encoder) error {
+	return emptyElement(e, xml.StartElement{
+		Name: xml.Name{
+			Local: "rect",
+		},
+		Attr: []xml.Attr{
+			{
+				Name: xml.Name{
+					Local: "width",
+				},
+				Value: "100%",
+			},
+			{
+				Name: xml.Name{
+					Local: "height",
+				},
+				Value: "100%",
+			},
+			cssClassAttr("herdstat-contribution-graph-bg"),
+		},
+	})
+}
+
+// matrixLayout returns the location of the cell matrix and the number of
+// (partial) weeks it consists of.
+func (g *ContributionGraph) matrixLayout() (image.Point, int) {
+
 	// "Default" case of 51 full and 2 partial weeks
 	location := image.Point{
 		X: 50,
@@ -234,6 +427,40 @@ func (g *ContributionGraph) renderContributionCellMatrix(e *xml.Encoder) error {
 		location = location.Add(image.Point{X: 12})
 		sliceCount = 52
 	}
+	return location, sliceCount
+}
+
+// extraHeight computes the height of the optional rows rendered between the
+// cell matrix and the footer.
+func (g *ContributionGraph) extraHeight() int {
+	h := 0
+	if g.WeeklyTotals {
+		h += weeklyTotalsRowHeight
+	}
+	if len(g.Annotations) != 0 {
+		h += annotationRowHeight
+	}
+	return h
+}
+
+// weekIndex returns the index of the week column the given date is rendered
+// in. The result is out of the range of week columns if the date is not
+// covered by the graph.
+func (g *ContributionGraph) weekIndex(date time.Time) int {
+	_, sliceCount := g.matrixLayout()
+	weeks := CalendarDaysBetween(previousSunday(date), previousSunday(g.LastDate)) / 7
+	if date.After(g.LastDate) {
+		weeks = -1
+	}
+	return sliceCount - 1 - weeks
+}
+
+func (g *ContributionGraph) renderContributionCellMatrix(e *xml.Encoder) error {
+	if err := g.renderWeekdayAxis(e); err != nil {
+		return err
+	}
+
+	location, sliceCount := g.matrixLayout()
 	err := translated(
 		e,
 		location,
@@ -271,6 +498,10 @@ func (g *ContributionGraph) renderContributionCellMatrix(e *xml.Encoder) error {
 				}
 			}
 
+			if g.NoTooltips {
+				return nil
+			}
+
 			// Render overlay
 			for i, slice := range slices {
 				err := translated(e, image.Point{X: 12 * i}, func(e *xml.Encoder) error {
@@ -291,57 +522,180 @@ func (g *ContributionGraph) renderContributionCellMatrix(e *xml.Encoder) error {
 	return nil
 }
 
-// renderWeekdayAxis renders the y-axis of the heatmap consisting of the days
-// of the week.
-func (g *ContributionGraph) renderWeekdayAxis(e *xml.Encoder) error {
-	clsAttrs := cssClassAttrs("herdstat-contribution-graph-fg")
-	err := simpleText(
-		e,
-		image.Point{
-			X: 40,
-			Y: 12 + 9 + 30,
-		},
-		end,
-		clsAttrs,
-		"Mon",
-	)
-	if err != nil {
-		return err
+// weeklyTotalsRowHeight is the height of the row containing the weekly
+// totals bar chart.
+const weeklyTotalsRowHeight = 30
+
+// weeklyTotals computes the total number of contributions per week column.
+func (g *ContributionGraph) weeklyTotals() []int {
+	_, sliceCount := g.matrixLayout()
+	totals := make([]int, sliceCount)
+	for _, record := range g.Records {
+		if i := g.weekIndex(record.Date); i >= 0 && i < sliceCount {
+			totals[i] += record.Count
+		}
 	}
+	return totals
+}
 
-	err = simpleText(
-		e,
-		image.Point{
-			X: 40,
-			Y: 36 + 9 + 30,
-		},
-		end,
-		clsAttrs,
-		"Wed",
-	)
-	if err != nil {
-		return err
+// renderWeeklyTotals renders a bar chart of the weekly contribution totals
+// with a bar beneath each week column. The bars are scaled relative to the
+// week with the most contributions.
+func (g *ContributionGraph) renderWeeklyTotals(e *xml.Encoder, y int) error {
+	location, _ := g.matrixLayout()
+	totals := g.weeklyTotals()
+	maxTotal := max(totals, func(a, b int) int {
+		return a - b
+	})
+	const maxBarHeight = weeklyTotalsRowHeight - 6
+	for i, total := range totals {
+		if total == 0 {
+			continue
+		}
+		h := int(math.Max(1, math.Round(float64(maxBarHeight*total)/float64(maxTotal))))
+		err := nonEmptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "rect"},
+			Attr: []xml.Attr{
+				{
+					Name: xml.Name{
+						Local: "x",
+					},
+					Value: strconv.Itoa(location.X + 12*i),
+				},
+				{
+					Name: xml.Name{
+						Local: "y",
+					},
+					Value: strconv.Itoa(y + maxBarHeight - h),
+				},
+				{
+					Name: xml.Name{
+						Local: "width",
+					},
+					Value: "10",
+				},
+				{
+					Name: xml.Name{
+						Local: "height",
+					},
+					Value: strconv.Itoa(h),
+				},
+				{
+					Name: xml.Name{
+						Local: "rx",
+					},
+					Value: "1",
+				},
+				cssClassAttr(fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", g.Levels-1)),
+			},
+		}, func(e *xml.Encoder) error {
+			return g.renderTitle(e, g.text().contributions(total))
+		})
+		if err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	err = simpleText(
-		e,
-		image.Point{
-			X: 40,
-			Y: 60 + 9 + 30,
-		},
-		end,
-		clsAttrs,
-		"Fri",
-	)
-	if err != nil {
-		return err
+// renderTitle renders a title element with the given content shown as
+// tooltip of its parent element. No title is rendered if tooltips are
+// omitted.
+func (g *ContributionGraph) renderTitle(e *xml.Encoder, content string) error {
+	if g.NoTooltips {
+		return nil
 	}
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "title"},
+	}, func(e *xml.Encoder) error {
+		return e.EncodeToken(xml.CharData(content))
+	})
+}
 
+// annotationRowHeight is the height of the row containing the annotation
+// markers.
+const annotationRowHeight = 12
+
+// renderAnnotations renders a marker beneath the week column of each
+// annotation. The label of the annotation is shown when hovering the marker.
+func (g *ContributionGraph) renderAnnotations(e *xml.Encoder, y int) error {
+	location, sliceCount := g.matrixLayout()
+	for _, annotation := range g.Annotations {
+		i := g.weekIndex(annotation.Date)
+		if i < 0 || i >= sliceCount {
+			continue
+		}
+		x := location.X + 12*i + 5
+		err := nonEmptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "polygon"},
+			Attr: []xml.Attr{
+				{
+					Name: xml.Name{
+						Local: "points",
+					},
+					Value: fmt.Sprintf("%d,%d %d,%d %d,%d", x, y, x-4, y+7, x+4, y+7),
+				},
+				cssClassAttr("herdstat-contribution-graph-annotation"),
+			},
+		}, func(e *xml.Encoder) error {
+			return g.renderTitle(e, fmt.Sprintf("%s: %s", g.text().date(annotation.Date), annotation.Label))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// weekdayAxisLayout returns the horizontal position the labels of the
+// weekday axis end at and the offset of their baselines from the top of the
+// rows. Labeling all weekdays uses a smaller font, so the labels are moved
+// closer to the cell matrix and centered on the rows.
+func (g *ContributionGraph) weekdayAxisLayout() (int, int) {
+	if !g.AllWeekdays {
+		return 40, 9
+	}
+	location, _ := g.matrixLayout()
+	return location.X - 6, 8
+}
+
+// renderWeekdayAxis renders the y-axis of the heatmap consisting of the days
+// of the week. Only Mondays, Wednesdays, and Fridays are labeled unless all
+// weekdays are requested, in which case a smaller font is used to fit the
+// labels to the rows.
+func (g *ContributionGraph) renderWeekdayAxis(e *xml.Encoder) error {
+	classes := []string{"herdstat-contribution-graph-fg"}
+	if g.AllWeekdays {
+		classes = append(classes, "herdstat-contribution-graph-weekday-compact")
+	}
+	clsAttrs := cssClassAttrs(classes...)
+	weekdays := g.text().Weekdays
+	x, baseline := g.weekdayAxisLayout()
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if !g.AllWeekdays && day%2 == 0 {
+			continue
+		}
+		// The rows of the cells start beneath the month labels
+		err := simpleText(
+			e,
+			image.Point{
+				X: x,
+				Y: 12*int(day) + baseline + 30,
+			},
+			end,
+			clsAttrs,
+			weekdays[day],
+		)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // renderOverallContributions renders a label with the overall number of contributions.
 func (g *ContributionGraph) renderOverallContributions(e *xml.Encoder, location image.Point, count int) error {
+	t := g.text()
 	return text(e, location.Add(image.Point{Y: 9}), start, cssClassAttrs("herdstat-contribution-graph-fg"),
 		func(e *xml.Encoder) error {
 			err := nonEmptyElement(e, xml.StartElement{
@@ -357,12 +711,15 @@ func (g *ContributionGraph) renderOverallContributions(e *xml.Encoder, location
 					},
 				},
 			}, func(e *xml.Encoder) error {
-				return e.EncodeToken(xml.CharData(fmt.Sprintf("%d contributions\u00A0", count)))
+				return e.EncodeToken(xml.CharData(t.contributions(count) + "\u00A0"))
 			})
 			if err != nil {
 				return nil
 			}
-			return e.EncodeToken(xml.CharData("in the last year"))
+			if g.Velocity != nil {
+				return e.EncodeToken(xml.CharData(fmt.Sprintf("%s (%s)", t.LastYear, g.Velocity)))
+			}
+			return e.EncodeToken(xml.CharData(t.LastYear))
 		})
 }
 
@@ -370,12 +727,13 @@ func (g *ContributionGraph) renderOverallContributions(e *xml.Encoder, location
 // indicators.
 func (g *ContributionGraph) renderLegend(e *xml.Encoder, location image.Point) error {
 	clsAttrs := cssClassAttrs("herdstat-contribution-graph-fg")
+	t := g.text()
 	err := simpleText(
 		e,
 		location.Add(image.Point{Y: 9}),
 		start,
 		clsAttrs,
-		"Less",
+		t.Less,
 	)
 	if err != nil {
 		return err
@@ -399,7 +757,7 @@ func (g *ContributionGraph) renderLegend(e *xml.Encoder, location image.Point) e
 		location.Add(image.Point{X: 29 + 5*12 + 1, Y: 9}),
 		start,
 		clsAttrs,
-		"More",
+		t.More,
 	)
 	if err != nil {
 		return err
@@ -466,7 +824,7 @@ func (w weekSlice) render(e *xml.Encoder, overlay bool) error {
 			dx = 10
 		}
 		err := simpleText(e, image.Point{X: dx, Y: 10}, ta,
-			cssClassAttrs("herdstat-contribution-graph-fg"), w.Date.Format("Jan"))
+			cssClassAttrs("herdstat-contribution-graph-fg"), w.Graph.text().month(w.Date))
 		if err != nil {
 			return err
 		}
@@ -645,12 +1003,12 @@ func (w weekSlice) renderTooltip(e *xml.Encoder, location image.Point, tipPositi
 						},
 					},
 				}, func(e *xml.Encoder) error {
-					return e.EncodeToken(xml.CharData(fmt.Sprintf("%d contributions\u00A0", record.Count)))
+					return e.EncodeToken(xml.CharData(w.Graph.text().contributions(record.Count) + "\u00A0"))
 				})
 				if err != nil {
 					return nil
 				}
-				return e.EncodeToken(xml.CharData(fmt.Sprintf("on %s", record.Date.Format("Jan 2, 2006"))))
+				return e.EncodeToken(xml.CharData(w.Graph.text().onDate(record.Date)))
 			},
 		)
 	})
@@ -660,7 +1018,7 @@ func (w weekSlice) renderTooltip(e *xml.Encoder, location image.Point, tipPositi
 // contributions.
 func (w weekSlice) renderDay(e *xml.Encoder, weekIndex uint8, record ContributionRecord, overlay bool) error {
 	y := int(record.Date.Weekday()) * 12
-	col := uint8(math.Min(math.Ceil(float64(w.Graph.intensity(record))/256.0*float64(w.Graph.Levels)), float64(w.Graph.Levels-1)))
+	col := w.Graph.level(record)
 	var attrs []xml.Attr
 	if overlay {
 		attrs = []xml.Attr{
@@ -673,9 +1031,14 @@ func (w weekSlice) renderDay(e *xml.Encoder, weekIndex uint8, record Contributio
 			cssClassAttr("herdstat-contribution-graph-cell-overlay"),
 		}
 	} else {
-		attrs = cssClassAttrs(
+		classes := []string{
 			"herdstat-contribution-graph-cell",
-			fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", col))
+			fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", col),
+		}
+		if !w.Graph.Highlighted.IsZero() && sameDay(record.Date, w.Graph.Highlighted) {
+			classes = append(classes, "herdstat-contribution-graph-cell-highlighted")
+		}
+		attrs = cssClassAttrs(classes...)
 	}
 	err := coloredRoundedRect(e, image.Point{
 		X: 0,
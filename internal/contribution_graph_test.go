@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+	"strings"
+	"time"
+)
+
+// renderGraph renders the given graph and returns the SVG document.
+func renderGraph(g *ContributionGraph) string {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	Expect(g.Render(enc)).To(Succeed())
+	Expect(enc.Flush()).To(Succeed())
+	return buf.String()
+}
+
+// svgText is a text element of an SVG document.
+type svgText struct {
+	X       int    `xml:"x,attr"`
+	Y       int    `xml:"y,attr"`
+	Class   string `xml:"class,attr"`
+	Content string `xml:",chardata"`
+}
+
+// svgTexts returns the text elements of the given SVG document having the
+// given CSS class.
+func svgTexts(svg string, class string) []svgText {
+	var texts []svgText
+	d := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "text" {
+			var t svgText
+			Expect(d.DecodeElement(&t, &start)).To(Succeed())
+			if strings.Contains(" "+t.Class+" ", " "+class+" ") {
+				texts = append(texts, t)
+			}
+		}
+	}
+	return texts
+}
+
+var _ = Describe("Computing the week column of a date", func() {
+	// A Wednesday, i.e., the graph consists of 53 (partial) weeks
+	lastDate := dateparse.MustParse("2023-03-15")
+	g := &ContributionGraph{LastDate: lastDate}
+
+	When("given the last date", func() {
+		It("returns the last column", func() {
+			Expect(g.weekIndex(lastDate)).To(Equal(52))
+		})
+	})
+	When("given the first date", func() {
+		It("returns the first column", func() {
+			Expect(g.weekIndex(lastDate.AddDate(0, 0, -52*7+1))).To(Equal(0))
+		})
+	})
+	When("given a date after the last date", func() {
+		It("returns an index out of range", func() {
+			Expect(g.weekIndex(lastDate.AddDate(0, 0, 7))).To(BeNumerically(">", 52))
+		})
+	})
+})
+
+var _ = Describe("Rendering the weekday axis", func() {
+	lastDate := dateparse.MustParse("2023-03-15")
+
+	It("labels Mondays, Wednesdays, and Fridays by default", func() {
+		svg := renderGraph(NewContributionMap(NewContributionRecords(lastDate), lastDate, testColoring, 5))
+		Expect(svgTexts(svg, "herdstat-contribution-graph-weekday-compact")).To(BeEmpty())
+		Expect(svg).To(ContainSubstring(`<text x="40" y="51" font-size="12px" text-anchor="end" class="herdstat-contribution-graph-fg">Mon</text>`))
+	})
+
+	It("labels all weekdays centered on the rows next to the cells", func() {
+		g := NewContributionMap(NewContributionRecords(lastDate), lastDate, testColoring, 5)
+		g.AllWeekdays = true
+		labels := svgTexts(renderGraph(g), "herdstat-contribution-graph-weekday-compact")
+		Expect(labels).To(Equal([]svgText{
+			{X: 44, Y: 38, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Sun"},
+			{X: 44, Y: 50, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Mon"},
+			{X: 44, Y: 62, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Tue"},
+			{X: 44, Y: 74, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Wed"},
+			{X: 44, Y: 86, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Thu"},
+			{X: 44, Y: 98, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Fri"},
+			{X: 44, Y: 110, Class: "herdstat-contribution-graph-fg herdstat-contribution-graph-weekday-compact", Content: "Sat"},
+		}))
+	})
+
+	It("keeps the labels of all weekdays next to the cells shifted by a full first week", func() {
+		saturday := dateparse.MustParse("2023-03-18")
+		g := NewContributionMap(NewContributionRecords(saturday), saturday, testColoring, 5)
+		g.AllWeekdays = true
+		labels := svgTexts(renderGraph(g), "herdstat-contribution-graph-weekday-compact")
+		Expect(labels).To(HaveLen(7))
+		Expect(labels[0].X).To(Equal(56))
+	})
+})
+
+var _ = Describe("Rendering the background", func() {
+	lastDate := dateparse.MustParse("2023-03-15")
+	bgRect := `class="herdstat-contribution-graph-bg"></rect>`
+
+	// colorScheme returns the styles of the given color scheme.
+	colorScheme := func(svg string, scheme string) string {
+		_, styles, found := strings.Cut(svg, "@media (prefers-color-scheme: "+scheme+")")
+		Expect(found).To(BeTrue())
+		styles, _, _ = strings.Cut(styles, "@media")
+		return styles
+	}
+
+	It("is transparent by default", func() {
+		svg := renderGraph(NewContributionMap(NewContributionRecords(lastDate), lastDate, testColoring, 5))
+		Expect(svg).NotTo(ContainSubstring(bgRect))
+		Expect(colorScheme(svg, "light")).To(ContainSubstring("--herdstat-contribution-graph-color-bg: transparent;"))
+		Expect(colorScheme(svg, "dark")).To(ContainSubstring("--herdstat-contribution-graph-color-bg: transparent;"))
+	})
+
+	It("fills a rect with the background color of the active color scheme", func() {
+		g := NewContributionMap(NewContributionRecords(lastDate), lastDate, testColoring, 5)
+		g.Background = Background{
+			Light: &color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			Dark:  &color.RGBA{R: 0x0d, G: 0x11, B: 0x17, A: 0xff},
+		}
+		svg := renderGraph(g)
+		Expect(strings.Count(svg, bgRect)).To(Equal(1))
+		Expect(svg).To(ContainSubstring(`<rect width="100%" height="100%" ` + bgRect))
+		Expect(colorScheme(svg, "light")).To(ContainSubstring("--herdstat-contribution-graph-color-bg: rgb(255, 255, 255);"))
+		Expect(colorScheme(svg, "dark")).To(ContainSubstring("--herdstat-contribution-graph-color-bg: rgb(13, 17, 23);"))
+	})
+
+	It("keeps the background of the other color scheme transparent", func() {
+		g := NewContributionMap(NewContributionRecords(lastDate), lastDate, testColoring, 5)
+		g.Background = Background{Dark: &color.RGBA{R: 0x0d, G: 0x11, B: 0x17, A: 0xff}}
+		svg := renderGraph(g)
+		Expect(strings.Count(svg, bgRect)).To(Equal(1))
+		Expect(colorScheme(svg, "light")).To(ContainSubstring("--herdstat-contribution-graph-color-bg: transparent;"))
+		Expect(colorScheme(svg, "dark")).To(ContainSubstring("--herdstat-contribution-graph-color-bg: rgb(13, 17, 23);"))
+	})
+})
+
+var _ = Describe("Omitting tooltips", func() {
+	lastDate := dateparse.MustParse("2023-03-15")
+
+	newGraph := func(noTooltips bool) *ContributionGraph {
+		records := NewContributionRecords(lastDate)
+		records[len(records)-1].Count = 3
+		g := NewContributionMap(records, lastDate, testColoring, 5)
+		g.WeeklyTotals = true
+		g.Annotations = []Annotation{{Date: lastDate, Label: "Release v1.0.0"}}
+		g.NoTooltips = noTooltips
+		return g
+	}
+
+	It("renders tooltips and titles by default", func() {
+		svg := renderGraph(newGraph(false))
+		Expect(svg).To(ContainSubstring("herdstat-contribution-graph-cell-overlay"))
+		Expect(svg).To(ContainSubstring(`<g class="herdstat-contribution-graph-cell-tooltip">`))
+		Expect(svg).To(ContainSubstring("<title>3 contributions</title>"))
+		Expect(svg).To(ContainSubstring("<title>Mar 15, 2023: Release v1.0.0</title>"))
+	})
+
+	It("leaves out the overlay and all title elements", func() {
+		svg := renderGraph(newGraph(true))
+		Expect(svg).NotTo(ContainSubstring("herdstat-contribution-graph-cell-overlay"))
+		Expect(svg).NotTo(ContainSubstring("herdstat-contribution-graph-cell-tooltip"))
+		Expect(svg).NotTo(ContainSubstring("<title>"))
+		// The overall number of contributions is still shown
+		Expect(svg).To(ContainSubstring("3 contributions"))
+	})
+})
+
+var _ = Describe("Rendering weekly totals", func() {
+	// A Wednesday, i.e., the first week consists of Thursday to Saturday only
+	lastDate := dateparse.MustParse("2023-03-15")
+	records := NewContributionRecords(lastDate)
+	for i, count := range map[int]int{0: 1, 1: 2, 2: 3, 3: 4, 9: 5, len(records) - 1: 7} {
+		records[i].Count = count
+	}
+	g := NewContributionMap(records, lastDate, testColoring, 5)
+	g.WeeklyTotals = true
+
+	It("sums up the contributions per week column", func() {
+		Expect(records[0].Date.Weekday()).To(Equal(time.Thursday))
+		totals := g.weeklyTotals()
+		Expect(totals).To(HaveLen(53))
+		// The partial first week
+		Expect(totals[0]).To(Equal(6))
+		Expect(totals[1]).To(Equal(9))
+		Expect(totals[2:52]).To(HaveEach(0))
+		// The partial last week
+		Expect(totals[52]).To(Equal(7))
+	})
+
+	It("renders a bar per week with contributions scaled to the highest total", func() {
+		svg := renderGraph(g)
+		Expect(svg).To(ContainSubstring(`<rect x="50" y="124" width="10" height="16" rx="1" class="herdstat-contribution-graph-cell-L4-bg"><title>6 contributions</title></rect>`))
+		Expect(svg).To(ContainSubstring(`<rect x="62" y="116" width="10" height="24" rx="1" class="herdstat-contribution-graph-cell-L4-bg"><title>9 contributions</title></rect>`))
+		Expect(svg).To(ContainSubstring(`<rect x="674" y="121" width="10" height="19" rx="1" class="herdstat-contribution-graph-cell-L4-bg"><title>7 contributions</title></rect>`))
+		Expect(strings.Count(svg, `rx="1" class="herdstat-contribution-graph-cell-L4-bg"`)).To(Equal(3))
+	})
+})
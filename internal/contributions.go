@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContributionType identifies the kind of a contribution.
+type ContributionType string
+
+const (
+
+	// CommitContribution is a commit to the default branch of a repository.
+	CommitContribution ContributionType = "commit"
+
+	// IssueContribution is an opened issue.
+	IssueContribution ContributionType = "issue"
+
+	// PullRequestContribution is an opened pull request.
+	PullRequestContribution ContributionType = "pull-request"
+)
+
+// Contribution is a single contribution made to a repository.
+type Contribution struct {
+
+	// The kind of contribution.
+	Type ContributionType
+
+	// The repository the contribution was made to in 'owner/name' notation.
+	Repository string
+
+	// The login of the contributor. Empty for commits whose author's login
+	// couldn't be resolved.
+	Login string
+
+	// The name of the contributor as given in the commit metadata. Empty for
+	// contributions made via GitHub.
+	Name string
+
+	// The email address of the contributor as given in the commit metadata.
+	// Empty for contributions made via GitHub.
+	Email string
+
+	// The point in time the contribution was made.
+	Date time.Time
+
+	// The URL of the web page of the contribution (e.g., the commit or the
+	// issue).
+	URL string
+
+	// The hash of a commit. Empty for other contributions and commits
+	// collected from the commit activity statistics.
+	Hash string
+
+	// The first line of the message of a commit.
+	Subject string
+
+	// Whether a commit is signed with a GPG or SSH key. The signature is not
+	// verified.
+	Signed bool
+
+	// Whether a commit message carries a 'Signed-off-by:' trailer as
+	// required by the Developer Certificate of Origin (DCO).
+	SignedOff bool
+
+	// The paths of the files changed by a commit. Only recorded if the
+	// collection of changed files or churn statistics is requested.
+	Files []string
+
+	// The number of lines added by a commit. Only recorded if the collection
+	// of churn statistics is requested.
+	Additions int
+
+	// The number of lines removed by a commit. Only recorded if the
+	// collection of churn statistics is requested.
+	Deletions int
+}
+
+// Contributor returns an identifier of the contributor. This is either the
+// GitHub login or the lower-cased email address for commits with unresolved
+// login.
+func (c Contribution) Contributor() string {
+	if c.Login != "" {
+		return c.Login
+	}
+	return strings.ToLower(c.Email)
+}
+
+// NewContributionRecords creates empty daily contribution records for the 52
+// weeks ending with the given day.
+func NewContributionRecords(lastDay time.Time) []ContributionRecord {
+	records := make([]ContributionRecord, 52*7)
+	for i := 0; i < 52*7; i++ {
+		records[i] = ContributionRecord{
+			Date:  lastDay.AddDate(0, 0, -(52*7 - 1 - i)),
+			Count: 0,
+		}
+	}
+	return records
+}
+
+// DailyRecords aggregates the given contributions into daily contribution
+// records for the 52 weeks ending with the given day. Contributions are
+// attributed to the calendar day of their date in its location (see
+// InPeriod). Contributions outside that period are ignored.
+func DailyRecords(contributions []Contribution, lastDay time.Time) []ContributionRecord {
+	records := NewContributionRecords(lastDay)
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		records[52*7-1-CalendarDaysBetween(c.Date, lastDay)].Count++
+	}
+	return records
+}
+
+// InPeriod returns true iff the calendar day of the given date in its
+// location lies within the 52 weeks ending with the given day.
+func InPeriod(date time.Time, lastDay time.Time) bool {
+	days := CalendarDaysBetween(date, lastDay)
+	return days >= 0 && days < 52*7
+}
+
+// ContributionsPerContributor counts the contributions made within the 52
+// weeks ending with the given day per contributor.
+func ContributionsPerContributor(contributions []Contribution, lastDay time.Time) map[string]int {
+	counts := make(map[string]int)
+	for _, c := range contributions {
+		if InPeriod(c.Date, lastDay) {
+			counts[c.Contributor()]++
+		}
+	}
+	return counts
+}
+
+// GroupByRepository groups the given contributions by repository.
+func GroupByRepository(contributions []Contribution) map[string][]Contribution {
+	groups := make(map[string][]Contribution)
+	for _, c := range contributions {
+		groups[c.Repository] = append(groups[c.Repository], c)
+	}
+	return groups
+}
+
+// ByContributor returns the given contributions made by the given contributor.
+// Contributors are compared case-insensitively.
+func ByContributor(contributions []Contribution, contributor string) []Contribution {
+	var filtered []Contribution
+	for _, c := range contributions {
+		if strings.EqualFold(c.Contributor(), contributor) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// TopContributors returns up to n contributors with the most contributions
+// within the 52 weeks ending with the given day ordered by descending number
+// of contributions.
+func TopContributors(contributions []Contribution, lastDay time.Time, n int) []string {
+	counts := ContributionsPerContributor(contributions, lastDay)
+	contributors := Keys(counts)
+	sort.Slice(contributors, func(i, j int) bool {
+		a, b := contributors[i], contributors[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	if len(contributors) > n {
+		contributors = contributors[:n]
+	}
+	return contributors
+}
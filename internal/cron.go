@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a schedule given by a cron expression.
+type Schedule struct {
+
+	// Bit sets of the minutes, hours, days of the month, months, and days of
+	// the week matched
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+
+	// Whether the days of the month or the days of the week are restricted,
+	// in which case a day matches if either of them matches (like in cron)
+	daysOfMonthRestricted, daysOfWeekRestricted bool
+}
+
+// cronField describes a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12,
+		names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dayOfWeekField = cronField{name: "day of week", min: 0, max: 7,
+		names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cronDescriptors are the supported shorthands for cron expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses the given cron expression consisting of the five
+// fields minute, hour, day of month, month, and day of week. Fields support
+// wildcards, lists, ranges, and steps (e.g., '*/15' or '1-5'), months and days
+// of the week can be given by their three-letter English names. The
+// descriptors '@yearly', '@monthly', '@weekly', '@daily', and '@hourly' are
+// supported as well.
+func ParseSchedule(expression string) (*Schedule, error) {
+	expr := strings.TrimSpace(expression)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression '%s' must consist of 5 fields but has %d", expression, len(fields))
+	}
+	var s Schedule
+	var err error
+	if s.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.daysOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// Sunday can be given as 0 or 7
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek |= 1
+	}
+	s.daysOfMonthRestricted = !strings.HasPrefix(fields[2], "*")
+	s.daysOfWeekRestricted = !strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+// parse parses the given value of the field into a bit set of the matched
+// values.
+func (f cronField) parse(value string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(value, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%s' in %s field '%s'", stepStr, f.name, value)
+			}
+		}
+		var from, to int
+		switch {
+		case rng == "*":
+			from, to = f.min, f.max
+		default:
+			lower, upper, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = f.value(lower); err != nil {
+				return 0, err
+			}
+			to = from
+			if isRange {
+				if to, err = f.value(upper); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				to = f.max
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid range '%s' in %s field '%s'", rng, f.name, value)
+			}
+		}
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value of the field given as number or name.
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s '%s' (must be between %d and %d)", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// matchesDay returns true iff the schedule matches the day of the given time.
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := s.daysOfMonth&(1<<t.Day()) != 0
+	dow := s.daysOfWeek&(1<<t.Weekday()) != 0
+	if s.daysOfMonthRestricted && s.daysOfWeekRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Next returns the first point in time matched by the schedule after the
+// given time. Returns the zero time if the schedule never matches (e.g., on
+// February 30th).
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// Every matching time recurs within 4 years (leap days)
+	limit := t.AddDate(4, 0, 1)
+	for t.Before(limit) {
+		switch {
+		case s.months&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hours&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Cron schedules", func() {
+
+	// next returns the next time matched by the given expression after the
+	// given time in UTC.
+	next := func(expression string, after string) time.Time {
+		s, err := ParseSchedule(expression)
+		Expect(err).NotTo(HaveOccurred())
+		t, err := time.Parse("2006-01-02 15:04", after)
+		Expect(err).NotTo(HaveOccurred())
+		return s.Next(t)
+	}
+
+	// at returns the given time in UTC.
+	at := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", s)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	It("matches wildcards, lists, ranges, and steps", func() {
+		Expect(next("* * * * *", "2023-04-01 12:00")).To(Equal(at("2023-04-01 12:01")))
+		Expect(next("*/15 * * * *", "2023-04-01 12:05")).To(Equal(at("2023-04-01 12:15")))
+		Expect(next("0 6,18 * * *", "2023-04-01 12:00")).To(Equal(at("2023-04-01 18:00")))
+		Expect(next("30 2 * * 1-5", "2023-04-01 12:00")).To(Equal(at("2023-04-03 02:30")))
+		Expect(next("0 0 1 jan *", "2023-04-01 12:00")).To(Equal(at("2024-01-01 00:00")))
+	})
+
+	It("supports descriptors", func() {
+		Expect(next("@daily", "2023-04-01 12:00")).To(Equal(at("2023-04-02 00:00")))
+		Expect(next("@weekly", "2023-04-01 12:00")).To(Equal(at("2023-04-02 00:00")))
+		Expect(next("@hourly", "2023-04-01 12:00")).To(Equal(at("2023-04-01 13:00")))
+	})
+
+	It("matches days given by the day of the month or the day of the week", func() {
+		Expect(next("0 0 13 * fri", "2023-04-01 12:00")).To(Equal(at("2023-04-07 00:00")))
+		Expect(next("0 0 * * 7", "2023-04-01 12:00")).To(Equal(at("2023-04-02 00:00")))
+	})
+
+	It("matches leap days", func() {
+		Expect(next("0 0 29 2 *", "2023-04-01 12:00")).To(Equal(at("2024-02-29 00:00")))
+	})
+
+	It("never matches impossible days", func() {
+		Expect(next("0 0 30 2 *", "2023-04-01 12:00")).To(BeZero())
+	})
+
+	It("rejects invalid expressions", func() {
+		for _, expression := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+			_, err := ParseSchedule(expression)
+			Expect(err).To(HaveOccurred(), expression)
+		}
+	})
+})
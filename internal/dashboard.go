@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"time"
+)
+
+// LeaderboardEntry is a contributor ranked by the number of contributions.
+type LeaderboardEntry struct {
+
+	// The rank of the contributor starting with 1.
+	Rank int
+
+	// The login or name of the contributor.
+	Contributor string
+
+	// The number of contributions made by the contributor.
+	Contributions int
+}
+
+// DashboardLink is a navigation link of a dashboard, e.g., to another page of
+// a static site.
+type DashboardLink struct {
+
+	// The text of the link.
+	Label string
+
+	// The relative or absolute URL of the link target.
+	URL string
+}
+
+// Dashboard is a self-contained HTML page combining the contribution graph,
+// the contribution trend, a leaderboard of the most active contributors, and
+// the summary statistics.
+type Dashboard struct {
+
+	// The title of the page.
+	Title string
+
+	// The heading of the navigation links.
+	LinksTitle string
+
+	// The navigation links rendered beneath the summary statistics.
+	Links []DashboardLink
+
+	// The summary statistics.
+	Summary *Summary
+
+	// The most active contributors.
+	Leaderboard []LeaderboardEntry
+
+	// The inline SVG document of the contribution graph. Omitted if empty.
+	Heatmap template.HTML
+
+	// The inline SVG document of the trend chart. Omitted if empty.
+	Trend template.HTML
+}
+
+// NewDashboard creates a Dashboard with the given title for the given
+// contributions made within the 52 weeks ending with the given day. The
+// leaderboard lists up to top contributors. The charts are left empty.
+func NewDashboard(title string, contributions []Contribution, lastDay time.Time, top int) *Dashboard {
+	counts := ContributionsPerContributor(contributions, lastDay)
+	dashboard := &Dashboard{
+		Title:   title,
+		Summary: NewSummary(contributions, lastDay),
+	}
+	for i, contributor := range TopContributors(contributions, lastDay, top) {
+		dashboard.Leaderboard = append(dashboard.Leaderboard, LeaderboardEntry{
+			Rank:          i + 1,
+			Contributor:   contributor,
+			Contributions: counts[contributor],
+		})
+	}
+	return dashboard
+}
+
+var (
+	// The embedded template used for rendering dashboards.
+	//go:embed dashboard.gohtml
+	dashboardTemplate string
+)
+
+// ParseDashboardTemplate parses the given template for rendering dashboards.
+// The default template is used if the given one is empty.
+func ParseDashboardTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = dashboardTemplate
+	}
+	return template.New("dashboard").Parse(text)
+}
+
+// HTML renders the dashboard as a standalone HTML document.
+func (d *Dashboard) HTML() (string, error) {
+	tmpl, err := ParseDashboardTemplate("")
+	if err != nil {
+		return "", err
+	}
+	return d.Render(tmpl)
+}
+
+// Render renders the dashboard using the given template.
+func (d *Dashboard) Render(tmpl *template.Template) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"html/template"
+)
+
+var _ = Describe("Dashboards", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contributions := []Contribution{
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Login: "alice", Date: lastDay},
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Login: "alice", Date: lastDay.AddDate(0, 0, -1)},
+		{Type: IssueContribution, Repository: "herdstat/action", Login: "bob", Date: lastDay.AddDate(0, 0, -2)},
+		{Type: IssueContribution, Repository: "herdstat/action", Login: "<carol>", Date: lastDay.AddDate(0, 0, -3)},
+	}
+	dashboard := NewDashboard("Herdstat", contributions, lastDay, 2)
+
+	It("ranks the most active contributors", func() {
+		Expect(dashboard.Leaderboard).To(Equal([]LeaderboardEntry{
+			{Rank: 1, Contributor: "alice", Contributions: 2},
+			{Rank: 2, Contributor: "<carol>", Contributions: 1},
+		}))
+	})
+
+	It("renders a standalone HTML document", func() {
+		dashboard.Heatmap = template.HTML(`<svg class="heatmap"></svg>`)
+		html, err := dashboard.HTML()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(HavePrefix("<!DOCTYPE html>"))
+		Expect(html).To(ContainSubstring("<title>Herdstat</title>"))
+		Expect(html).To(ContainSubstring("prefers-color-scheme: dark"))
+		Expect(html).To(ContainSubstring(`<svg class="heatmap"></svg>`))
+		Expect(html).To(ContainSubstring("&lt;carol&gt;"))
+		Expect(html).NotTo(ContainSubstring("<h2>Trend</h2>"))
+	})
+})
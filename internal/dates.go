@@ -19,3 +19,17 @@ func previousSunday(date time.Time) time.Time {
 func DaysBetween(a, b time.Time) int {
 	return int(b.Sub(a).Hours() / 24)
 }
+
+// sameDay returns true iff the given times fall on the same calendar day.
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// CalendarDaysBetween computes the number of calendar days between two days
+// ignoring the time of day (and daylight saving time transitions). The
+// calendar day of each date is the one in its location.
+func CalendarDaysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
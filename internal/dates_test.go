@@ -54,3 +54,28 @@ var _ = Describe("Computing the number of days between two days", func() {
 		})
 	})
 })
+
+var _ = Describe("Checking whether two times fall on the same day", func() {
+	When("given two times on the same day", func() {
+		It("returns true", func() {
+			a := dateparse.MustParse("2023-01-15")
+			Expect(sameDay(a, a.Add(23*time.Hour))).To(BeTrue())
+		})
+	})
+	When("given the same day in different years", func() {
+		It("returns false", func() {
+			a := dateparse.MustParse("2023-01-15")
+			Expect(sameDay(a, a.AddDate(1, 0, 0))).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Computing the number of calendar days between two days", func() {
+	When("the later time of day is earlier than the former one", func() {
+		It("counts calendar days", func() {
+			a := time.Date(2023, time.January, 15, 23, 0, 0, 0, time.UTC)
+			b := time.Date(2023, time.January, 17, 1, 0, 0, 0, time.UTC)
+			Expect(CalendarDaysBetween(a, b)).To(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"time"
+)
+
+// DigestItem is an issue, pull request, or release listed in a digest.
+type DigestItem struct {
+
+	// The repository of the item in 'owner/name' notation.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// The title of the item (e.g., the issue title or the release name).
+	Title string `json:"title" yaml:"title"`
+
+	// The URL of the web page of the item.
+	URL string `json:"url" yaml:"url"`
+
+	// The login of the author of the item.
+	Author string `json:"author" yaml:"author"`
+
+	// The point in time of the event the item is listed for (e.g., the
+	// merge of a pull request).
+	Date time.Time `json:"date" yaml:"date"`
+}
+
+// ContributorCount is the number of contributions of a contributor.
+type ContributorCount struct {
+
+	// The identifier of the contributor.
+	Contributor string `json:"contributor" yaml:"contributor"`
+
+	// The number of contributions.
+	Contributions int `json:"contributions" yaml:"contributions"`
+}
+
+// Digest summarizes the activity within a week or month for posting to
+// discussion forums or mailing lists.
+type Digest struct {
+
+	// The first day of the digest period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the digest period.
+	Until string `json:"until" yaml:"until"`
+
+	// The contributors with the most contributions ordered by descending
+	// number of contributions.
+	TopContributors []ContributorCount `json:"topContributors" yaml:"topContributors"`
+
+	// The pull requests merged within the digest period in chronological
+	// order.
+	MergedPullRequests []DigestItem `json:"mergedPullRequests" yaml:"mergedPullRequests"`
+
+	// The issues opened within the digest period in chronological order.
+	NewIssues []DigestItem `json:"newIssues" yaml:"newIssues"`
+
+	// The releases published within the digest period in chronological
+	// order.
+	Releases []DigestItem `json:"releases" yaml:"releases"`
+
+	// The URL of the contribution graph embedded into the digest. Nothing is
+	// embedded if empty.
+	Heatmap string `json:"heatmap,omitempty" yaml:"heatmap,omitempty"`
+}
+
+// DigestStart returns the first day of the digest period of the given
+// granularity ending with the given day.
+func DigestStart(lastDay time.Time, granularity Granularity) time.Time {
+	start := lastDay.AddDate(0, 0, -6)
+	switch granularity {
+	case MonthlyGranularity:
+		start = lastDay.AddDate(0, -1, 1)
+	case DailyGranularity:
+		start = lastDay
+	}
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, lastDay.Location())
+}
+
+// inDigestPeriod returns true iff the given date lies within the given
+// digest period.
+func inDigestPeriod(date time.Time, firstDay time.Time, lastDay time.Time) bool {
+	return !date.Before(firstDay) && !date.After(lastDay)
+}
+
+// digestItems returns the given items within the given digest period in
+// chronological order.
+func digestItems(items []DigestItem, firstDay time.Time, lastDay time.Time) []DigestItem {
+	var filtered []DigestItem
+	for _, item := range items {
+		if inDigestPeriod(item.Date, firstDay, lastDay) {
+			filtered = append(filtered, item)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Date.Before(filtered[j].Date)
+	})
+	return filtered
+}
+
+// NewDigest creates the Digest of the period from the given first day until
+// the given last day listing up to top contributors.
+func NewDigest(firstDay time.Time, lastDay time.Time, contributions []Contribution, mergedPullRequests []DigestItem,
+	newIssues []DigestItem, releases []DigestItem, top int, heatmap string) *Digest {
+	digest := &Digest{
+		From:               firstDay.Format(dateFormat),
+		Until:              lastDay.Format(dateFormat),
+		MergedPullRequests: digestItems(mergedPullRequests, firstDay, lastDay),
+		NewIssues:          digestItems(newIssues, firstDay, lastDay),
+		Releases:           digestItems(releases, firstDay, lastDay),
+		Heatmap:            heatmap,
+	}
+	counts := make(map[string]int)
+	for _, c := range contributions {
+		if inDigestPeriod(c.Date, firstDay, lastDay) {
+			counts[c.Contributor()]++
+		}
+	}
+	contributors := Keys(counts)
+	sort.Slice(contributors, func(i, j int) bool {
+		a, b := contributors[i], contributors[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	for i, contributor := range contributors {
+		if i >= top {
+			break
+		}
+		digest.TopContributors = append(digest.TopContributors, ContributorCount{
+			Contributor:   contributor,
+			Contributions: counts[contributor],
+		})
+	}
+	return digest
+}
+
+var (
+	// The embedded template used for rendering digests as markdown.
+	//go:embed digest.gomd
+	digestTemplate string
+)
+
+// Markdown renders the digest as a markdown newsletter.
+func (d *Digest) Markdown() (string, error) {
+	return renderMarkdown("digest", digestTemplate, d)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Digests", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+
+	It("covers the last seven days for weekly digests", func() {
+		Expect(DigestStart(lastDay, WeeklyGranularity)).To(Equal(dateparse.MustParse("2023-03-09")))
+	})
+
+	It("covers the last day for daily digests", func() {
+		Expect(DigestStart(lastDay, DailyGranularity)).To(Equal(dateparse.MustParse("2023-03-15")))
+	})
+
+	It("covers the last month for monthly digests", func() {
+		Expect(DigestStart(lastDay, MonthlyGranularity)).To(Equal(dateparse.MustParse("2023-02-16")))
+	})
+
+	When("there was activity", func() {
+		firstDay := DigestStart(lastDay, WeeklyGranularity)
+		contributions := []Contribution{
+			{Login: "alice", Date: dateparse.MustParse("2023-03-10")},
+			{Login: "alice", Date: dateparse.MustParse("2023-03-11")},
+			{Login: "bob", Date: dateparse.MustParse("2023-03-12")},
+			// Before the digest period
+			{Login: "bob", Date: dateparse.MustParse("2023-03-01")},
+			{Login: "bob", Date: dateparse.MustParse("2023-03-02")},
+		}
+		merged := []DigestItem{
+			{Repository: "herdstat/herdstat", Title: "Add digest", URL: "https://github.com/herdstat/herdstat/pull/2",
+				Author: "alice", Date: dateparse.MustParse("2023-03-14")},
+			{Repository: "herdstat/herdstat", Title: "Fix typo", URL: "https://github.com/herdstat/herdstat/pull/1",
+				Author: "bob", Date: dateparse.MustParse("2023-03-10")},
+			// Before the digest period
+			{Repository: "herdstat/herdstat", Title: "Old", Date: dateparse.MustParse("2023-03-01")},
+		}
+		digest := NewDigest(firstDay, lastDay, contributions, merged, nil, nil, 1, "https://herdstat.github.io/graph.svg")
+
+		It("lists the top contributors within the digest period", func() {
+			Expect(digest.TopContributors).To(Equal([]ContributorCount{{Contributor: "alice", Contributions: 2}}))
+		})
+
+		It("lists the items within the digest period in chronological order", func() {
+			Expect(digest.MergedPullRequests).To(HaveLen(2))
+			Expect(digest.MergedPullRequests[0].Title).To(Equal("Fix typo"))
+		})
+
+		It("renders a markdown newsletter", func() {
+			md, err := digest.Markdown()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(md).To(ContainSubstring("## Activity Digest 2023-03-09 – 2023-03-15"))
+			Expect(md).To(ContainSubstring("![Contributions](https://herdstat.github.io/graph.svg)"))
+			Expect(md).To(ContainSubstring("- [Fix typo](https://github.com/herdstat/herdstat/pull/1) in herdstat/herdstat by bob"))
+			Expect(md).To(ContainSubstring("No releases."))
+		})
+	})
+
+	When("titles contain markdown", func() {
+		issues := []DigestItem{
+			{Repository: "herdstat/herdstat", Title: "Crash on [x] |\nnext", URL: "https://github.com/herdstat/herdstat/issues/3",
+				Author: "eve", Date: lastDay},
+		}
+		digest := NewDigest(DigestStart(lastDay, WeeklyGranularity), lastDay, nil, nil, issues, nil, 1, "")
+
+		It("escapes them without mentioning the authors", func() {
+			md, err := digest.Markdown()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(md).To(ContainSubstring(`- [Crash on \[x\] \| next](https://github.com/herdstat/herdstat/issues/3) in herdstat/herdstat by eve`))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"time"
+)
+
+// ElephantFactor describes how concentrated the contributions to a repository
+// (or a set of repositories) are among the organizations the contributors are
+// affiliated with.
+type ElephantFactor struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall elephant
+	// factor.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The overall number of contributions.
+	Contributions int `json:"contributions" yaml:"contributions"`
+
+	// The number of contributions by contributors without known affiliation.
+	Unaffiliated int `json:"unaffiliated" yaml:"unaffiliated"`
+
+	// The number of distinct organizations.
+	Organizations int `json:"organizations" yaml:"organizations"`
+
+	// The minimal number of organizations accounting for 50% of the
+	// affiliated contributions.
+	Factor int `json:"factor" yaml:"factor"`
+}
+
+// ElephantFactorReport contains the elephant factors per repository and
+// overall.
+type ElephantFactorReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The elephant factor across all repositories.
+	Overall ElephantFactor `json:"overall" yaml:"overall"`
+
+	// The elephant factors of the individual repositories sorted by ascending
+	// elephant factor.
+	Repositories []ElephantFactor `json:"repositories" yaml:"repositories"`
+}
+
+// newElephantFactor computes the elephant factor of the given contributions.
+func newElephantFactor(repository string, contributions []Contribution, lastDay time.Time, affiliations *Affiliations) ElephantFactor {
+	factor := ElephantFactor{Repository: repository}
+	counts := make(map[string]int)
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		factor.Contributions++
+		if organization := affiliations.Organization(c); organization != "" {
+			counts[organization]++
+		} else {
+			factor.Unaffiliated++
+		}
+	}
+	factor.Organizations = len(counts)
+	factor.Factor = coveringCount(counts, 0.5)
+	return factor
+}
+
+// NewElephantFactorReport computes the elephant factors of the given
+// contributions made within the 52 weeks ending with the given day.
+func NewElephantFactorReport(contributions []Contribution, lastDay time.Time, affiliations *Affiliations) *ElephantFactorReport {
+	report := &ElephantFactorReport{
+		From:    lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:   lastDay.Format(dateFormat),
+		Overall: newElephantFactor("", contributions, lastDay, affiliations),
+	}
+	for repository, c := range GroupByRepository(contributions) {
+		report.Repositories = append(report.Repositories, newElephantFactor(repository, c, lastDay, affiliations))
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		a, b := report.Repositories[i], report.Repositories[j]
+		if a.Factor != b.Factor {
+			return a.Factor < b.Factor
+		}
+		return a.Repository < b.Repository
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering elephant factor reports as
+	// markdown.
+	//go:embed elephant-factor.gomd
+	elephantFactorTemplate string
+)
+
+// Markdown renders the elephant factor report as markdown.
+func (r *ElephantFactorReport) Markdown() (string, error) {
+	return renderMarkdown("elephant-factor", elephantFactorTemplate, r)
+}
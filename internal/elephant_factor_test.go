@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolving affiliations", func() {
+	affiliations := NewAffiliations([]Affiliation{
+		{Organization: "Acme", Domains: []string{"acme.io"}, Contributors: []string{"jdoe"}},
+	})
+
+	It("prefers explicitly mapped contributors", func() {
+		Expect(affiliations.Organization(Contribution{Login: "JDoe"})).To(Equal("Acme"))
+	})
+	It("maps email domains", func() {
+		Expect(affiliations.Organization(Contribution{Email: "jane@ACME.io"})).To(Equal("Acme"))
+	})
+	It("falls back to the email domain", func() {
+		Expect(affiliations.Organization(Contribution{Email: "jane@herdstat.com"})).To(Equal("herdstat.com"))
+	})
+	It("ignores public email providers", func() {
+		Expect(affiliations.Organization(Contribution{Email: "jane@gmail.com"})).To(BeEmpty())
+		Expect(affiliations.Organization(Contribution{Email: "1+jane@users.noreply.github.com"})).To(BeEmpty())
+	})
+	It("doesn't affiliate unmapped logins", func() {
+		Expect(affiliations.Organization(Contribution{Login: "jroe"})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Computing elephant factors", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	affiliations := NewAffiliations([]Affiliation{
+		{Organization: "Acme", Contributors: []string{"jdoe"}},
+	})
+	var contributions []Contribution
+	for i := 0; i < 6; i++ {
+		contributions = append(contributions, Contribution{Repository: "herdstat/a", Login: "jdoe", Date: lastDay})
+	}
+	for i := 0; i < 4; i++ {
+		contributions = append(contributions,
+			Contribution{Repository: "herdstat/b", Email: "jane@herdstat.com", Date: lastDay},
+			Contribution{Repository: "herdstat/b", Email: "mary@example.org", Date: lastDay})
+	}
+	contributions = append(contributions, Contribution{Repository: "herdstat/b", Login: "jroe", Date: lastDay})
+	report := NewElephantFactorReport(contributions, lastDay, affiliations)
+
+	It("computes the elephant factors per repository", func() {
+		Expect(report.Repositories).To(Equal([]ElephantFactor{
+			{Repository: "herdstat/a", Contributions: 6, Organizations: 1, Factor: 1},
+			{Repository: "herdstat/b", Contributions: 9, Unaffiliated: 1, Organizations: 2, Factor: 1},
+		}))
+	})
+	It("computes the overall elephant factor", func() {
+		Expect(report.Overall).To(Equal(ElephantFactor{Contributions: 15, Unaffiliated: 1, Organizations: 3, Factor: 2}))
+	})
+	It("renders as markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| **Overall** | 15 | 1 | 3 | 2 |"))
+	})
+})
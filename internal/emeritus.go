@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"strings"
+	"time"
+)
+
+// MaintainerActivity describes the recent activity of a maintainer.
+type MaintainerActivity struct {
+
+	// The login of the maintainer.
+	Maintainer string `json:"maintainer" yaml:"maintainer"`
+
+	// The number of contributions within the analyzed period.
+	Contributions int `json:"contributions" yaml:"contributions"`
+
+	// The day of the latest contribution within the analyzed period. Empty if
+	// there is none.
+	LastContribution string `json:"lastContribution,omitempty" yaml:"lastContribution,omitempty"`
+
+	// Whether the maintainer made no contributions within the analyzed period.
+	Inactive bool `json:"inactive" yaml:"inactive"`
+}
+
+// EmeritusReport flags maintainers without recent contributions as candidates
+// for emeritus status.
+type EmeritusReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The number of inactive maintainers.
+	Inactive int `json:"inactive" yaml:"inactive"`
+
+	// The activity of the maintainers in the order they are given.
+	Maintainers []MaintainerActivity `json:"maintainers" yaml:"maintainers"`
+}
+
+// NewEmeritusReport determines which of the given maintainers made none of
+// the given contributions after since until the given day. Maintainers are
+// identified by their logins, so commits are attributed to them only if their
+// logins are known.
+func NewEmeritusReport(contributions []Contribution, maintainers []string, since time.Time, lastDay time.Time) *EmeritusReport {
+	report := &EmeritusReport{
+		From:  since.Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	for _, maintainer := range maintainers {
+		activity := MaintainerActivity{Maintainer: maintainer}
+		var last time.Time
+		for _, c := range contributions {
+			if !strings.EqualFold(c.Login, maintainer) || c.Date.Before(since) || c.Date.After(lastDay) {
+				continue
+			}
+			activity.Contributions++
+			if c.Date.After(last) {
+				last = c.Date
+			}
+		}
+		if activity.Contributions == 0 {
+			activity.Inactive = true
+			report.Inactive++
+		} else {
+			activity.LastContribution = last.Format(dateFormat)
+		}
+		report.Maintainers = append(report.Maintainers, activity)
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering emeritus reports as markdown.
+	//go:embed emeritus.gomd
+	emeritusTemplate string
+)
+
+// Markdown renders the emeritus report as markdown.
+func (r *EmeritusReport) Markdown() (string, error) {
+	return renderMarkdown("emeritus", emeritusTemplate, r)
+}
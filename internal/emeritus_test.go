@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emeritus reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	since := lastDay.AddDate(0, -6, 0)
+	contributions := []Contribution{
+		{Repository: "herdstat/herdstat", Login: "alice", Date: dateparse.MustParse("2023-01-10 12:00")},
+		{Repository: "herdstat/action", Login: "Alice", Date: dateparse.MustParse("2023-03-01 12:00")},
+		{Repository: "herdstat/herdstat", Login: "bob", Date: dateparse.MustParse("2022-08-01 12:00")},
+	}
+	report := NewEmeritusReport(contributions, []string{"alice", "bob", "carol"}, since, lastDay)
+
+	It("flags maintainers without contributions", func() {
+		Expect(report.From).To(Equal("2022-09-15"))
+		Expect(report.Inactive).To(Equal(2))
+		Expect(report.Maintainers).To(Equal([]MaintainerActivity{
+			{Maintainer: "alice", Contributions: 2, LastContribution: "2023-03-01"},
+			{Maintainer: "bob", Inactive: true},
+			{Maintainer: "carol", Inactive: true},
+		}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| alice | 2 | 2023-03-01 | active |"))
+		Expect(md).To(ContainSubstring("| bob | 0 | - | inactive |"))
+	})
+})
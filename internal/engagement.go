@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"time"
+)
+
+// engagementBuckets are the classes of contributors by number of
+// contributions. Each bucket covers the contributors with at least min and at
+// most max contributions. A max of 0 denotes an open upper bound.
+var engagementBuckets = []struct {
+	name     string
+	min, max int
+}{
+	{"drive-by", 1, 1},
+	{"occasional", 2, 5},
+	{"regular", 6, 0},
+}
+
+// EngagementBucket is the number of contributors within a range of
+// contribution counts.
+type EngagementBucket struct {
+
+	// The name of the bucket ('drive-by', 'occasional', or 'regular').
+	Name string `json:"name" yaml:"name"`
+
+	// The minimal number of contributions of the contributors in the bucket.
+	Min int `json:"min" yaml:"min"`
+
+	// The maximal number of contributions of the contributors in the bucket.
+	// Zero if unbounded.
+	Max int `json:"max,omitempty" yaml:"max,omitempty"`
+
+	// The number of contributors in the bucket.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The share of contributors in the bucket in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+
+	// The number of contributions made by the contributors in the bucket.
+	Contributions int `json:"contributions" yaml:"contributions"`
+}
+
+// EngagementReport describes the distribution of contributors by number of
+// contributions, i.e., whether drive-by contributors are converted into
+// regulars.
+type EngagementReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The number of distinct contributors.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The contributors classified by number of contributions.
+	Buckets []EngagementBucket `json:"buckets" yaml:"buckets"`
+}
+
+// NewEngagementReport classifies the contributors by the number of the given
+// contributions they made within the 52 weeks ending with the given day.
+func NewEngagementReport(contributions []Contribution, lastDay time.Time) *EngagementReport {
+	report := &EngagementReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	for _, b := range engagementBuckets {
+		report.Buckets = append(report.Buckets, EngagementBucket{Name: b.name, Min: b.min, Max: b.max})
+	}
+	counts := ContributionsPerContributor(contributions, lastDay)
+	report.Contributors = len(counts)
+	for _, count := range counts {
+		for i := range report.Buckets {
+			bucket := &report.Buckets[i]
+			if count >= bucket.Min && (bucket.Max == 0 || count <= bucket.Max) {
+				bucket.Contributors++
+				bucket.Contributions += count
+				break
+			}
+		}
+	}
+	for i := range report.Buckets {
+		report.Buckets[i].Percentage = percentage(report.Buckets[i].Contributors, report.Contributors)
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering engagement reports as
+	// markdown.
+	//go:embed engagement.gomd
+	engagementTemplate string
+)
+
+// Markdown renders the engagement report as markdown.
+func (r *EngagementReport) Markdown() (string, error) {
+	return renderMarkdown("engagement", engagementTemplate, r)
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Engagement reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	date := dateparse.MustParse("2023-03-01 12:00")
+	var contributions []Contribution
+	for login, count := range map[string]int{"alice": 8, "bob": 1, "carol": 1, "dave": 2, "erin": 5} {
+		for i := 0; i < count; i++ {
+			contributions = append(contributions, Contribution{Repository: "herdstat/herdstat", Login: login, Date: date})
+		}
+	}
+	// Outside the analyzed period
+	contributions = append(contributions, Contribution{Repository: "herdstat/herdstat", Login: "bob",
+		Date: dateparse.MustParse("2021-03-01 12:00")})
+	report := NewEngagementReport(contributions, lastDay)
+
+	It("classifies the contributors by number of contributions", func() {
+		Expect(report.Contributors).To(Equal(5))
+		Expect(report.Buckets).To(Equal([]EngagementBucket{
+			{Name: "drive-by", Min: 1, Max: 1, Contributors: 2, Percentage: 40, Contributions: 2},
+			{Name: "occasional", Min: 2, Max: 5, Contributors: 2, Percentage: 40, Contributions: 7},
+			{Name: "regular", Min: 6, Contributors: 1, Percentage: 20, Contributions: 8},
+		}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| drive-by | 1 | 2 | 40.0% | 2 |"))
+		Expect(md).To(ContainSubstring("| occasional | 2–5 | 2 | 40.0% | 7 |"))
+		Expect(md).To(ContainSubstring("| regular | 6+ | 1 | 20.0% | 8 |"))
+	})
+})
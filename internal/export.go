@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AggregateRecord is the number of contributions made to a repository within
+// a single bucket, i.e., a day, week, or month.
+type AggregateRecord struct {
+
+	// The first day of the bucket in '2006-01-02' notation.
+	Date string `json:"date" yaml:"date"`
+
+	// The repository in 'owner/name' notation.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// The number of commits.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The number of opened issues.
+	Issues int `json:"issues" yaml:"issues"`
+
+	// The number of opened pull requests.
+	PullRequests int `json:"pullRequests" yaml:"pullRequests"`
+
+	// The number of distinct contributors.
+	Contributors int `json:"contributors" yaml:"contributors"`
+}
+
+// AggregateExport contains the contribution activity as aggregate counts
+// only. It contains neither logins, names, nor email addresses and is thus
+// suitable for publishing by privacy-sensitive organizations.
+type AggregateExport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The length of the buckets contributions are aggregated over (one of
+	// 'day', 'week', or 'month').
+	Bucket string `json:"bucket" yaml:"bucket"`
+
+	// The overall number of distinct contributors.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The records per bucket and repository of buckets with at least one
+	// contribution sorted by bucket and repository. The first week or month
+	// may start before the analyzed period.
+	Records []AggregateRecord `json:"records" yaml:"records"`
+}
+
+// NewAggregateExport aggregates the given contributions made within the 52
+// weeks ending with the given day into counts per repository and bucket of the
+// given granularity.
+func NewAggregateExport(contributions []Contribution, lastDay time.Time, granularity Granularity) *AggregateExport {
+	export := &AggregateExport{
+		From:   lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:  lastDay.Format(dateFormat),
+		Bucket: granularity.noun(),
+	}
+	type key struct {
+		date       string
+		repository string
+	}
+	records := make(map[key]*AggregateRecord)
+	contributors := make(map[key]map[string]bool)
+	all := make(map[string]bool)
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		k := key{date: granularity.periodStart(c.Date).Format(dateFormat), repository: c.Repository}
+		record, ok := records[k]
+		if !ok {
+			record = &AggregateRecord{Date: k.date, Repository: k.repository}
+			records[k] = record
+			contributors[k] = make(map[string]bool)
+		}
+		switch c.Type {
+		case CommitContribution:
+			record.Commits++
+		case IssueContribution:
+			record.Issues++
+		case PullRequestContribution:
+			record.PullRequests++
+		}
+		contributors[k][c.Contributor()] = true
+		all[c.Contributor()] = true
+	}
+	export.Contributors = len(all)
+	for k, record := range records {
+		record.Contributors = len(contributors[k])
+		export.Records = append(export.Records, *record)
+	}
+	sort.Slice(export.Records, func(i, j int) bool {
+		a, b := export.Records[i], export.Records[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.Repository < b.Repository
+	})
+	return export
+}
+
+// CSV renders the aggregate records as CSV records.
+func (e *AggregateExport) CSV() [][]string {
+	records := [][]string{{"date", "repository", "commits", "issues", "pullRequests", "contributors"}}
+	for _, r := range e.Records {
+		records = append(records, []string{
+			r.Date,
+			r.Repository,
+			strconv.Itoa(r.Commits),
+			strconv.Itoa(r.Issues),
+			strconv.Itoa(r.PullRequests),
+			strconv.Itoa(r.Contributors),
+		})
+	}
+	return records
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/json"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Aggregate exports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contributions := []Contribution{
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Name: "Alice", Email: "alice@herdstat.com",
+			Date: dateparse.MustParse("2023-03-01 10:00")},
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Name: "Alice", Email: "alice@herdstat.com",
+			Date: dateparse.MustParse("2023-03-01 12:00")},
+		{Type: PullRequestContribution, Repository: "herdstat/herdstat", Login: "bob",
+			Date: dateparse.MustParse("2023-03-01 14:00")},
+		{Type: IssueContribution, Repository: "herdstat/action", Login: "bob",
+			Date: dateparse.MustParse("2023-02-01 14:00")},
+		// Outside the analyzed period
+		{Type: IssueContribution, Repository: "herdstat/action", Login: "carol",
+			Date: dateparse.MustParse("2021-02-01 14:00")},
+	}
+	export := NewAggregateExport(contributions, lastDay, DailyGranularity)
+
+	It("aggregates the contributions per day and repository", func() {
+		Expect(export.Contributors).To(Equal(2))
+		Expect(export.Records).To(Equal([]AggregateRecord{
+			{Date: "2023-02-01", Repository: "herdstat/action", Issues: 1, Contributors: 1},
+			{Date: "2023-03-01", Repository: "herdstat/herdstat", Commits: 2, PullRequests: 1, Contributors: 2},
+		}))
+	})
+
+	It("aggregates the contributions per month and repository", func() {
+		monthly := NewAggregateExport(contributions, lastDay, MonthlyGranularity)
+		Expect(monthly.Bucket).To(Equal(MonthBucketName))
+		Expect(monthly.Records).To(Equal([]AggregateRecord{
+			{Date: "2023-02-01", Repository: "herdstat/action", Issues: 1, Contributors: 1},
+			{Date: "2023-03-01", Repository: "herdstat/herdstat", Commits: 2, PullRequests: 1, Contributors: 2},
+		}))
+	})
+
+	It("aggregates the contributions per week and repository", func() {
+		weekly := NewAggregateExport(contributions, lastDay, WeeklyGranularity)
+		Expect(weekly.Bucket).To(Equal(WeekBucketName))
+		Expect(weekly.Records[0].Date).To(Equal("2023-01-29"))
+		Expect(weekly.Records[1].Date).To(Equal("2023-02-26"))
+	})
+
+	It("doesn't expose any contributor", func() {
+		data, err := json.Marshal(export)
+		Expect(err).NotTo(HaveOccurred())
+		for _, s := range []string{"alice", "Alice", "bob"} {
+			Expect(string(data)).NotTo(ContainSubstring(s))
+		}
+	})
+
+	It("renders CSV", func() {
+		Expect(export.CSV()[2]).To(Equal([]string{"2023-03-01", "herdstat/herdstat", "2", "0", "1", "2"}))
+	})
+})
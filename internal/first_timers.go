@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FirstTimer is a contributor whose first contribution was made within the
+// analyzed period.
+type FirstTimer struct {
+
+	// The GitHub login of the contributor. Empty for commit authors whose
+	// login couldn't be resolved.
+	Login string `json:"login,omitempty" yaml:"login,omitempty"`
+
+	// The name of the contributor as given in the commit metadata. Empty for
+	// contributors with a login.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// The URL of the GitHub profile of the contributor. Empty for commit
+	// authors whose login couldn't be resolved.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// The kind of the first contribution.
+	Type ContributionType `json:"type" yaml:"type"`
+
+	// The repository the first contribution was made to in 'owner/name'
+	// notation.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// The day of the first contribution.
+	Date string `json:"date" yaml:"date"`
+
+	// The URL of the first contribution.
+	URL string `json:"url" yaml:"url"`
+}
+
+// FirstTimersReport lists the contributors whose first contribution was made
+// within the analyzed period.
+type FirstTimersReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The first day considered when looking for prior contributions.
+	LookbackFrom string `json:"lookbackFrom" yaml:"lookbackFrom"`
+
+	// The first-time contributors in the order of their first contribution.
+	Contributors []FirstTimer `json:"contributors" yaml:"contributors"`
+}
+
+// Noun returns the human-readable name of the contribution type.
+func (t ContributionType) Noun() string {
+	return strings.ReplaceAll(string(t), "-", " ")
+}
+
+// NewFirstTimersReport lists the contributors whose first contribution since
+// the given lookback date was made within the 52 weeks ending with the given
+// day.
+func NewFirstTimersReport(contributions []Contribution, lastDay time.Time, lookbackFrom time.Time) *FirstTimersReport {
+	report := &FirstTimersReport{
+		From:         lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:        lastDay.Format(dateFormat),
+		LookbackFrom: lookbackFrom.Format(dateFormat),
+	}
+	var firsts []Contribution
+	for _, c := range FirstContributions(contributions, lastDay, lookbackFrom) {
+		if InPeriod(c.Date, lastDay) {
+			firsts = append(firsts, c)
+		}
+	}
+	sort.Slice(firsts, func(i, j int) bool {
+		if !firsts[i].Date.Equal(firsts[j].Date) {
+			return firsts[i].Date.Before(firsts[j].Date)
+		}
+		return firsts[i].Contributor() < firsts[j].Contributor()
+	})
+	for _, c := range firsts {
+		firstTimer := FirstTimer{
+			Login:      c.Login,
+			Type:       c.Type,
+			Repository: c.Repository,
+			Date:       c.Date.Format(dateFormat),
+			URL:        c.URL,
+		}
+		if c.Login != "" {
+			firstTimer.Profile = "https://github.com/" + c.Login
+		} else {
+			firstTimer.Name = c.Name
+		}
+		report.Contributors = append(report.Contributors, firstTimer)
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering first-timer reports as
+	// markdown.
+	//go:embed first-timers.gomd
+	firstTimersTemplate string
+)
+
+// Markdown renders the first-timer report as a markdown snippet suitable for
+// release notes and community calls.
+func (r *FirstTimersReport) Markdown() (string, error) {
+	return renderMarkdown("first-timers", firstTimersTemplate, r)
+}
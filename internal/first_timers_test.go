@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("First-timer reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	lookbackFrom := dateparse.MustParse("2021-03-16")
+	contributions := []Contribution{
+		{Type: PullRequestContribution, Repository: "herdstat/herdstat", Login: "alice",
+			Date: dateparse.MustParse("2023-03-01"), URL: "https://github.com/herdstat/herdstat/pull/42"},
+		{Type: CommitContribution, Repository: "herdstat/action", Login: "alice", Date: dateparse.MustParse("2023-03-05")},
+		{Type: CommitContribution, Repository: "herdstat/action", Name: "Bob", Email: "bob@herdstat.com",
+			Date: dateparse.MustParse("2023-02-01"), URL: "https://github.com/herdstat/action/commit/abc"},
+		// Returning contributor
+		{Type: IssueContribution, Repository: "herdstat/herdstat", Login: "carol", Date: dateparse.MustParse("2021-06-01")},
+		{Type: IssueContribution, Repository: "herdstat/herdstat", Login: "carol", Date: dateparse.MustParse("2023-03-01")},
+	}
+	report := NewFirstTimersReport(contributions, lastDay, lookbackFrom)
+
+	It("lists the first-time contributors in the order of their first contribution", func() {
+		Expect(report.Contributors).To(Equal([]FirstTimer{
+			{Name: "Bob", Type: CommitContribution, Repository: "herdstat/action", Date: "2023-02-01",
+				URL: "https://github.com/herdstat/action/commit/abc"},
+			{Login: "alice", Profile: "https://github.com/alice", Type: PullRequestContribution,
+				Repository: "herdstat/herdstat", Date: "2023-03-01", URL: "https://github.com/herdstat/herdstat/pull/42"},
+		}))
+	})
+
+	It("renders a markdown snippet with links", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("welcome to the 2 people"))
+		Expect(md).To(ContainSubstring("- [@alice](https://github.com/alice) with their first pull request to " +
+			"[herdstat/herdstat](https://github.com/herdstat/herdstat/pull/42) on 2023-03-01"))
+	})
+})
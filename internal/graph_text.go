@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GraphText contains the texts of a contribution graph, e.g., to render the
+// graph in another language. Empty texts default to those of EnglishGraphText.
+type GraphText struct {
+
+	// The format of a number of contributions, e.g., "%d contributions".
+	Contributions string
+
+	// The text following the overall number of contributions.
+	LastYear string
+
+	// The format of the date of a day in its tooltip, e.g., "on %s".
+	OnDate string
+
+	// The labels of the lowest and the highest level of the legend.
+	Less string
+	More string
+
+	// The abbreviated names of the months starting with January.
+	Months [12]string
+
+	// The abbreviated names of the days of the week starting with Sunday.
+	Weekdays [7]string
+
+	// Formats dates, e.g., in tooltips. Dates are formatted like "Jan 2,
+	// 2006" using the names of the months if nil.
+	FormatDate func(date time.Time) string
+}
+
+// EnglishGraphText contains the English texts of contribution graphs.
+var EnglishGraphText = GraphText{
+	Contributions: "%d contributions",
+	LastYear:      "in the last year",
+	OnDate:        "on %s",
+	Less:          "Less",
+	More:          "More",
+	Months:        [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:      [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+}
+
+// Names of the supported locales.
+const (
+	EnglishLocaleName = "en"
+	GermanLocaleName  = "de"
+	FrenchLocaleName  = "fr"
+	SpanishLocaleName = "es"
+)
+
+// The abbreviated names of the months in the supported locales other than
+// English.
+var (
+	germanMonths  = [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"}
+	frenchMonths  = [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."}
+	spanishMonths = [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"}
+)
+
+// graphLocales are the texts of contribution graphs by the name of their
+// locale.
+var graphLocales = map[string]GraphText{
+	EnglishLocaleName: EnglishGraphText,
+	GermanLocaleName: {
+		Contributions: "%d Beiträge",
+		LastYear:      "im letzten Jahr",
+		OnDate:        "am %s",
+		Less:          "Weniger",
+		More:          "Mehr",
+		Months:        germanMonths,
+		Weekdays:      [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		FormatDate:    dayMonthYear("%d. %s %d", germanMonths),
+	},
+	FrenchLocaleName: {
+		Contributions: "%d contributions",
+		LastYear:      "au cours de la dernière année",
+		OnDate:        "le %s",
+		Less:          "Moins",
+		More:          "Plus",
+		Months:        frenchMonths,
+		Weekdays:      [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		FormatDate:    dayMonthYear("%d %s %d", frenchMonths),
+	},
+	SpanishLocaleName: {
+		Contributions: "%d contribuciones",
+		LastYear:      "en el último año",
+		OnDate:        "el %s",
+		Less:          "Menos",
+		More:          "Más",
+		Months:        spanishMonths,
+		Weekdays:      [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		FormatDate:    dayMonthYear("%d %s %d", spanishMonths),
+	},
+}
+
+// dayMonthYear returns a function formatting dates with the given format
+// receiving the day, the name of the month, and the year, e.g., "2. Jan 2006".
+func dayMonthYear(format string, months [12]string) func(date time.Time) string {
+	return func(date time.Time) string {
+		return fmt.Sprintf(format, date.Day(), months[date.Month()-time.January], date.Year())
+	}
+}
+
+// ParseGraphLocale returns the texts of contribution graphs in the locale
+// registered under the given name.
+func ParseGraphLocale(name string) (GraphText, error) {
+	if t, ok := graphLocales[name]; ok {
+		return t, nil
+	}
+	names := make([]string, 0, len(graphLocales))
+	for n := range graphLocales {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return GraphText{}, fmt.Errorf("unknown locale '%s'; supported are %s", name, strings.Join(names, ", "))
+}
+
+// withDefaults returns the texts with empty texts replaced by their English
+// counterparts.
+func (t GraphText) withDefaults() GraphText {
+	for _, s := range []struct {
+		value    *string
+		fallback string
+	}{
+		{&t.Contributions, EnglishGraphText.Contributions},
+		{&t.LastYear, EnglishGraphText.LastYear},
+		{&t.OnDate, EnglishGraphText.OnDate},
+		{&t.Less, EnglishGraphText.Less},
+		{&t.More, EnglishGraphText.More},
+	} {
+		if *s.value == "" {
+			*s.value = s.fallback
+		}
+	}
+	for i, month := range t.Months {
+		if month == "" {
+			t.Months[i] = EnglishGraphText.Months[i]
+		}
+	}
+	for i, weekday := range t.Weekdays {
+		if weekday == "" {
+			t.Weekdays[i] = EnglishGraphText.Weekdays[i]
+		}
+	}
+	return t
+}
+
+// contributions formats the given number of contributions.
+func (t GraphText) contributions(count int) string {
+	return fmt.Sprintf(t.Contributions, count)
+}
+
+// month returns the abbreviated name of the month of the given date.
+func (t GraphText) month(date time.Time) string {
+	return t.Months[date.Month()-time.January]
+}
+
+// monthOfYear formats the month of the given date including the year, e.g.,
+// "Jan 2006".
+func (t GraphText) monthOfYear(date time.Time) string {
+	return fmt.Sprintf("%s %d", t.month(date), date.Year())
+}
+
+// date formats the given date.
+func (t GraphText) date(date time.Time) string {
+	if t.FormatDate != nil {
+		return t.FormatDate(date)
+	}
+	return fmt.Sprintf("%s %d, %d", t.month(date), date.Day(), date.Year())
+}
+
+// onDate formats the given date for tooltips.
+func (t GraphText) onDate(date time.Time) string {
+	return fmt.Sprintf(t.OnDate, t.date(date))
+}
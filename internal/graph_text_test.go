@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parsing locales", func() {
+	date := dateparse.MustParse("2023-01-02")
+
+	It("formats dates in English by default", func() {
+		t, err := ParseGraphLocale("en")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.withDefaults().onDate(date)).To(Equal("on Jan 2, 2023"))
+	})
+	It("formats dates in German with the day first", func() {
+		t, err := ParseGraphLocale("de")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.withDefaults().onDate(date)).To(Equal("am 2. Jan 2023"))
+		Expect(t.withDefaults().monthOfYear(dateparse.MustParse("2023-03-01"))).To(Equal("Mär 2023"))
+	})
+	It("names the weekdays in the locale", func() {
+		t, err := ParseGraphLocale("fr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.Weekdays[1]).To(Equal("lun."))
+	})
+	It("rejects unknown locales", func() {
+		_, err := ParseGraphLocale("tlh")
+		Expect(err).To(MatchError(ContainSubstring("supported are de, en, es, fr")))
+	})
+})
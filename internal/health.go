@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"math"
+	"time"
+)
+
+// HealthThresholds are the thresholds a community has to meet for the
+// respective metrics to be considered healthy.
+type HealthThresholds struct {
+
+	// The minimal change in percent of the number of contributions in the
+	// last 13 weeks compared to the 13 weeks before.
+	ActivityTrend float64
+
+	// The minimal 50% bus factor.
+	BusFactor int
+
+	// The maximal median time to first response to issues in hours.
+	ResponseTime float64
+
+	// The minimal share of first-time contributors among the active
+	// contributors in percent.
+	NewContributorRate float64
+}
+
+// HealthMetric is a single metric contributing to the health score.
+type HealthMetric struct {
+
+	// The name of the metric.
+	Name string `json:"name" yaml:"name"`
+
+	// The unit of the metric's value and threshold.
+	Unit string `json:"unit" yaml:"unit"`
+
+	// Whether the metric could be computed from the available data.
+	Available bool `json:"available" yaml:"available"`
+
+	// The value of the metric.
+	Value float64 `json:"value" yaml:"value"`
+
+	// The threshold the value has to meet.
+	Threshold float64 `json:"threshold" yaml:"threshold"`
+
+	// Whether higher values are better than lower ones.
+	HigherIsBetter bool `json:"higherIsBetter" yaml:"higherIsBetter"`
+
+	// Whether the value meets the threshold.
+	Healthy bool `json:"healthy" yaml:"healthy"`
+}
+
+// newHealthMetric creates a new HealthMetric and assesses the given value.
+func newHealthMetric(name string, unit string, value float64, threshold float64, higherIsBetter bool) HealthMetric {
+	metric := HealthMetric{
+		Name:           name,
+		Unit:           unit,
+		Available:      !math.IsNaN(value),
+		Threshold:      threshold,
+		HigherIsBetter: higherIsBetter,
+	}
+	if metric.Available {
+		metric.Value = math.Round(value*10) / 10
+		metric.Healthy = (higherIsBetter && value >= threshold) || (!higherIsBetter && value <= threshold)
+	}
+	return metric
+}
+
+// HealthReport combines several metrics into a single health score.
+type HealthReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The share of available metrics meeting their thresholds in percent.
+	Score int `json:"score" yaml:"score"`
+
+	// The assessed metrics.
+	Metrics []HealthMetric `json:"metrics" yaml:"metrics"`
+}
+
+// activityTrend computes the change in percent of the number of contributions
+// made in the last 13 weeks compared to the 13 weeks before. Returns NaN if
+// there haven't been any contributions in the 13 weeks before.
+func activityTrend(contributions []Contribution, lastDay time.Time) float64 {
+	totals := WeeklyTotals(DailyRecords(contributions, lastDay), 26)
+	previous, recent := 0, 0
+	for i, total := range totals {
+		if i < 13 {
+			previous += total
+		} else {
+			recent += total
+		}
+	}
+	if previous == 0 {
+		return math.NaN()
+	}
+	return float64(recent-previous) / float64(previous) * 100
+}
+
+// NewHealthReport assesses the health of a community based on the given
+// contributions and issues within the 52 weeks ending with the given day.
+// Contributors without prior contributions since the given lookback date are
+// considered new.
+func NewHealthReport(contributions []Contribution, issues []Issue, lastDay time.Time, lookbackFrom time.Time,
+	thresholds HealthThresholds) *HealthReport {
+	report := &HealthReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+
+	busFactor := NewBusFactorReport(contributions, lastDay, thresholds.BusFactor).Overall
+	busFactorValue := float64(busFactor.Factor50)
+	if busFactor.Contributions == 0 {
+		busFactorValue = math.NaN()
+	}
+
+	responseTime := NewIssueMetricsReport(issues, lastDay).Overall.TimeToFirstResponse
+	responseTimeValue := responseTime.MedianHours
+	if responseTime.Count == 0 {
+		responseTimeValue = math.NaN()
+	}
+
+	newContributors := NewNewContributorsReport(contributions, lastDay, lookbackFrom)
+	newContributorRate := math.NaN()
+	if newContributors.Active > 0 {
+		newContributorRate = float64(newContributors.New) / float64(newContributors.Active) * 100
+	}
+
+	report.Metrics = []HealthMetric{
+		newHealthMetric("Activity trend", "%", activityTrend(contributions, lastDay), thresholds.ActivityTrend, true),
+		newHealthMetric("Bus factor", "contributors", busFactorValue, float64(thresholds.BusFactor), true),
+		newHealthMetric("Median time to first response", "hours", responseTimeValue, thresholds.ResponseTime, false),
+		newHealthMetric("New contributor rate", "%", newContributorRate, thresholds.NewContributorRate, true),
+	}
+
+	available, healthy := 0, 0
+	for _, metric := range report.Metrics {
+		if metric.Available {
+			available++
+		}
+		if metric.Healthy {
+			healthy++
+		}
+	}
+	if available > 0 {
+		report.Score = (healthy*100 + available/2) / available
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering health reports as markdown.
+	//go:embed health.gomd
+	healthMarkdownTemplate string
+
+	// The embedded template used for rendering health reports as HTML.
+	//go:embed health.gohtml
+	healthHTMLTemplate string
+)
+
+// Markdown renders the health report as markdown.
+func (r *HealthReport) Markdown() (string, error) {
+	return renderMarkdown("health", healthMarkdownTemplate, r)
+}
+
+// HTML renders the health report as a standalone HTML document.
+func (r *HealthReport) HTML() (string, error) {
+	tmpl := template.Must(template.New("health").Parse(healthHTMLTemplate))
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Assessing community health", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	thresholds := HealthThresholds{ActivityTrend: -10, BusFactor: 2, ResponseTime: 48, NewContributorRate: 10}
+	contributions := []Contribution{
+		{Login: "jdoe", Date: lastDay.AddDate(0, 0, -100)},
+		{Login: "jdoe", Date: lastDay.AddDate(0, 0, -99)},
+		{Login: "jdoe", Date: lastDay.AddDate(0, 0, -1)},
+		{Login: "jroe", Date: lastDay.AddDate(0, 0, -2)},
+		{Login: "jroe", Date: lastDay.AddDate(-1, -1, 0)},
+	}
+	created := lastDay.AddDate(0, 0, -5)
+	issues := []Issue{
+		{Created: created, FirstResponse: created.Add(72 * time.Hour)},
+	}
+	report := NewHealthReport(contributions, issues, lastDay, lastDay.AddDate(-2, 0, 0), thresholds)
+
+	It("assesses the individual metrics", func() {
+		Expect(report.Metrics).To(Equal([]HealthMetric{
+			{Name: "Activity trend", Unit: "%", Available: true, Value: 0, Threshold: -10, HigherIsBetter: true, Healthy: true},
+			{Name: "Bus factor", Unit: "contributors", Available: true, Value: 1, Threshold: 2, HigherIsBetter: true},
+			{Name: "Median time to first response", Unit: "hours", Available: true, Value: 72, Threshold: 48},
+			{Name: "New contributor rate", Unit: "%", Available: true, Value: 50, Threshold: 10, HigherIsBetter: true, Healthy: true},
+		}))
+	})
+	It("computes the score from the healthy metrics", func() {
+		Expect(report.Score).To(Equal(50))
+	})
+	It("excludes unavailable metrics from the score", func() {
+		report := NewHealthReport(contributions, nil, lastDay, lastDay.AddDate(-2, 0, 0), thresholds)
+		Expect(report.Metrics[2].Available).To(BeFalse())
+		Expect(report.Score).To(Equal(67))
+	})
+	It("renders as markdown and HTML", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| Bus factor | 1.0 contributors | ≥ 2.0 contributors | :warning: |"))
+		html, err := report.HTML()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring("<strong>50%</strong>"))
+	})
+})
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Hotspot is a file or directory of a repository that is changed frequently.
+type Hotspot struct {
+
+	// The repository in 'owner/name' notation.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// The path of the file or directory relative to the repository root.
+	Path string `json:"path" yaml:"path"`
+
+	// The number of commits changing the file or anything within the
+	// directory.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The number of distinct contributors authoring these commits.
+	Contributors int `json:"contributors" yaml:"contributors"`
+}
+
+// HotspotReport lists the most frequently changed files and directories
+// across repositories.
+type HotspotReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The most frequently changed files ordered by descending number of
+	// commits.
+	Files []Hotspot `json:"files" yaml:"files"`
+
+	// The most frequently changed directories ordered by descending number of
+	// commits.
+	Directories []Hotspot `json:"directories" yaml:"directories"`
+}
+
+// hotspotCount is the number of commits and the contributors changing a path.
+type hotspotCount struct {
+	commits      int
+	contributors map[string]bool
+}
+
+// hotspotCounter counts the commits and contributors per repository and path.
+type hotspotCounter map[[2]string]*hotspotCount
+
+// add accounts for a commit by the given contributor changing the given path.
+func (h hotspotCounter) add(repository string, path string, contributor string) {
+	key := [2]string{repository, path}
+	if h[key] == nil {
+		h[key] = &hotspotCount{contributors: make(map[string]bool)}
+	}
+	h[key].commits++
+	h[key].contributors[contributor] = true
+}
+
+// top returns the given number of paths changed by the most commits.
+func (h hotspotCounter) top(n int) []Hotspot {
+	var hotspots []Hotspot
+	for key, count := range h {
+		hotspots = append(hotspots, Hotspot{
+			Repository:   key[0],
+			Path:         key[1],
+			Commits:      count.commits,
+			Contributors: len(count.contributors),
+		})
+	}
+	sort.Slice(hotspots, func(i, j int) bool {
+		a, b := hotspots[i], hotspots[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.Path < b.Path
+	})
+	if len(hotspots) > n {
+		hotspots = hotspots[:n]
+	}
+	return hotspots
+}
+
+// directories returns the directories containing the file with the given path
+// up to the given depth, e.g., 'docs' and 'docs/guides' for
+// 'docs/guides/intro.md' and a depth of 2 or more. Directories at all depths
+// are returned if depth is not positive.
+func directories(file string, depth int) []string {
+	var dirs []string
+	parts := strings.Split(path.Dir(file), "/")
+	if parts[0] == "." {
+		return nil
+	}
+	for i := range parts {
+		if depth > 0 && i >= depth {
+			break
+		}
+		dirs = append(dirs, strings.Join(parts[:i+1], "/"))
+	}
+	return dirs
+}
+
+// NewHotspotReport identifies the top files and directories changed most
+// frequently by the given commits made within the 52 weeks ending with the
+// given day. Directories are considered up to the given depth. The commits are
+// expected to carry the paths of the changed files.
+func NewHotspotReport(commits []Contribution, lastDay time.Time, top int, depth int) *HotspotReport {
+	report := &HotspotReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	files := make(hotspotCounter)
+	dirs := make(hotspotCounter)
+	for _, c := range commits {
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		changedDirs := make(map[string]bool)
+		for _, file := range c.Files {
+			files.add(c.Repository, file, c.Contributor())
+			for _, dir := range directories(file, depth) {
+				changedDirs[dir] = true
+			}
+		}
+		for dir := range changedDirs {
+			dirs.add(c.Repository, dir, c.Contributor())
+		}
+	}
+	report.Files = files.top(top)
+	report.Directories = dirs.top(top)
+	return report
+}
+
+var (
+	// The embedded template used for rendering hotspot reports as markdown.
+	//go:embed hotspots.gomd
+	hotspotsTemplate string
+)
+
+// Markdown renders the hotspot report as markdown.
+func (r *HotspotReport) Markdown() (string, error) {
+	return renderMarkdown("hotspots", hotspotsTemplate, r)
+}
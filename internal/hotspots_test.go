@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hotspot reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	date := dateparse.MustParse("2023-03-01 12:00")
+	commit := func(repository string, login string, files ...string) Contribution {
+		return Contribution{Type: CommitContribution, Repository: repository, Login: login, Date: date, Files: files}
+	}
+	commits := []Contribution{
+		commit("herdstat/herdstat", "alice", "docs/guides/intro.md", "docs/guides/setup.md"),
+		commit("herdstat/herdstat", "bob", "docs/guides/intro.md", "README.md"),
+		commit("herdstat/herdstat", "alice", "docs/index.md"),
+		commit("herdstat/action", "alice", "README.md"),
+		// Outside the analyzed period
+		{Type: CommitContribution, Repository: "herdstat/action", Login: "bob",
+			Date: dateparse.MustParse("2021-03-01 12:00"), Files: []string{"README.md"}},
+	}
+
+	It("ranks the most frequently changed files", func() {
+		report := NewHotspotReport(commits, lastDay, 3, 0)
+		Expect(report.Files).To(Equal([]Hotspot{
+			{Repository: "herdstat/herdstat", Path: "docs/guides/intro.md", Commits: 2, Contributors: 2},
+			{Repository: "herdstat/action", Path: "README.md", Commits: 1, Contributors: 1},
+			{Repository: "herdstat/herdstat", Path: "README.md", Commits: 1, Contributors: 1},
+		}))
+	})
+
+	It("counts each commit once per directory", func() {
+		report := NewHotspotReport(commits, lastDay, 10, 0)
+		Expect(report.Directories).To(Equal([]Hotspot{
+			{Repository: "herdstat/herdstat", Path: "docs", Commits: 3, Contributors: 2},
+			{Repository: "herdstat/herdstat", Path: "docs/guides", Commits: 2, Contributors: 2},
+		}))
+	})
+
+	It("limits the directory depth", func() {
+		report := NewHotspotReport(commits, lastDay, 10, 1)
+		Expect(report.Directories).To(HaveLen(1))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := NewHotspotReport(commits, lastDay, 10, 0).Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| herdstat/herdstat | `docs/` | 3 | 2 |"))
+	})
+})
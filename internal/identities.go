@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Identity maps the email addresses used by a contributor in commits to the
+// contributor's GitHub login.
+type Identity struct {
+
+	// The GitHub login of the contributor.
+	Login string
+
+	// The email addresses used by the contributor.
+	Emails []string
+}
+
+// noreplyEmailPattern matches the noreply email addresses GitHub uses for
+// commits made via the web interface or by users hiding their email address.
+var noreplyEmailPattern = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// Identities resolves the GitHub logins of commit authors.
+type Identities struct {
+	byEmail map[string]string
+}
+
+// NewIdentities creates Identities from the given explicit mappings.
+func NewIdentities(identities []Identity) *Identities {
+	i := &Identities{byEmail: make(map[string]string)}
+	for _, identity := range identities {
+		for _, email := range identity.Emails {
+			i.byEmail[strings.ToLower(email)] = identity.Login
+		}
+	}
+	return i
+}
+
+// Login returns the GitHub login of the contributor using the given email
+// address or an empty string if unknown.
+func (i *Identities) Login(email string) string {
+	email = strings.ToLower(email)
+	if login, ok := i.byEmail[email]; ok {
+		return login
+	}
+	if m := noreplyEmailPattern.FindStringSubmatch(email); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// Resolve sets the login of the given contributions lacking one if it can be
+// resolved from the contributor's email address.
+func (i *Identities) Resolve(contributions []Contribution) {
+	for idx := range contributions {
+		if c := &contributions[idx]; c.Login == "" {
+			c.Login = i.Login(c.Email)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolving identities", func() {
+	identities := NewIdentities([]Identity{
+		{Login: "jdoe", Emails: []string{"john.doe@herdstat.com"}},
+	})
+
+	It("resolves configured email addresses", func() {
+		Expect(identities.Login("John.Doe@herdstat.com")).To(Equal("jdoe"))
+	})
+	It("resolves GitHub noreply email addresses", func() {
+		Expect(identities.Login("12345+jroe@users.noreply.github.com")).To(Equal("jroe"))
+		Expect(identities.Login("mmoe@users.noreply.github.com")).To(Equal("mmoe"))
+	})
+	It("keeps existing logins", func() {
+		contributions := []Contribution{
+			{Login: "octocat", Email: "john.doe@herdstat.com"},
+			{Email: "john.doe@herdstat.com"},
+			{Email: "jane@herdstat.com"},
+		}
+		identities.Resolve(contributions)
+		Expect(contributions[0].Login).To(Equal("octocat"))
+		Expect(contributions[1].Login).To(Equal("jdoe"))
+		Expect(contributions[2].Contributor()).To(Equal("jane@herdstat.com"))
+	})
+})
+
+var _ = Describe("Selecting contributors", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contributions := []Contribution{
+		{Login: "jdoe", Date: lastDay},
+		{Login: "jroe", Date: lastDay},
+		{Login: "jroe", Date: lastDay},
+		{Login: "mmoe", Date: lastDay},
+		{Login: "mmoe", Date: lastDay.AddDate(-2, 0, 0)},
+	}
+
+	It("filters contributions by contributor", func() {
+		Expect(ByContributor(contributions, "JRoe")).To(HaveLen(2))
+	})
+	It("determines the top contributors within the period", func() {
+		Expect(TopContributors(contributions, lastDay, 2)).To(Equal([]string{"jroe", "jdoe"}))
+	})
+})
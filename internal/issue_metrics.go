@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"math"
+	"sort"
+	"time"
+)
+
+// Issue is an issue opened in a repository.
+type Issue struct {
+
+	// The repository the issue was opened in in 'owner/name' notation.
+	Repository string
+
+	// The number of the issue.
+	Number int
+
+	// The login of the author of the issue.
+	Author string
+
+	// The point in time the issue was opened.
+	Created time.Time
+
+	// The point in time of the first comment by someone other than the
+	// author. Zero if there is no such comment.
+	FirstResponse time.Time
+
+	// The first comment of each commenter other than the author in
+	// chronological order.
+	Responses []Response
+
+	// The point in time the issue was closed. Zero if the issue is open.
+	Closed time.Time
+
+	// The names of the labels assigned to the issue.
+	Labels []string
+}
+
+// Response is the first comment of a commenter on an issue.
+type Response struct {
+
+	// The login of the commenter.
+	Responder string
+
+	// The point in time of the comment.
+	Time time.Time
+}
+
+// DurationStatistics describes the distribution of a set of durations.
+type DurationStatistics struct {
+
+	// The number of durations.
+	Count int `json:"count" yaml:"count"`
+
+	// The median duration in hours.
+	MedianHours float64 `json:"medianHours" yaml:"medianHours"`
+
+	// The 90th percentile of the durations in hours.
+	P90Hours float64 `json:"p90Hours" yaml:"p90Hours"`
+}
+
+// percentile computes the p-th percentile (0 <= p <= 1) of the given sorted
+// values using linear interpolation between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (position-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// newDurationStatistics computes the statistics of the given durations.
+func newDurationStatistics(durations []time.Duration) DurationStatistics {
+	hours := make([]float64, len(durations))
+	for i, d := range durations {
+		hours[i] = d.Hours()
+	}
+	sort.Float64s(hours)
+	round := func(h float64) float64 {
+		return math.Round(h*10) / 10
+	}
+	return DurationStatistics{
+		Count:       len(hours),
+		MedianHours: round(percentile(hours, 0.5)),
+		P90Hours:    round(percentile(hours, 0.9)),
+	}
+}
+
+// IssueMetrics describes how quickly issues opened in a repository (or a set
+// of repositories) are responded to and closed.
+type IssueMetrics struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall metrics.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The number of issues opened.
+	Issues int `json:"issues" yaml:"issues"`
+
+	// The number of opened issues that have been closed.
+	Closed int `json:"closed" yaml:"closed"`
+
+	// The time until the first response by someone other than the author.
+	TimeToFirstResponse DurationStatistics `json:"timeToFirstResponse" yaml:"timeToFirstResponse"`
+
+	// The time until the issue has been closed.
+	TimeToClose DurationStatistics `json:"timeToClose" yaml:"timeToClose"`
+}
+
+// IssueMetricsReport contains the issue metrics per repository and overall.
+type IssueMetricsReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The metrics across all repositories.
+	Overall IssueMetrics `json:"overall" yaml:"overall"`
+
+	// The metrics of the individual repositories sorted by name.
+	Repositories []IssueMetrics `json:"repositories" yaml:"repositories"`
+}
+
+// newIssueMetrics computes the metrics of the given issues. Responses and
+// closings after the given day are ignored.
+func newIssueMetrics(repository string, issues []Issue, lastDay time.Time) IssueMetrics {
+	metrics := IssueMetrics{Repository: repository}
+	var responseTimes, closeTimes []time.Duration
+	for _, issue := range issues {
+		if !InPeriod(issue.Created, lastDay) {
+			continue
+		}
+		metrics.Issues++
+		if !issue.FirstResponse.IsZero() && !issue.FirstResponse.After(lastDay) {
+			responseTimes = append(responseTimes, issue.FirstResponse.Sub(issue.Created))
+		}
+		if !issue.Closed.IsZero() && !issue.Closed.After(lastDay) {
+			metrics.Closed++
+			closeTimes = append(closeTimes, issue.Closed.Sub(issue.Created))
+		}
+	}
+	metrics.TimeToFirstResponse = newDurationStatistics(responseTimes)
+	metrics.TimeToClose = newDurationStatistics(closeTimes)
+	return metrics
+}
+
+// NewIssueMetricsReport computes the metrics of the given issues opened
+// within the 52 weeks ending with the given day.
+func NewIssueMetricsReport(issues []Issue, lastDay time.Time) *IssueMetricsReport {
+	report := &IssueMetricsReport{
+		From:    lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:   lastDay.Format(dateFormat),
+		Overall: newIssueMetrics("", issues, lastDay),
+	}
+	byRepository := make(map[string][]Issue)
+	for _, issue := range issues {
+		byRepository[issue.Repository] = append(byRepository[issue.Repository], issue)
+	}
+	for repository, i := range byRepository {
+		report.Repositories = append(report.Repositories, newIssueMetrics(repository, i, lastDay))
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering issue metrics reports as
+	// markdown.
+	//go:embed issue-metrics.gomd
+	issueMetricsTemplate string
+)
+
+// Markdown renders the issue metrics report as markdown.
+func (r *IssueMetricsReport) Markdown() (string, error) {
+	return renderMarkdown("issue-metrics", issueMetricsTemplate, r)
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Computing issue metrics", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	created := lastDay.AddDate(0, 0, -10)
+	issues := []Issue{
+		{Repository: "herdstat/b", Created: created, FirstResponse: created.Add(1 * time.Hour), Closed: created.Add(48 * time.Hour)},
+		{Repository: "herdstat/b", Created: created, FirstResponse: created.Add(3 * time.Hour)},
+		{Repository: "herdstat/a", Created: created, FirstResponse: created.Add(2 * time.Hour), Closed: created.AddDate(0, 0, 20)},
+		{Repository: "herdstat/a", Created: lastDay.AddDate(-2, 0, 0), Closed: created},
+	}
+	report := NewIssueMetricsReport(issues, lastDay)
+
+	It("considers issues opened within the period only", func() {
+		Expect(report.Overall.Issues).To(Equal(3))
+	})
+	It("ignores closings after the period", func() {
+		Expect(report.Repositories[0]).To(Equal(IssueMetrics{
+			Repository:          "herdstat/a",
+			Issues:              1,
+			TimeToFirstResponse: DurationStatistics{Count: 1, MedianHours: 2, P90Hours: 2},
+		}))
+	})
+	It("computes median and percentiles", func() {
+		Expect(report.Overall.TimeToFirstResponse).To(Equal(DurationStatistics{Count: 3, MedianHours: 2, P90Hours: 2.8}))
+		Expect(report.Overall.TimeToClose).To(Equal(DurationStatistics{Count: 1, MedianHours: 48, P90Hours: 48}))
+	})
+	It("renders as markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| herdstat/a | 1 | 0 | 2.0h | 2.0h | - | - |"))
+		Expect(md).To(ContainSubstring("| **Overall** | 3 | 1 | 2.0h | 2.8h | 2.0d | 2.0d |"))
+	})
+})
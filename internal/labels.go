@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+)
+
+// labelChartBars is the maximal number of bars of a label chart.
+const labelChartBars = 15
+
+// LabelCount is the number of issues a label is assigned to.
+type LabelCount struct {
+
+	// The name of the label. Empty for issues without labels.
+	Label string `json:"label" yaml:"label"`
+
+	// The number of open issues.
+	Open int `json:"open" yaml:"open"`
+
+	// The number of issues closed within the analyzed period.
+	Closed int `json:"closed" yaml:"closed"`
+}
+
+// LabelReport describes how labels are distributed across issues.
+type LabelReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The labels ordered by descending number of open issues.
+	Labels []LabelCount `json:"labels" yaml:"labels"`
+
+	// The number of issues without labels.
+	Unlabeled LabelCount `json:"unlabeled" yaml:"unlabeled"`
+}
+
+// NewLabelReport counts the given issues open at the end of the given day and
+// closed within the 52 weeks ending with that day per label. Labels are
+// compared by name across repositories.
+func NewLabelReport(issues []Issue, lastDay time.Time) *LabelReport {
+	report := &LabelReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	counts := make(map[string]*LabelCount)
+	count := func(c *LabelCount, issue Issue) {
+		switch {
+		case issue.Created.After(lastDay):
+		case issue.Closed.IsZero() || issue.Closed.After(lastDay):
+			c.Open++
+		case InPeriod(issue.Closed, lastDay):
+			c.Closed++
+		}
+	}
+	for _, issue := range issues {
+		if len(issue.Labels) == 0 {
+			count(&report.Unlabeled, issue)
+		}
+		for _, label := range issue.Labels {
+			c, ok := counts[label]
+			if !ok {
+				c = &LabelCount{Label: label}
+				counts[label] = c
+			}
+			count(c, issue)
+		}
+	}
+	for _, c := range counts {
+		if c.Open+c.Closed > 0 {
+			report.Labels = append(report.Labels, *c)
+		}
+	}
+	sort.Slice(report.Labels, func(i, j int) bool {
+		a, b := report.Labels[i], report.Labels[j]
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		}
+		if a.Closed != b.Closed {
+			return a.Closed > b.Closed
+		}
+		return a.Label < b.Label
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering label reports as markdown.
+	//go:embed labels.gomd
+	labelsTemplate string
+)
+
+// Markdown renders the label report as markdown.
+func (r *LabelReport) Markdown() (string, error) {
+	return renderMarkdown("labels", labelsTemplate, r)
+}
+
+// NewLabelChart creates a BarChart visualizing the number of open issues of
+// the labels with the most open issues.
+func NewLabelChart(report *LabelReport, color color.RGBA) *BarChart {
+	chart := &BarChart{
+		Title: "Open issues per label",
+		Color: color,
+	}
+	for i, label := range report.Labels {
+		if i >= labelChartBars {
+			break
+		}
+		chart.Bars = append(chart.Bars, Bar{
+			Label: label.Label,
+			Value: label.Open,
+			Text:  fmt.Sprintf("%d open, %d closed", label.Open, label.Closed),
+		})
+	}
+	return chart
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Label reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	created := dateparse.MustParse("2022-01-01")
+	closed := dateparse.MustParse("2023-03-01")
+	issues := []Issue{
+		{Created: created, Labels: []string{"bug"}},
+		{Created: created, Labels: []string{"bug", "good first issue"}},
+		{Created: created, Closed: closed, Labels: []string{"enhancement"}},
+		{Created: created},
+		// Closed before the analyzed period
+		{Created: created, Closed: dateparse.MustParse("2022-01-02"), Labels: []string{"wontfix"}},
+		// Closed after the analyzed period
+		{Created: created, Closed: dateparse.MustParse("2023-04-01"), Labels: []string{"bug"}},
+	}
+	report := NewLabelReport(issues, lastDay)
+
+	It("counts open and closed issues per label", func() {
+		Expect(report.Labels).To(Equal([]LabelCount{
+			{Label: "bug", Open: 3},
+			{Label: "good first issue", Open: 1},
+			{Label: "enhancement", Closed: 1},
+		}))
+	})
+
+	It("counts issues without labels", func() {
+		Expect(report.Unlabeled).To(Equal(LabelCount{Open: 1}))
+	})
+
+	It("renders markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| good first issue | 1 | 0 |"))
+	})
+
+	It("charts the open issues per label", func() {
+		chart := NewLabelChart(report, color.RGBA{})
+		Expect(chart.Bars).To(HaveLen(3))
+		Expect(chart.Bars[0]).To(Equal(Bar{Label: "bug", Value: 3, Text: "3 open, 0 closed"}))
+	})
+})
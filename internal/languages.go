@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"image/color"
+	"sort"
+)
+
+// languageChartBars is the maximal number of bars of a language chart. Less
+// used languages are aggregated into a single bar.
+const languageChartBars = 10
+
+// LanguageShare is the amount of code written in a programming language.
+type LanguageShare struct {
+
+	// The name of the language as reported by GitHub.
+	Language string `json:"language" yaml:"language"`
+
+	// The number of bytes of code written in the language.
+	Bytes int `json:"bytes" yaml:"bytes"`
+
+	// The share of code written in the language in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+
+	// The number of repositories containing code written in the language.
+	Repositories int `json:"repositories" yaml:"repositories"`
+}
+
+// LanguageReport describes the composition of the code of a set of
+// repositories by programming language.
+type LanguageReport struct {
+
+	// The number of analyzed repositories.
+	Repositories int `json:"repositories" yaml:"repositories"`
+
+	// The overall number of bytes of code.
+	Bytes int `json:"bytes" yaml:"bytes"`
+
+	// The languages ordered by descending amount of code.
+	Languages []LanguageShare `json:"languages" yaml:"languages"`
+}
+
+// NewLanguageReport aggregates the given number of bytes per language of each
+// repository into a LanguageReport.
+func NewLanguageReport(languages map[string]map[string]int) *LanguageReport {
+	report := &LanguageReport{Repositories: len(languages)}
+	shares := make(map[string]*LanguageShare)
+	for _, repoLanguages := range languages {
+		for language, bytes := range repoLanguages {
+			share, ok := shares[language]
+			if !ok {
+				share = &LanguageShare{Language: language}
+				shares[language] = share
+			}
+			share.Bytes += bytes
+			share.Repositories++
+			report.Bytes += bytes
+		}
+	}
+	for _, share := range shares {
+		share.Percentage = percentage(share.Bytes, report.Bytes)
+		report.Languages = append(report.Languages, *share)
+	}
+	sort.Slice(report.Languages, func(i, j int) bool {
+		a, b := report.Languages[i], report.Languages[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Language < b.Language
+	})
+	return report
+}
+
+// percentage computes the share of part in total in percent rounded down to
+// one decimal place.
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part*1000/total) / 10
+}
+
+var (
+	// The embedded template used for rendering language reports as markdown.
+	//go:embed languages.gomd
+	languagesTemplate string
+)
+
+// Markdown renders the language report as markdown.
+func (r *LanguageReport) Markdown() (string, error) {
+	return renderMarkdown("languages", languagesTemplate, r)
+}
+
+// NewLanguageChart creates a BarChart visualizing the share of code per
+// language. Languages beyond the most used ones are aggregated into a single
+// bar.
+func NewLanguageChart(report *LanguageReport, color color.RGBA) *BarChart {
+	chart := &BarChart{
+		Title: "Code per language",
+		Color: color,
+	}
+	bar := func(label string, bytes int) Bar {
+		return Bar{
+			Label: label,
+			Value: bytes,
+			Text:  fmt.Sprintf("%.1f%%", percentage(bytes, report.Bytes)),
+		}
+	}
+	other := 0
+	for i, language := range report.Languages {
+		if len(report.Languages) > languageChartBars && i >= languageChartBars-1 {
+			other += language.Bytes
+			continue
+		}
+		chart.Bars = append(chart.Bars, bar(language.Language, language.Bytes))
+	}
+	if other > 0 {
+		chart.Bars = append(chart.Bars, bar("Other", other))
+	}
+	return chart
+}
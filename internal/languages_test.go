@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+	"strconv"
+)
+
+var _ = Describe("Language reports", func() {
+	report := NewLanguageReport(map[string]map[string]int{
+		"herdstat/herdstat": {"Go": 600, "Shell": 100},
+		"herdstat/action":   {"Go": 200, "Dockerfile": 100},
+	})
+
+	It("aggregates the languages of all repositories", func() {
+		Expect(report.Repositories).To(Equal(2))
+		Expect(report.Bytes).To(Equal(1000))
+		Expect(report.Languages).To(Equal([]LanguageShare{
+			{Language: "Go", Bytes: 800, Percentage: 80, Repositories: 2},
+			{Language: "Dockerfile", Bytes: 100, Percentage: 10, Repositories: 1},
+			{Language: "Shell", Bytes: 100, Percentage: 10, Repositories: 1},
+		}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| Go | 800 | 80.0% | 2 |"))
+	})
+
+	When("there are more languages than bars", func() {
+		languages := make(map[string]int)
+		for i := 0; i < 12; i++ {
+			languages["L"+strconv.Itoa(i)] = 100 - i
+		}
+		chart := NewLanguageChart(NewLanguageReport(map[string]map[string]int{"a/b": languages}), color.RGBA{})
+		It("aggregates the least used languages", func() {
+			Expect(chart.Bars).To(HaveLen(languageChartBars))
+			Expect(chart.Bars[languageChartBars-1].Label).To(Equal("Other"))
+			Expect(chart.Bars[languageChartBars-1].Value).To(Equal(91 + 90 + 89))
+		})
+	})
+})
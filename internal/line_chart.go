@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dimensions of line charts.
+const (
+	lineChartWidth       = 700
+	lineChartHeight      = 220
+	lineChartLeftMargin  = 50
+	lineChartRightMargin = 20
+	lineChartTopMargin   = 30
+	lineChartAxisHeight  = 30
+	lineChartGridLines   = 4
+	lineChartMaxLabels   = 13
+)
+
+var (
+	// The embedded stylesheet used for styling charts.
+	//go:embed chart.gohtml
+	chartStyle string
+)
+
+// secondarySeriesColor is the color of secondary series (e.g., the opened
+// issues in burnup charts) rendered next to a series of configurable color.
+var secondarySeriesColor = color.RGBA{R: 0x8b, G: 0x94, B: 0x9e, A: 0xff}
+
+// Series is a named sequence of values rendered as a line.
+type Series struct {
+
+	// The name of the series shown in the legend.
+	Name string
+
+	// The values of the series. Must have the same length as the labels of
+	// the chart.
+	Values []int
+
+	// The color of the line.
+	Color color.RGBA
+}
+
+// LineChart visualizes one or more series of values over time.
+type LineChart struct {
+
+	// The title of the chart.
+	Title string
+
+	// The labels of the x-axis.
+	Labels []string
+
+	// The series to be rendered.
+	Series []Series
+
+	// Whether to fill the area beneath the lines.
+	Area bool
+}
+
+// renderChartStyle writes the chartStyle to the given encoder.
+func renderChartStyle(e *xml.Encoder) error {
+	tmpl := template.Must(template.New("chart-style").Parse(chartStyle))
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, nil); err != nil {
+		return err
+	}
+	// Strip away the enclosing `style` tag (required to used via the encoder)
+	styleTagStripped := strings.ReplaceAll(
+		strings.ReplaceAll(buf.String(), "<style>", ""), "</style>", "")
+	return style(e, styleTagStripped)
+}
+
+// niceCeiling rounds the given value up to the next number of the form
+// 1, 2, or 5 times a power of ten.
+func niceCeiling(value int) int {
+	if value <= 0 {
+		return 1
+	}
+	magnitude := int(math.Pow10(int(math.Floor(math.Log10(float64(value))))))
+	for _, factor := range []int{1, 2, 5, 10} {
+		if factor*magnitude >= value {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// maxValue computes the upper bound of the y-axis.
+func (c *LineChart) maxValue() int {
+	maxVal := 0
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
+	}
+	return niceCeiling(maxVal)
+}
+
+// plotArea returns the bounding box of the plot within the chart.
+func (c *LineChart) plotArea() image.Rectangle {
+	return image.Rect(lineChartLeftMargin, lineChartTopMargin,
+		lineChartWidth-lineChartRightMargin, lineChartHeight-lineChartAxisHeight)
+}
+
+// point computes the location of the i-th value within the plot area.
+func (c *LineChart) point(i int, value int, maxVal int) image.Point {
+	plot := c.plotArea()
+	x := plot.Min.X
+	if len(c.Labels) > 1 {
+		x += i * plot.Dx() / (len(c.Labels) - 1)
+	}
+	return image.Point{X: x, Y: plot.Max.Y - value*plot.Dy()/maxVal}
+}
+
+// rgb formats the given color as CSS color.
+func rgb(c color.RGBA) string {
+	return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+}
+
+// Render writes the line chart to the given xml.Encoder.
+func (c *LineChart) Render(e *xml.Encoder) error {
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			cssClassAttr("herdstat-chart"),
+			attr("width", strconv.Itoa(lineChartWidth)),
+			attr("height", strconv.Itoa(lineChartHeight)),
+		},
+	}, func(e *xml.Encoder) error {
+		if err := renderChartStyle(e); err != nil {
+			return err
+		}
+		fg := cssClassAttrs("herdstat-chart-fg")
+		if err := simpleText(e, image.Point{X: lineChartLeftMargin, Y: 18}, start,
+			append(fg, attr("font-weight", "800")), c.Title); err != nil {
+			return err
+		}
+		if err := c.renderGrid(e); err != nil {
+			return err
+		}
+		maxVal := c.maxValue()
+		for _, s := range c.Series {
+			if err := c.renderSeries(e, s, maxVal); err != nil {
+				return err
+			}
+		}
+		return c.renderLegend(e)
+	})
+}
+
+// renderGrid renders the horizontal grid lines including the y-axis labels
+// and the x-axis labels.
+func (c *LineChart) renderGrid(e *xml.Encoder) error {
+	plot := c.plotArea()
+	maxVal := c.maxValue()
+	fg := cssClassAttrs("herdstat-chart-fg")
+	for i := 0; i <= lineChartGridLines; i++ {
+		y := plot.Max.Y - i*plot.Dy()/lineChartGridLines
+		err := emptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "line"},
+			Attr: append([]xml.Attr{
+				attr("x1", strconv.Itoa(plot.Min.X)),
+				attr("y1", strconv.Itoa(y)),
+				attr("x2", strconv.Itoa(plot.Max.X)),
+				attr("y2", strconv.Itoa(y)),
+			}, cssClassAttrs("herdstat-chart-grid")...),
+		})
+		if err != nil {
+			return err
+		}
+		label := compactNumber(maxVal * i / lineChartGridLines)
+		if err := simpleText(e, image.Point{X: plot.Min.X - 6, Y: y + 4}, end, fg, label); err != nil {
+			return err
+		}
+	}
+	step := (len(c.Labels) + lineChartMaxLabels - 1) / lineChartMaxLabels
+	for i := 0; i < len(c.Labels); i += step {
+		location := image.Point{X: c.point(i, 0, maxVal).X, Y: plot.Max.Y + 18}
+		if err := simpleText(e, location, middle, fg, c.Labels[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderSeries renders the line (and area) of the given series.
+func (c *LineChart) renderSeries(e *xml.Encoder, s Series, maxVal int) error {
+	var points []string
+	for i, v := range s.Values {
+		p := c.point(i, v, maxVal)
+		points = append(points, fmt.Sprintf("%d,%d", p.X, p.Y))
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	if c.Area {
+		first := c.point(0, 0, maxVal)
+		last := c.point(len(s.Values)-1, 0, maxVal)
+		area := append(append([]string{fmt.Sprintf("%d,%d", first.X, first.Y)}, points...),
+			fmt.Sprintf("%d,%d", last.X, last.Y))
+		err := emptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "polygon"},
+			Attr: append([]xml.Attr{
+				attr("points", strings.Join(area, " ")),
+				attr("fill", rgb(s.Color)),
+			}, cssClassAttrs("herdstat-chart-area")...),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return emptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "polyline"},
+		Attr: append([]xml.Attr{
+			attr("points", strings.Join(points, " ")),
+			attr("stroke", rgb(s.Color)),
+		}, cssClassAttrs("herdstat-chart-line")...),
+	})
+}
+
+// renderLegend renders the names of the series in the top right corner if
+// there are multiple series.
+func (c *LineChart) renderLegend(e *xml.Encoder) error {
+	if len(c.Series) < 2 {
+		return nil
+	}
+	x := lineChartWidth - lineChartRightMargin
+	for i := len(c.Series) - 1; i >= 0; i-- {
+		s := c.Series[i]
+		err := simpleText(e, image.Point{X: x, Y: 18}, end, cssClassAttrs("herdstat-chart-fg"), s.Name)
+		if err != nil {
+			return err
+		}
+		x -= 7 * len(s.Name)
+		err = emptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "rect"},
+			Attr: []xml.Attr{
+				attr("x", strconv.Itoa(x-14)),
+				attr("y", "9"),
+				attr("width", "10"),
+				attr("height", "10"),
+				attr("rx", "2"),
+				attr("fill", rgb(s.Color)),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		x -= 24
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// markdownFunctions are the functions available in markdown templates.
+var markdownFunctions = template.FuncMap{
+	"escape": escapeMarkdown,
+	"hours":  formatHours,
+	"inc": func(i int) int {
+		return i + 1
+	},
+	"join": strings.Join,
+}
+
+// markdownEscaper escapes the characters of untrusted texts, e.g., issue
+// titles and contributor names, that would otherwise be interpreted as markdown
+// or break the structure of tables and lists.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"`", "\\`",
+	"*", "\\*",
+	"_", "\\_",
+	"[", "\\[",
+	"]", "\\]",
+	"<", "\\<",
+	">", "\\>",
+	"|", "\\|",
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// escapeMarkdown escapes the given untrusted text such that it's rendered
+// literally in markdown documents on a single line.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// formatHours formats the given number of hours using the most appropriate
+// unit.
+func formatHours(hours float64) string {
+	switch {
+	case hours < 1:
+		return fmt.Sprintf("%.0fm", hours*60)
+	case hours < 48:
+		return fmt.Sprintf("%.1fh", hours)
+	}
+	return fmt.Sprintf("%.1fd", hours/24)
+}
+
+// renderMarkdown renders the given markdown template using the given data.
+func renderMarkdown(name string, markdownTemplate string, data any) (string, error) {
+	tmpl := template.Must(template.New(name).Funcs(markdownFunctions).Parse(markdownTemplate))
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"time"
+)
+
+// monthFormat is the format used for months in machine-readable output.
+const monthFormat = "2006-01"
+
+// MonthlyContributors is the number of new and returning contributors active
+// in a single month.
+type MonthlyContributors struct {
+
+	// The month in 'YYYY-MM' notation.
+	Month string `json:"month" yaml:"month"`
+
+	// The number of contributors whose first contribution was made in the
+	// month.
+	New int `json:"new" yaml:"new"`
+
+	// The number of contributors active in the month who contributed before.
+	Returning int `json:"returning" yaml:"returning"`
+}
+
+// NewContributorsReport classifies the contributors active within the
+// analyzed period as first-time or returning contributors.
+type NewContributorsReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The first day considered when looking for prior contributions.
+	LookbackFrom string `json:"lookbackFrom" yaml:"lookbackFrom"`
+
+	// The number of contributors active within the analyzed period.
+	Active int `json:"active" yaml:"active"`
+
+	// The number of active contributors without prior contributions.
+	New int `json:"new" yaml:"new"`
+
+	// The number of active contributors with prior contributions.
+	Returning int `json:"returning" yaml:"returning"`
+
+	// The number of new and returning contributors per month in
+	// chronological order.
+	Monthly []MonthlyContributors `json:"monthly" yaml:"monthly"`
+}
+
+// monthOf returns the first day of the month the given date falls into.
+func monthOf(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+}
+
+// FirstContributions determines the first contribution of each contributor
+// made since the given lookback date until the given day.
+func FirstContributions(contributions []Contribution, lastDay time.Time, lookbackFrom time.Time) map[string]Contribution {
+	firstContributions := make(map[string]Contribution)
+	for _, c := range contributions {
+		if c.Date.Before(lookbackFrom) || c.Date.After(lastDay) {
+			continue
+		}
+		contributor := c.Contributor()
+		if first, ok := firstContributions[contributor]; !ok || c.Date.Before(first.Date) {
+			firstContributions[contributor] = c
+		}
+	}
+	return firstContributions
+}
+
+// NewNewContributorsReport classifies the contributors active within the 52
+// weeks ending with the given day. Contributors are considered new if they
+// haven't contributed since the given lookback date before.
+func NewNewContributorsReport(contributions []Contribution, lastDay time.Time, lookbackFrom time.Time) *NewContributorsReport {
+	firstDay := lastDay.AddDate(0, 0, -52*7+1)
+	report := &NewContributorsReport{
+		From:         firstDay.Format(dateFormat),
+		Until:        lastDay.Format(dateFormat),
+		LookbackFrom: lookbackFrom.Format(dateFormat),
+	}
+
+	firstContributions := FirstContributions(contributions, lastDay, lookbackFrom)
+	activeMonths := make(map[string]map[string]bool)
+	for _, c := range contributions {
+		if InPeriod(c.Date, lastDay) {
+			month := c.Date.Format(monthFormat)
+			if activeMonths[month] == nil {
+				activeMonths[month] = make(map[string]bool)
+			}
+			activeMonths[month][c.Contributor()] = true
+		}
+	}
+
+	active := make(map[string]bool)
+	for month := monthOf(firstDay); !month.After(lastDay); month = month.AddDate(0, 1, 0) {
+		monthly := MonthlyContributors{Month: month.Format(monthFormat)}
+		for contributor := range activeMonths[monthly.Month] {
+			active[contributor] = true
+			if monthOf(firstContributions[contributor].Date).Equal(month) {
+				monthly.New++
+			} else {
+				monthly.Returning++
+			}
+		}
+		report.Monthly = append(report.Monthly, monthly)
+	}
+
+	report.Active = len(active)
+	for contributor := range active {
+		if InPeriod(firstContributions[contributor].Date, lastDay) {
+			report.New++
+		} else {
+			report.Returning++
+		}
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering new contributors reports as
+	// markdown.
+	//go:embed new-contributors.gomd
+	newContributorsTemplate string
+)
+
+// Markdown renders the new contributors report as markdown.
+func (r *NewContributorsReport) Markdown() (string, error) {
+	return renderMarkdown("new-contributors", newContributorsTemplate, r)
+}
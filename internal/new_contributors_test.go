@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Classifying new and returning contributors", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	lookbackFrom := lastDay.AddDate(-3, 0, 0)
+	contributions := []Contribution{
+		{Login: "jdoe", Date: lastDay.AddDate(-1, -6, 0)},
+		{Login: "jdoe", Date: dateparse.MustParse("2023-03-01")},
+		{Login: "jroe", Date: dateparse.MustParse("2023-02-10")},
+		{Login: "jroe", Date: dateparse.MustParse("2023-03-10")},
+		{Login: "mmoe", Date: lastDay.AddDate(-5, 0, 0)},
+		{Login: "mmoe", Date: dateparse.MustParse("2023-03-02")},
+	}
+	report := NewNewContributorsReport(contributions, lastDay, lookbackFrom)
+
+	It("classifies contributors by their prior contributions within the lookback period", func() {
+		Expect(report.Active).To(Equal(3))
+		Expect(report.New).To(Equal(2))
+		Expect(report.Returning).To(Equal(1))
+	})
+	It("computes the monthly trend", func() {
+		Expect(report.Monthly).To(HaveLen(13))
+		Expect(report.Monthly[0].Month).To(Equal("2022-03"))
+		Expect(report.Monthly[11]).To(Equal(MonthlyContributors{Month: "2023-02", New: 1}))
+		Expect(report.Monthly[12]).To(Equal(MonthlyContributors{Month: "2023-03", New: 1, Returning: 2}))
+	})
+	It("renders as markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| 2023-03 | 1 | 2 |"))
+	})
+})
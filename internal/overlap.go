@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// RepositoryContributors is the number of contributors of a repository.
+type RepositoryContributors struct {
+
+	// The repository in 'owner/name' notation.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// The number of distinct contributors.
+	Contributors int `json:"contributors" yaml:"contributors"`
+}
+
+// Connector is a contributor active in multiple repositories.
+type Connector struct {
+
+	// The identifier of the contributor.
+	Contributor string `json:"contributor" yaml:"contributor"`
+
+	// The repositories the contributor is active in sorted by name.
+	Repositories []string `json:"repositories" yaml:"repositories"`
+}
+
+// OverlapReport describes how many contributors are shared between
+// repositories.
+type OverlapReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The repositories with at least one contribution sorted by name.
+	Repositories []RepositoryContributors `json:"repositories" yaml:"repositories"`
+
+	// The number of contributors shared between the repositories. The element
+	// at (i, j) is the number of contributors active in both the i-th and the
+	// j-th repository. The diagonal holds the number of contributors of the
+	// respective repository.
+	Shared [][]int `json:"shared" yaml:"shared"`
+
+	// The contributors active in the most repositories ordered by descending
+	// number of repositories.
+	Connectors []Connector `json:"connectors" yaml:"connectors"`
+}
+
+// NewOverlapReport computes the overlap between the contributors of the
+// repositories the given contributions made within the 52 weeks ending with
+// the given day have been made to. Lists up to the given number of contributors
+// active in more than one repository.
+func NewOverlapReport(contributions []Contribution, lastDay time.Time, connectors int) *OverlapReport {
+	report := &OverlapReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	active := make(map[string]map[string]bool)
+	for repository, c := range GroupByRepository(contributions) {
+		contributors := ContributionsPerContributor(c, lastDay)
+		if len(contributors) == 0 {
+			continue
+		}
+		active[repository] = make(map[string]bool)
+		for contributor := range contributors {
+			active[repository][contributor] = true
+		}
+	}
+	repositories := Keys(active)
+	sort.Strings(repositories)
+
+	memberships := make(map[string][]string)
+	report.Shared = make([][]int, len(repositories))
+	for i, a := range repositories {
+		report.Repositories = append(report.Repositories, RepositoryContributors{
+			Repository:   a,
+			Contributors: len(active[a]),
+		})
+		for contributor := range active[a] {
+			memberships[contributor] = append(memberships[contributor], a)
+		}
+		report.Shared[i] = make([]int, len(repositories))
+		for j, b := range repositories {
+			for contributor := range active[a] {
+				if active[b][contributor] {
+					report.Shared[i][j]++
+				}
+			}
+		}
+	}
+
+	for contributor, repos := range memberships {
+		if len(repos) > 1 {
+			sort.Strings(repos)
+			report.Connectors = append(report.Connectors, Connector{Contributor: contributor, Repositories: repos})
+		}
+	}
+	sort.Slice(report.Connectors, func(i, j int) bool {
+		a, b := report.Connectors[i], report.Connectors[j]
+		if len(a.Repositories) != len(b.Repositories) {
+			return len(a.Repositories) > len(b.Repositories)
+		}
+		return a.Contributor < b.Contributor
+	})
+	if len(report.Connectors) > connectors {
+		report.Connectors = report.Connectors[:connectors]
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering overlap reports as markdown.
+	//go:embed overlap.gomd
+	overlapTemplate string
+)
+
+// Markdown renders the overlap report as markdown.
+func (r *OverlapReport) Markdown() (string, error) {
+	return renderMarkdown("overlap", overlapTemplate, r)
+}
+
+// CSV renders the overlap matrix as CSV records.
+func (r *OverlapReport) CSV() [][]string {
+	header := []string{"repository"}
+	for _, repository := range r.Repositories {
+		header = append(header, repository.Repository)
+	}
+	records := [][]string{header}
+	for i, repository := range r.Repositories {
+		record := []string{repository.Repository}
+		for _, shared := range r.Shared[i] {
+			record = append(record, strconv.Itoa(shared))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Dimensions of the overlap heatmap.
+const (
+	overlapLabelWidth   = 180
+	overlapColumnWidth  = 40
+	overlapRowHeight    = 14
+	overlapHeaderHeight = 20
+	overlapMargin       = 10
+)
+
+// OverlapHeatmap renders the overlap matrix as a heatmap with cells colored by
+// the share of the contributors of the row's repository that are also active
+// in the column's repository.
+type OverlapHeatmap struct {
+
+	// The rendered overlap report.
+	Report *OverlapReport
+
+	// The graph providing the styling (colors, levels, tooltips).
+	style *ContributionGraph
+}
+
+// NewOverlapHeatmap creates a new OverlapHeatmap for the given report using the
+// given coloring and number of levels.
+func NewOverlapHeatmap(report *OverlapReport, coloring Coloring, levels uint8, noTooltips bool) *OverlapHeatmap {
+	return &OverlapHeatmap{
+		Report: report,
+		style: &ContributionGraph{
+			Coloring:   coloring,
+			Levels:     levels,
+			NoTooltips: noTooltips,
+			MaxCount:   100,
+		},
+	}
+}
+
+// size computes the width and height of the rendered heatmap.
+func (h *OverlapHeatmap) size() (int, int) {
+	n := len(h.Report.Repositories)
+	return overlapLabelWidth + n*overlapColumnWidth + overlapMargin,
+		overlapHeaderHeight + n*overlapRowHeight + overlapMargin
+}
+
+// Render writes the overlap heatmap to the given xml.Encoder. Columns are
+// labeled by the number of the repository in the row labels.
+func (h *OverlapHeatmap) Render(e *xml.Encoder) error {
+	width, height := h.size()
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			cssClassAttr("herdstat-contribution-graph", "herdstat-contribution-graph-var"),
+			attr("width", strconv.Itoa(width)),
+			attr("height", strconv.Itoa(height)),
+		},
+	}, func(e *xml.Encoder) error {
+		if err := h.style.renderStyle(e); err != nil {
+			return err
+		}
+		fg := cssClassAttrs("herdstat-contribution-graph-fg")
+		for j := range h.Report.Repositories {
+			x := overlapLabelWidth + j*overlapColumnWidth
+			if err := simpleText(e, image.Point{X: x, Y: 12}, start, fg, fmt.Sprintf("#%d", j+1)); err != nil {
+				return err
+			}
+		}
+		for i := range h.Report.Repositories {
+			if err := h.renderRow(e, i, overlapHeaderHeight+i*overlapRowHeight); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// renderRow renders the row of the i-th repository at the given vertical
+// position.
+func (h *OverlapHeatmap) renderRow(e *xml.Encoder, i int, y int) error {
+	fg := cssClassAttrs("herdstat-contribution-graph-fg")
+	repository := h.Report.Repositories[i]
+	err := simpleText(e, image.Point{X: overlapMargin, Y: y + 9}, start, fg,
+		fmt.Sprintf("#%d %s", i+1, repository.Repository))
+	if err != nil {
+		return err
+	}
+	for j, shared := range h.Report.Shared[i] {
+		rate := shared * 100 / repository.Contributors
+		x := overlapLabelWidth + j*overlapColumnWidth
+		classes := cssClassAttrs(
+			"herdstat-contribution-graph-cell",
+			fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", h.style.level(ContributionRecord{Count: rate})),
+		)
+		if h.style.NoTooltips {
+			err = coloredRoundedRect(e, image.Point{X: x, Y: y}, classes)
+		} else {
+			other := h.Report.Repositories[j].Repository
+			err = nonEmptyElement(e, xml.StartElement{
+				Name: xml.Name{Local: "rect"},
+				Attr: append([]xml.Attr{
+					attr("x", strconv.Itoa(x)),
+					attr("y", strconv.Itoa(y)),
+					attr("rx", "2"),
+				}, classes...),
+			}, func(e *xml.Encoder) error {
+				return nonEmptyElement(e, xml.StartElement{
+					Name: xml.Name{Local: "title"},
+				}, func(e *xml.Encoder) error {
+					return e.EncodeToken(xml.CharData(fmt.Sprintf("%d of %d contributors of %s are active in %s",
+						shared, repository.Contributors, repository.Repository, other)))
+				})
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if err := simpleText(e, image.Point{X: x + 14, Y: y + 9}, start, fg, strconv.Itoa(shared)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
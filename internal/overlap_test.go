@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Overlap reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	date := dateparse.MustParse("2023-03-01")
+	contribution := func(repository string, login string) Contribution {
+		return Contribution{Repository: repository, Login: login, Date: date}
+	}
+	contributions := []Contribution{
+		contribution("herdstat/herdstat", "alice"),
+		contribution("herdstat/herdstat", "bob"),
+		contribution("herdstat/herdstat", "carol"),
+		contribution("herdstat/action", "alice"),
+		contribution("herdstat/action", "bob"),
+		contribution("herdstat/docs", "alice"),
+		// Outside the analyzed period
+		{Repository: "herdstat/docs", Login: "bob", Date: dateparse.MustParse("2021-03-01")},
+	}
+	report := NewOverlapReport(contributions, lastDay, 1)
+
+	It("lists the repositories sorted by name", func() {
+		Expect(report.Repositories).To(Equal([]RepositoryContributors{
+			{Repository: "herdstat/action", Contributors: 2},
+			{Repository: "herdstat/docs", Contributors: 1},
+			{Repository: "herdstat/herdstat", Contributors: 3},
+		}))
+	})
+
+	It("counts the shared contributors", func() {
+		Expect(report.Shared).To(Equal([][]int{
+			{2, 1, 2},
+			{1, 1, 1},
+			{2, 1, 3},
+		}))
+	})
+
+	It("lists the contributors active in the most repositories", func() {
+		Expect(report.Connectors).To(Equal([]Connector{
+			{Contributor: "alice", Repositories: []string{"herdstat/action", "herdstat/docs", "herdstat/herdstat"}},
+		}))
+	})
+
+	It("renders the matrix as CSV", func() {
+		Expect(report.CSV()[1]).To(Equal([]string{"herdstat/action", "2", "1", "2"}))
+	})
+
+	It("renders markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| #3 herdstat/herdstat | 2 | 1 | 3 |"))
+		Expect(md).To(ContainSubstring("| alice | herdstat/action, herdstat/docs, herdstat/herdstat |"))
+	})
+
+	It("renders a heatmap", func() {
+		var buf bytes.Buffer
+		enc := xml.NewEncoder(&buf)
+		Expect(NewOverlapHeatmap(report, testColoring, 5, false).Render(enc)).To(Succeed())
+		Expect(enc.Flush()).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("1 of 2 contributors of herdstat/action are active in herdstat/docs"))
+	})
+})
@@ -0,0 +1,370 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// The subset of the Parquet file format (see
+// https://github.com/apache/parquet-format) used to write flat datasets. Each
+// dataset is written as a single row group with one uncompressed, PLAIN encoded
+// data page per column. All columns are required, i.e., there are neither
+// definition nor repetition levels.
+
+// parquetMagic starts and ends every Parquet file.
+const parquetMagic = "PAR1"
+
+// parquetType is the physical type of a Parquet column.
+type parquetType int32
+
+const (
+	parquetBoolean   parquetType = 0
+	parquetInt32     parquetType = 1
+	parquetInt64     parquetType = 2
+	parquetByteArray parquetType = 6
+)
+
+// parquetConvertedType is the logical type of a Parquet column. Only the
+// legacy converted types are used as they are understood by all readers.
+type parquetConvertedType int32
+
+const (
+	parquetNoConvertedType parquetConvertedType = -1
+	parquetUTF8            parquetConvertedType = 0
+	parquetDate            parquetConvertedType = 6
+	parquetTimestampMillis parquetConvertedType = 9
+)
+
+const (
+	parquetRequired      = 0
+	parquetPlainEncoding = 0
+	parquetRLEEncoding   = 3
+	parquetUncompressed  = 0
+	parquetDataPage      = 0
+)
+
+// parquetColumn is a column of PLAIN encoded values.
+type parquetColumn struct {
+	name          string
+	physicalType  parquetType
+	convertedType parquetConvertedType
+	data          []byte
+}
+
+// stringColumn returns a column of UTF-8 strings.
+func stringColumn(name string, values []string) parquetColumn {
+	var b bytes.Buffer
+	for _, v := range values {
+		_ = binary.Write(&b, binary.LittleEndian, uint32(len(v)))
+		b.WriteString(v)
+	}
+	return parquetColumn{name: name, physicalType: parquetByteArray, convertedType: parquetUTF8, data: b.Bytes()}
+}
+
+// int32Column returns a column of 32-bit integers with the given logical type.
+func int32Column(name string, convertedType parquetConvertedType, values []int32) parquetColumn {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], uint32(v))
+	}
+	return parquetColumn{name: name, physicalType: parquetInt32, convertedType: convertedType, data: data}
+}
+
+// intColumn returns a column of integers.
+func intColumn(name string, values []int) parquetColumn {
+	converted := make([]int32, len(values))
+	for i, v := range values {
+		converted[i] = int32(v)
+	}
+	return int32Column(name, parquetNoConvertedType, converted)
+}
+
+// dateColumn returns a column of dates given by the days since the Unix epoch.
+func dateColumn(name string, values []time.Time) parquetColumn {
+	days := make([]int32, len(values))
+	for i, v := range values {
+		days[i] = int32(time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+	}
+	return int32Column(name, parquetDate, days)
+}
+
+// timestampColumn returns a column of points in time with millisecond
+// precision.
+func timestampColumn(name string, values []time.Time) parquetColumn {
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(data[8*i:], uint64(v.UnixMilli()))
+	}
+	return parquetColumn{name: name, physicalType: parquetInt64, convertedType: parquetTimestampMillis, data: data}
+}
+
+// boolColumn returns a column of booleans.
+func boolColumn(name string, values []bool) parquetColumn {
+	data := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			data[i/8] |= 1 << (i % 8)
+		}
+	}
+	return parquetColumn{name: name, physicalType: parquetBoolean, convertedType: parquetNoConvertedType, data: data}
+}
+
+// thriftWriter encodes structures in the Thrift compact protocol Parquet
+// metadata is serialized with.
+type thriftWriter struct {
+	b bytes.Buffer
+
+	// The IDs of the last fields written to each open structure.
+	lastFields []int16
+}
+
+const (
+	thriftStop   = 0
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+func (t *thriftWriter) varint(v uint64) {
+	t.b.Write(binary.AppendUvarint(nil, v))
+}
+
+func (t *thriftWriter) zigzag(v int64) {
+	t.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := &t.lastFields[len(t.lastFields)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.b.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.b.WriteByte(fieldType)
+		t.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) beginStruct() {
+	t.lastFields = append(t.lastFields, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.b.WriteByte(thriftStop)
+	t.lastFields = t.lastFields[:len(t.lastFields)-1]
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.zigzag(v)
+}
+
+func (t *thriftWriter) binary(v string) {
+	t.varint(uint64(len(v)))
+	t.b.WriteString(v)
+}
+
+func (t *thriftWriter) string(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary(v)
+}
+
+func (t *thriftWriter) listHeader(id int16, elementType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.b.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		t.b.WriteByte(0xf0 | elementType)
+		t.varint(uint64(size))
+	}
+}
+
+// structField writes a field holding the structure written by the given
+// function.
+func (t *thriftWriter) structField(id int16, write func()) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+	write()
+	t.endStruct()
+}
+
+// structList writes a field holding a list of size structures, each written
+// by the given function.
+func (t *thriftWriter) structList(id int16, size int, write func(i int)) {
+	t.listHeader(id, thriftStruct, size)
+	for i := 0; i < size; i++ {
+		t.beginStruct()
+		write(i)
+		t.endStruct()
+	}
+}
+
+// newThriftWriter returns a writer for a top-level structure.
+func newThriftWriter() *thriftWriter {
+	t := &thriftWriter{}
+	t.beginStruct()
+	return t
+}
+
+// bytes ends the top-level structure and returns its encoding.
+func (t *thriftWriter) bytes() []byte {
+	t.endStruct()
+	return t.b.Bytes()
+}
+
+// columnChunk is a column written to the file.
+type columnChunk struct {
+	parquetColumn
+	offset int64
+	size   int64
+}
+
+// writeParquet writes a Parquet file with the given number of rows and the
+// given columns to the given writer.
+func writeParquet(w io.Writer, rows int, columns []parquetColumn) error {
+	var b bytes.Buffer
+	b.WriteString(parquetMagic)
+	chunks := make([]columnChunk, len(columns))
+	for i, c := range columns {
+		header := newThriftWriter()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(c.data)))
+		header.i32(3, int32(len(c.data)))
+		header.structField(5, func() {
+			header.i32(1, int32(rows))
+			header.i32(2, parquetPlainEncoding)
+			header.i32(3, parquetRLEEncoding)
+			header.i32(4, parquetRLEEncoding)
+		})
+		chunks[i] = columnChunk{parquetColumn: c, offset: int64(b.Len())}
+		b.Write(header.bytes())
+		b.Write(c.data)
+		chunks[i].size = int64(b.Len()) - chunks[i].offset
+	}
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+	metadata := newThriftWriter()
+	metadata.i32(1, 1)
+	metadata.structList(2, len(columns)+1, func(i int) {
+		if i == 0 {
+			metadata.string(4, "schema")
+			metadata.i32(5, int32(len(columns)))
+			return
+		}
+		c := columns[i-1]
+		metadata.i32(1, int32(c.physicalType))
+		metadata.i32(3, parquetRequired)
+		metadata.string(4, c.name)
+		if c.convertedType != parquetNoConvertedType {
+			metadata.i32(6, int32(c.convertedType))
+		}
+	})
+	metadata.i64(3, int64(rows))
+	metadata.structList(4, 1, func(int) {
+		metadata.structList(1, len(chunks), func(i int) {
+			c := chunks[i]
+			metadata.i64(2, c.offset)
+			metadata.structField(3, func() {
+				metadata.i32(1, int32(c.physicalType))
+				metadata.listHeader(2, thriftI32, 2)
+				metadata.zigzag(parquetPlainEncoding)
+				metadata.zigzag(parquetRLEEncoding)
+				metadata.listHeader(3, thriftBinary, 1)
+				metadata.binary(c.name)
+				metadata.i32(4, parquetUncompressed)
+				metadata.i64(5, int64(rows))
+				metadata.i64(6, c.size)
+				metadata.i64(7, c.size)
+				metadata.i64(9, c.offset)
+			})
+		})
+		metadata.i64(2, total)
+		metadata.i64(3, int64(rows))
+	})
+	metadata.string(6, "herdstat")
+	footer := metadata.bytes()
+	b.Write(footer)
+	_ = binary.Write(&b, binary.LittleEndian, uint32(len(footer)))
+	b.WriteString(parquetMagic)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteContributionsParquet writes the given contributions as Parquet file
+// with one row per contribution to the given writer. Empty strings denote
+// missing values. Changed files aren't included.
+func WriteContributionsParquet(w io.Writer, contributions []Contribution) error {
+	n := len(contributions)
+	types, repositories, contributors := make([]string, n), make([]string, n), make([]string, n)
+	logins, names, emails := make([]string, n), make([]string, n), make([]string, n)
+	urls, hashes, subjects := make([]string, n), make([]string, n), make([]string, n)
+	dates := make([]time.Time, n)
+	signed, signedOff := make([]bool, n), make([]bool, n)
+	additions, deletions := make([]int, n), make([]int, n)
+	for i, c := range contributions {
+		types[i], repositories[i], contributors[i] = string(c.Type), c.Repository, c.Contributor()
+		logins[i], names[i], emails[i] = c.Login, c.Name, c.Email
+		urls[i], hashes[i], subjects[i] = c.URL, c.Hash, c.Subject
+		dates[i] = c.Date
+		signed[i], signedOff[i] = c.Signed, c.SignedOff
+		additions[i], deletions[i] = c.Additions, c.Deletions
+	}
+	return writeParquet(w, n, []parquetColumn{
+		stringColumn("type", types),
+		stringColumn("repository", repositories),
+		stringColumn("contributor", contributors),
+		stringColumn("login", logins),
+		stringColumn("name", names),
+		stringColumn("email", emails),
+		timestampColumn("date", dates),
+		stringColumn("url", urls),
+		stringColumn("hash", hashes),
+		stringColumn("subject", subjects),
+		boolColumn("signed", signed),
+		boolColumn("signed_off", signedOff),
+		intColumn("additions", additions),
+		intColumn("deletions", deletions),
+	})
+}
+
+// WriteDailyContributionsParquet writes the given daily aggregate records as
+// Parquet file with one row per day and repository to the given writer.
+func WriteDailyContributionsParquet(w io.Writer, records []AggregateRecord) error {
+	n := len(records)
+	dates := make([]time.Time, n)
+	repositories := make([]string, n)
+	commits, issues, pullRequests, contributors := make([]int, n), make([]int, n), make([]int, n), make([]int, n)
+	for i, r := range records {
+		date, err := time.Parse(dateFormat, r.Date)
+		if err != nil {
+			return err
+		}
+		dates[i], repositories[i] = date, r.Repository
+		commits[i], issues[i], pullRequests[i], contributors[i] = r.Commits, r.Issues, r.PullRequests, r.Contributors
+	}
+	return writeParquet(w, n, []parquetColumn{
+		dateColumn("date", dates),
+		stringColumn("repository", repositories),
+		intColumn("commits", commits),
+		intColumn("issues", issues),
+		intColumn("pull_requests", pullRequests),
+		intColumn("contributors", contributors),
+	})
+}
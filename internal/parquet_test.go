@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Parquet export", func() {
+	lastDay := dateparse.MustParse("2023-04-01")
+
+	It("encodes Thrift structures in the compact protocol", func() {
+		t := newThriftWriter()
+		t.i32(1, -1)
+		t.i64(17, 300)
+		t.structList(18, 1, func(int) {
+			t.string(4, "a")
+		})
+		Expect(t.bytes()).To(Equal([]byte{
+			0x15, 0x01, // field 1 (delta 1): i32 -1
+			0x06, 0x22, 0xd8, 0x04, // field 17 (long form): i64 300
+			0x19, 0x1c, // field 18 (delta 1): list of one struct
+			0x48, 0x01, 'a', 0x00, // field 4: string "a", stop
+			0x00, // stop
+		}))
+	})
+
+	It("encodes column values plainly", func() {
+		Expect(boolColumn("b", []bool{true, false, true, false, false, false, false, false, true}).data).
+			To(Equal([]byte{0x05, 0x01}))
+		Expect(stringColumn("s", []string{"ab"}).data).To(Equal([]byte{2, 0, 0, 0, 'a', 'b'}))
+		Expect(dateColumn("d", []time.Time{lastDay}).data).To(Equal([]byte{0xf8, 0x4b, 0, 0}))
+	})
+
+	It("frames the data with the metadata footer", func() {
+		var b bytes.Buffer
+		Expect(WriteDailyContributionsParquet(&b, NewAggregateExport([]Contribution{
+			{Type: CommitContribution, Repository: "herdstat/herdstat", Login: "jane", Date: lastDay},
+		}, lastDay, DailyGranularity).Records)).To(Succeed())
+		data := b.Bytes()
+		Expect(string(data[:4])).To(Equal(parquetMagic))
+		Expect(string(data[len(data)-4:])).To(Equal(parquetMagic))
+		footer := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+		Expect(footer).To(BeNumerically("<", len(data)-12))
+		Expect(string(data[len(data)-8-footer:])).To(ContainSubstring("pull_requests"))
+	})
+})
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"time"
+)
+
+// PullRequestMetrics describes how quickly pull requests opened in a
+// repository (or a set of repositories) are reviewed.
+type PullRequestMetrics struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall metrics.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The number of pull requests opened.
+	PullRequests int `json:"pullRequests" yaml:"pullRequests"`
+
+	// The number of opened pull requests that have been reviewed.
+	Reviewed int `json:"reviewed" yaml:"reviewed"`
+
+	// The time until the first review by someone other than the author.
+	TimeToFirstReview DurationStatistics `json:"timeToFirstReview" yaml:"timeToFirstReview"`
+}
+
+// PullRequestMetricsReport contains the pull request metrics per repository
+// and overall.
+type PullRequestMetricsReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The metrics across all repositories.
+	Overall PullRequestMetrics `json:"overall" yaml:"overall"`
+
+	// The metrics of the individual repositories sorted by name.
+	Repositories []PullRequestMetrics `json:"repositories" yaml:"repositories"`
+}
+
+// NewPullRequestMetrics computes the metrics of the given pull requests.
+// Reviews after the given day are ignored.
+func NewPullRequestMetrics(repository string, pullRequests []PullRequest, lastDay time.Time) PullRequestMetrics {
+	metrics := PullRequestMetrics{Repository: repository}
+	var reviewTimes []time.Duration
+	for _, pr := range pullRequests {
+		if !InPeriod(pr.Created, lastDay) {
+			continue
+		}
+		metrics.PullRequests++
+		if len(pr.Reviews) > 0 && !pr.Reviews[0].Submitted.After(lastDay) {
+			metrics.Reviewed++
+			reviewTimes = append(reviewTimes, pr.Reviews[0].Submitted.Sub(pr.Created))
+		}
+	}
+	metrics.TimeToFirstReview = newDurationStatistics(reviewTimes)
+	return metrics
+}
+
+// NewPullRequestMetricsReport computes the metrics of the given pull requests
+// opened within the 52 weeks ending with the given day.
+func NewPullRequestMetricsReport(pullRequests []PullRequest, lastDay time.Time) *PullRequestMetricsReport {
+	report := &PullRequestMetricsReport{
+		From:    lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until:   lastDay.Format(dateFormat),
+		Overall: NewPullRequestMetrics("", pullRequests, lastDay),
+	}
+	byRepository := make(map[string][]PullRequest)
+	for _, pr := range pullRequests {
+		byRepository[pr.Repository] = append(byRepository[pr.Repository], pr)
+	}
+	for repository, prs := range byRepository {
+		report.Repositories = append(report.Repositories, NewPullRequestMetrics(repository, prs, lastDay))
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering pull request metrics reports
+	// as markdown.
+	//go:embed pr-metrics.gomd
+	pullRequestMetricsTemplate string
+)
+
+// Markdown renders the pull request metrics report as markdown.
+func (r *PullRequestMetricsReport) Markdown() (string, error) {
+	return renderMarkdown("pr-metrics", pullRequestMetricsTemplate, r)
+}
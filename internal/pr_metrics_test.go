@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pull request metrics reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	pullRequests := []PullRequest{
+		{Repository: "herdstat/herdstat", Created: dateparse.MustParse("2023-03-01 10:00"),
+			Reviews: []Review{{Reviewer: "alice", Submitted: dateparse.MustParse("2023-03-01 12:00")}}},
+		{Repository: "herdstat/herdstat", Created: dateparse.MustParse("2023-03-02 10:00"),
+			Reviews: []Review{{Reviewer: "bob", Submitted: dateparse.MustParse("2023-03-03 10:00")}}},
+		{Repository: "herdstat/action", Created: dateparse.MustParse("2023-03-02 10:00")},
+		// Reviewed after the analyzed period
+		{Repository: "herdstat/action", Created: dateparse.MustParse("2023-03-10 10:00"),
+			Reviews: []Review{{Reviewer: "bob", Submitted: dateparse.MustParse("2023-03-20 10:00")}}},
+		// Opened before the analyzed period
+		{Repository: "herdstat/action", Created: dateparse.MustParse("2021-03-10 10:00")},
+	}
+	report := NewPullRequestMetricsReport(pullRequests, lastDay)
+
+	It("computes the time to first review across all repositories", func() {
+		Expect(report.Overall).To(Equal(PullRequestMetrics{
+			PullRequests: 4,
+			Reviewed:     2,
+			TimeToFirstReview: DurationStatistics{
+				Count:       2,
+				MedianHours: 13,
+				P90Hours:    21.8,
+			},
+		}))
+	})
+
+	It("computes the metrics per repository sorted by name", func() {
+		Expect(report.Repositories).To(HaveLen(2))
+		Expect(report.Repositories[0].Repository).To(Equal("herdstat/action"))
+		Expect(report.Repositories[0].Reviewed).To(Equal(0))
+		Expect(report.Repositories[1].TimeToFirstReview.Count).To(Equal(2))
+	})
+
+	It("renders markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| herdstat/action | 2 | 0 | - | - |"))
+	})
+})
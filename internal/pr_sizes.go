@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"math"
+	"sort"
+	"time"
+)
+
+// pullRequestSizes are the size classes of pull requests by number of changed
+// lines. Each class covers the pull requests changing less than max lines and
+// not falling into a smaller class. A max of 0 denotes an open upper bound.
+var pullRequestSizes = []struct {
+	name string
+	max  int
+}{
+	{"XS", 10},
+	{"S", 50},
+	{"M", 250},
+	{"L", 1000},
+	{"XL", 0},
+}
+
+// PullRequestSize describes the merged pull requests of a size class.
+type PullRequestSize struct {
+
+	// The name of the size class (one of 'XS', 'S', 'M', 'L', or 'XL').
+	Size string `json:"size" yaml:"size"`
+
+	// The minimal number of lines changed by the pull requests in the class.
+	MinLines int `json:"minLines" yaml:"minLines"`
+
+	// The maximal number of lines changed by the pull requests in the class.
+	// Zero if unbounded.
+	MaxLines int `json:"maxLines,omitempty" yaml:"maxLines,omitempty"`
+
+	// The number of merged pull requests in the class.
+	PullRequests int `json:"pullRequests" yaml:"pullRequests"`
+
+	// The share of merged pull requests in the class in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+
+	// The median number of files changed by the pull requests in the class.
+	MedianFiles float64 `json:"medianFiles" yaml:"medianFiles"`
+
+	// The time from opening to merging the pull requests in the class.
+	TimeToMerge DurationStatistics `json:"timeToMerge" yaml:"timeToMerge"`
+}
+
+// PullRequestSizeReport describes the distribution of the sizes of merged pull
+// requests and how the size relates to the time to merge.
+type PullRequestSizeReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The number of merged pull requests.
+	PullRequests int `json:"pullRequests" yaml:"pullRequests"`
+
+	// The Spearman rank correlation between the number of changed lines and
+	// the time to merge ranging from -1 to 1. Positive values indicate that
+	// larger pull requests take longer to merge.
+	Correlation float64 `json:"correlation" yaml:"correlation"`
+
+	// The merged pull requests by size class from small to large.
+	Sizes []PullRequestSize `json:"sizes" yaml:"sizes"`
+}
+
+// ranks computes the ranks of the given values averaging the ranks of ties.
+func ranks(values []float64) []float64 {
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return values[indices[i]] < values[indices[j]]
+	})
+	r := make([]float64, len(values))
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && values[indices[j+1]] == values[indices[i]] {
+			j++
+		}
+		for k := i; k <= j; k++ {
+			r[indices[k]] = float64(i+j)/2 + 1
+		}
+		i = j + 1
+	}
+	return r
+}
+
+// spearman computes the Spearman rank correlation coefficient of the given
+// paired values rounded to two decimal places. Returns 0 if it is undefined.
+func spearman(x, y []float64) float64 {
+	if len(x) < 2 {
+		return 0
+	}
+	rx, ry := ranks(x), ranks(y)
+	mean := float64(len(x)+1) / 2
+	var cov, varX, varY float64
+	for i := range rx {
+		cov += (rx[i] - mean) * (ry[i] - mean)
+		varX += (rx[i] - mean) * (rx[i] - mean)
+		varY += (ry[i] - mean) * (ry[i] - mean)
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return math.Round(cov/math.Sqrt(varX*varY)*100) / 100
+}
+
+// NewPullRequestSizeReport classifies the given pull requests merged within
+// the 52 weeks ending with the given day by the number of lines they change.
+func NewPullRequestSizeReport(pullRequests []PullRequest, lastDay time.Time) *PullRequestSizeReport {
+	report := &PullRequestSizeReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	durations := make([][]time.Duration, len(pullRequestSizes))
+	files := make([][]float64, len(pullRequestSizes))
+	var lines, hours []float64
+	for _, pr := range pullRequests {
+		if pr.Merged.IsZero() || !InPeriod(pr.Merged, lastDay) {
+			continue
+		}
+		report.PullRequests++
+		changed := pr.Additions + pr.Deletions
+		for i, size := range pullRequestSizes {
+			if size.max == 0 || changed < size.max {
+				durations[i] = append(durations[i], pr.Merged.Sub(pr.Created))
+				files[i] = append(files[i], float64(pr.ChangedFiles))
+				break
+			}
+		}
+		lines = append(lines, float64(changed))
+		hours = append(hours, pr.Merged.Sub(pr.Created).Hours())
+	}
+	report.Correlation = spearman(lines, hours)
+	minLines := 0
+	for i, size := range pullRequestSizes {
+		sort.Float64s(files[i])
+		report.Sizes = append(report.Sizes, PullRequestSize{
+			Size:         size.name,
+			MinLines:     minLines,
+			MaxLines:     size.max - 1,
+			PullRequests: len(durations[i]),
+			Percentage:   percentage(len(durations[i]), report.PullRequests),
+			MedianFiles:  percentile(files[i], 0.5),
+			TimeToMerge:  newDurationStatistics(durations[i]),
+		})
+		minLines = size.max
+	}
+	// The largest class is unbounded
+	report.Sizes[len(report.Sizes)-1].MaxLines = 0
+	return report
+}
+
+var (
+	// The embedded template used for rendering pull request size reports as
+	// markdown.
+	//go:embed pr-sizes.gomd
+	pullRequestSizesTemplate string
+)
+
+// Markdown renders the pull request size report as markdown.
+func (r *PullRequestSizeReport) Markdown() (string, error) {
+	return renderMarkdown("pr-sizes", pullRequestSizesTemplate, r)
+}
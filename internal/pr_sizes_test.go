@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Pull request size reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	created := dateparse.MustParse("2023-03-01 12:00")
+	pr := func(files int, additions int, deletions int, hours int) PullRequest {
+		return PullRequest{Repository: "herdstat/herdstat", Created: created, Merged: created.Add(time.Duration(hours) * time.Hour),
+			ChangedFiles: files, Additions: additions, Deletions: deletions}
+	}
+	pullRequests := []PullRequest{
+		pr(1, 2, 1, 1),
+		pr(1, 5, 4, 2),
+		pr(3, 30, 10, 4),
+		pr(10, 500, 100, 48),
+		pr(40, 2000, 0, 200),
+		// Not merged
+		{Repository: "herdstat/herdstat", Created: created, Additions: 1},
+	}
+	report := NewPullRequestSizeReport(pullRequests, lastDay)
+
+	It("classifies the merged pull requests by size", func() {
+		Expect(report.PullRequests).To(Equal(5))
+		Expect(report.Sizes).To(HaveLen(5))
+		Expect(report.Sizes[0]).To(Equal(PullRequestSize{
+			Size: "XS", MinLines: 0, MaxLines: 9, PullRequests: 2, Percentage: 40, MedianFiles: 1,
+			TimeToMerge: DurationStatistics{Count: 2, MedianHours: 1.5, P90Hours: 1.9},
+		}))
+		Expect(report.Sizes[2].PullRequests).To(Equal(0))
+		Expect(report.Sizes[3].MinLines).To(Equal(250))
+		Expect(report.Sizes[4].MaxLines).To(Equal(0))
+		Expect(report.Sizes[4].MedianFiles).To(Equal(40.0))
+	})
+
+	It("correlates size and time to merge", func() {
+		Expect(report.Correlation).To(Equal(1.0))
+	})
+
+	It("averages the ranks of ties", func() {
+		Expect(ranks([]float64{3, 1, 3, 2})).To(Equal([]float64{3.5, 1, 3.5, 2}))
+		Expect(spearman([]float64{1, 2, 3}, []float64{3, 2, 1})).To(Equal(-1.0))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| XS | 0–9 | 2 | 40.0% | 1 | 1.5h | 1.9h |"))
+		Expect(md).To(ContainSubstring("| XL | 1000+ | 1 |"))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import "time"
+
+// Review is a review submitted for a pull request.
+type Review struct {
+
+	// The login of the reviewer.
+	Reviewer string
+
+	// The point in time the review was submitted.
+	Submitted time.Time
+}
+
+// ReviewRequest is a request for a review of a pull request.
+type ReviewRequest struct {
+
+	// The login of the requested reviewer.
+	Reviewer string
+
+	// The point in time the review was requested.
+	Requested time.Time
+}
+
+// PullRequest is a pull request opened in a repository.
+type PullRequest struct {
+
+	// The repository the pull request was opened in in 'owner/name' notation.
+	Repository string
+
+	// The number of the pull request.
+	Number int
+
+	// The login of the author of the pull request.
+	Author string
+
+	// The point in time the pull request was opened.
+	Created time.Time
+
+	// The point in time the pull request was merged. Zero if the pull request
+	// hasn't been merged.
+	Merged time.Time
+
+	// The number of files changed. Only collected on demand.
+	ChangedFiles int
+
+	// The number of lines added. Only collected on demand.
+	Additions int
+
+	// The number of lines removed. Only collected on demand.
+	Deletions int
+
+	// The reviews submitted by others than the author in chronological order.
+	Reviews []Review
+
+	// The requests for reviews of individual users in chronological order.
+	// Only collected on demand.
+	ReviewRequests []ReviewRequest
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Dimensions of the punch card.
+const (
+	punchCardCellSize   = 24
+	punchCardAxisWidth  = 40
+	punchCardAxisHeight = 20
+	punchCardMargin     = 10
+)
+
+// PunchCard visualizes the number of contributions per weekday and hour of
+// the day.
+type PunchCard struct {
+
+	// The number of contributions per weekday (starting with Sunday) and hour.
+	Counts [7][24]int
+
+	// The graph providing the styling (colors, levels, tooltips).
+	style *ContributionGraph
+}
+
+// NewPunchCard creates a new PunchCard of the given contributions made within
+// the 52 weeks ending with the given day. The weekday and hour of each
+// contribution are determined in the given location or the time zone recorded
+// with the contribution if location is nil.
+func NewPunchCard(contributions []Contribution, lastDay time.Time, location *time.Location,
+	coloring Coloring, levels uint8, noTooltips bool) *PunchCard {
+	p := &PunchCard{
+		style: &ContributionGraph{
+			Coloring:   coloring,
+			Levels:     levels,
+			NoTooltips: noTooltips,
+		},
+	}
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		date := c.Date
+		if location != nil {
+			date = date.In(location)
+		}
+		p.Counts[date.Weekday()][date.Hour()]++
+	}
+	for _, hours := range p.Counts {
+		for _, count := range hours {
+			if count > p.style.MaxCount {
+				p.style.MaxCount = count
+			}
+		}
+	}
+	return p
+}
+
+// size computes the width and height of the rendered punch card.
+func (p *PunchCard) size() (int, int) {
+	return punchCardAxisWidth + 24*punchCardCellSize + punchCardMargin,
+		7*punchCardCellSize + punchCardAxisHeight + punchCardMargin
+}
+
+// hourLabel returns the label of the given hour on the hour axis.
+func hourLabel(hour int) string {
+	switch {
+	case hour == 0:
+		return "12a"
+	case hour == 12:
+		return "12p"
+	case hour > 12:
+		return strconv.Itoa(hour - 12)
+	}
+	return strconv.Itoa(hour)
+}
+
+// Render writes the punch card to the given xml.Encoder.
+func (p *PunchCard) Render(e *xml.Encoder) error {
+	width, height := p.size()
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			cssClassAttr("herdstat-contribution-graph", "herdstat-contribution-graph-var"),
+			attr("width", strconv.Itoa(width)),
+			attr("height", strconv.Itoa(height)),
+		},
+	}, func(e *xml.Encoder) error {
+		if err := p.style.renderStyle(e); err != nil {
+			return err
+		}
+		fg := cssClassAttrs("herdstat-contribution-graph-fg")
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			y := punchCardMargin + int(day)*punchCardCellSize
+			if err := simpleText(e, image.Point{X: punchCardAxisWidth - 8, Y: y + 16}, end, fg, day.String()[:3]); err != nil {
+				return err
+			}
+			for hour := 0; hour < 24; hour++ {
+				if err := p.renderCell(e, day, hour, image.Point{X: punchCardAxisWidth + hour*punchCardCellSize, Y: y}); err != nil {
+					return err
+				}
+			}
+		}
+		for hour := 0; hour < 24; hour++ {
+			location := image.Point{
+				X: punchCardAxisWidth + hour*punchCardCellSize + punchCardCellSize/2,
+				Y: punchCardMargin + 7*punchCardCellSize + 14,
+			}
+			if err := simpleText(e, location, middle, fg, hourLabel(hour)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// renderCell renders the circle representing the contributions made on the
+// given weekday within the given hour. The area of the circle is proportional
+// to the number of contributions.
+func (p *PunchCard) renderCell(e *xml.Encoder, day time.Weekday, hour int, location image.Point) error {
+	count := p.Counts[day][hour]
+	if count == 0 {
+		return nil
+	}
+	radius := float64(punchCardCellSize/2-2) * math.Sqrt(float64(count)/float64(p.style.MaxCount))
+	attrs := append([]xml.Attr{
+		attr("cx", strconv.Itoa(location.X+punchCardCellSize/2)),
+		attr("cy", strconv.Itoa(location.Y+punchCardCellSize/2)),
+		attr("r", strconv.FormatFloat(math.Max(radius, 1), 'f', 1, 64)),
+	}, cssClassAttrs(
+		"herdstat-contribution-graph-cell",
+		fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", p.style.level(ContributionRecord{Count: count})),
+	)...)
+	circle := xml.StartElement{Name: xml.Name{Local: "circle"}, Attr: attrs}
+	if p.style.NoTooltips {
+		return emptyElement(e, circle)
+	}
+	return nonEmptyElement(e, circle, func(e *xml.Encoder) error {
+		return nonEmptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "title"},
+		}, func(e *xml.Encoder) error {
+			return e.EncodeToken(xml.CharData(fmt.Sprintf("%d commits on %ss between %02d:00 and %02d:00",
+				count, day, hour, (hour+1)%24)))
+		})
+	})
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Punch cards", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	berlin := time.FixedZone("CET", 60*60)
+	contributions := []Contribution{
+		// Wednesday, 2023-03-15 at 00:30 CET
+		{Date: time.Date(2023, 3, 15, 0, 30, 0, 0, berlin)},
+		{Date: time.Date(2023, 3, 15, 0, 45, 0, 0, berlin)},
+		// Outside the analyzed period
+		{Date: time.Date(2020, 3, 15, 0, 45, 0, 0, berlin)},
+	}
+
+	When("keeping the recorded time zones", func() {
+		card := NewPunchCard(contributions, lastDay, nil, testColoring, 5, false)
+		It("counts contributions in local time", func() {
+			Expect(card.Counts[time.Wednesday][0]).To(Equal(2))
+		})
+	})
+
+	When("normalizing to UTC", func() {
+		card := NewPunchCard(contributions, lastDay, time.UTC, testColoring, 5, false)
+		It("counts contributions in UTC", func() {
+			Expect(card.Counts[time.Tuesday][23]).To(Equal(2))
+		})
+		It("renders tooltips", func() {
+			var buf bytes.Buffer
+			enc := xml.NewEncoder(&buf)
+			Expect(card.Render(enc)).To(Succeed())
+			Expect(enc.Flush()).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("2 commits on Tuesdays between 23:00 and 00:00"))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"strconv"
+)
+
+// Repository describes a repository selected for analysis.
+type Repository struct {
+
+	// The repository in 'owner/name' notation.
+	Name string `json:"name" yaml:"name"`
+
+	// The URL of the repository.
+	URL string `json:"url" yaml:"url"`
+
+	// The name of the default branch.
+	DefaultBranch string `json:"defaultBranch" yaml:"defaultBranch"`
+
+	// Whether the repository is private.
+	Private bool `json:"private" yaml:"private"`
+
+	// Whether the repository is archived.
+	Archived bool `json:"archived" yaml:"archived"`
+
+	// Whether the repository is a fork.
+	Fork bool `json:"fork" yaml:"fork"`
+
+	// The size of the repository in KB as reported by GitHub.
+	Size int `json:"size" yaml:"size"`
+}
+
+// RepositoryList lists the repositories selected for analysis.
+type RepositoryList struct {
+
+	// The number of selected repositories.
+	Count int `json:"count" yaml:"count"`
+
+	// The selected repositories sorted by name.
+	Repositories []Repository `json:"repositories" yaml:"repositories"`
+}
+
+// NewRepositoryList creates a RepositoryList of the given repositories.
+func NewRepositoryList(repositories []Repository) *RepositoryList {
+	list := &RepositoryList{
+		Count:        len(repositories),
+		Repositories: append([]Repository{}, repositories...),
+	}
+	sort.Slice(list.Repositories, func(i, j int) bool {
+		return list.Repositories[i].Name < list.Repositories[j].Name
+	})
+	return list
+}
+
+var (
+	// The embedded template used for rendering repository lists as markdown.
+	//go:embed repositories.gomd
+	repositoriesTemplate string
+)
+
+// Markdown renders the repository list as a markdown table.
+func (l *RepositoryList) Markdown() (string, error) {
+	return renderMarkdown("repositories", repositoriesTemplate, l)
+}
+
+// CSV renders the repository list as CSV records.
+func (l *RepositoryList) CSV() [][]string {
+	records := [][]string{{"name", "url", "default_branch", "private", "archived", "fork", "size"}}
+	for _, r := range l.Repositories {
+		records = append(records, []string{
+			r.Name,
+			r.URL,
+			r.DefaultBranch,
+			strconv.FormatBool(r.Private),
+			strconv.FormatBool(r.Archived),
+			strconv.FormatBool(r.Fork),
+			strconv.Itoa(r.Size),
+		})
+	}
+	return records
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Listing repositories", func() {
+	list := NewRepositoryList([]Repository{
+		{Name: "herdstat/herdstat", URL: "https://github.com/herdstat/herdstat", DefaultBranch: "main", Size: 1024},
+		{Name: "herdstat/action", URL: "https://github.com/herdstat/action", DefaultBranch: "main", Archived: true},
+	})
+
+	It("sorts the repositories by name", func() {
+		Expect(list.Count).To(Equal(2))
+		Expect(list.Repositories[0].Name).To(Equal("herdstat/action"))
+	})
+	It("renders the repositories as markdown table", func() {
+		md, err := list.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| [herdstat/action](https://github.com/herdstat/action) | main | no | yes | no | 0 |"))
+	})
+	It("renders the repositories as CSV", func() {
+		Expect(list.CSV()).To(ContainElement([]string{
+			"herdstat/herdstat", "https://github.com/herdstat/herdstat", "main", "false", "false", "false", "1024"}))
+	})
+})
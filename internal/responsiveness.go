@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"strings"
+	"time"
+)
+
+// MaintainerResponsiveness describes how fast a maintainer responds to issues
+// and review requests.
+type MaintainerResponsiveness struct {
+
+	// The login of the maintainer.
+	Maintainer string `json:"maintainer" yaml:"maintainer"`
+
+	// The time from opening an issue to the first comment of the maintainer
+	// for the issues the maintainer commented on.
+	IssueResponse DurationStatistics `json:"issueResponse" yaml:"issueResponse"`
+
+	// The time from requesting a review from the maintainer to the
+	// maintainer submitting a review.
+	ReviewResponse DurationStatistics `json:"reviewResponse" yaml:"reviewResponse"`
+
+	// The number of review requests the maintainer hasn't responded to.
+	UnansweredReviewRequests int `json:"unansweredReviewRequests" yaml:"unansweredReviewRequests"`
+}
+
+// ResponsivenessReport describes the responsiveness of a team of maintainers.
+type ResponsivenessReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The responsiveness of the maintainers in the order they are given.
+	Maintainers []MaintainerResponsiveness `json:"maintainers" yaml:"maintainers"`
+}
+
+// NewResponsivenessReport computes the response times of the given maintainers
+// to the given issues opened and the review requests made within the 52 weeks
+// ending with the given day. Only the first review submitted by a maintainer
+// after a review request counts as a response to that request.
+func NewResponsivenessReport(issues []Issue, pullRequests []PullRequest, maintainers []string, lastDay time.Time) *ResponsivenessReport {
+	report := &ResponsivenessReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	for _, maintainer := range maintainers {
+		responsiveness := MaintainerResponsiveness{Maintainer: maintainer}
+
+		var issueResponses []time.Duration
+		for _, issue := range issues {
+			if !InPeriod(issue.Created, lastDay) {
+				continue
+			}
+			for _, response := range issue.Responses {
+				if strings.EqualFold(response.Responder, maintainer) {
+					issueResponses = append(issueResponses, response.Time.Sub(issue.Created))
+					break
+				}
+			}
+		}
+		responsiveness.IssueResponse = newDurationStatistics(issueResponses)
+
+		var reviewResponses []time.Duration
+		for _, pr := range pullRequests {
+			for _, request := range pr.ReviewRequests {
+				if !strings.EqualFold(request.Reviewer, maintainer) || !InPeriod(request.Requested, lastDay) {
+					continue
+				}
+				answered := false
+				for _, review := range pr.Reviews {
+					if strings.EqualFold(review.Reviewer, maintainer) && !review.Submitted.Before(request.Requested) {
+						reviewResponses = append(reviewResponses, review.Submitted.Sub(request.Requested))
+						answered = true
+						break
+					}
+				}
+				if !answered {
+					responsiveness.UnansweredReviewRequests++
+				}
+			}
+		}
+		responsiveness.ReviewResponse = newDurationStatistics(reviewResponses)
+
+		report.Maintainers = append(report.Maintainers, responsiveness)
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering responsiveness reports as
+	// markdown.
+	//go:embed responsiveness.gomd
+	responsivenessTemplate string
+)
+
+// Markdown renders the responsiveness report as markdown.
+func (r *ResponsivenessReport) Markdown() (string, error) {
+	return renderMarkdown("responsiveness", responsivenessTemplate, r)
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Responsiveness reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	created := dateparse.MustParse("2023-03-01 12:00")
+	issues := []Issue{
+		{Repository: "herdstat/herdstat", Number: 1, Author: "carol", Created: created, Responses: []Response{
+			{Responder: "bob", Time: created.Add(time.Hour)},
+			{Responder: "alice", Time: created.Add(2 * time.Hour)},
+		}},
+		{Repository: "herdstat/herdstat", Number: 2, Author: "carol", Created: created, Responses: []Response{
+			{Responder: "Alice", Time: created.Add(4 * time.Hour)},
+		}},
+		// Outside the analyzed period
+		{Repository: "herdstat/herdstat", Number: 3, Author: "carol", Created: dateparse.MustParse("2021-03-01 12:00"),
+			Responses: []Response{{Responder: "alice", Time: created}}},
+	}
+	pullRequests := []PullRequest{
+		{Repository: "herdstat/herdstat", Number: 4, Author: "carol", Created: created,
+			ReviewRequests: []ReviewRequest{
+				{Reviewer: "alice", Requested: created},
+				{Reviewer: "bob", Requested: created},
+			},
+			Reviews: []Review{
+				{Reviewer: "alice", Submitted: created.Add(-time.Hour)},
+				{Reviewer: "alice", Submitted: created.Add(10 * time.Hour)},
+			}},
+	}
+	report := NewResponsivenessReport(issues, pullRequests, []string{"alice", "bob"}, lastDay)
+
+	It("computes the issue response times of each maintainer", func() {
+		Expect(report.Maintainers).To(HaveLen(2))
+		Expect(report.Maintainers[0].Maintainer).To(Equal("alice"))
+		Expect(report.Maintainers[0].IssueResponse).To(Equal(DurationStatistics{Count: 2, MedianHours: 3, P90Hours: 3.8}))
+		Expect(report.Maintainers[1].IssueResponse).To(Equal(DurationStatistics{Count: 1, MedianHours: 1, P90Hours: 1}))
+	})
+
+	It("computes the review response times of each maintainer", func() {
+		Expect(report.Maintainers[0].ReviewResponse).To(Equal(DurationStatistics{Count: 1, MedianHours: 10, P90Hours: 10}))
+		Expect(report.Maintainers[0].UnansweredReviewRequests).To(Equal(0))
+		Expect(report.Maintainers[1].ReviewResponse.Count).To(Equal(0))
+		Expect(report.Maintainers[1].UnansweredReviewRequests).To(Equal(1))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| bob | 1 |"))
+		Expect(md).To(ContainSubstring("| 0 | - | - | 1 |"))
+	})
+})
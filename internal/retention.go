@@ -0,0 +1,249 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"strconv"
+	"time"
+)
+
+// retentionOffsets are the offsets in months after which the retention of a
+// cohort is determined.
+var retentionOffsets = []int{1, 3, 6}
+
+// RetentionCohort describes how many of the contributors active in a month
+// have still been active in later months.
+type RetentionCohort struct {
+
+	// The month in 'YYYY-MM' notation.
+	Month string `json:"month" yaml:"month"`
+
+	// The number of contributors active in the month.
+	Size int `json:"size" yaml:"size"`
+
+	// The number of contributors of the cohort active in the month lying the
+	// respective offset after the cohort month. Offsets lying beyond the
+	// analyzed period are omitted.
+	Retained []int `json:"retained" yaml:"retained"`
+}
+
+// rate computes the percentage of contributors retained after the offset with
+// the given index.
+func (c RetentionCohort) rate(i int) int {
+	if c.Size == 0 {
+		return 0
+	}
+	return (c.Retained[i]*100 + c.Size/2) / c.Size
+}
+
+// RetentionReport contains the retention matrix of monthly contributor
+// cohorts.
+type RetentionReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The offsets in months after which the retention is determined.
+	Offsets []int `json:"offsets" yaml:"offsets"`
+
+	// The cohorts in chronological order.
+	Cohorts []RetentionCohort `json:"cohorts" yaml:"cohorts"`
+}
+
+// NewRetentionReport computes the retention of the monthly cohorts of
+// contributors active within the 52 weeks ending with the given day.
+func NewRetentionReport(contributions []Contribution, lastDay time.Time) *RetentionReport {
+	firstDay := lastDay.AddDate(0, 0, -52*7+1)
+	report := &RetentionReport{
+		From:    firstDay.Format(dateFormat),
+		Until:   lastDay.Format(dateFormat),
+		Offsets: retentionOffsets,
+	}
+	active := make(map[string]map[string]bool)
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		month := c.Date.Format(monthFormat)
+		if active[month] == nil {
+			active[month] = make(map[string]bool)
+		}
+		active[month][c.Contributor()] = true
+	}
+	lastMonth := monthOf(lastDay)
+	for month := monthOf(firstDay); !month.After(lastMonth); month = month.AddDate(0, 1, 0) {
+		cohort := RetentionCohort{
+			Month:    month.Format(monthFormat),
+			Size:     len(active[month.Format(monthFormat)]),
+			Retained: []int{},
+		}
+		for _, offset := range retentionOffsets {
+			later := month.AddDate(0, offset, 0)
+			if later.After(lastMonth) {
+				break
+			}
+			retained := 0
+			for contributor := range active[cohort.Month] {
+				if active[later.Format(monthFormat)][contributor] {
+					retained++
+				}
+			}
+			cohort.Retained = append(cohort.Retained, retained)
+		}
+		report.Cohorts = append(report.Cohorts, cohort)
+	}
+	return report
+}
+
+// CSV renders the retention matrix as CSV records including a header.
+func (r *RetentionReport) CSV() [][]string {
+	header := []string{"cohort", "size"}
+	for _, offset := range r.Offsets {
+		header = append(header, fmt.Sprintf("month+%d", offset))
+	}
+	records := [][]string{header}
+	for _, cohort := range r.Cohorts {
+		record := []string{cohort.Month, strconv.Itoa(cohort.Size)}
+		for i := range r.Offsets {
+			value := ""
+			if i < len(cohort.Retained) {
+				value = strconv.Itoa(cohort.Retained[i])
+			}
+			record = append(record, value)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Dimensions of the retention heatmap.
+const (
+	retentionLabelWidth   = 100
+	retentionColumnWidth  = 50
+	retentionRowHeight    = 14
+	retentionHeaderHeight = 20
+	retentionMargin       = 10
+)
+
+// RetentionHeatmap renders the retention matrix as a heatmap with cells
+// colored by the percentage of retained contributors.
+type RetentionHeatmap struct {
+
+	// The rendered retention report.
+	Report *RetentionReport
+
+	// The graph providing the styling (colors, levels, tooltips).
+	style *ContributionGraph
+}
+
+// NewRetentionHeatmap creates a new RetentionHeatmap for the given report
+// using the given coloring and number of levels.
+func NewRetentionHeatmap(report *RetentionReport, coloring Coloring, levels uint8, noTooltips bool) *RetentionHeatmap {
+	return &RetentionHeatmap{
+		Report: report,
+		style: &ContributionGraph{
+			Coloring:   coloring,
+			Levels:     levels,
+			NoTooltips: noTooltips,
+			MaxCount:   100,
+		},
+	}
+}
+
+// size computes the width and height of the rendered heatmap.
+func (h *RetentionHeatmap) size() (int, int) {
+	return retentionLabelWidth + len(h.Report.Offsets)*retentionColumnWidth + retentionMargin,
+		retentionHeaderHeight + len(h.Report.Cohorts)*retentionRowHeight + retentionMargin
+}
+
+// Render writes the retention heatmap to the given xml.Encoder.
+func (h *RetentionHeatmap) Render(e *xml.Encoder) error {
+	width, height := h.size()
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			cssClassAttr("herdstat-contribution-graph", "herdstat-contribution-graph-var"),
+			attr("width", strconv.Itoa(width)),
+			attr("height", strconv.Itoa(height)),
+		},
+	}, func(e *xml.Encoder) error {
+		if err := h.style.renderStyle(e); err != nil {
+			return err
+		}
+		fg := cssClassAttrs("herdstat-contribution-graph-fg")
+		for j, offset := range h.Report.Offsets {
+			x := retentionLabelWidth + j*retentionColumnWidth
+			if err := simpleText(e, image.Point{X: x, Y: 12}, start, fg, fmt.Sprintf("+%d mo", offset)); err != nil {
+				return err
+			}
+		}
+		for i, cohort := range h.Report.Cohorts {
+			y := retentionHeaderHeight + i*retentionRowHeight
+			if err := h.renderCohort(e, cohort, y); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// renderCohort renders the row of the given cohort at the given vertical
+// position.
+func (h *RetentionHeatmap) renderCohort(e *xml.Encoder, cohort RetentionCohort, y int) error {
+	fg := cssClassAttrs("herdstat-contribution-graph-fg")
+	month, err := time.Parse(monthFormat, cohort.Month)
+	if err != nil {
+		return err
+	}
+	err = simpleText(e, image.Point{X: retentionMargin, Y: y + 9}, start, fg,
+		fmt.Sprintf("%s (%d)", month.Format("Jan 2006"), cohort.Size))
+	if err != nil {
+		return err
+	}
+	for j := range cohort.Retained {
+		rate := cohort.rate(j)
+		x := retentionLabelWidth + j*retentionColumnWidth
+		classes := cssClassAttrs(
+			"herdstat-contribution-graph-cell",
+			fmt.Sprintf("herdstat-contribution-graph-cell-L%d-bg", h.style.level(ContributionRecord{Count: rate})),
+		)
+		if h.style.NoTooltips {
+			err = coloredRoundedRect(e, image.Point{X: x, Y: y}, classes)
+		} else {
+			err = nonEmptyElement(e, xml.StartElement{
+				Name: xml.Name{Local: "rect"},
+				Attr: append([]xml.Attr{
+					attr("x", strconv.Itoa(x)),
+					attr("y", strconv.Itoa(y)),
+					attr("rx", "2"),
+				}, classes...),
+			}, func(e *xml.Encoder) error {
+				return nonEmptyElement(e, xml.StartElement{
+					Name: xml.Name{Local: "title"},
+				}, func(e *xml.Encoder) error {
+					return e.EncodeToken(xml.CharData(fmt.Sprintf("%d of %d contributors active in %s were active %d months later",
+						cohort.Retained[j], cohort.Size, month.Format("Jan 2006"), h.Report.Offsets[j])))
+				})
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if err := simpleText(e, image.Point{X: x + 14, Y: y + 9}, start, fg, fmt.Sprintf("%d%%", rate)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+// testColoring is a coloring going from black to green used in tests.
+var testColoring = GetColoring(ColorScheme{
+	Light: ColorSpectrum{Min: color.RGBA{A: 0xff}, Max: color.RGBA{G: 0xff, A: 0xff}},
+	Dark:  ColorSpectrum{Min: color.RGBA{A: 0xff}, Max: color.RGBA{G: 0xff, A: 0xff}},
+}, defaultColoring)
+
+var _ = Describe("Computing contributor retention", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contributions := []Contribution{
+		{Login: "jdoe", Date: dateparse.MustParse("2022-09-05")},
+		{Login: "jroe", Date: dateparse.MustParse("2022-09-06")},
+		{Login: "jdoe", Date: dateparse.MustParse("2022-10-01")},
+		{Login: "jdoe", Date: dateparse.MustParse("2022-12-24")},
+		{Login: "jroe", Date: dateparse.MustParse("2023-03-01")},
+	}
+	report := NewRetentionReport(contributions, lastDay)
+
+	It("creates a cohort per month of the period", func() {
+		Expect(report.Cohorts).To(HaveLen(13))
+		Expect(report.Cohorts[0].Month).To(Equal("2022-03"))
+	})
+	It("counts retained contributors per offset", func() {
+		Expect(report.Cohorts[6]).To(Equal(RetentionCohort{Month: "2022-09", Size: 2, Retained: []int{1, 1, 1}}))
+	})
+	It("omits offsets beyond the period", func() {
+		Expect(report.Cohorts[10].Retained).To(HaveLen(1))
+		Expect(report.Cohorts[12].Retained).To(BeEmpty())
+	})
+	It("renders as CSV", func() {
+		records := report.CSV()
+		Expect(records[0]).To(Equal([]string{"cohort", "size", "month+1", "month+3", "month+6"}))
+		Expect(records[7]).To(Equal([]string{"2022-09", "2", "1", "1", "1"}))
+		Expect(records[11]).To(Equal([]string{"2023-01", "0", "0", "", ""}))
+	})
+	It("renders as heatmap", func() {
+		var buf bytes.Buffer
+		heatmap := NewRetentionHeatmap(report, testColoring, 5, false)
+		enc := xml.NewEncoder(&buf)
+		Expect(heatmap.Render(enc)).To(Succeed())
+		Expect(enc.Flush()).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("1 of 2 contributors active in Sep 2022 were active 3 months later"))
+		Expect(buf.String()).To(ContainSubstring("50%"))
+	})
+})
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"time"
+)
+
+// ReviewerLoad is the number of reviews submitted by a single reviewer.
+type ReviewerLoad struct {
+
+	// The login of the reviewer.
+	Reviewer string `json:"reviewer" yaml:"reviewer"`
+
+	// The number of submitted reviews.
+	Reviews int `json:"reviews" yaml:"reviews"`
+
+	// The share of all reviews submitted by the reviewer in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+}
+
+// ReviewLoadReport describes how the reviews of pull requests are distributed
+// across reviewers.
+type ReviewLoadReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The overall number of submitted reviews.
+	Reviews int `json:"reviews" yaml:"reviews"`
+
+	// The number of distinct reviewers.
+	Reviewers int `json:"reviewers" yaml:"reviewers"`
+
+	// The Gini coefficient of the number of reviews per reviewer ranging from
+	// 0 (evenly distributed) to 1 (all reviews submitted by a single reviewer).
+	Gini float64 `json:"gini" yaml:"gini"`
+
+	// The reviewers with the most reviews ordered by descending number of
+	// reviews.
+	TopReviewers []ReviewerLoad `json:"topReviewers" yaml:"topReviewers"`
+}
+
+// gini computes the Gini coefficient of the given counts.
+func gini(counts []int) float64 {
+	sorted := append([]int(nil), counts...)
+	sort.Ints(sorted)
+	n := len(sorted)
+	total, weighted := 0, 0
+	for i, c := range sorted {
+		total += c
+		weighted += (i + 1) * c
+	}
+	if n == 0 || total == 0 {
+		return 0
+	}
+	return float64(2*weighted)/float64(n*total) - float64(n+1)/float64(n)
+}
+
+// NewReviewLoadReport computes the distribution of the reviews of the given
+// pull requests submitted within the 52 weeks ending with the given day
+// across reviewers. Lists up to top reviewers.
+func NewReviewLoadReport(pullRequests []PullRequest, lastDay time.Time, top int) *ReviewLoadReport {
+	report := &ReviewLoadReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	counts := make(map[string]int)
+	for _, pr := range pullRequests {
+		for _, review := range pr.Reviews {
+			if InPeriod(review.Submitted, lastDay) {
+				counts[review.Reviewer]++
+				report.Reviews++
+			}
+		}
+	}
+	report.Reviewers = len(counts)
+	reviewers := Keys(counts)
+	sort.Slice(reviewers, func(i, j int) bool {
+		a, b := reviewers[i], reviewers[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	values := make([]int, len(reviewers))
+	for i, reviewer := range reviewers {
+		values[i] = counts[reviewer]
+		if i < top {
+			report.TopReviewers = append(report.TopReviewers, ReviewerLoad{
+				Reviewer:   reviewer,
+				Reviews:    counts[reviewer],
+				Percentage: percentage(counts[reviewer], report.Reviews),
+			})
+		}
+	}
+	report.Gini = gini(values)
+	return report
+}
+
+var (
+	// The embedded template used for rendering review load reports as
+	// markdown.
+	//go:embed review-load.gomd
+	reviewLoadTemplate string
+)
+
+// Markdown renders the review load report as markdown.
+func (r *ReviewLoadReport) Markdown() (string, error) {
+	return renderMarkdown("review-load", reviewLoadTemplate, r)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Review load reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	review := func(reviewer string, date string) Review {
+		return Review{Reviewer: reviewer, Submitted: dateparse.MustParse(date)}
+	}
+	pullRequests := []PullRequest{
+		{Reviews: []Review{review("alice", "2023-03-01"), review("bob", "2023-03-02")}},
+		{Reviews: []Review{review("alice", "2023-03-03"), review("alice", "2023-03-04")}},
+		// Outside the analyzed period
+		{Reviews: []Review{review("carol", "2021-03-01")}},
+	}
+	report := NewReviewLoadReport(pullRequests, lastDay, 1)
+
+	It("counts the reviews within the analyzed period", func() {
+		Expect(report.Reviews).To(Equal(4))
+		Expect(report.Reviewers).To(Equal(2))
+	})
+
+	It("lists the top reviewers", func() {
+		Expect(report.TopReviewers).To(Equal([]ReviewerLoad{{Reviewer: "alice", Reviews: 3, Percentage: 75}}))
+	})
+
+	It("computes the Gini coefficient", func() {
+		Expect(report.Gini).To(BeNumerically("~", 0.25, 1e-9))
+	})
+
+	It("renders markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| alice | 3 | 75.0% |"))
+	})
+})
+
+var _ = Describe("Gini coefficients", func() {
+	It("is zero for evenly distributed counts", func() {
+		Expect(gini([]int{3, 3, 3})).To(BeNumerically("~", 0, 1e-9))
+	})
+	It("approaches one if a single key accounts for all counts", func() {
+		Expect(gini([]int{0, 0, 0, 10})).To(BeNumerically("~", 0.75, 1e-9))
+	})
+	It("is zero without counts", func() {
+		Expect(gini(nil)).To(BeZero())
+	})
+})
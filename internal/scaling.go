@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Scaling determines how the counts of contribution records are mapped to
+// intensities.
+type Scaling uint8
+
+const (
+
+	// LinearScaling maps the counts proportionally to intensities, such that
+	// the highest count is mapped to the highest intensity.
+	LinearScaling Scaling = iota
+
+	// WinsorizedScaling caps the counts at a percentile of the non-zero counts
+	// before mapping them proportionally, such that a few outliers, e.g., days
+	// of mass imports, don't flatten the intensities of all other days.
+	WinsorizedScaling
+)
+
+// Names of the supported scalings.
+const (
+	LinearScalingName     = "linear"
+	WinsorizedScalingName = "winsorized"
+)
+
+// DefaultCapPercentile is the percentile counts are capped at by the
+// WinsorizedScaling unless configured otherwise.
+const DefaultCapPercentile = 95
+
+// ParseScaling returns the Scaling registered under the given name.
+func ParseScaling(name string) (Scaling, error) {
+	switch name {
+	case LinearScalingName:
+		return LinearScaling, nil
+	case WinsorizedScalingName:
+		return WinsorizedScaling, nil
+	}
+	return 0, fmt.Errorf("unknown scaling '%s'; supported are %s and %s",
+		name, LinearScalingName, WinsorizedScalingName)
+}
+
+// percentileCount returns the given percentile of the non-zero counts of the
+// given records using the nearest-rank method. Returns 0 if there are no
+// non-zero counts.
+func percentileCount(records []ContributionRecord, percentile float64) int {
+	var counts []int
+	for _, r := range records {
+		if r.Count > 0 {
+			counts = append(counts, r.Count)
+		}
+	}
+	if len(counts) == 0 {
+		return 0
+	}
+	sort.Ints(counts)
+	rank := int(math.Ceil(percentile / 100 * float64(len(counts))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(counts) {
+		rank = len(counts)
+	}
+	return counts[rank-1]
+}
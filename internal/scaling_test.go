@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parsing scalings", func() {
+	It("supports the winsorized scaling", func() {
+		Expect(ParseScaling("winsorized")).To(Equal(WinsorizedScaling))
+	})
+	It("rejects unknown scalings", func() {
+		_, err := ParseScaling("logarithmic")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Scaling counts to intensities", func() {
+	// 19 days with 1 to 19 contributions, one day without any, and a mass
+	// import of 1000 contributions
+	records := []ContributionRecord{{Count: 0}, {Count: 1000}}
+	for i := 1; i < 20; i++ {
+		records = append(records, ContributionRecord{Count: i})
+	}
+
+	When("the scaling is linear", func() {
+		It("maps the highest count to the highest intensity", func() {
+			g := &ContributionGraph{Records: records, Levels: 5}
+			Expect(g.intensity(ContributionRecord{Count: 1000})).To(Equal(uint8(255)))
+			Expect(g.level(ContributionRecord{Count: 19})).To(Equal(uint8(1)))
+		})
+	})
+	When("the scaling is winsorized", func() {
+		g := &ContributionGraph{Records: records, Levels: 5, Scaling: WinsorizedScaling}
+
+		It("caps the counts at the 95th percentile of the non-zero counts by default", func() {
+			Expect(g.scaledMaxCount()).To(Equal(19))
+			Expect(g.intensity(ContributionRecord{Count: 19})).To(Equal(uint8(255)))
+			Expect(g.intensity(ContributionRecord{Count: 1000})).To(Equal(uint8(255)))
+		})
+		It("spreads the other counts over the levels", func() {
+			Expect(g.level(ContributionRecord{Count: 5})).To(Equal(uint8(2)))
+			Expect(g.level(ContributionRecord{Count: 0})).To(Equal(uint8(0)))
+		})
+		It("caps the counts at the configured percentile", func() {
+			g := &ContributionGraph{Records: records, Scaling: WinsorizedScaling, CapPercentile: 50}
+			Expect(g.scaledMaxCount()).To(Equal(10))
+		})
+		It("maps all counts to the lowest intensity without contributions", func() {
+			g := &ContributionGraph{Records: []ContributionRecord{{Count: 0}}, Scaling: WinsorizedScaling}
+			Expect(g.intensity(g.Records[0])).To(Equal(uint8(0)))
+		})
+	})
+})
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"regexp"
+	"time"
+)
+
+// signOffPattern matches a 'Signed-off-by:' trailer naming a person and an
+// email address.
+var signOffPattern = regexp.MustCompile(`(?mi)^Signed-off-by:\s*.+<\S+@\S+>\s*$`)
+
+// HasSignOff returns true iff the given commit message carries a
+// 'Signed-off-by:' trailer.
+func HasSignOff(message string) bool {
+	return signOffPattern.MatchString(message)
+}
+
+// SignOffReport contains the share of commits carrying a DCO sign-off.
+type SignOffReport CommitShareReport
+
+// NewSignOffReport computes the share of signed-off commits among the given
+// commits made within the 52 weeks ending with the given day.
+func NewSignOffReport(commits []Contribution, lastDay time.Time) *SignOffReport {
+	report := SignOffReport(newCommitShareReport(commits, lastDay, func(c Contribution) bool {
+		return c.SignedOff
+	}))
+	return &report
+}
+
+var (
+	// The embedded template used for rendering sign-off reports as markdown.
+	//go:embed sign-offs.gomd
+	signOffsTemplate string
+)
+
+// Markdown renders the sign-off report as markdown.
+func (r *SignOffReport) Markdown() (string, error) {
+	return renderMarkdown("sign-offs", signOffsTemplate, r)
+}
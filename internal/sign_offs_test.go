@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sign-off reports", func() {
+	DescribeTable("detects sign-off trailers",
+		func(message string, signedOff bool) {
+			Expect(HasSignOff(message)).To(Equal(signedOff))
+		},
+		Entry("with trailer", "Fix bug\n\nSigned-off-by: Jane Roe <jane.roe@herdstat.com>\n", true),
+		Entry("with lower-case trailer", "Fix bug\n\nsigned-off-by: Jane Roe <jane.roe@herdstat.com>", true),
+		Entry("without trailer", "Fix bug\n\nCo-authored-by: Jane Roe <jane.roe@herdstat.com>", false),
+		Entry("with trailer lacking an email address", "Fix bug\n\nSigned-off-by: Jane Roe", false),
+		Entry("with mention in body", "Fix bug\n\nAdd the Signed-off-by: <x@y.z> check", false),
+	)
+
+	It("computes the share of signed-off commits", func() {
+		lastDay := dateparse.MustParse("2023-03-15 23:59")
+		date := dateparse.MustParse("2023-03-01 12:00")
+		report := NewSignOffReport([]Contribution{
+			{Type: CommitContribution, Repository: "herdstat/herdstat", SignedOff: true, Date: date},
+			{Type: CommitContribution, Repository: "herdstat/herdstat", Signed: true, Date: date},
+			{Type: CommitContribution, Repository: "herdstat/action", SignedOff: true, Date: date},
+		}, lastDay)
+		Expect(report.Overall).To(Equal(CommitShare{Commits: 3, Count: 2, Percentage: 66.6}))
+		Expect(report.Repositories[0]).To(Equal(CommitShare{Repository: "herdstat/herdstat", Commits: 2, Count: 1, Percentage: 50}))
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| herdstat/action | 1 | 1 | 100.0% |"))
+	})
+})
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"time"
+)
+
+// SignatureReport contains the share of GPG or SSH signed commits.
+type SignatureReport CommitShareReport
+
+// NewSignatureReport computes the share of signed commits among the given
+// commits made within the 52 weeks ending with the given day.
+func NewSignatureReport(commits []Contribution, lastDay time.Time) *SignatureReport {
+	report := SignatureReport(newCommitShareReport(commits, lastDay, func(c Contribution) bool {
+		return c.Signed
+	}))
+	return &report
+}
+
+var (
+	// The embedded template used for rendering signature reports as
+	// markdown.
+	//go:embed signatures.gomd
+	signaturesTemplate string
+)
+
+// Markdown renders the signature report as markdown.
+func (r *SignatureReport) Markdown() (string, error) {
+	return renderMarkdown("signatures", signaturesTemplate, r)
+}
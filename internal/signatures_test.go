@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	commits := []Contribution{
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Signed: true,
+			Date: dateparse.MustParse("2023-03-01 12:00")},
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Signed: true,
+			Date: dateparse.MustParse("2023-02-01 12:00")},
+		{Type: CommitContribution, Repository: "herdstat/herdstat",
+			Date: dateparse.MustParse("2023-02-02 12:00")},
+		{Type: CommitContribution, Repository: "herdstat/action",
+			Date: dateparse.MustParse("2023-03-02 12:00")},
+		// Not a commit
+		{Type: IssueContribution, Repository: "herdstat/action",
+			Date: dateparse.MustParse("2023-03-02 12:00")},
+	}
+	report := NewSignatureReport(commits, lastDay)
+
+	It("computes the overall share of signed commits", func() {
+		Expect(report.Overall).To(Equal(CommitShare{Commits: 4, Count: 2, Percentage: 50}))
+	})
+
+	It("computes the share of signed commits per repository", func() {
+		Expect(report.Repositories).To(Equal([]CommitShare{
+			{Repository: "herdstat/action", Commits: 1, Count: 0, Percentage: 0},
+			{Repository: "herdstat/herdstat", Commits: 3, Count: 2, Percentage: 66.6},
+		}))
+	})
+
+	It("computes the share of signed commits per month", func() {
+		Expect(report.Months).To(HaveLen(13))
+		Expect(report.Months[0].Month).To(Equal("2022-03-01"))
+		Expect(report.Months[11]).To(Equal(CommitShare{Month: "2023-02-01", Commits: 2, Count: 1, Percentage: 50}))
+		Expect(report.Months[12]).To(Equal(CommitShare{Month: "2023-03-01", Commits: 2, Count: 1, Percentage: 50}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| **Overall** | 4 | 2 | 50.0% |"))
+	})
+})
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// siteIndexPath is the path of the index page of a static site.
+const siteIndexPath = "index.html"
+
+// SitePage is a single page of a static site.
+type SitePage struct {
+
+	// The path of the page relative to the root of the site.
+	Path string
+
+	// The repository the page is about in 'owner/name' notation. Empty for
+	// the index page covering all repositories.
+	Repository string
+
+	// The contributions visualized by the page.
+	Contributions []Contribution
+
+	// The dashboard rendered as the page.
+	Dashboard *Dashboard
+}
+
+// NewSite creates the pages of a static site for the given contributions made
+// within the 52 weeks ending with the given day. The site consists of an index
+// page covering all repositories and linking to a page per repository with at
+// least one contribution. The leaderboards list up to top contributors. The
+// charts of the dashboards are left empty.
+func NewSite(title string, contributions []Contribution, lastDay time.Time, top int) []SitePage {
+	index := SitePage{
+		Path:          siteIndexPath,
+		Contributions: contributions,
+		Dashboard:     NewDashboard(title, contributions, lastDay, top),
+	}
+	index.Dashboard.LinksTitle = "Repositories"
+	pages := []SitePage{index}
+
+	groups := GroupByRepository(contributions)
+	repositories := Keys(groups)
+	sort.Strings(repositories)
+	for _, repository := range repositories {
+		page := SitePage{
+			Path:          repositoryPagePath(repository),
+			Repository:    repository,
+			Contributions: groups[repository],
+			Dashboard:     NewDashboard(repository, groups[repository], lastDay, top),
+		}
+		if page.Dashboard.Summary.TotalContributions == 0 {
+			continue
+		}
+		page.Dashboard.LinksTitle = "Navigation"
+		page.Dashboard.Links = []DashboardLink{{
+			Label: title,
+			URL:   relativeLink(page.Path, siteIndexPath),
+		}}
+		index.Dashboard.Links = append(index.Dashboard.Links, DashboardLink{
+			Label: repository,
+			URL:   page.Path,
+		})
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// repositoryPagePath returns the path of the page of the given repository in
+// 'owner/name' notation.
+func repositoryPagePath(repository string) string {
+	return path.Join("repositories", repository+".html")
+}
+
+// relativeLink returns the link from the page with the given path to the page
+// with the given target path.
+func relativeLink(from string, to string) string {
+	return strings.Repeat("../", strings.Count(from, "/")) + to
+}
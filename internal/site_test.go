@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Static sites", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contributions := []Contribution{
+		{Type: CommitContribution, Repository: "herdstat/herdstat", Login: "alice", Date: lastDay},
+		{Type: IssueContribution, Repository: "herdstat/action", Login: "bob", Date: lastDay.AddDate(0, 0, -2)},
+		// Outside the analyzed period
+		{Type: IssueContribution, Repository: "herdstat/archive", Login: "bob", Date: lastDay.AddDate(-2, 0, 0)},
+	}
+	pages := NewSite("Herdstat", contributions, lastDay, 10)
+
+	It("creates an index page and a page per active repository", func() {
+		Expect(pages).To(HaveLen(3))
+		Expect(pages[0].Path).To(Equal("index.html"))
+		Expect(pages[0].Contributions).To(HaveLen(3))
+		Expect(pages[1].Path).To(Equal("repositories/herdstat/action.html"))
+		Expect(pages[1].Repository).To(Equal("herdstat/action"))
+		Expect(pages[1].Contributions).To(HaveLen(1))
+		Expect(pages[2].Path).To(Equal("repositories/herdstat/herdstat.html"))
+	})
+
+	It("links the pages", func() {
+		Expect(pages[0].Dashboard.Links).To(Equal([]DashboardLink{
+			{Label: "herdstat/action", URL: "repositories/herdstat/action.html"},
+			{Label: "herdstat/herdstat", URL: "repositories/herdstat/herdstat.html"},
+		}))
+		Expect(pages[1].Dashboard.Links).To(Equal([]DashboardLink{{Label: "Herdstat", URL: "../../index.html"}}))
+	})
+
+	It("renders the pages using custom templates", func() {
+		tmpl, err := ParseDashboardTemplate(`{{ .Title }}: {{ .Summary.TotalContributions }}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pages[2].Dashboard.Render(tmpl)).To(Equal("herdstat/herdstat: 1"))
+	})
+
+	It("renders the links", func() {
+		html, err := pages[0].Dashboard.HTML()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(html).To(ContainSubstring(`<li><a href="repositories/herdstat/action.html">herdstat/action</a></li>`))
+	})
+})
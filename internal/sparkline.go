@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Dimensions of the sparkline badge. Chosen to match the height of
+// shields.io badges.
+const (
+	sparklineWidth      = 120
+	sparklineHeight     = 20
+	sparklineLabelWidth = 54
+)
+
+// Sparkline is a compact badge visualizing the trend of weekly contribution
+// totals next to the overall number of contributions in that period.
+type Sparkline struct {
+
+	// The contribution totals per week in chronological order.
+	Totals []int
+
+	// The label rendered on the left side of the badge.
+	Label string
+
+	// The background color of the right side of the badge.
+	Color color.RGBA
+}
+
+// WeeklyTotals sums up the given daily contribution records into totals for
+// the given number of weeks ending with the last record. Weeks not covered by
+// the records have a total of zero.
+func WeeklyTotals(records []ContributionRecord, weeks int) []int {
+	totals := make([]int, weeks)
+	for i := 0; i < weeks*7 && i < len(records); i++ {
+		totals[weeks-1-i/7] += records[len(records)-1-i].Count
+	}
+	return totals
+}
+
+// NewSparkline creates a new Sparkline for the given number of weeks ending
+// with the last of the given records.
+func NewSparkline(records []ContributionRecord, weeks int, label string, color color.RGBA) *Sparkline {
+	return &Sparkline{
+		Totals: WeeklyTotals(records, weeks),
+		Label:  label,
+		Color:  color,
+	}
+}
+
+// compactNumber formats the given number using metric suffixes (e.g., 1.2k)
+// to fit into small spaces.
+func compactNumber(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000_000), ".0") + "M"
+	case n >= 1_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000), ".0") + "k"
+	}
+	return strconv.Itoa(n)
+}
+
+// points computes the SVG polyline points of the trend line within the given
+// bounding box.
+func (s *Sparkline) points(bounds image.Rectangle) string {
+	maxTotal := max(s.Totals, func(a, b int) int {
+		return a - b
+	})
+	var points []string
+	for i, total := range s.Totals {
+		x := bounds.Min.X
+		if len(s.Totals) > 1 {
+			x += i * bounds.Dx() / (len(s.Totals) - 1)
+		}
+		y := bounds.Max.Y
+		if maxTotal > 0 {
+			y -= total * bounds.Dy() / maxTotal
+		}
+		points = append(points, fmt.Sprintf("%d,%d", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+// Render writes the sparkline badge to the given xml.Encoder.
+func (s *Sparkline) Render(e *xml.Encoder) error {
+	return nonEmptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "svg"},
+		Attr: []xml.Attr{
+			attr("xmlns", "http://www.w3.org/2000/svg"),
+			attr("width", strconv.Itoa(sparklineWidth)),
+			attr("height", strconv.Itoa(sparklineHeight)),
+			attr("font-family", "Verdana,Geneva,DejaVu Sans,sans-serif"),
+		},
+	}, func(e *xml.Encoder) error {
+		err := nonEmptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "clipPath"},
+			Attr: []xml.Attr{attr("id", "herdstat-sparkline-clip")},
+		}, func(e *xml.Encoder) error {
+			return emptyElement(e, xml.StartElement{
+				Name: xml.Name{Local: "rect"},
+				Attr: []xml.Attr{
+					attr("width", strconv.Itoa(sparklineWidth)),
+					attr("height", strconv.Itoa(sparklineHeight)),
+					attr("rx", "3"),
+				},
+			})
+		})
+		if err != nil {
+			return err
+		}
+		return nonEmptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "g"},
+			Attr: []xml.Attr{attr("clip-path", "url(#herdstat-sparkline-clip)")},
+		}, s.renderContent)
+	})
+}
+
+// renderContent renders the label, the trend line, and the overall number of
+// contributions.
+func (s *Sparkline) renderContent(e *xml.Encoder) error {
+	rect := func(x, width int, fill string) error {
+		return emptyElement(e, xml.StartElement{
+			Name: xml.Name{Local: "rect"},
+			Attr: []xml.Attr{
+				attr("x", strconv.Itoa(x)),
+				attr("width", strconv.Itoa(width)),
+				attr("height", strconv.Itoa(sparklineHeight)),
+				attr("fill", fill),
+			},
+		})
+	}
+	if err := rect(0, sparklineLabelWidth, "#555"); err != nil {
+		return err
+	}
+	if err := rect(sparklineLabelWidth, sparklineWidth-sparklineLabelWidth,
+		fmt.Sprintf("rgb(%d, %d, %d)", s.Color.R, s.Color.G, s.Color.B)); err != nil {
+		return err
+	}
+
+	fill := []xml.Attr{attr("fill", "#fff")}
+	err := simpleText(e, image.Point{X: sparklineLabelWidth / 2, Y: 14}, middle, fill, s.Label)
+	if err != nil {
+		return err
+	}
+
+	err = emptyElement(e, xml.StartElement{
+		Name: xml.Name{Local: "polyline"},
+		Attr: []xml.Attr{
+			attr("points", s.points(image.Rect(sparklineLabelWidth+4, 4, sparklineLabelWidth+34, 16))),
+			attr("fill", "none"),
+			attr("stroke", "#fff"),
+			attr("stroke-width", "1.5"),
+			attr("stroke-linejoin", "round"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, t := range s.Totals {
+		total += t
+	}
+	return simpleText(e, image.Point{X: sparklineWidth - 4, Y: 14}, end, fill, compactNumber(total))
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Summing up weekly totals", func() {
+	records := make([]ContributionRecord, 21)
+	for i := range records {
+		records[i].Count = i / 7
+	}
+	When("the records cover all weeks", func() {
+		It("sums up the records of each week", func() {
+			Expect(WeeklyTotals(records, 2)).To(Equal([]int{7, 14}))
+		})
+	})
+	When("the records do not cover all weeks", func() {
+		It("leaves the uncovered weeks empty", func() {
+			Expect(WeeklyTotals(records, 4)).To(Equal([]int{0, 0, 7, 14}))
+		})
+	})
+})
+
+var _ = Describe("Formatting numbers compactly", func() {
+	It("keeps small numbers", func() {
+		Expect(compactNumber(999)).To(Equal("999"))
+	})
+	It("abbreviates thousands", func() {
+		Expect(compactNumber(1200)).To(Equal("1.2k"))
+		Expect(compactNumber(2000)).To(Equal("2k"))
+	})
+	It("abbreviates millions", func() {
+		Expect(compactNumber(3_450_000)).To(Equal("3.5M"))
+	})
+})
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SQLiteSchema creates the tables of the SQLite export. Existing tables are
+// replaced so that exporting into the same database again doesn't duplicate
+// rows.
+const SQLiteSchema = `DROP TABLE IF EXISTS metadata;
+DROP TABLE IF EXISTS contribution_files;
+DROP TABLE IF EXISTS contributions;
+DROP TABLE IF EXISTS daily_contributions;
+CREATE TABLE metadata (
+  key TEXT PRIMARY KEY,
+  value TEXT NOT NULL
+);
+CREATE TABLE contributions (
+  id INTEGER PRIMARY KEY,
+  type TEXT NOT NULL,
+  repository TEXT NOT NULL,
+  contributor TEXT,
+  login TEXT,
+  name TEXT,
+  email TEXT,
+  date TEXT NOT NULL,
+  url TEXT,
+  hash TEXT,
+  subject TEXT,
+  signed INTEGER NOT NULL,
+  signed_off INTEGER NOT NULL,
+  additions INTEGER NOT NULL,
+  deletions INTEGER NOT NULL
+);
+CREATE INDEX contributions_date ON contributions (date);
+CREATE INDEX contributions_repository ON contributions (repository);
+CREATE TABLE contribution_files (
+  contribution_id INTEGER NOT NULL REFERENCES contributions (id),
+  path TEXT NOT NULL
+);
+CREATE TABLE daily_contributions (
+  date TEXT NOT NULL,
+  repository TEXT NOT NULL,
+  commits INTEGER NOT NULL,
+  issues INTEGER NOT NULL,
+  pull_requests INTEGER NOT NULL,
+  contributors INTEGER NOT NULL,
+  PRIMARY KEY (date, repository)
+);
+`
+
+// sqlText quotes the given string as SQL string literal. Empty strings are
+// rendered as NULL.
+func sqlText(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlBool renders the given boolean as SQLite integer.
+func sqlBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// SQLiteMetadata returns the key-value pairs of the metadata table describing
+// the given aggregates generated at the given time.
+func SQLiteMetadata(aggregates *AggregateExport, generated time.Time) [][2]string {
+	return [][2]string{
+		{"from", aggregates.From},
+		{"until", aggregates.Until},
+		{"generated", generated.UTC().Format(time.RFC3339)},
+	}
+}
+
+// WriteSQLiteScript writes an SQL script to the given writer that loads the
+// given contributions and their daily aggregates for the 52 weeks ending with
+// the given day into an SQLite database. All contributions are written
+// regardless of their date, whereas the daily aggregates cover the analyzed
+// period only. The script runs in a single transaction.
+func WriteSQLiteScript(w io.Writer, contributions []Contribution, lastDay time.Time, generated time.Time) error {
+	var b strings.Builder
+	b.WriteString("BEGIN TRANSACTION;\n")
+	b.WriteString(SQLiteSchema)
+	aggregates := NewAggregateExport(contributions, lastDay, DailyGranularity)
+	for _, kv := range SQLiteMetadata(aggregates, generated) {
+		fmt.Fprintf(&b, "INSERT INTO metadata VALUES (%s, %s);\n", sqlText(kv[0]), sqlText(kv[1]))
+	}
+	for i, c := range contributions {
+		id := i + 1
+		fmt.Fprintf(&b, "INSERT INTO contributions VALUES (%d, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %d, %d, %d, %d);\n",
+			id, sqlText(string(c.Type)), sqlText(c.Repository), sqlText(c.Contributor()), sqlText(c.Login),
+			sqlText(c.Name), sqlText(c.Email), sqlText(c.Date.UTC().Format(time.RFC3339)), sqlText(c.URL),
+			sqlText(c.Hash), sqlText(c.Subject), sqlBool(c.Signed), sqlBool(c.SignedOff), c.Additions, c.Deletions)
+		for _, path := range c.Files {
+			fmt.Fprintf(&b, "INSERT INTO contribution_files VALUES (%d, %s);\n", id, sqlText(path))
+		}
+	}
+	for _, r := range aggregates.Records {
+		fmt.Fprintf(&b, "INSERT INTO daily_contributions VALUES (%s, %s, %d, %d, %d, %d);\n",
+			sqlText(r.Date), sqlText(r.Repository), r.Commits, r.Issues, r.PullRequests, r.Contributors)
+	}
+	b.WriteString("COMMIT;\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
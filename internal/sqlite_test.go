@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"strings"
+)
+
+var _ = Describe("SQLite export", func() {
+	lastDay := dateparse.MustParse("2023-04-01")
+
+	It("writes contributions and daily aggregates", func() {
+		var b strings.Builder
+		Expect(WriteSQLiteScript(&b, []Contribution{
+			{Type: CommitContribution, Repository: "herdstat/herdstat", Name: "Jane", Email: "jane@example.com",
+				Date: lastDay, Subject: "Don't panic", Signed: true, Files: []string{"main.go"}},
+			{Type: IssueContribution, Repository: "herdstat/herdstat", Login: "john", Date: lastDay.AddDate(-2, 0, 0)},
+		}, lastDay, lastDay)).To(Succeed())
+		script := b.String()
+		Expect(script).To(HavePrefix("BEGIN TRANSACTION;\n"))
+		Expect(script).To(HaveSuffix("COMMIT;\n"))
+		Expect(script).To(ContainSubstring("INSERT INTO metadata VALUES ('until', '2023-04-01');\n"))
+		Expect(script).To(ContainSubstring("INSERT INTO contributions VALUES (1, 'commit', 'herdstat/herdstat', " +
+			"'jane@example.com', NULL, 'Jane', 'jane@example.com', '2023-04-01T00:00:00Z', NULL, NULL, 'Don''t panic', 1, 0, 0, 0);\n"))
+		Expect(script).To(ContainSubstring("INSERT INTO contribution_files VALUES (1, 'main.go');\n"))
+		Expect(script).To(ContainSubstring("INSERT INTO contributions VALUES (2, 'issue'"))
+		Expect(strings.Count(script, "INSERT INTO daily_contributions")).To(Equal(1))
+		Expect(script).To(ContainSubstring("INSERT INTO daily_contributions VALUES ('2023-04-01', 'herdstat/herdstat', 1, 0, 0, 1);\n"))
+	})
+})
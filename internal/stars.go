@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"image/color"
+	"sort"
+	"time"
+)
+
+// StarHistory computes the cumulative number of stars at the end of each
+// period from the period of the first star until the period of the given day.
+// Stars after the given day are ignored. Returns the axis labels and the
+// numbers of stars of the periods in chronological order.
+func StarHistory(starredAt []time.Time, lastDay time.Time, granularity Granularity) ([]string, []int) {
+	var stars []time.Time
+	for _, t := range starredAt {
+		if !t.After(lastDay) {
+			stars = append(stars, t)
+		}
+	}
+	if len(stars) == 0 {
+		return nil, nil
+	}
+	sort.Slice(stars, func(i, j int) bool {
+		return stars[i].Before(stars[j])
+	})
+
+	var labels []string
+	var totals []int
+	last := granularity.periodStart(lastDay)
+	i := 0
+	for start := granularity.periodStart(stars[0].In(lastDay.Location())); !start.After(last); start = granularity.next(start) {
+		end := granularity.next(start)
+		for i < len(stars) && stars[i].Before(end) {
+			i++
+		}
+		labels = append(labels, granularity.label(start))
+		totals = append(totals, i)
+	}
+	return labels, totals
+}
+
+// NewStarHistoryChart creates a LineChart visualizing the cumulative number
+// of stars over time.
+func NewStarHistoryChart(title string, starredAt []time.Time, lastDay time.Time, granularity Granularity,
+	color color.RGBA) *LineChart {
+	labels, totals := StarHistory(starredAt, lastDay, granularity)
+	return &LineChart{
+		Title:  title,
+		Labels: labels,
+		Series: []Series{{Name: "Stars", Values: totals, Color: color}},
+		Area:   true,
+	}
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Star histories", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+
+	It("accumulates the stars per period starting with the first star", func() {
+		labels, totals := StarHistory([]time.Time{
+			dateparse.MustParse("2023-01-31"),
+			dateparse.MustParse("2022-12-01"),
+			dateparse.MustParse("2023-01-01"),
+			// After the last day
+			dateparse.MustParse("2023-03-20"),
+		}, lastDay, MonthlyGranularity)
+		Expect(labels).To(Equal([]string{"Dec '22", "Jan '23", "Feb '23", "Mar '23"}))
+		Expect(totals).To(Equal([]int{1, 3, 3, 3}))
+	})
+
+	It("is empty without stars", func() {
+		labels, totals := StarHistory(nil, lastDay, WeeklyGranularity)
+		Expect(labels).To(BeEmpty())
+		Expect(totals).To(BeEmpty())
+	})
+})
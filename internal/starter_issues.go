@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StarterIssues describes the open issues of a repository (or a set of
+// repositories) labeled as suitable for newcomers.
+type StarterIssues struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall
+	// availability.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The number of open starter issues.
+	Issues int `json:"issues" yaml:"issues"`
+
+	// The median age of the open starter issues in days.
+	MedianAgeDays float64 `json:"medianAgeDays" yaml:"medianAgeDays"`
+
+	// The age of the oldest open starter issue in days.
+	OldestAgeDays int `json:"oldestAgeDays" yaml:"oldestAgeDays"`
+
+	// Whether there are no open starter issues.
+	Unavailable bool `json:"unavailable" yaml:"unavailable"`
+}
+
+// StarterIssuesReport contains the availability of starter issues per
+// repository and overall.
+type StarterIssuesReport struct {
+
+	// The day the availability is determined for.
+	Until string `json:"until" yaml:"until"`
+
+	// The labels identifying starter issues.
+	Labels []string `json:"labels" yaml:"labels"`
+
+	// The availability across all repositories.
+	Overall StarterIssues `json:"overall" yaml:"overall"`
+
+	// The availability in the individual repositories sorted by name.
+	Repositories []StarterIssues `json:"repositories" yaml:"repositories"`
+}
+
+// isStarterIssue returns true iff the given issue has one of the given labels.
+// Labels are compared case-insensitively.
+func isStarterIssue(issue Issue, labels []string) bool {
+	for _, label := range issue.Labels {
+		for _, l := range labels {
+			if strings.EqualFold(label, l) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newStarterIssues computes the availability of the given starter issues at
+// the end of the given day.
+func newStarterIssues(repository string, issues []Issue, lastDay time.Time) StarterIssues {
+	var ages []float64
+	for _, issue := range issues {
+		ages = append(ages, float64(DaysBetween(issue.Created, lastDay)))
+	}
+	sort.Float64s(ages)
+	availability := StarterIssues{
+		Repository:    repository,
+		Issues:        len(ages),
+		MedianAgeDays: math.Round(percentile(ages, 0.5)*10) / 10,
+		Unavailable:   len(ages) == 0,
+	}
+	if len(ages) > 0 {
+		availability.OldestAgeDays = int(ages[len(ages)-1])
+	}
+	return availability
+}
+
+// NewStarterIssuesReport computes the availability of issues with one of the
+// given labels open at the end of the given day in each of the given
+// repositories.
+func NewStarterIssuesReport(repositories []string, issues []Issue, lastDay time.Time, labels []string) *StarterIssuesReport {
+	byRepository := make(map[string][]Issue)
+	var starterIssues []Issue
+	for _, issue := range issues {
+		open := !issue.Created.After(lastDay) && (issue.Closed.IsZero() || issue.Closed.After(lastDay))
+		if open && isStarterIssue(issue, labels) {
+			starterIssues = append(starterIssues, issue)
+			byRepository[issue.Repository] = append(byRepository[issue.Repository], issue)
+		}
+	}
+	report := &StarterIssuesReport{
+		Until:   lastDay.Format(dateFormat),
+		Labels:  labels,
+		Overall: newStarterIssues("", starterIssues, lastDay),
+	}
+	sorted := append([]string(nil), repositories...)
+	sort.Strings(sorted)
+	for _, repository := range sorted {
+		report.Repositories = append(report.Repositories, newStarterIssues(repository, byRepository[repository], lastDay))
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering starter issue reports as
+	// markdown.
+	//go:embed starter-issues.gomd
+	starterIssuesTemplate string
+)
+
+// Markdown renders the starter issue report as markdown.
+func (r *StarterIssuesReport) Markdown() (string, error) {
+	return renderMarkdown("starter-issues", starterIssuesTemplate, r)
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Starter issue reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	labels := []string{"good first issue", "help wanted"}
+	issues := []Issue{
+		{Repository: "herdstat/herdstat", Created: dateparse.MustParse("2023-03-05"), Labels: []string{"Good First Issue"}},
+		{Repository: "herdstat/herdstat", Created: dateparse.MustParse("2023-02-13"), Labels: []string{"help wanted", "bug"}},
+		{Repository: "herdstat/herdstat", Created: dateparse.MustParse("2023-01-01"), Labels: []string{"bug"}},
+		// Closed
+		{Repository: "herdstat/action", Created: dateparse.MustParse("2023-01-01"), Closed: dateparse.MustParse("2023-02-01"),
+			Labels: []string{"help wanted"}},
+	}
+	report := NewStarterIssuesReport([]string{"herdstat/herdstat", "herdstat/action"}, issues, lastDay, labels)
+
+	It("computes the availability across all repositories", func() {
+		Expect(report.Overall).To(Equal(StarterIssues{
+			Issues:        2,
+			MedianAgeDays: 20,
+			OldestAgeDays: 30,
+		}))
+	})
+
+	It("flags repositories without open starter issues", func() {
+		Expect(report.Repositories).To(HaveLen(2))
+		Expect(report.Repositories[0]).To(Equal(StarterIssues{Repository: "herdstat/action", Unavailable: true}))
+		Expect(report.Repositories[1].Unavailable).To(BeFalse())
+	})
+
+	It("renders markdown", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| herdstat/action | 0 | - | - | :warning: |"))
+		Expect(md).To(ContainSubstring("labeled good first issue or help wanted"))
+	})
+})
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"time"
+)
+
+// DayTotal is the number of contributions made on a single day.
+type DayTotal struct {
+	Date  string `json:"date" yaml:"date"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// WeekTotal is the number of contributions made in the week starting with
+// the given Sunday.
+type WeekTotal struct {
+	Start string `json:"start" yaml:"start"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// Summary contains aggregate statistics about the contributions made within
+// the analyzed period.
+type Summary struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The number of repositories with at least one contribution.
+	ActiveRepositories int `json:"activeRepositories" yaml:"activeRepositories"`
+
+	// The overall number of contributions.
+	TotalContributions int `json:"totalContributions" yaml:"totalContributions"`
+
+	// The number of distinct contributors.
+	UniqueContributors int `json:"uniqueContributors" yaml:"uniqueContributors"`
+
+	// The number of days with at least one contribution.
+	ActiveDays int `json:"activeDays" yaml:"activeDays"`
+
+	// The day with the most contributions.
+	BusiestDay DayTotal `json:"busiestDay" yaml:"busiestDay"`
+
+	// The week with the most contributions.
+	BusiestWeek WeekTotal `json:"busiestWeek" yaml:"busiestWeek"`
+
+	// The trend of the weekly contribution volume.
+	Velocity Velocity `json:"velocity" yaml:"velocity"`
+
+	// The days with a statistically unusual number of contributions. Only
+	// detected if requested.
+	Anomalies []Anomaly `json:"anomalies,omitempty" yaml:"anomalies,omitempty"`
+
+	// How quickly the pull requests opened within the period have been
+	// reviewed. Only computed if requested.
+	PullRequests *PullRequestMetrics `json:"pullRequests,omitempty" yaml:"pullRequests,omitempty"`
+
+	// The number of contributions per contribution type.
+	ContributionsByType map[ContributionType]int `json:"contributionsByType" yaml:"contributionsByType"`
+}
+
+// dateFormat is the format used for dates in machine-readable output.
+const dateFormat = "2006-01-02"
+
+// NewSummary computes the Summary of the given contributions made within the
+// 52 weeks ending with the given day.
+func NewSummary(contributions []Contribution, lastDay time.Time) *Summary {
+	records := DailyRecords(contributions, lastDay)
+	summary := &Summary{
+		From:                records[0].Date.Format(dateFormat),
+		Until:               lastDay.Format(dateFormat),
+		ContributionsByType: make(map[ContributionType]int),
+	}
+
+	repositories := make(map[string]bool)
+	contributors := make(map[string]bool)
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		repositories[c.Repository] = true
+		contributors[c.Contributor()] = true
+		summary.ContributionsByType[c.Type]++
+	}
+	summary.ActiveRepositories = len(repositories)
+	summary.UniqueContributors = len(contributors)
+
+	weeks := make(map[string]int)
+	var weekStarts []string
+	for _, r := range records {
+		summary.TotalContributions += r.Count
+		if r.Count > 0 {
+			summary.ActiveDays++
+		}
+		if r.Count > summary.BusiestDay.Count {
+			summary.BusiestDay = DayTotal{Date: r.Date.Format(dateFormat), Count: r.Count}
+		}
+		week := previousSunday(r.Date).Format(dateFormat)
+		if _, ok := weeks[week]; !ok {
+			weekStarts = append(weekStarts, week)
+		}
+		weeks[week] += r.Count
+	}
+	for _, week := range weekStarts {
+		if weeks[week] > summary.BusiestWeek.Count {
+			summary.BusiestWeek = WeekTotal{Start: week, Count: weeks[week]}
+		}
+	}
+	summary.Velocity = NewVelocity(records)
+	return summary
+}
+
+var (
+	// The embedded template used for rendering summaries as markdown.
+	//go:embed summary.gomd
+	summaryTemplate string
+)
+
+// Markdown renders the summary as a markdown block suitable for pasting into
+// community updates.
+func (s *Summary) Markdown() (string, error) {
+	return renderMarkdown("summary", summaryTemplate, s)
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Summarizing contributions", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	contributions := []Contribution{
+		{Type: CommitContribution, Repository: "herdstat/a", Email: "Jane.Roe@herdstat.com", Date: lastDay.AddDate(0, 0, -1)},
+		{Type: CommitContribution, Repository: "herdstat/a", Email: "jane.roe@herdstat.com", Date: lastDay.AddDate(0, 0, -1)},
+		{Type: IssueContribution, Repository: "herdstat/b", Login: "jroe", Date: lastDay.AddDate(0, 0, -10)},
+		{Type: IssueContribution, Repository: "herdstat/c", Login: "jdoe", Date: lastDay.AddDate(-2, 0, 0)},
+	}
+	summary := NewSummary(contributions, lastDay)
+
+	It("counts contributions within the period only", func() {
+		Expect(summary.TotalContributions).To(Equal(3))
+		Expect(summary.ActiveRepositories).To(Equal(2))
+		Expect(summary.ContributionsByType).To(Equal(map[ContributionType]int{
+			CommitContribution: 2,
+			IssueContribution:  1,
+		}))
+	})
+	It("identifies contributors case-insensitively by email", func() {
+		Expect(summary.UniqueContributors).To(Equal(2))
+	})
+	It("determines the active and busiest days", func() {
+		Expect(summary.ActiveDays).To(Equal(2))
+		Expect(summary.BusiestDay).To(Equal(DayTotal{Date: "2023-03-14", Count: 2}))
+		Expect(summary.BusiestWeek).To(Equal(WeekTotal{Start: "2023-03-12", Count: 2}))
+	})
+	It("determines the velocity", func() {
+		Expect(summary.Velocity.Direction).To(Equal(VelocityUp))
+	})
+	It("renders as markdown", func() {
+		md, err := summary.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| Total contributions | 3 |"))
+		Expect(md).To(ContainSubstring("| Velocity | ↑ "))
+		Expect(md).NotTo(ContainSubstring("time to first review"))
+	})
+	It("renders the time to first review as markdown if computed", func() {
+		metrics := NewPullRequestMetrics("", []PullRequest{
+			{Repository: "herdstat/a", Created: lastDay.AddDate(0, 0, -2),
+				Reviews: []Review{{Reviewer: "jroe", Submitted: lastDay.AddDate(0, 0, -2).Add(2 * time.Hour)}}},
+			{Repository: "herdstat/b", Created: lastDay.AddDate(0, 0, -1)},
+		}, lastDay)
+		withReviews := *summary
+		withReviews.PullRequests = &metrics
+		md, err := withReviews.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| Reviewed pull requests | 1 of 2 |"))
+		Expect(md).To(ContainSubstring("| Median time to first review | 2.0h |"))
+		Expect(md).To(ContainSubstring("| P90 time to first review | 2.0h |"))
+	})
+})
@@ -15,6 +15,24 @@ import (
 	"strings"
 )
 
+// Renderer is implemented by all visualizations that can be rendered as an
+// SVG document.
+type Renderer interface {
+
+	// Render writes the SVG document to the given xml.Encoder.
+	Render(e *xml.Encoder) error
+}
+
+// attr creates an XML attribute with the given name and value.
+func attr(name string, value string) xml.Attr {
+	return xml.Attr{
+		Name: xml.Name{
+			Local: name,
+		},
+		Value: value,
+	}
+}
+
 // cssClassAttr creates a `class` XML attribute with the given array of classes.
 func cssClassAttr(classes ...string) xml.Attr {
 	return xml.Attr{
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+)
+
+// TimezoneShare is the number of contributors located in a time zone.
+type TimezoneShare struct {
+
+	// The UTC offset of the time zone (e.g., 'UTC+05:30').
+	Timezone string `json:"timezone" yaml:"timezone"`
+
+	// The UTC offset of the time zone in minutes.
+	OffsetMinutes int `json:"offsetMinutes" yaml:"offsetMinutes"`
+
+	// The number of contributors located in the time zone.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The share of contributors located in the time zone in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+}
+
+// TimezoneReport describes the distribution of contributors across time
+// zones.
+type TimezoneReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The number of contributors with a known time zone.
+	Contributors int `json:"contributors" yaml:"contributors"`
+
+	// The time zones of the contributors ordered by UTC offset.
+	Timezones []TimezoneShare `json:"timezones" yaml:"timezones"`
+}
+
+// formatOffset formats the given UTC offset in minutes.
+func formatOffset(minutes int) string {
+	sign := "+"
+	if minutes < 0 {
+		sign = "-"
+		minutes = -minutes
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, minutes/60, minutes%60)
+}
+
+// NewTimezoneReport infers the time zones of the contributors from the UTC
+// offsets recorded with their commits made within the 52 weeks ending with
+// the given day. The time zone of a contributor is the one most of the
+// contributor's commits have been made in.
+func NewTimezoneReport(commits []Contribution, lastDay time.Time) *TimezoneReport {
+	report := &TimezoneReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	offsets := make(map[string]map[int]int)
+	for _, c := range commits {
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		_, offset := c.Date.Zone()
+		contributor := c.Contributor()
+		if offsets[contributor] == nil {
+			offsets[contributor] = make(map[int]int)
+		}
+		offsets[contributor][offset/60]++
+	}
+
+	contributors := make(map[int]int)
+	for _, counts := range offsets {
+		best, bestCount := 0, -1
+		for offset, count := range counts {
+			if count > bestCount || (count == bestCount && offset < best) {
+				best, bestCount = offset, count
+			}
+		}
+		contributors[best]++
+	}
+
+	report.Contributors = len(offsets)
+	for offset, count := range contributors {
+		report.Timezones = append(report.Timezones, TimezoneShare{
+			Timezone:      formatOffset(offset),
+			OffsetMinutes: offset,
+			Contributors:  count,
+			Percentage:    percentage(count, report.Contributors),
+		})
+	}
+	sort.Slice(report.Timezones, func(i, j int) bool {
+		return report.Timezones[i].OffsetMinutes < report.Timezones[j].OffsetMinutes
+	})
+	return report
+}
+
+var (
+	// The embedded template used for rendering time zone reports as markdown.
+	//go:embed timezones.gomd
+	timezonesTemplate string
+)
+
+// Markdown renders the time zone report as markdown.
+func (r *TimezoneReport) Markdown() (string, error) {
+	return renderMarkdown("timezones", timezonesTemplate, r)
+}
+
+// NewTimezoneChart creates a BarChart visualizing the number of contributors
+// per time zone.
+func NewTimezoneChart(report *TimezoneReport, color color.RGBA) *BarChart {
+	chart := &BarChart{
+		Title: "Contributors per time zone",
+		Color: color,
+	}
+	for _, tz := range report.Timezones {
+		chart.Bars = append(chart.Bars, Bar{
+			Label: tz.Timezone,
+			Value: tz.Contributors,
+			Text:  fmt.Sprintf("%d (%.1f%%)", tz.Contributors, tz.Percentage),
+		})
+	}
+	return chart
+}
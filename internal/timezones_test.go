@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+	"time"
+)
+
+var _ = Describe("Time zone reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	berlin := time.FixedZone("CET", 60*60)
+	india := time.FixedZone("IST", 5*60*60+30*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+	commit := func(login string, date time.Time) Contribution {
+		return Contribution{Type: CommitContribution, Login: login, Date: date}
+	}
+	commits := []Contribution{
+		commit("alice", time.Date(2023, 3, 1, 10, 0, 0, 0, berlin)),
+		commit("alice", time.Date(2023, 3, 2, 10, 0, 0, 0, berlin)),
+		commit("alice", time.Date(2023, 3, 3, 10, 0, 0, 0, newYork)),
+		commit("bob", time.Date(2023, 3, 1, 10, 0, 0, 0, india)),
+		commit("carol", time.Date(2023, 3, 1, 10, 0, 0, 0, newYork)),
+		commit("dave", time.Date(2023, 3, 1, 10, 0, 0, 0, berlin)),
+		// Outside the analyzed period
+		commit("erin", time.Date(2020, 3, 1, 10, 0, 0, 0, india)),
+		// Not a commit
+		{Type: IssueContribution, Login: "frank", Date: time.Date(2023, 3, 1, 10, 0, 0, 0, india)},
+	}
+	report := NewTimezoneReport(commits, lastDay)
+
+	It("counts contributors with commits in the analyzed period", func() {
+		Expect(report.Contributors).To(Equal(4))
+	})
+
+	It("assigns contributors to their most frequent time zone ordered by offset", func() {
+		Expect(report.Timezones).To(Equal([]TimezoneShare{
+			{Timezone: "UTC-05:00", OffsetMinutes: -300, Contributors: 1, Percentage: 25},
+			{Timezone: "UTC+01:00", OffsetMinutes: 60, Contributors: 2, Percentage: 50},
+			{Timezone: "UTC+05:30", OffsetMinutes: 330, Contributors: 1, Percentage: 25},
+		}))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| UTC+01:00 | 2 | 50.0% |"))
+	})
+
+	It("renders a bar chart", func() {
+		var buf bytes.Buffer
+		enc := xml.NewEncoder(&buf)
+		Expect(NewTimezoneChart(report, color.RGBA{R: 0x39, G: 0xd3, B: 0x52, A: 0xff}).Render(enc)).To(Succeed())
+		Expect(enc.Flush()).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("2 (50.0%)"))
+	})
+})
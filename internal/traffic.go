@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"image/color"
+	"sort"
+	"time"
+)
+
+// TrafficDay is the traffic of a repository on a single day.
+type TrafficDay struct {
+
+	// The day in '2006-01-02' notation.
+	Date string `json:"date" yaml:"date"`
+
+	// The number of page views.
+	Views int `json:"views" yaml:"views"`
+
+	// The number of unique visitors.
+	Visitors int `json:"visitors" yaml:"visitors"`
+
+	// The number of clones.
+	Clones int `json:"clones" yaml:"clones"`
+
+	// The number of unique cloners.
+	Cloners int `json:"cloners" yaml:"cloners"`
+}
+
+// RepositoryTraffic is the traffic of a repository as reported by GitHub for
+// the last 14 days.
+type RepositoryTraffic struct {
+
+	// The repository in 'owner/name' notation.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// The overall number of page views.
+	Views int `json:"views" yaml:"views"`
+
+	// The overall number of unique visitors.
+	Visitors int `json:"visitors" yaml:"visitors"`
+
+	// The overall number of clones.
+	Clones int `json:"clones" yaml:"clones"`
+
+	// The overall number of unique cloners.
+	Cloners int `json:"cloners" yaml:"cloners"`
+
+	// The traffic per day in chronological order.
+	Days []TrafficDay `json:"days" yaml:"days"`
+}
+
+// TrafficReport contains the traffic per repository and overall.
+type TrafficReport struct {
+
+	// The traffic across all repositories. Unique visitors and cloners are
+	// summed up over the repositories.
+	Overall RepositoryTraffic `json:"overall" yaml:"overall"`
+
+	// The traffic of the individual repositories sorted by name.
+	Repositories []RepositoryTraffic `json:"repositories" yaml:"repositories"`
+}
+
+// NewTrafficReport aggregates the traffic of the given repositories. The days
+// of each repository are sorted chronologically.
+func NewTrafficReport(repositories []RepositoryTraffic) *TrafficReport {
+	report := &TrafficReport{Repositories: repositories}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		return report.Repositories[i].Repository < report.Repositories[j].Repository
+	})
+	days := make(map[string]*TrafficDay)
+	for _, r := range repositories {
+		sort.Slice(r.Days, func(i, j int) bool {
+			return r.Days[i].Date < r.Days[j].Date
+		})
+		report.Overall.Views += r.Views
+		report.Overall.Visitors += r.Visitors
+		report.Overall.Clones += r.Clones
+		report.Overall.Cloners += r.Cloners
+		for _, d := range r.Days {
+			day, ok := days[d.Date]
+			if !ok {
+				day = &TrafficDay{Date: d.Date}
+				days[d.Date] = day
+			}
+			day.Views += d.Views
+			day.Visitors += d.Visitors
+			day.Clones += d.Clones
+			day.Cloners += d.Cloners
+		}
+	}
+	dates := Keys(days)
+	sort.Strings(dates)
+	for _, date := range dates {
+		report.Overall.Days = append(report.Overall.Days, *days[date])
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering traffic reports as markdown.
+	//go:embed traffic.gomd
+	trafficTemplate string
+)
+
+// Markdown renders the traffic report as markdown.
+func (r *TrafficReport) Markdown() (string, error) {
+	return renderMarkdown("traffic", trafficTemplate, r)
+}
+
+// NewTrafficChart creates a LineChart visualizing the daily views and clones
+// across all repositories.
+func NewTrafficChart(report *TrafficReport, color color.RGBA) (*LineChart, error) {
+	chart := &LineChart{
+		Title: "Traffic",
+		Series: []Series{
+			{Name: "Views", Color: color},
+			{Name: "Clones", Color: secondarySeriesColor},
+		},
+	}
+	for _, day := range report.Overall.Days {
+		date, err := time.Parse(dateFormat, day.Date)
+		if err != nil {
+			return nil, err
+		}
+		chart.Labels = append(chart.Labels, date.Format("Jan 2"))
+		chart.Series[0].Values = append(chart.Series[0].Values, day.Views)
+		chart.Series[1].Values = append(chart.Series[1].Values, day.Clones)
+	}
+	return chart, nil
+}
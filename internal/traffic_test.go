@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Traffic reports", func() {
+	report := NewTrafficReport([]RepositoryTraffic{
+		{
+			Repository: "herdstat/herdstat", Views: 30, Visitors: 5, Clones: 4, Cloners: 2,
+			Days: []TrafficDay{
+				{Date: "2023-03-02", Views: 10, Visitors: 2, Clones: 1, Cloners: 1},
+				{Date: "2023-03-01", Views: 20, Visitors: 3, Clones: 3, Cloners: 1},
+			},
+		},
+		{
+			Repository: "herdstat/action", Views: 5, Visitors: 1, Clones: 0, Cloners: 0,
+			Days: []TrafficDay{
+				{Date: "2023-03-01", Views: 5, Visitors: 1},
+			},
+		},
+	})
+
+	It("sorts the repositories by name", func() {
+		Expect(report.Repositories[0].Repository).To(Equal("herdstat/action"))
+	})
+
+	It("sorts the days of each repository chronologically", func() {
+		Expect(report.Repositories[1].Days[0].Date).To(Equal("2023-03-01"))
+	})
+
+	It("aggregates the traffic of all repositories per day", func() {
+		Expect(report.Overall.Views).To(Equal(35))
+		Expect(report.Overall.Days).To(Equal([]TrafficDay{
+			{Date: "2023-03-01", Views: 25, Visitors: 4, Clones: 3, Cloners: 1},
+			{Date: "2023-03-02", Views: 10, Visitors: 2, Clones: 1, Cloners: 1},
+		}))
+	})
+
+	It("charts the daily views and clones", func() {
+		chart, err := NewTrafficChart(report, color.RGBA{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chart.Labels).To(Equal([]string{"Mar 1", "Mar 2"}))
+		Expect(chart.Series[0].Values).To(Equal([]int{25, 10}))
+		Expect(chart.Series[1].Values).To(Equal([]int{3, 1}))
+	})
+})
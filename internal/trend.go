@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+)
+
+// Granularity determines the length of the periods contributions are
+// aggregated over.
+type Granularity uint8
+
+const (
+
+	// WeeklyGranularity aggregates contributions per week starting on Sunday.
+	WeeklyGranularity Granularity = iota
+
+	// MonthlyGranularity aggregates contributions per calendar month.
+	MonthlyGranularity
+
+	// DailyGranularity aggregates contributions per day.
+	DailyGranularity
+)
+
+// Names of the supported granularities.
+const (
+	DailyGranularityName   = "daily"
+	WeeklyGranularityName  = "weekly"
+	MonthlyGranularityName = "monthly"
+)
+
+// Names of the supported buckets, i.e., granularities referred to by the
+// length of their periods.
+const (
+	DayBucketName   = "day"
+	WeekBucketName  = "week"
+	MonthBucketName = "month"
+)
+
+// ParseGranularity returns the Granularity registered under the given name.
+func ParseGranularity(name string) (Granularity, error) {
+	switch name {
+	case DailyGranularityName:
+		return DailyGranularity, nil
+	case WeeklyGranularityName:
+		return WeeklyGranularity, nil
+	case MonthlyGranularityName:
+		return MonthlyGranularity, nil
+	}
+	return 0, fmt.Errorf("unknown granularity '%s'; supported are %s, %s, and %s",
+		name, DailyGranularityName, WeeklyGranularityName, MonthlyGranularityName)
+}
+
+// ParseBucket returns the Granularity of the bucket registered under the
+// given name.
+func ParseBucket(name string) (Granularity, error) {
+	switch name {
+	case DayBucketName:
+		return DailyGranularity, nil
+	case WeekBucketName:
+		return WeeklyGranularity, nil
+	case MonthBucketName:
+		return MonthlyGranularity, nil
+	}
+	return 0, fmt.Errorf("unknown bucket '%s'; supported are %s, %s, and %s",
+		name, DayBucketName, WeekBucketName, MonthBucketName)
+}
+
+// periodStart returns the first day of the period the given date falls into.
+func (g Granularity) periodStart(date time.Time) time.Time {
+	switch g {
+	case MonthlyGranularity:
+		return monthOf(date)
+	case DailyGranularity:
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	}
+	sunday := previousSunday(date)
+	return time.Date(sunday.Year(), sunday.Month(), sunday.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// next returns the first day of the period following the one starting with the
+// given day.
+func (g Granularity) next(start time.Time) time.Time {
+	switch g {
+	case MonthlyGranularity:
+		return start.AddDate(0, 1, 0)
+	case DailyGranularity:
+		return start.AddDate(0, 0, 1)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+// label returns the axis label of the period starting with the given day.
+func (g Granularity) label(start time.Time) string {
+	if g == MonthlyGranularity {
+		return start.Format("Jan '06")
+	}
+	return start.Format("Jan 2")
+}
+
+// noun returns the noun describing a single period.
+func (g Granularity) noun() string {
+	switch g {
+	case MonthlyGranularity:
+		return "month"
+	case DailyGranularity:
+		return "day"
+	}
+	return "week"
+}
+
+// PeriodTotals sums up the given daily contribution records per period.
+// Returns the axis labels and the totals of the periods in chronological
+// order.
+func PeriodTotals(records []ContributionRecord, granularity Granularity) ([]string, []int) {
+	var labels []string
+	var totals []int
+	var current time.Time
+	for _, r := range records {
+		if start := granularity.periodStart(r.Date); len(totals) == 0 || !start.Equal(current) {
+			current = start
+			labels = append(labels, granularity.label(start))
+			totals = append(totals, 0)
+		}
+		totals[len(totals)-1] += r.Count
+	}
+	return labels, totals
+}
+
+// PeriodContributors counts the distinct contributors active per period
+// within the 52 weeks ending with the given day. Returns the axis labels and
+// the counts of the periods in chronological order.
+func PeriodContributors(contributions []Contribution, lastDay time.Time, granularity Granularity) ([]string, []int) {
+	var labels []string
+	indices := make(map[string]int)
+	for _, r := range NewContributionRecords(lastDay) {
+		start := granularity.periodStart(r.Date)
+		if _, ok := indices[start.Format(dateFormat)]; !ok {
+			indices[start.Format(dateFormat)] = len(labels)
+			labels = append(labels, granularity.label(start))
+		}
+	}
+	active := make([]map[string]bool, len(labels))
+	for _, c := range contributions {
+		if !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		idx, ok := indices[granularity.periodStart(c.Date).Format(dateFormat)]
+		if !ok {
+			continue
+		}
+		if active[idx] == nil {
+			active[idx] = make(map[string]bool)
+		}
+		active[idx][c.Contributor()] = true
+	}
+	counts := make([]int, len(labels))
+	for i, contributors := range active {
+		counts[i] = len(contributors)
+	}
+	return labels, counts
+}
+
+// NewTrendChart creates a LineChart visualizing the contribution totals per
+// period.
+func NewTrendChart(records []ContributionRecord, granularity Granularity, color color.RGBA, area bool) *LineChart {
+	labels, totals := PeriodTotals(records, granularity)
+	return &LineChart{
+		Title:  fmt.Sprintf("Contributions per %s", granularity.noun()),
+		Labels: labels,
+		Series: []Series{{Name: "Contributions", Values: totals, Color: color}},
+		Area:   area,
+	}
+}
+
+// NewContributorTrendChart creates a LineChart visualizing the number of
+// distinct active contributors per period.
+func NewContributorTrendChart(contributions []Contribution, lastDay time.Time, granularity Granularity,
+	color color.RGBA, area bool) *LineChart {
+	labels, counts := PeriodContributors(contributions, lastDay, granularity)
+	return &LineChart{
+		Title:  fmt.Sprintf("Active contributors per %s", granularity.noun()),
+		Labels: labels,
+		Series: []Series{{Name: "Contributors", Values: counts, Color: color}},
+		Area:   area,
+	}
+}
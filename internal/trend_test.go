@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"image/color"
+)
+
+var _ = Describe("Contribution trends", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	records := NewContributionRecords(lastDay)
+	records[len(records)-1].Count = 3
+	records[len(records)-4].Count = 2
+	records[0].Count = 1
+
+	When("aggregating weekly", func() {
+		labels, totals := PeriodTotals(records, WeeklyGranularity)
+		It("creates a period per week", func() {
+			Expect(labels).To(HaveLen(53))
+			Expect(labels[0]).To(Equal("Mar 13"))
+			Expect(labels[52]).To(Equal("Mar 12"))
+		})
+		It("sums up the contributions per week", func() {
+			Expect(totals[0]).To(Equal(1))
+			Expect(totals[52]).To(Equal(5))
+		})
+	})
+
+	When("aggregating monthly", func() {
+		labels, totals := PeriodTotals(records, MonthlyGranularity)
+		It("creates a period per month", func() {
+			Expect(labels).To(HaveLen(13))
+			Expect(labels[0]).To(Equal("Mar '22"))
+		})
+		It("sums up the contributions per month", func() {
+			Expect(totals[12]).To(Equal(5))
+		})
+	})
+
+	When("aggregating daily", func() {
+		labels, totals := PeriodTotals(records, DailyGranularity)
+		It("creates a period per day", func() {
+			Expect(labels).To(HaveLen(364))
+			Expect(totals[0]).To(Equal(1))
+			Expect(totals[363]).To(Equal(3))
+		})
+	})
+
+	It("parses buckets", func() {
+		for name, granularity := range map[string]Granularity{
+			DayBucketName:   DailyGranularity,
+			WeekBucketName:  WeeklyGranularity,
+			MonthBucketName: MonthlyGranularity,
+		} {
+			Expect(ParseBucket(name)).To(Equal(granularity))
+		}
+		_, err := ParseBucket("year")
+		Expect(err).To(HaveOccurred())
+	})
+
+	When("counting contributors", func() {
+		contributions := []Contribution{
+			{Login: "jdoe", Date: lastDay},
+			{Login: "jdoe", Date: lastDay.AddDate(0, 0, -1)},
+			{Login: "jroe", Date: lastDay.AddDate(0, 0, -1)},
+			{Login: "jroe", Date: lastDay.AddDate(0, -1, 0)},
+			{Login: "mmoe", Date: lastDay.AddDate(-2, 0, 0)},
+		}
+		It("counts distinct contributors per week", func() {
+			labels, counts := PeriodContributors(contributions, lastDay, WeeklyGranularity)
+			Expect(labels).To(HaveLen(53))
+			Expect(counts[52]).To(Equal(2))
+			Expect(counts[48]).To(Equal(1))
+		})
+		It("counts distinct contributors per month", func() {
+			_, counts := PeriodContributors(contributions, lastDay, MonthlyGranularity)
+			Expect(counts).To(HaveLen(13))
+			Expect(counts[11:]).To(Equal([]int{1, 2}))
+		})
+	})
+
+	It("renders a line chart", func() {
+		var buf bytes.Buffer
+		enc := xml.NewEncoder(&buf)
+		chart := NewTrendChart(records, MonthlyGranularity, color.RGBA{G: 0xff, A: 0xff}, true)
+		Expect(chart.Render(enc)).To(Succeed())
+		Expect(enc.Flush()).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("Contributions per month"))
+		Expect(buf.String()).To(ContainSubstring("<polygon"))
+	})
+
+	It("rounds axis maxima to nice numbers", func() {
+		Expect(niceCeiling(0)).To(Equal(1))
+		Expect(niceCeiling(7)).To(Equal(10))
+		Expect(niceCeiling(13)).To(Equal(20))
+		Expect(niceCeiling(420)).To(Equal(500))
+	})
+})
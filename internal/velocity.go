@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"math"
+)
+
+// velocityFlatThreshold is the absolute change in percent below which the
+// contribution volume is considered flat.
+const velocityFlatThreshold = 10
+
+// The directions of a velocity trend.
+const (
+	VelocityUp   = "up"
+	VelocityFlat = "flat"
+	VelocityDown = "down"
+)
+
+// Velocity describes whether the weekly contribution volume is trending up,
+// flat, or down.
+type Velocity struct {
+
+	// The direction of the trend (one of 'up', 'flat', or 'down').
+	Direction string `json:"direction" yaml:"direction"`
+
+	// The slope of the linear regression line of the weekly totals in
+	// contributions per week.
+	WeeklySlope float64 `json:"weeklySlope" yaml:"weeklySlope"`
+
+	// The change of the regression line over the analyzed period relative to
+	// the average weekly total in percent.
+	Percentage float64 `json:"percentage" yaml:"percentage"`
+}
+
+// NewVelocity fits a regression line to the weekly totals of the given 52
+// weeks of daily records to determine the trend of the contribution volume.
+func NewVelocity(records []ContributionRecord) Velocity {
+	totals := WeeklyTotals(records, 52)
+	n := float64(len(totals))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, total := range totals {
+		x, y := float64(i), float64(total)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	velocity := Velocity{Direction: VelocityFlat}
+	if sumY == 0 {
+		return velocity
+	}
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	mean := sumY / n
+	velocity.WeeklySlope = math.Round(slope*100) / 100
+	velocity.Percentage = math.Round(slope*(n-1)/mean*1000) / 10
+	switch {
+	case velocity.Percentage >= velocityFlatThreshold:
+		velocity.Direction = VelocityUp
+	case velocity.Percentage <= -velocityFlatThreshold:
+		velocity.Direction = VelocityDown
+	}
+	return velocity
+}
+
+// String renders the velocity as an arrow followed by the change in percent,
+// e.g., '↑ +23.5%'.
+func (v Velocity) String() string {
+	arrow := "→"
+	switch v.Direction {
+	case VelocityUp:
+		arrow = "↑"
+	case VelocityDown:
+		arrow = "↓"
+	}
+	return fmt.Sprintf("%s %+.1f%%", arrow, v.Percentage)
+}
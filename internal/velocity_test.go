@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Velocity", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	records := func(weekly func(week int) int) []ContributionRecord {
+		r := NewContributionRecords(lastDay)
+		for week := 0; week < 52; week++ {
+			r[week*7].Count = weekly(week)
+		}
+		return r
+	}
+
+	It("detects an upward trend", func() {
+		v := NewVelocity(records(func(week int) int { return 10 + week }))
+		Expect(v.Direction).To(Equal(VelocityUp))
+		Expect(v.WeeklySlope).To(Equal(1.0))
+		// 51 weeks at slope 1 relative to an average of 35.5
+		Expect(v.Percentage).To(Equal(143.7))
+		Expect(v.String()).To(Equal("↑ +143.7%"))
+	})
+
+	It("detects a downward trend", func() {
+		v := NewVelocity(records(func(week int) int { return 100 - week }))
+		Expect(v.Direction).To(Equal(VelocityDown))
+		Expect(v.String()).To(HavePrefix("↓ -"))
+	})
+
+	It("considers small changes flat", func() {
+		v := NewVelocity(records(func(week int) int { return 100 + week%2 }))
+		Expect(v.Direction).To(Equal(VelocityFlat))
+		Expect(v.String()).To(HavePrefix("→"))
+	})
+
+	It("considers no contributions flat", func() {
+		Expect(NewVelocity(NewContributionRecords(lastDay))).To(Equal(Velocity{Direction: VelocityFlat}))
+	})
+
+	It("is rendered on the contribution graph", func() {
+		v := NewVelocity(records(func(week int) int { return 10 + week }))
+		graph := NewContributionMap(records(func(week int) int { return 10 + week }), lastDay, testColoring, 5)
+		graph.Velocity = &v
+		var buf bytes.Buffer
+		enc := xml.NewEncoder(&buf)
+		Expect(graph.Render(enc)).To(Succeed())
+		Expect(enc.Flush()).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("in the last year (↑ +143.7%)"))
+	})
+})
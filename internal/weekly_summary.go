@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WeeklySummary summarizes the contributions made within the week ending
+// with a given day for posting to chat services like Slack or Discord.
+type WeeklySummary struct {
+
+	// The first day of the week.
+	From string
+
+	// The last day of the week.
+	Until string
+
+	// The overall number of contributions.
+	TotalContributions int
+
+	// The number of contributions per contribution type.
+	ContributionsByType map[ContributionType]int
+
+	// The number of distinct contributors.
+	UniqueContributors int
+
+	// The contributors with the most contributions ordered by descending
+	// number of contributions.
+	TopContributors []ContributorCount
+
+	// The URL of the linked contribution graph. Nothing is linked if empty.
+	Link string
+}
+
+// NewWeeklySummary creates the WeeklySummary of the given contributions made
+// within the week ending with the given day listing up to top contributors
+// and linking the given URL.
+func NewWeeklySummary(contributions []Contribution, lastDay time.Time, top int, link string) *WeeklySummary {
+	firstDay := DigestStart(lastDay, WeeklyGranularity)
+	summary := &WeeklySummary{
+		From:                firstDay.Format(dateFormat),
+		Until:               lastDay.Format(dateFormat),
+		ContributionsByType: make(map[ContributionType]int),
+		TopContributors:     NewDigest(firstDay, lastDay, contributions, nil, nil, nil, top, "").TopContributors,
+		Link:                link,
+	}
+	contributors := make(map[string]bool)
+	for _, c := range contributions {
+		if inDigestPeriod(c.Date, firstDay, lastDay) {
+			summary.TotalContributions++
+			summary.ContributionsByType[c.Type]++
+			contributors[c.Contributor()] = true
+		}
+	}
+	summary.UniqueContributors = len(contributors)
+	return summary
+}
+
+// chatMarkup is the markup of a chat service.
+type chatMarkup struct {
+	bold   func(s string) string
+	link   func(url string, text string) string
+	escape func(s string) string
+}
+
+// slackMarkup is the mrkdwn markup of Slack.
+var slackMarkup = chatMarkup{
+	bold: func(s string) string { return "*" + s + "*" },
+	link: func(url string, text string) string { return "<" + url + "|" + text + ">" },
+	escape: strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	).Replace,
+}
+
+// discordMarkup is the markdown flavor of Discord.
+var discordMarkup = chatMarkup{
+	bold: func(s string) string { return "**" + s + "**" },
+	link: func(url string, text string) string { return "[" + text + "](<" + url + ">)" },
+	escape: strings.NewReplacer(
+		"\\", "\\\\",
+		"*", "\\*",
+		"_", "\\_",
+		"~", "\\~",
+		"`", "\\`",
+		"|", "\\|",
+		">", "\\>",
+	).Replace,
+}
+
+// text renders the summary as a message in the given markup.
+func (s *WeeklySummary) text(m chatMarkup) string {
+	var b strings.Builder
+	b.WriteString(m.bold(fmt.Sprintf("Weekly summary %s – %s", s.From, s.Until)))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%d contributions (%d commits, %d issues, %d pull requests) by %d contributors",
+		s.TotalContributions, s.ContributionsByType[CommitContribution], s.ContributionsByType[IssueContribution],
+		s.ContributionsByType[PullRequestContribution], s.UniqueContributors)
+	if len(s.TopContributors) != 0 {
+		var top []string
+		for _, c := range s.TopContributors {
+			top = append(top, fmt.Sprintf("%s (%d)", m.escape(c.Contributor), c.Contributions))
+		}
+		b.WriteString("\nTop contributors: ")
+		b.WriteString(strings.Join(top, ", "))
+	}
+	if s.Link != "" {
+		b.WriteString("\n")
+		b.WriteString(m.link(s.Link, "Contribution graph"))
+	}
+	return b.String()
+}
+
+// SlackText renders the summary as a Slack message in mrkdwn.
+func (s *WeeklySummary) SlackText() string {
+	return s.text(slackMarkup)
+}
+
+// DiscordText renders the summary as a Discord message in markdown.
+func (s *WeeklySummary) DiscordText() string {
+	return s.text(discordMarkup)
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Weekly summaries", func() {
+	lastDay := dateparse.MustParse("2023-04-01")
+	contributions := []Contribution{
+		{Type: CommitContribution, Login: "jane_doe", Date: lastDay},
+		{Type: CommitContribution, Login: "jane_doe", Date: lastDay.AddDate(0, 0, -6)},
+		{Type: PullRequestContribution, Login: "<bot>", Date: lastDay.AddDate(0, 0, -2)},
+		{Type: IssueContribution, Login: "old", Date: lastDay.AddDate(0, 0, -7)},
+	}
+
+	It("summarizes the contributions of the week ending with the given day", func() {
+		summary := NewWeeklySummary(contributions, lastDay, 1, "")
+		Expect(summary.From).To(Equal("2023-03-26"))
+		Expect(summary.TotalContributions).To(Equal(3))
+		Expect(summary.UniqueContributors).To(Equal(2))
+		Expect(summary.ContributionsByType[IssueContribution]).To(BeZero())
+		Expect(summary.TopContributors).To(Equal([]ContributorCount{{Contributor: "jane_doe", Contributions: 2}}))
+	})
+
+	It("renders Slack and Discord messages", func() {
+		summary := NewWeeklySummary(contributions, lastDay, 2, "https://example.com/graph.svg")
+		Expect(summary.SlackText()).To(Equal("*Weekly summary 2023-03-26 – 2023-04-01*\n" +
+			"3 contributions (2 commits, 0 issues, 1 pull requests) by 2 contributors\n" +
+			"Top contributors: jane_doe (2), &lt;bot&gt; (1)\n" +
+			"<https://example.com/graph.svg|Contribution graph>"))
+		Expect(summary.DiscordText()).To(Equal("**Weekly summary 2023-03-26 – 2023-04-01**\n" +
+			"3 contributions (2 commits, 0 issues, 1 pull requests) by 2 contributors\n" +
+			"Top contributors: jane\\_doe (2), <bot\\> (1)\n" +
+			"[Contribution graph](<https://example.com/graph.svg>)"))
+	})
+})
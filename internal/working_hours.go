@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	_ "embed"
+	"sort"
+	"time"
+)
+
+// The local working hours. Commits made on weekdays before workdayStart or
+// from workdayEnd on are considered to be made outside working hours.
+const (
+	workdayStart = 9
+	workdayEnd   = 18
+)
+
+// workingHoursMinCommits is the minimal number of commits of a contributor to
+// be listed individually. Shares computed from fewer commits are not
+// meaningful.
+const workingHoursMinCommits = 10
+
+// WorkingHours describes how many commits have been made on weekends and
+// outside working hours.
+type WorkingHours struct {
+
+	// The repository in 'owner/name' notation. Empty for the overall numbers
+	// and those of individual contributors.
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+
+	// The contributor. Empty for the overall numbers and those of individual
+	// repositories.
+	Contributor string `json:"contributor,omitempty" yaml:"contributor,omitempty"`
+
+	// The overall number of commits.
+	Commits int `json:"commits" yaml:"commits"`
+
+	// The number of commits made on Saturdays and Sundays.
+	Weekend int `json:"weekend" yaml:"weekend"`
+
+	// The number of commits made on weekdays outside working hours.
+	OffHours int `json:"offHours" yaml:"offHours"`
+
+	// The share of commits made on weekends in percent.
+	WeekendPercentage float64 `json:"weekendPercentage" yaml:"weekendPercentage"`
+
+	// The share of commits made on weekdays outside working hours in percent.
+	OffHoursPercentage float64 `json:"offHoursPercentage" yaml:"offHoursPercentage"`
+
+	// The share of commits made either on weekends or outside working hours
+	// in percent.
+	OutsidePercentage float64 `json:"outsidePercentage" yaml:"outsidePercentage"`
+}
+
+// WorkingHoursReport describes the share of commits made on weekends and
+// outside working hours per repository and overall. A high share can be an
+// early warning sign of maintainer burnout.
+type WorkingHoursReport struct {
+
+	// The first day of the analyzed period.
+	From string `json:"from" yaml:"from"`
+
+	// The last day of the analyzed period.
+	Until string `json:"until" yaml:"until"`
+
+	// The working hours across all repositories.
+	Overall WorkingHours `json:"overall" yaml:"overall"`
+
+	// The working hours of the individual repositories sorted by descending
+	// share of commits outside working hours.
+	Repositories []WorkingHours `json:"repositories" yaml:"repositories"`
+
+	// The contributors with the highest share of commits outside working
+	// hours.
+	Contributors []WorkingHours `json:"contributors" yaml:"contributors"`
+}
+
+// add accounts for the given commit.
+func (w *WorkingHours) add(c Contribution) {
+	w.Commits++
+	switch weekday := c.Date.Weekday(); {
+	case weekday == time.Saturday || weekday == time.Sunday:
+		w.Weekend++
+	case c.Date.Hour() < workdayStart || c.Date.Hour() >= workdayEnd:
+		w.OffHours++
+	}
+}
+
+// complete computes the shares once all commits are accounted for.
+func (w *WorkingHours) complete() {
+	w.WeekendPercentage = percentage(w.Weekend, w.Commits)
+	w.OffHoursPercentage = percentage(w.OffHours, w.Commits)
+	w.OutsidePercentage = percentage(w.Weekend+w.OffHours, w.Commits)
+}
+
+// sortWorkingHours sorts the given working hours by descending share of
+// commits outside working hours.
+func sortWorkingHours(hours []WorkingHours) {
+	sort.Slice(hours, func(i, j int) bool {
+		a, b := hours[i], hours[j]
+		if a.OutsidePercentage != b.OutsidePercentage {
+			return a.OutsidePercentage > b.OutsidePercentage
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.Contributor < b.Contributor
+	})
+}
+
+// NewWorkingHoursReport analyzes when the given commits made within the 52
+// weeks ending with the given day have been made in the local time of the
+// respective author as given by the UTC offset recorded with each commit. Up
+// to top contributors with at least a minimal number of commits are listed.
+func NewWorkingHoursReport(commits []Contribution, lastDay time.Time, top int) *WorkingHoursReport {
+	report := &WorkingHoursReport{
+		From:  lastDay.AddDate(0, 0, -52*7+1).Format(dateFormat),
+		Until: lastDay.Format(dateFormat),
+	}
+	repositories := make(map[string]*WorkingHours)
+	contributors := make(map[string]*WorkingHours)
+	for _, c := range commits {
+		if c.Type != CommitContribution || !InPeriod(c.Date, lastDay) {
+			continue
+		}
+		report.Overall.add(c)
+		if _, ok := repositories[c.Repository]; !ok {
+			repositories[c.Repository] = &WorkingHours{Repository: c.Repository}
+		}
+		repositories[c.Repository].add(c)
+		if _, ok := contributors[c.Contributor()]; !ok {
+			contributors[c.Contributor()] = &WorkingHours{Contributor: c.Contributor()}
+		}
+		contributors[c.Contributor()].add(c)
+	}
+	report.Overall.complete()
+	for _, hours := range repositories {
+		hours.complete()
+		report.Repositories = append(report.Repositories, *hours)
+	}
+	sortWorkingHours(report.Repositories)
+	for _, hours := range contributors {
+		if hours.Commits < workingHoursMinCommits {
+			continue
+		}
+		hours.complete()
+		report.Contributors = append(report.Contributors, *hours)
+	}
+	sortWorkingHours(report.Contributors)
+	if len(report.Contributors) > top {
+		report.Contributors = report.Contributors[:top]
+	}
+	return report
+}
+
+var (
+	// The embedded template used for rendering working hours reports as
+	// markdown.
+	//go:embed working-hours.gomd
+	workingHoursTemplate string
+)
+
+// Markdown renders the working hours report as markdown.
+func (r *WorkingHoursReport) Markdown() (string, error) {
+	return renderMarkdown("working-hours", workingHoursTemplate, r)
+}
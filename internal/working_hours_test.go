@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2023 - for information on the respective copyright owner
+ * see the NOTICE file and/or the repository https://github.com/herdstat/herdstat.
+ *
+ * SPDX-License-Identifier: MIT
+ */
+
+package internal
+
+import (
+	"github.com/araddon/dateparse"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Working hours reports", func() {
+	lastDay := dateparse.MustParse("2023-03-15 23:59")
+	ist := time.FixedZone("IST", 5*3600+1800)
+	commit := func(repository string, login string, date time.Time) Contribution {
+		return Contribution{Type: CommitContribution, Repository: repository, Login: login, Date: date}
+	}
+	var commits []Contribution
+	// Wednesday within working hours
+	for i := 0; i < 6; i++ {
+		commits = append(commits, commit("herdstat/herdstat", "alice", time.Date(2023, 3, 1, 10, 0, 0, 0, ist)))
+	}
+	// Wednesday evening, Saturday, and Wednesday early morning
+	commits = append(commits,
+		commit("herdstat/herdstat", "alice", time.Date(2023, 3, 1, 18, 0, 0, 0, ist)),
+		commit("herdstat/herdstat", "alice", time.Date(2023, 3, 4, 12, 0, 0, 0, ist)),
+		commit("herdstat/herdstat", "alice", time.Date(2023, 3, 4, 13, 0, 0, 0, ist)),
+		commit("herdstat/action", "alice", time.Date(2023, 3, 1, 8, 59, 0, 0, ist)),
+		commit("herdstat/action", "bob", time.Date(2023, 3, 5, 10, 0, 0, 0, time.UTC)),
+		// Outside the analyzed period
+		commit("herdstat/action", "bob", time.Date(2021, 3, 5, 10, 0, 0, 0, time.UTC)),
+	)
+	report := NewWorkingHoursReport(commits, lastDay, 10)
+
+	It("computes the overall shares", func() {
+		Expect(report.Overall).To(Equal(WorkingHours{
+			Commits: 11, Weekend: 3, OffHours: 2,
+			WeekendPercentage: 27.2, OffHoursPercentage: 18.1, OutsidePercentage: 45.4,
+		}))
+	})
+
+	It("sorts the repositories by the share outside working hours", func() {
+		Expect(report.Repositories).To(HaveLen(2))
+		Expect(report.Repositories[0].Repository).To(Equal("herdstat/action"))
+		Expect(report.Repositories[0].OutsidePercentage).To(Equal(100.0))
+		Expect(report.Repositories[1].Repository).To(Equal("herdstat/herdstat"))
+		Expect(report.Repositories[1].OutsidePercentage).To(Equal(33.3))
+	})
+
+	It("lists contributors with enough commits only", func() {
+		Expect(report.Contributors).To(HaveLen(1))
+		Expect(report.Contributors[0].Contributor).To(Equal("alice"))
+		Expect(report.Contributors[0].Commits).To(Equal(10))
+	})
+
+	It("renders a markdown table", func() {
+		md, err := report.Markdown()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(ContainSubstring("| **Overall** | 11 | 27.2% | 18.1% | 45.4% |"))
+	})
+})
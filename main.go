@@ -9,7 +9,7 @@
 package main
 
 import (
-	"herdstat/cmd"
+	"github.com/herdstat/herdstat/cmd"
 )
 
 func main() {